@@ -0,0 +1,637 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiClient answers the gh invocations prtop makes by calling the GitHub
+// API itself with a token from the environment, for containers, CI jobs
+// and servers where gh isn't installed. run is called in place of gh, so
+// retries, recording and the response cache work as they do with gh, and
+// errors read like gh's.
+type apiClient struct {
+	token           string // GH_TOKEN or GITHUB_TOKEN, for github.com
+	enterpriseToken string // GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN, for other hosts
+	host            string // GH_HOST, else github.com
+	http            *http.Client
+	// baseURL is the API root for host; tests point it at a local server.
+	baseURL func(host string) string
+}
+
+// tokenClient is set when gh isn't on PATH but a token is in the
+// environment; runGh then goes through it instead of gh.
+var tokenClient *apiClient
+
+// newAPIClient returns the client for the tokens in the environment, or
+// nil when there are none.
+func newAPIClient(getenv func(string) string) *apiClient {
+	c := &apiClient{
+		token:           firstEnv(getenv, "GH_TOKEN", "GITHUB_TOKEN"),
+		enterpriseToken: firstEnv(getenv, "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"),
+		host:            firstEnv(getenv, "GH_HOST"),
+		http:            &http.Client{Timeout: ghTimeout},
+		baseURL:         apiBaseURL,
+	}
+	if c.token == "" && c.enterpriseToken == "" {
+		return nil
+	}
+	addSecret(c.token)
+	addSecret(c.enterpriseToken)
+	if c.host == "" {
+		c.host = "github.com"
+	}
+	return c
+}
+
+// apiTransport is the client's transport for the [api] config: proxies
+// from HTTPS_PROXY and NO_PROXY, as for gh, and the system's CAs plus
+// cfg.CAFile.
+func apiTransport(cfg API) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if cfg.CAFile == "" && !cfg.InsecureSkipVerify {
+		return t, nil
+	}
+	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CAFile != "" {
+		path := cfg.CAFile
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, rest)
+			}
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("api.ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("api.ca_file: no PEM certificates in %s", path)
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+	return t, nil
+}
+
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, n := range names {
+		if v := getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// apiBaseURL is the REST root of host; GitHub Enterprise Server serves it
+// under /api/v3 and GraphQL under /api/graphql.
+func apiBaseURL(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// ghArgs are a gh command line split into its positional arguments and
+// flags. Flags are --name value, --name=value or, for boolFlags, --name.
+type ghArgs struct {
+	pos   []string
+	flags map[string][]string
+}
+
+var boolFlags = map[string]bool{"--failed": true, "--rebase": true, "--auto": true, "--squash": true, "--merge": true}
+
+func parseGhArgs(args []string) ghArgs {
+	a := ghArgs{flags: map[string][]string{}}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			a.pos = append(a.pos, args[i+1:]...)
+			return a
+		case boolFlags[arg]:
+			a.flags[arg] = append(a.flags[arg], "true")
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			a.flags[name] = append(a.flags[name], value)
+		default:
+			a.pos = append(a.pos, arg)
+		}
+	}
+	return a
+}
+
+func (a ghArgs) flag(name string) string {
+	if v := a.flags[name]; len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return ""
+}
+
+// run answers one gh invocation. acct's host, when set, is the default
+// host, as GH_HOST is for gh.
+func (c *apiClient) run(acct *Account, args []string) ([]byte, error) {
+	start := time.Now()
+	host := c.host
+	if acct != nil && acct.Host != "" {
+		host = acct.Host
+	}
+	out, err := c.dispatch(host, args)
+	logger.Debug("api request", "args", args, "duration", time.Since(start), "bytes", len(out), "err", err)
+	return out, err
+}
+
+func (c *apiClient) dispatch(host string, args []string) ([]byte, error) {
+	if len(args) >= 1 && args[0] == "api" {
+		return c.api(host, parseGhArgs(args[1:]))
+	}
+	if len(args) >= 2 {
+		a := parseGhArgs(args[2:])
+		switch args[0] + " " + args[1] {
+		case "pr view":
+			return c.prView(host, a)
+		case "pr list":
+			return c.prList(host, a)
+		case "pr comment":
+			return c.prWrite(host, a, "POST", "repos/%s/issues/%s/comments", map[string]any{"body": a.flag("--body")})
+		case "pr edit":
+			return c.prWrite(host, a, "PATCH", "repos/%s/pulls/%s", map[string]any{"body": a.flag("--body")})
+		case "pr merge":
+			return c.prMerge(host, a)
+		case "pr update-branch":
+			if a.flag("--rebase") != "" {
+				return nil, newGhError("rebasing a PR branch needs the gh CLI; install it from https://cli.github.com/")
+			}
+			return c.prWrite(host, a, "PUT", "repos/%s/pulls/%s/update-branch", nil)
+		case "search prs":
+			return c.searchPRs(host, a)
+		case "run rerun":
+			return c.rerun(host, a)
+		}
+	}
+	return nil, newGhError(fmt.Sprintf("`gh %s` needs the gh CLI; install it from https://cli.github.com/", strings.Join(args, " ")))
+}
+
+// request makes one API call and returns the body, turning HTTP errors
+// into gh's "Message (HTTP 404)".
+func (c *apiClient) request(host, method, path string, body any, headers map[string]string) ([]byte, error) {
+	token := c.token
+	if host != "github.com" {
+		token = c.enterpriseToken
+	}
+	if token == "" {
+		return nil, newGhError(fmt.Sprintf("no token for %s: set GH_ENTERPRISE_TOKEN, or GITHUB_TOKEN for github.com (HTTP 401)", host))
+	}
+	url := c.baseURL(host) + "/" + strings.TrimPrefix(path, "/")
+	if path == "graphql" && host != "github.com" {
+		url = strings.TrimSuffix(c.baseURL(host), "/v3") + "/graphql"
+	}
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	v, _, _ := buildInfo()
+	req.Header.Set("User-Agent", "prtop/"+v)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, &ghError{kind: ghErrNetwork, msg: redact(err.Error())}
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ghError{kind: ghErrNetwork, msg: redact(err.Error())}
+	}
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		msg := http.StatusText(resp.StatusCode)
+		if json.Unmarshal(out, &e) == nil && e.Message != "" {
+			msg = e.Message
+		}
+		return nil, newGhError(fmt.Sprintf("%s (HTTP %d)", msg, resp.StatusCode))
+	}
+	return out, nil
+}
+
+// graphql runs query and returns its whole response, failing like
+// `gh api graphql` when GitHub reports errors.
+func (c *apiClient) graphql(host, query string, vars map[string]any) ([]byte, error) {
+	out, err := c.request(host, "POST", "graphql", map[string]any{"query": query, "variables": vars}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Path    []any  `json:"path"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(out, &resp) == nil && len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			// Like gh, name where the error is: "... (repository.pullRequest)"
+			msgs[i] = e.Message
+			if len(e.Path) > 0 {
+				path := make([]string, len(e.Path))
+				for j, p := range e.Path {
+					path[j] = fmt.Sprint(p)
+				}
+				msgs[i] += " (" + strings.Join(path, ".") + ")"
+			}
+		}
+		return nil, newGhError("GraphQL: " + strings.Join(msgs, ", "))
+	}
+	return out, nil
+}
+
+// api is `gh api`: -X, -H, --hostname, and -f/-F fields, which become
+// GraphQL variables (typed with -F) or a JSON body.
+func (c *apiClient) api(host string, a ghArgs) ([]byte, error) {
+	if len(a.pos) != 1 {
+		return nil, newGhError("gh api: expected one endpoint")
+	}
+	if h := a.flag("--hostname"); h != "" {
+		host = h
+	}
+	fields := map[string]any{}
+	for _, f := range a.flags["-f"] {
+		k, v, _ := strings.Cut(f, "=")
+		fields[k] = v
+	}
+	for _, f := range a.flags["-F"] {
+		k, v, _ := strings.Cut(f, "=")
+		fields[k] = typedField(v)
+	}
+	if a.pos[0] == "graphql" {
+		query, _ := fields["query"].(string)
+		delete(fields, "query")
+		return c.graphql(host, query, fields)
+	}
+	method := a.flag("-X")
+	if method == "" {
+		method = "GET"
+		if len(fields) > 0 {
+			method = "POST"
+		}
+	}
+	var body any
+	if len(fields) > 0 {
+		body = fields
+	}
+	headers := map[string]string{}
+	for _, h := range a.flags["-H"] {
+		k, v, _ := strings.Cut(h, ":")
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return c.request(host, method, a.pos[0], body, headers)
+}
+
+// typedField converts a -F value as gh does: numbers, booleans and null.
+func typedField(v string) any {
+	switch v {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	return v
+}
+
+// prRef is the --repo and PR number of a `gh pr` command, and the host
+// from the repo when it names one.
+func prRef(host string, a ghArgs) (h, owner, name, number string, err error) {
+	repo := a.flag("--repo")
+	if len(a.pos) == 0 || repo == "" {
+		return "", "", "", "", newGhError("the current branch's PR needs the gh CLI; give a PR number and --repo")
+	}
+	if rh, ownerRepo := splitRepoHost(repo); rh != "" {
+		host, repo = rh, ownerRepo
+	}
+	owner, name, _ = strings.Cut(repo, "/")
+	return host, owner, name, a.pos[0], nil
+}
+
+func (c *apiClient) prWrite(host string, a ghArgs, method, pathFmt string, body any) ([]byte, error) {
+	host, owner, name, number, err := prRef(host, a)
+	if err != nil {
+		return nil, err
+	}
+	return c.request(host, method, fmt.Sprintf(pathFmt, owner+"/"+name, number), body, nil)
+}
+
+// ghMergeMethod is the merge method of a `gh pr merge` command line.
+func ghMergeMethod(a ghArgs) string {
+	for _, m := range []string{"squash", "rebase"} {
+		if a.flag("--"+m) != "" {
+			return m
+		}
+	}
+	return "merge"
+}
+
+const prIDQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) { pullRequest(number: $number) { id } }
+}`
+
+const enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`
+
+// prMerge merges a PR with the REST API, or with --auto turns on
+// auto-merge, which only GraphQL can do.
+func (c *apiClient) prMerge(host string, a ghArgs) ([]byte, error) {
+	method := ghMergeMethod(a)
+	if a.flag("--auto") == "" {
+		return c.prWrite(host, a, "PUT", "repos/%s/pulls/%s/merge", map[string]any{"merge_method": method})
+	}
+	host, owner, name, number, err := prRef(host, a)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.graphql(host, prIDQuery, map[string]any{"owner": owner, "name": name, "number": typedField(number)})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					ID string `json:"id"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Repository.PullRequest == nil {
+		return nil, newGhError("Could not resolve to a PullRequest with the number of " + number)
+	}
+	return c.graphql(host, enableAutoMergeMutation, map[string]any{"id": resp.Data.Repository.PullRequest.ID, "method": strings.ToUpper(method)})
+}
+
+func (c *apiClient) rerun(host string, a ghArgs) ([]byte, error) {
+	repo := a.flag("--repo")
+	if rh, ownerRepo := splitRepoHost(repo); rh != "" {
+		host, repo = rh, ownerRepo
+	}
+	switch {
+	case a.flag("--job") != "":
+		return c.request(host, "POST", "repos/"+repo+"/actions/jobs/"+a.flag("--job")+"/rerun", nil, nil)
+	case len(a.pos) == 0:
+		return nil, newGhError("gh run rerun: expected a run ID")
+	case a.flag("--failed") != "":
+		return c.request(host, "POST", "repos/"+repo+"/actions/runs/"+a.pos[0]+"/rerun-failed-jobs", nil, nil)
+	}
+	return c.request(host, "POST", "repos/"+repo+"/actions/runs/"+a.pos[0]+"/rerun", nil, nil)
+}
+
+// prViewQuery fetches the fields prtop asks `gh pr view --json` for, a
+// page of status checks at a time.
+const prViewQuery = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      title body url headRefName headRefOid baseRefName reviewDecision mergeable state
+      commits(last: 1) { nodes { commit { statusCheckRollup { contexts(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          __typename
+          ... on CheckRun {
+            name status conclusion startedAt completedAt detailsUrl
+            checkSuite { workflowRun { workflow { name } } }
+          }
+          ... on StatusContext { context state targetUrl description createdAt }
+        }
+      } } } } }
+    }
+  }
+}`
+
+// prView is `gh pr view N --repo R --json ...`, shaped like gh's output:
+// workflow names flattened into workflowName, and a status context's
+// createdAt as its startedAt.
+func (c *apiClient) prView(host string, a ghArgs) ([]byte, error) {
+	host, owner, name, number, err := prRef(host, a)
+	if err != nil {
+		return nil, err
+	}
+	type checkNode struct {
+		Typename     string `json:"__typename"`
+		Name         string `json:"name,omitempty"`
+		Context      string `json:"context,omitempty"`
+		Status       string `json:"status,omitempty"`
+		Conclusion   string `json:"conclusion,omitempty"`
+		State        string `json:"state,omitempty"`
+		StartedAt    string `json:"startedAt,omitempty"`
+		CompletedAt  string `json:"completedAt,omitempty"`
+		CreatedAt    string `json:"createdAt,omitempty"`
+		DetailsURL   string `json:"detailsUrl,omitempty"`
+		TargetURL    string `json:"targetUrl,omitempty"`
+		Description  string `json:"description,omitempty"`
+		WorkflowName string `json:"workflowName,omitempty"`
+		CheckSuite   *struct {
+			WorkflowRun *struct {
+				Workflow struct {
+					Name string `json:"name"`
+				} `json:"workflow"`
+			} `json:"workflowRun"`
+		} `json:"checkSuite,omitempty"`
+	}
+	var pr map[string]any
+	var checks []checkNode
+	vars := map[string]any{"owner": owner, "name": name, "number": typedField(number)}
+	for {
+		out, err := c.graphql(host, prViewQuery, vars)
+		if err != nil {
+			return nil, err
+		}
+		var resp struct {
+			Data struct {
+				Repository struct {
+					PullRequest map[string]json.RawMessage `json:"pullRequest"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return nil, err
+		}
+		raw := resp.Data.Repository.PullRequest
+		if raw == nil {
+			return nil, newGhError("GraphQL: Could not resolve to a PullRequest with the number of " + number + ". (repository.pullRequest)")
+		}
+		var commits struct {
+			Nodes []struct {
+				Commit struct {
+					StatusCheckRollup *struct {
+						Contexts struct {
+							PageInfo struct {
+								HasNextPage bool   `json:"hasNextPage"`
+								EndCursor   string `json:"endCursor"`
+							} `json:"pageInfo"`
+							Nodes []checkNode `json:"nodes"`
+						} `json:"contexts"`
+					} `json:"statusCheckRollup"`
+				} `json:"commit"`
+			} `json:"nodes"`
+		}
+		if err := json.Unmarshal(raw["commits"], &commits); err != nil {
+			return nil, err
+		}
+		if pr == nil {
+			pr = map[string]any{}
+			for k, v := range raw {
+				if k != "commits" {
+					pr[k] = v
+				}
+			}
+		}
+		if len(commits.Nodes) == 0 || commits.Nodes[0].Commit.StatusCheckRollup == nil {
+			break
+		}
+		contexts := commits.Nodes[0].Commit.StatusCheckRollup.Contexts
+		checks = append(checks, contexts.Nodes...)
+		if !contexts.PageInfo.HasNextPage {
+			break
+		}
+		vars["after"] = contexts.PageInfo.EndCursor
+	}
+	for i, n := range checks {
+		if n.CheckSuite != nil && n.CheckSuite.WorkflowRun != nil {
+			checks[i].WorkflowName = n.CheckSuite.WorkflowRun.Workflow.Name
+		}
+		checks[i].CheckSuite = nil
+		if n.Typename == "StatusContext" {
+			checks[i].StartedAt, checks[i].CreatedAt = n.CreatedAt, ""
+		}
+	}
+	if checks == nil {
+		checks = []checkNode{}
+	}
+	pr["statusCheckRollup"] = checks
+	return json.Marshal(pr)
+}
+
+// prListQuery is `gh pr list --state=open`: newest first.
+const prListQuery = `query($owner: String!, $name: String!, $first: Int!, $head: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: OPEN, headRefName: $head, first: $first, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { number title url updatedAt createdAt isDraft author { login } }
+    }
+  }
+}`
+
+func (c *apiClient) prList(host string, a ghArgs) ([]byte, error) {
+	repo := a.flag("--repo")
+	if rh, ownerRepo := splitRepoHost(repo); rh != "" {
+		host, repo = rh, ownerRepo
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	first := min(max(typedInt(a.flag("--limit"), 30), 1), 100)
+	vars := map[string]any{"owner": owner, "name": name, "first": first}
+	if head := a.flag("--head"); head != "" {
+		vars["head"] = head
+	}
+	out, err := c.graphql(host, prListQuery, vars)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Repository *struct {
+				PullRequests struct {
+					Nodes []json.RawMessage `json:"nodes"`
+				} `json:"pullRequests"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Repository == nil {
+		return nil, newGhError("GraphQL: Could not resolve to a Repository with the name '" + repo + "'. (repository)")
+	}
+	return json.Marshal(resp.Data.Repository.PullRequests.Nodes)
+}
+
+func typedInt(v string, fallback int) int {
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	return fallback
+}
+
+const searchPRsQuery = `query($q: String!, $first: Int!) {
+  search(query: $q, type: ISSUE, first: $first) {
+    nodes { ... on PullRequest { number title url updatedAt createdAt isDraft repository { nameWithOwner } } }
+  }
+}`
+
+// searchPRs is `gh search prs`: its flags become qualifiers of the query.
+func (c *apiClient) searchPRs(host string, a ghArgs) ([]byte, error) {
+	q := []string{"is:pr"}
+	if v := a.flag("--author"); v != "" {
+		q = append(q, "author:"+v)
+	}
+	if v := a.flag("--state"); v != "" {
+		q = append(q, "is:"+v)
+	}
+	if v := a.flag("--sort"); v != "" {
+		q = append(q, "sort:"+v+"-desc")
+	}
+	q = append(q, a.pos...)
+	first := min(max(typedInt(a.flag("--limit"), 30), 1), 100)
+	out, err := c.graphql(host, searchPRsQuery, map[string]any{"q": strings.Join(q, " "), "first": first})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Search struct {
+				Nodes []json.RawMessage `json:"nodes"`
+			} `json:"search"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+	return json.Marshal(resp.Data.Search.Nodes)
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// apiRequest is one request the fake GitHub API saw.
+type apiRequest struct {
+	method, path, auth string
+	body               map[string]any
+}
+
+// fakeAPI serves handler's responses to a client with a GITHUB_TOKEN and
+// records the requests it sees.
+func fakeAPI(t *testing.T, handler func(r apiRequest) (int, string)) (*apiClient, *[]apiRequest) {
+	t.Helper()
+	var reqs []apiRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := apiRequest{method: r.Method, path: r.URL.RequestURI(), auth: r.Header.Get("Authorization")}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &req.body)
+		reqs = append(reqs, req)
+		code, body := handler(req)
+		w.WriteHeader(code)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	c := newAPIClient(func(name string) string {
+		return map[string]string{"GITHUB_TOKEN": "tok", "GH_ENTERPRISE_TOKEN": "ghe"}[name]
+	})
+	c.baseURL = func(host string) string {
+		if host == "github.com" {
+			return srv.URL
+		}
+		return srv.URL + "/" + host + "/api/v3"
+	}
+	return c, &reqs
+}
+
+func TestNewAPIClient(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	if c := newAPIClient(env(nil)); c != nil {
+		t.Errorf("no token: got a client")
+	}
+	c := newAPIClient(env(map[string]string{"GITHUB_TOKEN": "a", "GH_TOKEN": "b"}))
+	if c == nil || c.token != "b" || c.host != "github.com" {
+		t.Errorf("got %+v, want GH_TOKEN preferred and github.com", c)
+	}
+	c = newAPIClient(env(map[string]string{"GH_ENTERPRISE_TOKEN": "e", "GH_HOST": "ghe.example.com"}))
+	if c == nil || c.enterpriseToken != "e" || c.host != "ghe.example.com" {
+		t.Errorf("got %+v, want the enterprise token and GH_HOST", c)
+	}
+}
+
+func TestParseGhArgs(t *testing.T) {
+	a := parseGhArgs([]string{"7", "--repo", "o/r", "--state=open", "--auto", "--squash", "-F", "a=1", "-F", "b=x", "--", "--weird", "q"})
+	if strings.Join(a.pos, " ") != "7 --weird q" {
+		t.Errorf("pos = %q", a.pos)
+	}
+	if a.flag("--repo") != "o/r" || a.flag("--state") != "open" || a.flag("--auto") == "" || a.flag("--squash") == "" {
+		t.Errorf("flags = %v", a.flags)
+	}
+	if got := a.flags["-F"]; len(got) != 2 {
+		t.Errorf("-F = %v, want both values", got)
+	}
+}
+
+func TestAPIClientGraphQL(t *testing.T) {
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) {
+		return 200, `{"data":{"ok":true}}`
+	})
+	out, err := c.run(nil, []string{"api", "graphql", "-f", "query=query { ok }", "-F", "number=42", "-F", "draft=true", "-f", "name=42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"data":{"ok":true}}` {
+		t.Errorf("out = %s", out)
+	}
+	r := (*reqs)[0]
+	if r.method != "POST" || r.path != "/graphql" || r.auth != "Bearer tok" {
+		t.Errorf("request = %+v", r)
+	}
+	vars, _ := r.body["variables"].(map[string]any)
+	if r.body["query"] != "query { ok }" || vars["number"] != 42.0 || vars["draft"] != true || vars["name"] != "42" {
+		t.Errorf("body = %v", r.body)
+	}
+}
+
+func TestAPIClientEnterpriseHost(t *testing.T) {
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) { return 200, `{}` })
+	if _, err := c.run(nil, []string{"api", "--hostname", "ghe.example.com", "graphql", "-f", "query=q"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.run(&Account{Host: "ghe.example.com"}, []string{"api", "repos/o/r/pulls/1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := (*reqs)[0]; got.path != "/ghe.example.com/api/graphql" || got.auth != "Bearer ghe" {
+		t.Errorf("graphql request = %+v", got)
+	}
+	if got := (*reqs)[1]; got.method != "GET" || got.path != "/ghe.example.com/api/v3/repos/o/r/pulls/1" {
+		t.Errorf("REST request = %+v", got)
+	}
+}
+
+func TestAPIClientErrors(t *testing.T) {
+	c, _ := fakeAPI(t, func(r apiRequest) (int, string) {
+		switch {
+		case strings.Contains(r.path, "missing"):
+			return 404, `{"message":"Not Found"}`
+		case strings.Contains(r.path, "secret"):
+			return 401, `{"message":"Bad credentials"}`
+		}
+		return 200, `{"errors":[{"message":"Could not resolve to a Repository with the name 'o/x'.","path":["pr3"]}]}`
+	})
+	tests := []struct {
+		args []string
+		kind ghErrorKind
+		msg  string
+	}{
+		{[]string{"api", "repos/o/missing"}, ghErrNotFound, "Not Found (HTTP 404)"},
+		{[]string{"api", "repos/o/secret"}, ghErrAuth, "Bad credentials (HTTP 401)"},
+		{[]string{"api", "graphql", "-f", "query=q"}, ghErrNotFound, "GraphQL: Could not resolve to a Repository with the name 'o/x'. (pr3)"},
+		{[]string{"workflow", "list"}, ghErrOther, "needs the gh CLI"},
+		{[]string{"pr", "view", "--json", "title"}, ghErrOther, "needs the gh CLI"},
+	}
+	for _, tt := range tests {
+		_, err := c.run(nil, tt.args)
+		if err == nil || ghErrKind(err) != tt.kind || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("%v: err = %v (kind %v), want kind %v and %q", tt.args, err, ghErrKind(err), tt.kind, tt.msg)
+		}
+	}
+}
+
+func TestAPIClientNetworkError(t *testing.T) {
+	c, _ := fakeAPI(t, func(r apiRequest) (int, string) { return 200, `` })
+	c.baseURL = func(string) string { return "http://127.0.0.1:1" }
+	if _, err := c.run(nil, []string{"api", "user"}); ghErrKind(err) != ghErrNetwork {
+		t.Errorf("err = %v, want a network error", err)
+	}
+}
+
+func TestAPIClientPRView(t *testing.T) {
+	pages := []string{
+		`{"data":{"repository":{"pullRequest":{"title":"Fix it","state":"OPEN","headRefOid":"abc",
+		  "commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{
+		    "pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+		    "nodes":[{"__typename":"CheckRun","name":"test","status":"COMPLETED","conclusion":"FAILURE",
+		      "checkSuite":{"workflowRun":{"workflow":{"name":"CI"}}}}]}}}}]}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"title":"Fix it","state":"OPEN","headRefOid":"abc",
+		  "commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{
+		    "pageInfo":{"hasNextPage":false},
+		    "nodes":[{"__typename":"StatusContext","context":"ci/jenkins","state":"PENDING","createdAt":"2024-05-01T10:00:00Z"}]}}}}]}}}}}`,
+	}
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) {
+		vars, _ := r.body["variables"].(map[string]any)
+		if vars["after"] == "c1" {
+			return 200, pages[1]
+		}
+		return 200, pages[0]
+	})
+	out, err := c.run(nil, []string{"pr", "view", "7", "--repo", "o/r", "--json", "title,headRefOid,statusCheckRollup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*reqs) != 2 {
+		t.Fatalf("made %d requests, want 2 pages", len(*reqs))
+	}
+	data, err := parsePRView(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "Fix it" || data.HeadSHA != "abc" || len(data.Checks) != 2 {
+		t.Fatalf("data = %+v", data)
+	}
+	names := map[string]Check{}
+	for _, ch := range data.Checks {
+		names[ch.JobName] = ch
+	}
+	if ch, ok := names["test"]; !ok || ch.Status != Fail || ch.Workflow != "CI" {
+		t.Errorf("check run = %+v", names)
+	}
+	if ch, ok := names["ci/jenkins"]; !ok || ch.Status != Running || ch.StartedAt.IsZero() {
+		t.Errorf("status context = %+v", names)
+	}
+}
+
+func TestAPIClientPRList(t *testing.T) {
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) {
+		return 200, `{"data":{"repository":{"pullRequests":{"nodes":[
+		  {"number":3,"title":"Bump x","url":"u","updatedAt":"2024-05-01T10:00:00Z","isDraft":false,"author":{"login":"app/dependabot"}}]}}}}`
+	})
+	prs, err := fetchBotPRsWith(t, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 1 || prs[0].Number != 3 || prs[0].Author != "app/dependabot" {
+		t.Errorf("prs = %+v", prs)
+	}
+	vars, _ := (*reqs)[0].body["variables"].(map[string]any)
+	if vars["owner"] != "o" || vars["name"] != "r" || vars["first"] != 100.0 {
+		t.Errorf("variables = %v", vars)
+	}
+}
+
+// fetchBotPRsWith lists o/r's bot PRs through c, as runGh does without gh.
+func fetchBotPRsWith(t *testing.T, c *apiClient) ([]PRSummary, error) {
+	t.Helper()
+	tokenClient = c
+	t.Cleanup(func() { tokenClient = nil })
+	return fetchBotPRs(nil, "o/r")
+}
+
+func TestAPIClientSearchPRs(t *testing.T) {
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) {
+		return 200, `{"data":{"search":{"nodes":[{"number":9,"title":"t","repository":{"nameWithOwner":"o/r"}}]}}}`
+	})
+	out, err := c.run(nil, []string{"search", "prs", "--author", "@me", "--state", "open", "--limit", "50", "--json", "number", "--", "label:bug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"nameWithOwner":"o/r"`) {
+		t.Errorf("out = %s", out)
+	}
+	vars, _ := (*reqs)[0].body["variables"].(map[string]any)
+	if vars["q"] != "is:pr author:@me is:open label:bug" || vars["first"] != 50.0 {
+		t.Errorf("variables = %v", vars)
+	}
+}
+
+func TestAPIClientWrites(t *testing.T) {
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) {
+		if r.path == "/graphql" && strings.Contains(r.body["query"].(string), "pullRequest(number") {
+			return 200, `{"data":{"repository":{"pullRequest":{"id":"PR_1"}}}}`
+		}
+		return 200, `{}`
+	})
+	tests := []struct {
+		args         []string
+		method, path string
+		body         string
+	}{
+		{[]string{"pr", "comment", "5", "--repo", "o/r", "--body", "@dependabot rebase"}, "POST", "/repos/o/r/issues/5/comments", `{"body":"@dependabot rebase"}`},
+		{[]string{"pr", "edit", "5", "--repo", "o/r", "--body", "new"}, "PATCH", "/repos/o/r/pulls/5", `{"body":"new"}`},
+		{[]string{"pr", "merge", "5", "--repo", "o/r", "--squash"}, "PUT", "/repos/o/r/pulls/5/merge", `{"merge_method":"squash"}`},
+		{[]string{"pr", "update-branch", "5", "--repo", "o/r"}, "PUT", "/repos/o/r/pulls/5/update-branch", `null`},
+		{[]string{"run", "rerun", "99", "--repo", "o/r", "--failed"}, "POST", "/repos/o/r/actions/runs/99/rerun-failed-jobs", `null`},
+		{[]string{"run", "rerun", "99", "--repo", "o/r"}, "POST", "/repos/o/r/actions/runs/99/rerun", `null`},
+		{[]string{"run", "rerun", "--job", "12", "--repo", "o/r"}, "POST", "/repos/o/r/actions/jobs/12/rerun", `null`},
+	}
+	for _, tt := range tests {
+		*reqs = nil
+		if _, err := c.run(nil, tt.args); err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+			continue
+		}
+		r := (*reqs)[0]
+		body, _ := json.Marshal(r.body)
+		if r.method != tt.method || r.path != tt.path || string(body) != tt.body {
+			t.Errorf("%v: got %s %s %s, want %s %s %s", tt.args, r.method, r.path, body, tt.method, tt.path, tt.body)
+		}
+	}
+
+	*reqs = nil
+	if _, err := c.run(nil, []string{"pr", "merge", "5", "--repo", "o/r", "--auto", "--rebase"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(*reqs) != 2 {
+		t.Fatalf("auto-merge made %d requests, want the PR's id then the mutation", len(*reqs))
+	}
+	vars, _ := (*reqs)[1].body["variables"].(map[string]any)
+	if vars["id"] != "PR_1" || vars["method"] != "REBASE" {
+		t.Errorf("mutation variables = %v", vars)
+	}
+
+	if _, err := c.run(nil, []string{"pr", "update-branch", "5", "--repo", "o/r", "--rebase"}); err == nil {
+		t.Error("update-branch --rebase: want an error asking for gh")
+	}
+}
+
+func TestRunGhUsesTokenClient(t *testing.T) {
+	c, reqs := fakeAPI(t, func(r apiRequest) (int, string) { return 200, `{"login":"me"}` })
+	tokenClient = c
+	t.Cleanup(func() { tokenClient = nil })
+	out, err := runGh(nil, "api", "user")
+	if err != nil || string(out) != `{"login":"me"}` || len(*reqs) != 1 {
+		t.Errorf("out = %s, err = %v, requests = %d", out, err, len(*reqs))
+	}
+}
+
+func TestAPITransport(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"login":"me"}`)
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // the rejected handshake
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(ca, cert, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(cfg API) error {
+		transport, err := apiTransport(cfg)
+		if err != nil {
+			return err
+		}
+		if transport.Proxy == nil {
+			t.Error("transport doesn't take proxies from the environment")
+		}
+		c, _ := fakeAPI(t, func(r apiRequest) (int, string) { return 200, `` })
+		c.http.Transport = transport
+		c.baseURL = func(string) string { return srv.URL }
+		_, err = c.run(nil, []string{"api", "user"})
+		return err
+	}
+	if err := get(API{}); err == nil {
+		t.Error("private CA without ca_file: want a certificate error")
+	}
+	if err := get(API{CAFile: ca}); err != nil {
+		t.Errorf("ca_file: %v", err)
+	}
+	if err := get(API{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("insecure_skip_verify: %v", err)
+	}
+	if _, err := apiTransport(API{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("missing ca_file: want an error")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// approvalRun is a workflow run GitHub is holding until a maintainer
+// approves it, as it does for first-time contributors' fork PRs.
+type approvalRun struct {
+	id   string
+	name string
+	fork string // the repo the PR comes from
+}
+
+// pendingApprovals are the head commit's runs awaiting approval.
+type pendingApprovals struct {
+	repo string
+	sha  string
+	runs []approvalRun
+	err  error
+}
+
+type approvalsMsg struct {
+	approvals *pendingApprovals
+}
+
+// fetchPendingApprovals lists the head commit's workflow runs that need a
+// maintainer's approval before they start.
+func fetchPendingApprovals(acct *Account, repo, sha string) *pendingApprovals {
+	a := &pendingApprovals{repo: repo, sha: sha}
+	out, err := ghAPI(acct, repo, "repos/{repo}/actions/runs?status=action_required&per_page=50&head_sha="+sha)
+	if err != nil {
+		a.err = err
+		return a
+	}
+	var resp struct {
+		WorkflowRuns []struct {
+			ID             int64  `json:"id"`
+			Name           string `json:"name"`
+			HeadRepository struct {
+				FullName string `json:"full_name"`
+			} `json:"head_repository"`
+		} `json:"workflow_runs"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		a.err = fmt.Errorf("failed to parse workflow runs: %w", err)
+		return a
+	}
+	for _, r := range resp.WorkflowRuns {
+		a.runs = append(a.runs, approvalRun{id: strconv.FormatInt(r.ID, 10), name: r.Name, fork: r.HeadRepository.FullName})
+	}
+	return a
+}
+
+// approvalsCmd looks for runs awaiting approval once per head commit, then
+// on each refresh while some are, so the banner goes once someone approves
+// them.
+func (m model) approvalsCmd() (model, tea.Cmd) {
+	if m.prData == nil || m.prData.HeadSHA == "" || m.approvalsBusy {
+		return m, nil
+	}
+	key := prKey(m.repo, m.prData.HeadSHA)
+	if m.approvalsAsked == key && len(m.awaitingApproval()) == 0 {
+		return m, nil
+	}
+	repo, sha := m.repo, m.prData.HeadSHA
+	m.approvalsAsked, m.approvalsBusy = key, true
+	acct := m.repoAccount(repo)
+	return m, func() tea.Msg {
+		return approvalsMsg{approvals: fetchPendingApprovals(acct, repo, sha)}
+	}
+}
+
+// awaitingApproval is the head commit's runs awaiting approval, if known.
+func (m model) awaitingApproval() []approvalRun {
+	a := m.approvals
+	if a == nil || m.prData == nil || a.repo != m.repo || a.sha != m.prData.HeadSHA {
+		return nil
+	}
+	return a.runs
+}
+
+// approvalBanner is the status line while runs await approval.
+func (m model) approvalBanner() string {
+	runs := m.awaitingApproval()
+	if len(runs) == 0 {
+		return ""
+	}
+	what := "1 workflow run is"
+	if len(runs) > 1 {
+		what = fmt.Sprintf("%d workflow runs are", len(runs))
+	}
+	return fmt.Sprintf("AWAITING APPROVAL: %s waiting for a maintainer to approve running %s's code (W: approve)", what, approvalSource(runs))
+}
+
+// approvalSource is where the code awaiting approval comes from.
+func approvalSource(runs []approvalRun) string {
+	if len(runs) > 0 && runs[0].fork != "" {
+		return runs[0].fork
+	}
+	return "this PR"
+}
+
+// approveRuns asks for confirmation (W), then approves every run awaiting
+// approval (W again). Approving lets the contributor's code run in the
+// repo's Actions, so it isn't done on a single key press.
+func (m model) approveRuns(confirmed bool) (model, tea.Cmd) {
+	if m.readOnly {
+		m.flash = "Approving workflow runs is off in " + readOnlyReason
+		return m, nil
+	}
+	runs := m.awaitingApproval()
+	if len(runs) == 0 {
+		m.flash = "No workflow runs are awaiting approval"
+		return m, nil
+	}
+	if !confirmed {
+		m.approveArmed = true
+		m.flash = fmt.Sprintf("Approve %d workflow run(s) to run code from %s? Review the PR's changes first. W again to approve", len(runs), approvalSource(runs))
+		return m, nil
+	}
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	return m, func() tea.Msg {
+		for _, r := range runs {
+			if _, err := ghAPI(acct, repo, "repos/{repo}/actions/runs/"+r.id+"/approve", "-X", "POST"); err != nil {
+				return actionMsg{text: "Approving " + r.name, err: err}
+			}
+		}
+		return actionMsg{text: fmt.Sprintf("Approved %d workflow run(s)", len(runs))}
+	}
+}
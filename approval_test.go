@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPendingApprovals(t *testing.T) {
+	var approved []string
+	runs := `{"workflow_runs":[{"id":11,"name":"CI","head_repository":{"full_name":"newbie/widgets"}},
+		{"id":12,"name":"Lint","head_repository":{"full_name":"newbie/widgets"}}]}`
+	fake := fakeExecByArgs(map[string]string{
+		"status=action_required": runs,
+		"/approve":               "",
+	})
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		if joined := strings.Join(args, " "); strings.Contains(joined, "/approve") {
+			approved = append(approved, joined)
+		}
+		return fake(command, args...)
+	}
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newModel("acme/widgets", "7", 5*time.Second)
+	m.width, m.height = 160, 20
+	m.prData = &PRData{HeadSHA: "abc123", Checks: []Check{{Name: "CI", Status: Fail}}}
+	m, cmd := m.approvalsCmd()
+	if cmd == nil {
+		t.Fatal("no lookup for a new head commit")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+
+	out := ansi.Strip(m.View())
+	for _, want := range []string{
+		"PR Checks - acme/widgets #7 AWAITING APPROVAL",
+		"AWAITING APPROVAL: 2 workflow runs are waiting for a maintainer to approve running newbie/widgets's code (W: approve)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+	// Still waiting: asked again on the next refresh
+	if _, cmd := m.approvalsCmd(); cmd == nil {
+		t.Error("not rechecked while runs await approval")
+	}
+
+	// W asks first; any other key disarms it
+	updated, cmd = m.Update(runeKey('W'))
+	m = updated.(model)
+	if cmd != nil || !strings.Contains(m.flash, "W again to approve") {
+		t.Fatalf("first W: %q", m.flash)
+	}
+	updated, _ = m.Update(runeKey('j'))
+	m = updated.(model)
+	if updated, cmd = m.Update(runeKey('W')); cmd != nil {
+		t.Fatal("approved after an intervening key")
+	}
+	m = updated.(model)
+	updated, cmd = m.Update(runeKey('W'))
+	m = updated.(model)
+	if cmd == nil {
+		t.Fatal("second W didn't approve")
+	}
+	msg := cmd().(actionMsg)
+	if msg.err != nil || msg.text != "Approved 2 workflow run(s)" || len(approved) != 2 ||
+		!strings.Contains(approved[0], "-X POST repos/acme/widgets/actions/runs/11/approve") {
+		t.Errorf("approve: %+v, calls %q", msg, approved)
+	}
+
+	// Nothing waiting after a push: no banner, no further lookups
+	m.approvals = &pendingApprovals{repo: "acme/widgets", sha: "abc123"}
+	if m.approvalBanner() != "" {
+		t.Error("banner with nothing awaiting approval")
+	}
+	if _, cmd := m.approvalsCmd(); cmd != nil {
+		t.Error("rechecked with nothing awaiting approval")
+	}
+	if m, _ = m.approveRuns(false); m.flash != "No workflow runs are awaiting approval" {
+		t.Errorf("W with nothing to approve: %q", m.flash)
+	}
+}
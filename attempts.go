@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runJob is one job of one attempt of a GitHub Actions workflow run.
+type runJob struct {
+	ID          string
+	Name        string
+	Attempt     int
+	Status      CheckStatus
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+type attemptsMsg struct {
+	runID string
+	jobs  []runJob
+	err   error
+}
+
+// fetchRunJobs lists the jobs of every attempt of a workflow run.
+func fetchRunJobs(acct *Account, repo, runID string) ([]runJob, error) {
+	out, err := ghAPI(acct, repo, "repos/{repo}/actions/runs/"+runID+"/jobs?filter=all&per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Jobs []struct {
+			ID          int64     `json:"id"`
+			Name        string    `json:"name"`
+			RunAttempt  int       `json:"run_attempt"`
+			Status      string    `json:"status"`
+			Conclusion  string    `json:"conclusion"`
+			StartedAt   time.Time `json:"started_at"`
+			CompletedAt time.Time `json:"completed_at"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run jobs: %w", err)
+	}
+	jobs := make([]runJob, 0, len(resp.Jobs))
+	for _, j := range resp.Jobs {
+		status := j.Conclusion
+		if status == "" {
+			status = j.Status
+		}
+		jobs = append(jobs, runJob{
+			ID: strconv.FormatInt(j.ID, 10), Name: j.Name, Attempt: max(j.RunAttempt, 1),
+			Status: normalizeStatus(status), StartedAt: j.StartedAt, CompletedAt: j.CompletedAt,
+		})
+	}
+	return jobs, nil
+}
+
+// attemptsCmd fetches the jobs of each Actions run whose checks include a
+// job it hasn't seen. A rerun gives every job a new ID, so a run is fetched
+// again only when it is rerun or a new job starts.
+func (m model) attemptsCmd() tea.Cmd {
+	if m.prData == nil {
+		return nil
+	}
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	var cmds []tea.Cmd
+	asked := map[string]bool{}
+	for _, c := range m.prData.Checks {
+		runID, jobID := actionsRunID(c.DetailsURL)
+		if jobID == "" || asked[runID] || m.findRunJob(runID, jobID) != nil {
+			continue
+		}
+		asked[runID] = true
+		cmds = append(cmds, func() tea.Msg {
+			jobs, err := fetchRunJobs(acct, repo, runID)
+			return attemptsMsg{runID: runID, jobs: jobs, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) findRunJob(runID, jobID string) *runJob {
+	for i, j := range m.attempts[runID] {
+		if j.ID == jobID {
+			return &m.attempts[runID][i]
+		}
+	}
+	return nil
+}
+
+// checkAttempts returns the attempt c belongs to and the same job's results
+// on earlier attempts, oldest first. attempt is 0 when it isn't known.
+func (m model) checkAttempts(c Check) (attempt int, earlier []runJob) {
+	runID, jobID := actionsRunID(c.DetailsURL)
+	cur := m.findRunJob(runID, jobID)
+	if cur == nil {
+		return 0, nil
+	}
+	for _, j := range m.attempts[runID] {
+		if j.Name == cur.Name && j.Attempt < cur.Attempt {
+			earlier = append(earlier, j)
+		}
+	}
+	sort.Slice(earlier, func(a, b int) bool { return earlier[a].Attempt < earlier[b].Attempt })
+	return cur.Attempt, earlier
+}
+
+// attemptNote is the table's note for a check on a rerun, e.g. "attempt 2"
+// or "attempt 2, flaky" when it passed after failing on an earlier attempt.
+func (m model) attemptNote(c Check) string {
+	attempt, earlier := m.checkAttempts(c)
+	if attempt < 2 {
+		return ""
+	}
+	note := fmt.Sprintf("attempt %d", attempt)
+	if c.Status == Pass && failedEarlier(earlier) {
+		note += ", flaky"
+	}
+	return note
+}
+
+func failedEarlier(earlier []runJob) bool {
+	for _, j := range earlier {
+		if j.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// renderAttempts lists every rerun check's attempts, with the checks that
+// failed and then passed called out as flaky.
+func (m model) renderAttempts() []string {
+	if m.prData == nil {
+		return nil
+	}
+	var lines []string
+	g := m.glyphs.glyph
+	for _, c := range m.prData.Checks {
+		attempt, earlier := m.checkAttempts(c)
+		if len(earlier) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		title := styleBold.Render(c.Name)
+		if c.Status == Pass && failedEarlier(earlier) {
+			title += "  " + styleRunning.Render(fmt.Sprintf("flaky: failed on attempt %d, passed on attempt %d",
+				earlier[len(earlier)-1].Attempt, attempt))
+		}
+		lines = append(lines, title)
+		for _, j := range earlier {
+			dur := "-"
+			if !j.StartedAt.IsZero() && !j.CompletedAt.IsZero() {
+				dur = formatDuration(int(j.CompletedAt.Sub(j.StartedAt).Seconds()))
+			}
+			lines = append(lines, fmt.Sprintf("  attempt %d  %s  %s", j.Attempt,
+				statusStyle(j.Status).Render(fmt.Sprintf("%s %-9s", g(j.Status), j.Status)), dur))
+		}
+		lines = append(lines, fmt.Sprintf("  attempt %d  %s  %s  (current)", attempt,
+			statusStyle(c.Status).Render(fmt.Sprintf("%s %-9s", g(c.Status), c.Status)), c.Duration))
+	}
+	if len(lines) == 0 {
+		return []string{"No check has been rerun."}
+	}
+	return lines
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// build failed on attempt 1 and passed on attempt 2; test only ran once
+// before the rerun copied it.
+const runJobsResponse = `{"total_count":4,"jobs":[
+  {"id":101,"name":"build","run_attempt":1,"status":"completed","conclusion":"failure","started_at":"2024-05-01T10:00:00Z","completed_at":"2024-05-01T10:02:10Z"},
+  {"id":102,"name":"test","run_attempt":1,"status":"completed","conclusion":"success","started_at":"2024-05-01T10:00:00Z","completed_at":"2024-05-01T10:01:00Z"},
+  {"id":201,"name":"build","run_attempt":2,"status":"completed","conclusion":"success","started_at":"2024-05-01T10:05:00Z","completed_at":"2024-05-01T10:07:00Z"},
+  {"id":202,"name":"test","run_attempt":2,"status":"in_progress","conclusion":null,"started_at":"2024-05-01T10:05:00Z","completed_at":null}
+]}`
+
+func attemptsModel(t *testing.T) model {
+	t.Helper()
+	execCommand = fakeExecByArgs(map[string]string{"actions/runs/7/jobs?filter=all": runJobsResponse})
+	t.Cleanup(func() { execCommand = exec.Command })
+	jobs, err := fetchRunJobs(nil, "o/r", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 100, 30
+	m.prData = &PRData{Title: "t", Checks: []Check{
+		{Name: "build (CI)", JobName: "build", Status: Pass, Duration: "2m00s", DetailsURL: "https://github.com/o/r/actions/runs/7/job/201"},
+		{Name: "test (CI)", JobName: "test", Status: Running, Duration: "-", DetailsURL: "https://github.com/o/r/actions/runs/7/job/202"},
+		{Name: "lint (CI)", JobName: "lint", Status: Pass, Duration: "10s", DetailsURL: "https://github.com/o/r/actions/runs/8/job/301"},
+	}}
+	m.attempts = map[string][]runJob{"7": jobs}
+	return m
+}
+
+func TestFetchRunJobs(t *testing.T) {
+	m := attemptsModel(t)
+	jobs := m.attempts["7"]
+	if len(jobs) != 4 || jobs[0].ID != "101" || jobs[0].Attempt != 1 || jobs[0].Status != Fail ||
+		jobs[3].Status != Running || jobs[2].CompletedAt.Sub(jobs[2].StartedAt) != 2*time.Minute {
+		t.Errorf("jobs = %+v", jobs)
+	}
+}
+
+func TestCheckAttempts(t *testing.T) {
+	m := attemptsModel(t)
+	if attempt, earlier := m.checkAttempts(m.prData.Checks[0]); attempt != 2 || len(earlier) != 1 || earlier[0].ID != "101" {
+		t.Errorf("build: attempt %d, earlier %+v", attempt, earlier)
+	}
+	if got := m.attemptNote(m.prData.Checks[0]); got != "attempt 2, flaky" {
+		t.Errorf("build note = %q", got)
+	}
+	if got := m.attemptNote(m.prData.Checks[1]); got != "attempt 2" {
+		t.Errorf("test note = %q", got)
+	}
+	if got := m.attemptNote(m.prData.Checks[2]); got != "" {
+		t.Errorf("lint (run not fetched) note = %q", got)
+	}
+
+	if out := m.View(); !strings.Contains(out, "build (CI)  (attempt 2, flaky)") {
+		t.Errorf("table should note the attempt:\n%s", out)
+	}
+	// Only run 8 still needs fetching
+	if cmd := m.attemptsCmd(); cmd == nil {
+		t.Error("run 8's jobs weren't fetched")
+	}
+	m.attempts["8"] = []runJob{{ID: "301", Name: "lint", Attempt: 1}}
+	if cmd := m.attemptsCmd(); cmd != nil {
+		t.Error("known jobs were fetched again")
+	}
+}
+
+func TestAttemptsOverlay(t *testing.T) {
+	m := attemptsModel(t)
+	out := strings.Join(m.renderAttempts(), "\n")
+	for _, want := range []string{
+		"build (CI)  flaky: failed on attempt 1, passed on attempt 2",
+		"  attempt 1  ✗ FAIL       2m10s",
+		"  attempt 2  ✓ PASS       2m00s  (current)",
+		"test (CI)\n  attempt 1  ✓ PASS       1m00s\n  attempt 2  ● RUNNING    -  (current)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("attempts missing %q:\n%s", want, out)
+		}
+	}
+
+	m.attempts = nil
+	if got := m.renderAttempts(); len(got) != 1 || got[0] != "No check has been rerun." {
+		t.Errorf("no reruns: %q", got)
+	}
+}
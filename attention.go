@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// attentionBlink is how often the failure banner flips between normal and
+// reverse video.
+const attentionBlink = 600 * time.Millisecond
+
+type attentionTickMsg struct{}
+
+func attentionTickCmd() tea.Cmd {
+	return tea.Tick(attentionBlink, func(time.Time) tea.Msg {
+		return attentionTickMsg{}
+	})
+}
+
+// checkAttention raises the blinking "N FAILED" banner the first time a
+// failure shows up this session, when attention mode is on.
+func (m model) checkAttention() (model, tea.Cmd) {
+	if !m.attention || m.alertSeen || len(m.failingChecks()) == 0 {
+		return m, nil
+	}
+	logger.Debug("attention banner raised", "failed", len(m.failingChecks()))
+	m.alert, m.alertSeen, m.blinkOn = true, true, true
+	return m, attentionTickCmd()
+}
+
+// attentionBanner renders the banner while it is raised, or "".
+func (m model) attentionBanner() string {
+	if !m.alert {
+		return ""
+	}
+	return styleFail.Reverse(m.blinkOn).Render(fmt.Sprintf(" %d FAILED ", len(m.failingChecks()))) + " "
+}
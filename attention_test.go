@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAttention(t *testing.T) {
+	failing := &PRData{Title: "t", Checks: []Check{{Name: "a", Status: Fail}, {Name: "b", Status: Fail}, {Name: "c", Status: Pass}}}
+	passing := &PRData{Title: "t", Checks: []Check{{Name: "c", Status: Pass}}}
+	newAttentionModel := func() model {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width, m.height = 80, 20
+		m.attention = true
+		return m
+	}
+	update := func(m model, msg tea.Msg) (model, tea.Cmd) {
+		updated, cmd := m.Update(msg)
+		return updated.(model), cmd
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m, _ = update(m, prDataMsg{data: failing})
+		if m.alert {
+			t.Error("banner raised with attention mode off")
+		}
+	})
+
+	t.Run("first failure raises a blinking banner", func(t *testing.T) {
+		m, _ := update(newAttentionModel(), prDataMsg{data: passing})
+		if m.alert {
+			t.Fatal("banner raised without failures")
+		}
+		m, cmd := update(m, prDataMsg{data: failing})
+		if !m.alert || cmd == nil {
+			t.Fatalf("alert = %v, cmd = %v", m.alert, cmd)
+		}
+		if !strings.Contains(m.View(), " 2 FAILED ") {
+			t.Error("view missing banner")
+		}
+		blink := m.blinkOn
+		m, cmd = update(m, attentionTickMsg{})
+		if m.blinkOn == blink || cmd == nil {
+			t.Errorf("blinkOn = %v, cmd = %v; want toggled and rescheduled", m.blinkOn, cmd)
+		}
+	})
+
+	t.Run("any key clears it and it does not return", func(t *testing.T) {
+		m, _ := update(newAttentionModel(), prDataMsg{data: failing})
+		m, _ = update(m, tea.KeyMsg{Type: tea.KeyDown})
+		if m.alert || m.selected != 0 {
+			t.Errorf("alert = %v, selected = %d; want cleared and key swallowed", m.alert, m.selected)
+		}
+		if strings.Contains(m.View(), "FAILED") {
+			t.Error("banner still shown")
+		}
+		if _, cmd := update(m, attentionTickMsg{}); cmd != nil {
+			t.Error("blink continued after clearing")
+		}
+		m, _ = update(m, prDataMsg{data: failing})
+		if m.alert {
+			t.Error("banner raised a second time")
+		}
+	})
+
+	t.Run("q still quits", func(t *testing.T) {
+		m, _ := update(newAttentionModel(), prDataMsg{data: failing})
+		_, cmd := update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+		if cmd == nil {
+			t.Fatal("expected quit")
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Error("q did not quit")
+		}
+	})
+
+	t.Run("compact header shows the banner", func(t *testing.T) {
+		m := newAttentionModel()
+		m.density = densityCompact
+		m, _ = update(m, prDataMsg{data: failing})
+		if first := strings.Split(m.View(), "\n")[0]; !strings.Contains(first, " 2 FAILED ") {
+			t.Errorf("header = %q", first)
+		}
+	})
+}
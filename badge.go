@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// badgeState is the latest poll of the badge's PR, shared between the
+// polling loop and the HTTP handlers.
+type badgeState struct {
+	mu       sync.Mutex
+	repo     string
+	prNumber string
+	data     *PRData
+	status   string
+	counts   map[CheckStatus]int // of the checks status was rolled up from, less acknowledged failures
+	failing  []string            // names of the failures counted
+	err      error
+	updated  time.Time
+}
+
+// badgeJSON is the /status.json response.
+type badgeJSON struct {
+	Repo      string         `json:"repo"`
+	Number    int            `json:"number"`
+	Title     string         `json:"title,omitempty"`
+	URL       string         `json:"url,omitempty"`
+	Status    string         `json:"status"`
+	Counts    map[string]int `json:"counts"`
+	Failing   []string       `json:"failing,omitempty"`
+	UpdatedAt time.Time      `json:"updatedAt,omitzero"`
+	CachedAt  time.Time      `json:"cachedAt,omitzero"`
+	Error     string         `json:"error,omitempty"`
+}
+
+func runBadge(s *session, args []string) (int, error) {
+	repo, prNumber, err := s.prArgs(args)
+	if err != nil {
+		return exitFailed, err
+	}
+	ln, err := net.Listen("tcp", s.opts.listen)
+	if err != nil {
+		return exitFailed, err
+	}
+	fmt.Fprintf(s.stderr, "Serving %s #%s at http://%s/badge.svg and /status.json\n", repo, prNumber, ln.Addr())
+
+	b := &badgeState{repo: repo, prNumber: prNumber}
+	go func() {
+		for {
+			b.refresh(s, args)
+			respCache.flush()
+			time.Sleep(s.interval)
+		}
+	}()
+	return exitFailed, http.Serve(ln, b.handler())
+}
+
+// refresh polls the PR once. A failed poll keeps the last good data.
+func (b *badgeState) refresh(s *session, args []string) {
+	_, _, data, checks, acked, err := s.fetchCounted(args)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+	if err != nil {
+		return
+	}
+	b.data, b.status, b.updated = data, rollupStatus(checks, acked), time.Now().UTC()
+	b.counts, b.failing = map[CheckStatus]int{}, nil
+	for _, c := range checks {
+		if acked(c) {
+			continue
+		}
+		b.counts[c.Status]++
+		if c.Status == Fail {
+			b.failing = append(b.failing, c.Name)
+		}
+	}
+}
+
+func (b *badgeState) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /badge.svg", func(w http.ResponseWriter, r *http.Request) {
+		label, color := b.badge()
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-cache")
+		fmt.Fprint(w, badgeSVG("checks", label, color))
+	})
+	mux.HandleFunc("GET /status.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		_ = json.NewEncoder(w).Encode(b.summary())
+	})
+	return mux
+}
+
+// badge is the badge's message and color for the latest poll.
+func (b *badgeState) badge() (label, color string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.data == nil {
+		if b.err != nil {
+			return "error", "#9f9f9f"
+		}
+		return "pending", "#9f9f9f"
+	}
+	switch b.status {
+	case "":
+		return "no checks", "#9f9f9f"
+	case rollupFailure:
+		return fmt.Sprintf("%d failed", b.counts[Fail]), "#e05d44"
+	case rollupPending:
+		return fmt.Sprintf("%d running", b.counts[Running]), "#dfb317"
+	}
+	return "passing", "#4c1"
+}
+
+func (b *badgeState) summary() badgeJSON {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	number, _ := strconv.Atoi(b.prNumber)
+	out := badgeJSON{Repo: b.repo, Number: number, Status: b.status, Counts: map[string]int{}, UpdatedAt: b.updated}
+	if b.err != nil {
+		out.Error = b.err.Error()
+	}
+	if b.data == nil {
+		return out
+	}
+	out.Title, out.URL, out.CachedAt = b.data.Title, b.data.URL, b.data.CachedAt
+	for status, n := range b.counts {
+		out.Counts[strings.ToLower(status.String())] = n
+	}
+	out.Failing = b.failing
+	return out
+}
+
+// badgeSVG draws a flat two-part badge in the style of shields.io. Text
+// widths are estimated, which is close enough for short labels.
+func badgeSVG(label, message, color string) string {
+	lw, mw := 10+7*len(label), 10+7*len(message)
+	label, message = html.EscapeString(label), html.EscapeString(message)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>`+
+		`<g fill="#fff" font-family="Verdana,Geneva,sans-serif" font-size="11" text-anchor="middle">`+
+		`<text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`,
+		lw+mw, lw, mw, label, message, color, lw/2, lw+mw/2)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestBadgeRefreshAndServe(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{"pr view 7": cliFailingPR})
+	t.Cleanup(func() { execCommand = exec.Command })
+	s, _, _ := testSession(t)
+	b := &badgeState{repo: "o/r", prNumber: "7"}
+	b.refresh(s, []string{"o/r#7"})
+	srv := httptest.NewServer(b.handler())
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/badge.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(string(svg), `aria-label="checks: 1 failed"`) || !strings.Contains(string(svg), "#e05d44") {
+		t.Errorf("badge:\n%s", string(svg))
+	}
+
+	resp, err = srv.Client().Get(srv.URL + "/status.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got badgeJSON
+	err = json.NewDecoder(resp.Body).Decode(&got)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Repo != "o/r" || got.Number != 7 || got.Title != "Fix it" || got.Status != rollupFailure ||
+		got.Counts["fail"] != 1 || got.Counts["pass"] != 1 || len(got.Failing) != 1 || got.Failing[0] != "build" {
+		t.Errorf("status.json = %+v", got)
+	}
+
+	// While gh fails, the last good response comes from the cache
+	execCommand = fakeExecByArgs(map[string]string{})
+	b.refresh(s, []string{"o/r#7"})
+	if sum := b.summary(); sum.CachedAt.IsZero() || sum.Status != rollupFailure {
+		t.Errorf("after a failed poll: %+v", sum)
+	}
+}
+
+func TestBadgeLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *badgeState
+		label string
+		color string
+	}{
+		{"not polled yet", &badgeState{}, "pending", "#9f9f9f"},
+		{"first poll failed", &badgeState{err: errors.New("boom")}, "error", "#9f9f9f"},
+		{"no checks", &badgeState{data: &PRData{}}, "no checks", "#9f9f9f"},
+		{"running", &badgeState{data: &PRData{}, counts: map[CheckStatus]int{Running: 1, Pass: 1}, status: rollupPending}, "1 running", "#dfb317"},
+		{"passed", &badgeState{data: &PRData{Checks: []Check{{Status: Pass}}}, status: rollupSuccess}, "passing", "#4c1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if label, color := tt.state.badge(); label != tt.label || color != tt.color {
+				t.Errorf("badge = %q, %q; want %q, %q", label, color, tt.label, tt.color)
+			}
+		})
+	}
+}
+
+func TestBadgeCountsWhatDecides(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{"pr view 7": `{"title":"Fix it","statusCheckRollup":[
+		{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"FAILURE"},
+		{"__typename":"CheckRun","name":"lint","status":"COMPLETED","conclusion":"FAILURE"},
+		{"__typename":"CheckRun","name":"nightly","status":"COMPLETED","conclusion":"FAILURE"}]}`})
+	t.Cleanup(func() { execCommand = exec.Command })
+	s, _, _ := testSession(t)
+	store, _ := openStateStore(defaultStatePath())
+	if _, err := store.toggleAck("o/r", "7", "lint"); err != nil {
+		t.Fatal(err)
+	}
+	grading = gradePolicy{ignore: []string{"nightly"}}
+	t.Cleanup(func() { grading = gradePolicy{} })
+
+	b := &badgeState{repo: "o/r", prNumber: "7"}
+	b.refresh(s, []string{"o/r#7"})
+	if label, _ := b.badge(); label != "1 failed" {
+		t.Errorf("badge = %q, want 1 failed (lint is acknowledged, nightly not graded)", label)
+	}
+	if sum := b.summary(); sum.Counts["fail"] != 1 || len(sum.Failing) != 1 || sum.Failing[0] != "build" {
+		t.Errorf("status.json counts %v, failing %q; want only build", sum.Counts, sum.Failing)
+	}
+}
+
+func TestBadgeListensLocally(t *testing.T) {
+	if got := defaultOptions().listen; got != "127.0.0.1:8080" {
+		t.Errorf("default --listen = %q, want only this machine", got)
+	}
+}
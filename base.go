@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// baseStatusTTL is how long the base branch's status is reused before a
+// refresh fetches it again. It changes far less often than the PR's checks.
+const baseStatusTTL = time.Minute
+
+// baseStatus is the CI state of the latest commit on the PR's base branch,
+// shown so failures the PR inherited can be told apart from its own.
+type baseStatus struct {
+	repo      string
+	branch    string
+	state     string // statusCheckRollup state; "" when the commit has no checks
+	committed time.Time
+	err       error
+}
+
+type baseStatusMsg struct {
+	status *baseStatus
+}
+
+const baseStatusQuery = `query($owner: String!, $name: String!, $ref: String!) {
+  repository(owner: $owner, name: $name) {
+    ref(qualifiedName: $ref) {
+      target { ... on Commit { committedDate statusCheckRollup { state } } }
+    }
+  }
+}`
+
+// fetchBaseStatus looks up the rollup state of branch's head commit.
+func fetchBaseStatus(acct *Account, repo, branch string) *baseStatus {
+	s := &baseStatus{repo: repo, branch: branch}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+baseStatusQuery,
+		"-f", "owner="+owner, "-f", "name="+name, "-f", "ref=refs/heads/"+branch)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	var resp struct {
+		Data struct {
+			Repository *struct {
+				Ref *struct {
+					Target struct {
+						CommittedDate     time.Time `json:"committedDate"`
+						StatusCheckRollup *struct {
+							State string `json:"state"`
+						} `json:"statusCheckRollup"`
+					} `json:"target"`
+				} `json:"ref"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		s.err = fmt.Errorf("failed to parse base branch status: %w", err)
+		return s
+	}
+	if resp.Data.Repository == nil || resp.Data.Repository.Ref == nil {
+		s.err = fmt.Errorf("branch %s not found", branch)
+		return s
+	}
+	target := resp.Data.Repository.Ref.Target
+	s.committed = target.CommittedDate
+	if target.StatusCheckRollup != nil {
+		s.state = target.StatusCheckRollup.State
+	}
+	return s
+}
+
+func baseKey(repo, branch string) string {
+	return repo + "@" + branch
+}
+
+// baseStatusCmd fetches the base branch's status unless it was last asked
+// for less than baseStatusTTL ago, so each refresh cycle costs at most one
+// extra request and usually none.
+func (m model) baseStatusCmd(now time.Time) (model, tea.Cmd) {
+	if m.prData == nil || m.prData.BaseRefName == "" {
+		return m, nil
+	}
+	repo, branch := m.repo, m.prData.BaseRefName
+	key := baseKey(repo, branch)
+	if key == m.baseKey && now.Sub(m.baseAsked) < baseStatusTTL {
+		return m, nil
+	}
+	if key != m.baseKey {
+		m.base = nil
+	}
+	m.baseKey, m.baseAsked = key, now
+	acct := m.repoAccount(repo)
+	return m, func() tea.Msg {
+		return baseStatusMsg{status: fetchBaseStatus(acct, repo, branch)}
+	}
+}
+
+// baseBanner renders the base branch's status as "main: ✓ green 12m ago",
+// or "" while it is unknown.
+func (m model) baseBanner() string {
+	s := m.base
+	if s == nil || s.err != nil || m.prData == nil || baseKey(s.repo, s.branch) != baseKey(m.repo, m.prData.BaseRefName) {
+		return ""
+	}
+	g := m.glyphs.glyph
+	ago := ""
+	if !s.committed.IsZero() {
+		ago = " " + relativeTime(s.committed.Format(time.RFC3339))
+	}
+	switch s.state {
+	case "SUCCESS":
+		return s.branch + ": " + stylePass.Render(g(Pass)+" green") + styleDim.Render(ago)
+	case "FAILURE", "ERROR":
+		return s.branch + ": " + styleFail.Render(g(Fail)+" broken") + styleDim.Render(ago)
+	case "PENDING", "EXPECTED":
+		return s.branch + ": " + styleRunning.Render(g(Running)+" running") + styleDim.Render(ago)
+	}
+	return styleDim.Render(s.branch + ": no checks" + ago)
+}
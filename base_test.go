@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// fetchBaseStatus
+// ---------------------------------------------------------------------------
+
+func TestFetchBaseStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		out       string
+		wantState string
+		wantErr   bool
+	}{
+		{"green", `{"data":{"repository":{"ref":{"target":{"committedDate":"2024-01-02T03:04:05Z","statusCheckRollup":{"state":"SUCCESS"}}}}}}`, "SUCCESS", false},
+		{"no checks", `{"data":{"repository":{"ref":{"target":{"committedDate":"2024-01-02T03:04:05Z","statusCheckRollup":null}}}}}`, "", false},
+		{"missing branch", `{"data":{"repository":{"ref":null}}}`, "", true},
+		{"bad json", `nope`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCommand = fakeExecByArgs(map[string]string{"ref=refs/heads/main": tt.out})
+			t.Cleanup(func() { execCommand = exec.Command })
+
+			s := fetchBaseStatus(nil, "o/r", "main")
+			if (s.err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", s.err, tt.wantErr)
+			}
+			if s.state != tt.wantState {
+				t.Errorf("state = %q, want %q", s.state, tt.wantState)
+			}
+			if !tt.wantErr && s.committed.IsZero() {
+				t.Error("committed date not parsed")
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// baseStatusCmd caching
+// ---------------------------------------------------------------------------
+
+func TestBaseStatusCmd(t *testing.T) {
+	now := time.Now()
+	m := newModel("o/r", "7", 5*time.Second)
+	m.prData = &PRData{BaseRefName: "main"}
+
+	m, cmd := m.baseStatusCmd(now)
+	if cmd == nil {
+		t.Fatal("first refresh didn't fetch")
+	}
+	if _, cmd = m.baseStatusCmd(now.Add(5 * time.Second)); cmd != nil {
+		t.Error("refresh within the TTL fetched again")
+	}
+	if _, cmd = m.baseStatusCmd(now.Add(baseStatusTTL)); cmd == nil {
+		t.Error("refresh after the TTL didn't fetch")
+	}
+
+	m.base = &baseStatus{repo: "o/r", branch: "main", state: "SUCCESS"}
+	m.prData = &PRData{BaseRefName: "release"}
+	m, cmd = m.baseStatusCmd(now.Add(time.Second))
+	if cmd == nil || m.base != nil {
+		t.Errorf("new base branch: cmd = %v, base = %+v", cmd, m.base)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// baseBanner
+// ---------------------------------------------------------------------------
+
+func TestBaseBanner(t *testing.T) {
+	committed := time.Now().Add(-12 * time.Minute)
+	tests := []struct {
+		state string
+		want  string
+	}{
+		{"SUCCESS", "main: ✓ green 12m ago"},
+		{"FAILURE", "main: ✗ broken 12m ago"},
+		{"ERROR", "main: ✗ broken 12m ago"},
+		{"PENDING", "main: ● running 12m ago"},
+		{"", "main: no checks 12m ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			m := newModel("o/r", "7", 5*time.Second)
+			m.prData = &PRData{BaseRefName: "main"}
+			m.base = &baseStatus{repo: "o/r", branch: "main", state: tt.state, committed: committed}
+			if got := m.baseBanner(); got != tt.want {
+				t.Errorf("baseBanner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("hidden on error or for another branch", func(t *testing.T) {
+		m := newModel("o/r", "7", 5*time.Second)
+		m.prData = &PRData{BaseRefName: "main"}
+		m.base = &baseStatus{repo: "o/r", branch: "main", err: errors.New("boom")}
+		if got := m.baseBanner(); got != "" {
+			t.Errorf("error banner = %q", got)
+		}
+		m.base = &baseStatus{repo: "o/r", branch: "release", state: "SUCCESS"}
+		if got := m.baseBanner(); got != "" {
+			t.Errorf("other branch banner = %q", got)
+		}
+	})
+
+	t.Run("status line", func(t *testing.T) {
+		m := newModel("o/r", "7", 5*time.Second)
+		m.width, m.height = 100, 20
+		m.prData = &PRData{Title: "t", BaseRefName: "main"}
+		m.base = &baseStatus{repo: "o/r", branch: "main", state: "FAILURE", committed: committed}
+		lines := strings.Split(m.View(), "\n")
+		if lines[3] != "main: ✗ broken 12m ago" {
+			t.Errorf("status line = %q", lines[3])
+		}
+		m.flash = "copied"
+		if lines = strings.Split(m.View(), "\n"); lines[3] != "copied" {
+			t.Errorf("flash didn't take the status line: %q", lines[3])
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Dependency update bots, as `gh pr list` reports their PRs' authors.
+const (
+	botDependabot = "dependabot"
+	botRenovate   = "renovate"
+)
+
+// botLogins maps the author logins of dependency update PRs to their bot:
+// gh reports apps as app/NAME, the REST API as NAME[bot].
+var botLogins = map[string]string{
+	"app/dependabot":  botDependabot,
+	"dependabot[bot]": botDependabot,
+	"dependabot":      botDependabot,
+	"app/renovate":    botRenovate,
+	"renovate[bot]":   botRenovate,
+	"renovate":        botRenovate,
+}
+
+// defaultMergeMethod is how `prtop bots` merges, and the dashboard turns
+// on auto-merge, unless --merge-method says otherwise.
+const defaultMergeMethod = "squash"
+
+// parseMergeMethod checks a --merge-method: merge, squash or rebase.
+func parseMergeMethod(method string) (string, error) {
+	switch method {
+	case "merge", "squash", "rebase":
+		return method, nil
+	}
+	return "", fmt.Errorf("unknown merge method %q (want merge, squash or rebase)", method)
+}
+
+// fetchBotPRs lists repo's open Dependabot and Renovate PRs, most recently
+// updated first.
+func fetchBotPRs(acct *Account, repo string) ([]PRSummary, error) {
+	out, err := runGh(acct, "pr", "list",
+		"--repo", repo,
+		"--state=open",
+		"--limit=100",
+		"--json", "number,title,url,updatedAt,createdAt,isDraft,author",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []struct {
+		Number    int    `json:"number"`
+		Title     string `json:"title"`
+		URL       string `json:"url"`
+		UpdatedAt string `json:"updatedAt"`
+		CreatedAt string `json:"createdAt"`
+		IsDraft   bool   `json:"isDraft"`
+		Author    struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+
+	var prs []PRSummary
+	for _, r := range raw {
+		if botLogins[r.Author.Login] == "" {
+			continue
+		}
+		prs = append(prs, PRSummary{
+			Repo:      repo,
+			Number:    r.Number,
+			Title:     r.Title,
+			URL:       r.URL,
+			UpdatedAt: r.UpdatedAt,
+			CreatedAt: r.CreatedAt,
+			Draft:     r.IsDraft,
+			Author:    r.Author.Login,
+		})
+	}
+	sortPRs(prs, sortUpdated, false)
+	return prs, nil
+}
+
+func runBots(s *session, args []string) (int, error) {
+	repo, err := s.repoArg("bots", args)
+	if err != nil {
+		return exitFailed, err
+	}
+	if repo == "" {
+		return exitFailed, errors.New("expected a repo: prtop bots owner/repo")
+	}
+	m := newDashboardModel(repo, s.interval)
+	m.bots = true
+	return runTUI(s, m)
+}
+
+// renovateRebaseBox is the checkbox in a Renovate PR's description that
+// asks Renovate to rebase it.
+const renovateRebaseBox = "- [ ] <!-- rebase-check -->"
+
+// rebaseBotPR asks the bot that opened pr to rebase it: Dependabot with a
+// comment, Renovate by ticking the checkbox in the description. Rebasing
+// the branch directly would make either bot stop updating it.
+func rebaseBotPR(acct *Account, pr PRSummary) error {
+	number := strconv.Itoa(pr.Number)
+	switch botLogins[pr.Author] {
+	case botDependabot:
+		_, err := runGh(acct, "pr", "comment", number, "--repo", pr.Repo, "--body", "@dependabot rebase")
+		return err
+	case botRenovate:
+		out, err := runGh(acct, "pr", "view", number, "--repo", pr.Repo, "--json", "body")
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Body string `json:"body"`
+		}
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return fmt.Errorf("failed to parse PR description: %w", err)
+		}
+		if !strings.Contains(resp.Body, renovateRebaseBox) {
+			return errors.New("no unticked rebase checkbox in the description; Renovate may already be on it")
+		}
+		body := strings.Replace(resp.Body, renovateRebaseBox, "- [x] <!-- rebase-check -->", 1)
+		_, err = runGh(acct, "pr", "edit", number, "--repo", pr.Repo, "--body", body)
+		return err
+	}
+	return fmt.Errorf("%s isn't a dependency update bot", pr.Author)
+}
+
+// greenToMerge reports whether a PR can be merged in bulk: not a draft,
+// free of conflicts, and its checks (acknowledged failures and ignored
+// checks aside) passed and settled.
+func (m model) greenToMerge(pr PRSummary) bool {
+	row := m.dashRows[summaryKey(pr)]
+	if pr.Draft || row.data == nil || row.data.State == "MERGED" || row.data.State == "CLOSED" || row.data.Mergeable == "CONFLICTING" {
+		return false
+	}
+	status, _ := m.rollupFor(pr.Repo, strconv.Itoa(pr.Number), row.data)
+	return status == rollupSuccess && row.settle.settled(timeNow(), m.settleWindow)
+}
+
+// greenBotPRs are the PRs `M` merges.
+func (m model) greenBotPRs() []PRSummary {
+	var green []PRSummary
+	for _, pr := range m.prs {
+		if m.greenToMerge(pr) {
+			green = append(green, pr)
+		}
+	}
+	return green
+}
+
+// rebaseSelectedBot asks the bot to rebase the PR under the cursor (b).
+func (m model) rebaseSelectedBot() (model, tea.Cmd) {
+	if m.readOnly {
+		m.flash = "Rebasing is off in " + readOnlyReason
+		return m, nil
+	}
+	if m.selected < 0 || m.selected >= len(m.prs) {
+		return m, nil
+	}
+	pr := m.prs[m.selected]
+	acct := m.repoAccount(pr.Repo)
+	return m, func() tea.Msg {
+		err := rebaseBotPR(acct, pr)
+		return actionMsg{text: fmt.Sprintf("Asked %s to rebase #%d", botLogins[pr.Author], pr.Number), err: err}
+	}
+}
+
+// mergeGreenBots merges every green dependency update (M), after asking
+// for a second M to confirm. A failed merge doesn't stop the others.
+func (m model) mergeGreenBots(confirmed bool) (model, tea.Cmd) {
+	if m.readOnly {
+		m.flash = "Merging is off in " + readOnlyReason
+		return m, nil
+	}
+	green := m.greenBotPRs()
+	if len(green) == 0 {
+		m.flash = "No dependency updates are green yet"
+		return m, nil
+	}
+	if !confirmed {
+		m.mergeArmed = true
+		m.flash = fmt.Sprintf("%s-merge %d green dependency update(s)? M again to merge", strings.ToUpper(m.mergeMethod[:1])+m.mergeMethod[1:], len(green))
+		return m, nil
+	}
+	method := "--" + m.mergeMethod
+	accts := make([]*Account, len(green))
+	for i, pr := range green {
+		accts[i] = m.repoAccount(pr.Repo)
+	}
+	return m, func() tea.Msg {
+		var errs []error
+		for i, pr := range green {
+			if _, err := runGh(accts[i], "pr", "merge", strconv.Itoa(pr.Number), "--repo", pr.Repo, method); err != nil {
+				errs = append(errs, fmt.Errorf("#%d: %w", pr.Number, err))
+			}
+		}
+		text := fmt.Sprintf("Merged %d dependency update(s)", len(green)-len(errs))
+		if len(errs) > 0 {
+			text = fmt.Sprintf("Merged %d of %d dependency updates", len(green)-len(errs), len(green))
+		}
+		return actionMsg{text: text, err: errors.Join(errs...)}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchBotPRs(t *testing.T) {
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		return []byte(`[
+			{"number":1,"title":"Fix login","updatedAt":"2024-03-01T00:00:00Z","author":{"login":"alice"}},
+			{"number":2,"title":"Bump lodash from 4.17.20 to 4.17.21","updatedAt":"2024-01-01T00:00:00Z","author":{"login":"app/dependabot"}},
+			{"number":3,"title":"Update dependency go to v1.22","updatedAt":"2024-02-01T00:00:00Z","author":{"login":"app/renovate"}}
+		]`), nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	prs, err := fetchBotPRs(nil, "o/r")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 2 || prs[0].Number != 3 || prs[1].Number != 2 || prs[1].Author != "app/dependabot" {
+		t.Errorf("prs = %+v, want renovate #3 then dependabot #2", prs)
+	}
+}
+
+func TestRebaseBotPR(t *testing.T) {
+	var calls []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[1] == "view" {
+			return []byte(`{"body":"Update go.\n\n- [ ] <!-- rebase-check -->If you want to rebase/retry this PR, check this box"}`), nil
+		}
+		return nil, nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	if err := rebaseBotPR(nil, PRSummary{Repo: "o/r", Number: 2, Author: "app/dependabot"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rebaseBotPR(nil, PRSummary{Repo: "o/r", Number: 3, Author: "renovate[bot]"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"pr comment 2 --repo o/r --body @dependabot rebase",
+		"pr view 3 --repo o/r --json body",
+		"pr edit 3 --repo o/r --body Update go.\n\n- [x] <!-- rebase-check -->If you want to rebase/retry this PR, check this box",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("gh calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+	if err := rebaseBotPR(nil, PRSummary{Repo: "o/r", Number: 1, Author: "alice"}); err == nil {
+		t.Error("rebasing a person's PR should fail")
+	}
+}
+
+func botsTestModel() model {
+	m := newDashboardModel("o/r", 5*time.Second)
+	m.store = &stateStore{}
+	m.bots = true
+	m.mergeMethod = "squash"
+	m.width, m.height = 120, 12
+	m.loading = false
+	m.prs = []PRSummary{
+		{Repo: "o/r", Number: 2, Author: "app/dependabot"},
+		{Repo: "o/r", Number: 3, Author: "app/renovate"},
+		{Repo: "o/r", Number: 4, Author: "app/renovate"},
+		{Repo: "o/r", Number: 5, Author: "app/renovate", Draft: true},
+	}
+	green := []Check{{Name: "build", Status: Pass}}
+	m.dashRows[prKey("o/r", "2")] = dashRow{data: &PRData{State: "OPEN", Checks: green}}
+	m.dashRows[prKey("o/r", "3")] = dashRow{data: &PRData{State: "OPEN", Checks: []Check{{Name: "build", Status: Fail}}}}
+	m.dashRows[prKey("o/r", "4")] = dashRow{data: &PRData{State: "OPEN", Mergeable: "CONFLICTING", Checks: green}}
+	m.dashRows[prKey("o/r", "5")] = dashRow{data: &PRData{State: "OPEN", Checks: green}}
+	return m
+}
+
+func TestMergeGreenBots(t *testing.T) {
+	var calls []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		return nil, nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	m := botsTestModel()
+	if !strings.Contains(m.View(), "M: merge green (1)") {
+		t.Errorf("footer doesn't count the green PRs:\n%s", m.View())
+	}
+	m, _ = press(t, m, runeKey('M'))
+	if !strings.Contains(m.flash, "Squash-merge 1 green dependency update(s)? M again") || len(calls) != 0 {
+		t.Fatalf("first M should ask first: flash %q, calls %v", m.flash, calls)
+	}
+	m, cmd := press(t, m, runeKey('M'))
+	msg := cmd().(actionMsg)
+	if msg.err != nil || msg.text != "Merged 1 dependency update(s)" {
+		t.Errorf("merge = %+v", msg)
+	}
+	if len(calls) != 1 || calls[0] != "pr merge 2 --repo o/r --squash" {
+		t.Errorf("gh calls = %v", calls)
+	}
+
+	m.readOnly = true
+	m, _ = m.mergeGreenBots(true)
+	if !strings.Contains(m.flash, readOnlyReason) || len(calls) != 1 {
+		t.Errorf("read-only merge: flash %q, calls %v", m.flash, calls)
+	}
+}
+
+func TestGreenToMergeGrades(t *testing.T) {
+	m := botsTestModel()
+	pr := m.prs[1] // failing build
+	if m.greenToMerge(pr) {
+		t.Fatal("a failing build shouldn't be green")
+	}
+	grading, _ = parseGrade(Grade{Ignore: []string{"build"}})
+	t.Cleanup(func() { grading = gradePolicy{} })
+	m.dashRows[summaryKey(pr)].data.Checks = append(m.dashRows[summaryKey(pr)].data.Checks, Check{Name: "lint", Status: Pass})
+	if !m.greenToMerge(pr) {
+		t.Error("a failure [grade] ignores should still merge")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleMark marks the dashboard row under the cursor for a bulk action
+// (space), or unmarks it.
+func (m model) toggleMark() model {
+	if m.selected < 0 || m.selected >= len(m.prs) {
+		return m
+	}
+	key := summaryKey(m.prs[m.selected])
+	if m.marked == nil {
+		m.marked = map[string]bool{}
+	}
+	if m.marked[key] {
+		delete(m.marked, key)
+	} else {
+		m.marked[key] = true
+	}
+	return m.moveSelection(1)
+}
+
+// markedPRs are the dashboard PRs marked for a bulk action, in list order.
+// PRs that have left the list since they were marked are dropped.
+func (m model) markedPRs() []PRSummary {
+	var prs []PRSummary
+	for _, pr := range m.prs {
+		if m.marked[summaryKey(pr)] {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// rowMarker is the two columns before a dashboard row: the cursor and the
+// bulk action mark.
+func (m model) rowMarker(idx int, pr PRSummary) string {
+	cursor, mark := " ", " "
+	if idx == m.selected {
+		cursor = styleSelected.Render("▸")
+	}
+	if m.marked[summaryKey(pr)] {
+		mark = styleSelected.Render("*")
+	}
+	return cursor + mark
+}
+
+// bulkFooter is the dashboard footer while PRs are marked.
+func (m model) bulkFooter() string {
+	n := len(m.markedPRs())
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d marked | space: mark/unmark | F: rerun failed | E: auto-merge green | r: refresh marked | q: quit", n)
+}
+
+// refreshMarked polls the marked PRs now (r), leaving the rest on their
+// schedule.
+func (m model) refreshMarked() (model, tea.Cmd) {
+	now := time.Now()
+	for _, pr := range m.markedPRs() {
+		m.sched.poke(summaryKey(pr), now)
+	}
+	return m, m.pollDueCmd(now)
+}
+
+// rerunMarked reruns the failed jobs of every Actions run with a failing
+// check on the marked PRs (F). Ignored checks are left alone.
+func (m model) rerunMarked() (model, tea.Cmd) {
+	if m.readOnly {
+		m.flash = "Reruns are off in " + readOnlyReason
+		return m, nil
+	}
+	type rerun struct {
+		acct  *Account
+		repo  string
+		runID string
+	}
+	var reruns []rerun
+	prs := 0
+	for _, pr := range m.markedPRs() {
+		row := m.dashRows[summaryKey(pr)]
+		if row.data == nil {
+			continue
+		}
+		ignored := m.store.ignored(pr.Repo)
+		seen := map[string]bool{}
+		for _, c := range row.data.Checks {
+			runID, _ := actionsRunID(c.DetailsURL)
+			if c.Status != Fail || ignored[c.Name] || runID == "" || seen[runID] {
+				continue
+			}
+			seen[runID] = true
+			reruns = append(reruns, rerun{m.repoAccount(pr.Repo), pr.Repo, runID})
+		}
+		if len(seen) > 0 {
+			prs++
+		}
+	}
+	if len(reruns) == 0 {
+		m.flash = "No failed Actions runs on the marked PRs"
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		var errs []error
+		for _, r := range reruns {
+			if err := rerunWorkflow(r.acct, r.repo, r.runID, "", true); err != nil {
+				errs = append(errs, fmt.Errorf("%s run %s: %w", r.repo, r.runID, err))
+			}
+		}
+		return actionMsg{text: fmt.Sprintf("Requested reruns of failed jobs in %d run(s) on %d PR(s)", len(reruns)-len(errs), prs), err: errors.Join(errs...)}
+	}
+}
+
+// autoMergeMarked turns on auto-merge for the marked PRs that are green
+// (E), after asking for a second E to confirm.
+func (m model) autoMergeMarked(confirmed bool) (model, tea.Cmd) {
+	if m.readOnly {
+		m.flash = "Auto-merge is off in " + readOnlyReason
+		return m, nil
+	}
+	var green []PRSummary
+	for _, pr := range m.markedPRs() {
+		if m.greenToMerge(pr) {
+			green = append(green, pr)
+		}
+	}
+	if len(green) == 0 {
+		m.flash = "None of the marked PRs are green"
+		return m, nil
+	}
+	if !confirmed {
+		m.autoMergeArmed = true
+		m.flash = fmt.Sprintf("Enable auto-merge (%s) on %d green PR(s)? E again to enable", m.mergeMethod, len(green))
+		return m, nil
+	}
+	method := "--" + m.mergeMethod
+	accts := make([]*Account, len(green))
+	for i, pr := range green {
+		accts[i] = m.repoAccount(pr.Repo)
+	}
+	return m, func() tea.Msg {
+		var errs []error
+		for i, pr := range green {
+			if _, err := runGh(accts[i], "pr", "merge", strconv.Itoa(pr.Number), "--repo", pr.Repo, "--auto", method); err != nil {
+				errs = append(errs, fmt.Errorf("%s#%d: %w", pr.Repo, pr.Number, err))
+			}
+		}
+		return actionMsg{text: fmt.Sprintf("Enabled auto-merge on %d PR(s)", len(green)-len(errs)), err: errors.Join(errs...)}
+	}
+}
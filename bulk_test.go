@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var keySpace = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+func bulkTestModel() model {
+	m := newDashboardModel("", 5*time.Second)
+	m.store = &stateStore{}
+	m.mergeMethod = "squash"
+	m.width, m.height = 140, 12
+	m.loading = false
+	m.prs = []PRSummary{{Repo: "o/a", Number: 1}, {Repo: "o/b", Number: 2}, {Repo: "o/c", Number: 3}}
+	run := func(id string) string { return "https://github.com/o/x/actions/runs/" + id + "/job/9" }
+	m.dashRows[prKey("o/a", "1")] = dashRow{data: &PRData{State: "OPEN", Checks: []Check{
+		{Name: "unit", Status: Fail, DetailsURL: run("10")},
+		{Name: "e2e", Status: Fail, DetailsURL: run("10")},
+		{Name: "lint", Status: Fail, DetailsURL: run("11")},
+	}}}
+	m.dashRows[prKey("o/b", "2")] = dashRow{data: &PRData{State: "OPEN", Checks: []Check{{Name: "build", Status: Pass}}}}
+	m.dashRows[prKey("o/c", "3")] = dashRow{data: &PRData{State: "OPEN", Checks: []Check{{Name: "build", Status: Pass}}}}
+	m.syncDashboard(time.Now())
+	return m
+}
+
+func TestMarkRows(t *testing.T) {
+	m := bulkTestModel()
+	m, _ = press(t, m, keySpace, keySpace)
+	if got := m.markedPRs(); len(got) != 2 || got[0].Number != 1 || got[1].Number != 2 || m.selected != 2 {
+		t.Fatalf("marked %+v, selected %d; want #1 and #2 marked and the cursor on #3", got, m.selected)
+	}
+	out := m.View()
+	for _, want := range []string{" *o/a #1", " *o/b #2", "▸ o/c #3", "2 marked | space: mark/unmark"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() missing %q:\n%s", want, out)
+		}
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyUp}, keySpace)
+	if got := m.markedPRs(); len(got) != 1 || got[0].Number != 1 {
+		t.Errorf("space again should unmark: %+v", got)
+	}
+}
+
+func TestRefreshMarked(t *testing.T) {
+	m := bulkTestModel()
+	m.sched.rand = func() float64 { return 0.5 } // no jitter
+	now := time.Now()
+	for _, k := range []string{"o/a#1", "o/b#2", "o/c#3"} {
+		m.sched.done(k, now, k == m.activeKey())
+	}
+	m.marked = map[string]bool{"o/b#2": true}
+	m, _ = m.refreshMarked()
+	if !m.sched.inflight["o/b#2"] || m.sched.inflight["o/a#1"] || m.sched.inflight["o/c#3"] {
+		t.Errorf("only the marked PR should be fetched: %v", m.sched.inflight)
+	}
+}
+
+func TestBulkActions(t *testing.T) {
+	var calls []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		return nil, nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	m := bulkTestModel()
+	m.store.state.Ignored = map[string][]string{"o/a": {"lint"}}
+	m.marked = map[string]bool{"o/a#1": true, "o/b#2": true}
+
+	m, cmd := press(t, m, runeKey('F'))
+	if msg := cmd().(actionMsg); msg.err != nil || msg.text != "Requested reruns of failed jobs in 1 run(s) on 1 PR(s)" {
+		t.Errorf("rerun = %+v", msg)
+	}
+	if len(calls) != 1 || calls[0] != "run rerun 10 --failed --repo o/a" {
+		t.Errorf("gh calls = %v", calls)
+	}
+
+	calls = nil
+	m, _ = press(t, m, runeKey('E'))
+	if !strings.Contains(m.flash, "Enable auto-merge (squash) on 1 green PR(s)? E again") || len(calls) != 0 {
+		t.Fatalf("first E should ask first: flash %q, calls %v", m.flash, calls)
+	}
+	m, cmd = press(t, m, runeKey('E'))
+	if msg := cmd().(actionMsg); msg.err != nil || msg.text != "Enabled auto-merge on 1 PR(s)" {
+		t.Errorf("auto-merge = %+v", msg)
+	}
+	if len(calls) != 1 || calls[0] != "pr merge 2 --repo o/b --auto --squash" {
+		t.Errorf("gh calls = %v", calls)
+	}
+
+	calls = nil
+	m.readOnly = true
+	m, _ = press(t, m, runeKey('F'))
+	if !strings.Contains(m.flash, readOnlyReason) || len(calls) != 0 {
+		t.Errorf("read-only rerun: flash %q, calls %v", m.flash, calls)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheTTL is how long a response is served without asking gh again. It
+	// only dedupes near-simultaneous fetches (dashboard poll, then enter).
+	cacheTTL = 3 * time.Second
+	// cacheMaxAge drops entries this old when the cache file is loaded.
+	cacheMaxAge = 7 * 24 * time.Hour
+	// cacheFlushInterval limits how often the cache file is rewritten.
+	cacheFlushInterval = 10 * time.Second
+)
+
+// cacheEntry is one cached gh response.
+type cacheEntry struct {
+	At   time.Time `json:"at"`
+	Data string    `json:"data"`
+}
+
+// responseCache keeps the last good gh response per (repo, pr, endpoint) in
+// memory and, when path is set, on disk. Fresh entries (younger than ttl)
+// are served instead of calling gh; older ones are the last-known-good
+// fallback when gh fails and what a fresh start shows before its first fetch.
+type responseCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	dirty   bool
+	saved   time.Time
+}
+
+// respCache is the process-wide response cache. main replaces it with one
+// backed by disk; until then nothing is served fresh from it.
+var respCache = newResponseCache("", 0)
+
+func newResponseCache(path string, ttl time.Duration) *responseCache {
+	return &responseCache{path: path, ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+func defaultCachePath() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "prtop", "responses.json")
+}
+
+// openResponseCache loads the cache file at path. A missing file is not an
+// error; entries older than cacheMaxAge are dropped. An empty path keeps
+// the cache in memory only.
+func openResponseCache(path string) (*responseCache, error) {
+	c := newResponseCache(path, cacheTTL)
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse cache %s: %w", path, err)
+	}
+	for k, e := range c.entries {
+		if time.Since(e.At) > cacheMaxAge {
+			delete(c.entries, k)
+		}
+	}
+	return c, nil
+}
+
+func cacheKey(repo, prNumber, endpoint string) string {
+	return prKey(repo, prNumber) + " " + endpoint
+}
+
+// get returns the cached response for key and whether it is still fresh.
+func (c *responseCache) get(key string) (cacheEntry, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok && time.Since(e.At) < c.ttl, ok
+}
+
+// put stores a good response and writes the file if the last write was more
+// than cacheFlushInterval ago.
+func (c *responseCache) put(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{At: time.Now(), Data: string(data)}
+	c.dirty = true
+	if time.Since(c.saved) >= cacheFlushInterval {
+		c.saveLocked()
+	}
+}
+
+// flush writes any unsaved entries. main calls it on exit.
+func (c *responseCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dirty {
+		c.saveLocked()
+	}
+}
+
+func (c *responseCache) saveLocked() {
+	if c.path == "" {
+		return
+	}
+	c.saved = time.Now()
+	c.dirty = false
+	data, err := json.Marshal(c.entries)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.path), 0o755)
+	}
+	if err == nil {
+		tmp := c.path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o600); err == nil {
+			err = os.Rename(tmp, c.path)
+		}
+	}
+	if err != nil {
+		logger.Debug("cache save failed", "path", c.path, "err", err)
+	}
+}
+
+// cachedGh runs gh for key unless a fresh cached response exists. When gh
+// fails and an older response is cached, that response is returned instead
+// along with the time it was fetched. A zero time means the data is live.
+func cachedGh(acct *Account, key string, args ...string) ([]byte, time.Time, error) {
+	e, fresh, ok := respCache.get(key)
+	if fresh {
+		return []byte(e.Data), time.Time{}, nil
+	}
+	out, err := runGh(acct, args...)
+	if err == nil {
+		if json.Valid(out) {
+			respCache.put(key, out)
+		}
+		return out, time.Time{}, nil
+	}
+	// gh rejecting a --json field is an answer, not an outage to ride out
+	if ok && unknownJSONField(err) == "" {
+		logger.Debug("serving cached response", "key", key, "age", time.Since(e.At), "err", err)
+		return []byte(e.Data), e.At, nil
+	}
+	return nil, time.Time{}, err
+}
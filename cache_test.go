@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetRespCache gives the test an empty in-memory response cache.
+func resetRespCache(t *testing.T) {
+	t.Helper()
+	old := respCache
+	respCache = newResponseCache("", 0)
+	t.Cleanup(func() { respCache = old })
+}
+
+func TestCachedGh(t *testing.T) {
+	resetRespCache(t)
+	t.Cleanup(func() { execCommand = exec.Command })
+	key := cacheKey("o/r", "1", "pr view")
+
+	execCommand = fakeExecCommand(`{"title":"live"}`, "", 0)
+	out, cachedAt, err := cachedGh(nil, key, "pr", "view", "1")
+	if err != nil || string(out) != `{"title":"live"}` || !cachedAt.IsZero() {
+		t.Fatalf("live fetch = %q, %v, %v", out, cachedAt, err)
+	}
+
+	t.Run("gh failure falls back to last good response", func(t *testing.T) {
+		execCommand = fakeExecCommand("", "HTTP 502", 1)
+		out, cachedAt, err := cachedGh(nil, key, "pr", "view", "1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(out) != `{"title":"live"}` || cachedAt.IsZero() {
+			t.Errorf("fallback = %q at %v, want cached response with its time", out, cachedAt)
+		}
+	})
+
+	t.Run("fresh entries skip gh", func(t *testing.T) {
+		respCache.ttl = time.Minute
+		t.Cleanup(func() { respCache.ttl = 0 })
+		execCommand = fakeExecCommand("", "gh should not run", 1)
+		if _, _, err := cachedGh(nil, key, "pr", "view", "1"); err != nil {
+			t.Errorf("fresh entry should be served without gh, got %v", err)
+		}
+	})
+
+	t.Run("nothing cached returns the error", func(t *testing.T) {
+		execCommand = fakeExecCommand("", "HTTP 502", 1)
+		if _, _, err := cachedGh(nil, cacheKey("o/r", "2", "pr view"), "pr", "view", "2"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("invalid JSON is not cached", func(t *testing.T) {
+		execCommand = fakeExecCommand("oops", "", 0)
+		cachedGh(nil, cacheKey("o/r", "3", "pr view"), "pr", "view", "3")
+		if _, _, ok := respCache.get(cacheKey("o/r", "3", "pr view")); ok {
+			t.Error("invalid JSON should not become last-known-good")
+		}
+	})
+}
+
+func TestResponseCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prtop", "responses.json")
+	c, err := openResponseCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.put("o/r#1 pr view", []byte(`{"title":"x"}`))
+	c.entries["old"] = cacheEntry{At: time.Now().Add(-2 * cacheMaxAge), Data: "{}"}
+	c.flush()
+
+	c2, err := openResponseCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, _, ok := c2.get("o/r#1 pr view"); !ok || e.Data != `{"title":"x"}` {
+		t.Errorf("reloaded entry = %+v, %v", e, ok)
+	}
+	if _, _, ok := c2.get("old"); ok {
+		t.Error("entries older than cacheMaxAge should be dropped on load")
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openResponseCache(path); err == nil {
+		t.Error("expected error for corrupt cache file")
+	}
+}
+
+func TestPeekPRData(t *testing.T) {
+	resetRespCache(t)
+	if peekPRData("o/r", "1") != nil {
+		t.Error("peek with empty cache should be nil")
+	}
+	respCache.put(cacheKey("o/r", "1", "pr view"), []byte(`{"title":"cached","statusCheckRollup":[]}`))
+	data := peekPRData("o/r", "1")
+	if data == nil || data.Title != "cached" || data.CachedAt.IsZero() {
+		t.Errorf("peekPRData = %+v, want cached data with CachedAt", data)
+	}
+}
+
+func TestPeekShownUntilLiveData(t *testing.T) {
+	resetRespCache(t)
+	respCache.put(cacheKey("o/r", "1", "pr view"), []byte(`{"title":"cached","statusCheckRollup":[]}`))
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width = 100
+	m.height = 20
+	msg := m.peekCmd()()
+	updated, _ := m.Update(msg)
+	m = updated.(model)
+	if out := m.View(); !strings.Contains(out, "cached") || !strings.Contains(out, "Showing cached data") {
+		t.Errorf("peeked data should be shown and labelled, got %q", out)
+	}
+
+	updated, _ = m.Update(prDataMsg{data: &PRData{Title: "live"}, latency: time.Millisecond})
+	m = updated.(model)
+	updated, _ = m.Update(msg)
+	m = updated.(model)
+	if m.prData.Title != "live" {
+		t.Errorf("a late peek replaced live data: title = %q", m.prData.Title)
+	}
+	if out := m.View(); strings.Contains(out, "Showing cached data") {
+		t.Error("live data should not be labelled as cached")
+	}
+}
+
+func TestStatusServesDiskCache(t *testing.T) {
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte("#!/bin/sh\necho 'HTTP 502: Bad Gateway' >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	config := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(config, []byte("[polling]\nattempts = 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cache := respCache
+	t.Cleanup(func() {
+		respCache = cache
+		retryPolicy = retrier{attempts: 1}
+	})
+
+	// A previous run left the PR's last good response on disk
+	c, err := openResponseCache(defaultCachePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.entries[cacheKey("o/r", "7", "pr view")] = cacheEntry{At: time.Now().Add(-time.Hour), Data: cliFailingPR}
+	c.dirty = true
+	c.flush()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", config, "status", "o/r#7"}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitFailed || !strings.Contains(stdout.String(), "1 passed, 1 failed") {
+		t.Errorf("status with gh down = %d\nstdout:\n%s\nstderr:\n%s", code, stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// celebration is what prtop shows when a PR's checks turn all green.
+type celebration int
+
+const (
+	celebrateOff      celebration = iota // default
+	celebrateBanner                      // a big check mark
+	celebrateConfetti                    // the check mark under falling confetti
+)
+
+var celebrationNames = []string{"off", "banner", "confetti"}
+
+func (c celebration) String() string {
+	return celebrationNames[c]
+}
+
+// parseCelebration parses [display] celebrate. An empty name is
+// celebrateOff.
+func parseCelebration(name string) (celebration, error) {
+	if name == "" {
+		return celebrateOff, nil
+	}
+	for i, n := range celebrationNames {
+		if strings.EqualFold(name, n) {
+			return celebration(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown celebration %q (want one of %s)", name, strings.Join(celebrationNames, ", "))
+}
+
+// celebrateFrame is how often the confetti falls a row, and
+// celebrateFrames how many frames the celebration lasts.
+const (
+	celebrateFrame  = 120 * time.Millisecond
+	celebrateFrames = 25
+)
+
+type celebrateTickMsg struct{}
+
+func celebrateTickCmd() tea.Cmd {
+	return tea.Tick(celebrateFrame, func(time.Time) tea.Msg {
+		return celebrateTickMsg{}
+	})
+}
+
+// checkCelebration starts the celebration when the rollup has just turned
+// to success: settled, if --settle is on, since rollupHook holds success
+// back until then. Opening a PR that is already green doesn't count.
+func (m model) checkCelebration(previous string) (model, tea.Cmd) {
+	if m.celebration == celebrateOff || m.mini || previous == "" || previous == rollupSuccess || m.rollup != rollupSuccess {
+		return m, nil
+	}
+	logger.Debug("celebrating", "repo", m.repo, "pr", m.prNumber, "from", previous)
+	m.celebrating = celebrateFrames
+	return m, celebrateTickCmd()
+}
+
+// bigCheck is the celebration's check mark.
+var bigCheck = []string{
+	"          ##",
+	"         ## ",
+	"##      ##  ",
+	" ##    ##   ",
+	"  ##  ##    ",
+	"   ####     ",
+	"    ##      ",
+}
+
+// confettiColors are the confetti's colors, picked per piece.
+var confettiColors = []lipgloss.Color{"9", "11", "34", "39", "99", "208", "213"}
+
+// viewCelebration draws rows lines of width: the check mark and a caption
+// in the middle, and with confetti, pieces falling a row per frame around
+// them.
+func (m model) viewCelebration(width, rows int) []string {
+	caption := fmt.Sprintf("All %d checks passed", len(m.prData.Checks))
+	if len(m.prData.Checks) == 1 {
+		caption = "The check passed"
+	}
+	block := "█"
+	if m.glyphs == glyphsASCII || m.glyphs == glyphsNone {
+		block = "#"
+	}
+	art := make([]string, 0, len(bigCheck)+2)
+	for _, l := range bigCheck {
+		art = append(art, strings.ReplaceAll(l, "#", block))
+	}
+	art = append(art, "", caption)
+	top := max(0, (rows-len(art))/2)
+
+	frame := celebrateFrames - m.celebrating
+	lines := make([]string, rows)
+	for y := range rows {
+		if i := y - top; i >= 0 && i < len(art) {
+			text := art[i]
+			pad := max(0, (width-lipgloss.Width(text))/2)
+			if i == len(art)-1 {
+				text = styleBold.Render(text)
+			} else {
+				text = stylePass.Render(text)
+			}
+			lines[y] = strings.Repeat(" ", pad) + text
+			continue
+		}
+		if m.celebration == celebrateConfetti {
+			lines[y] = confettiRow(width, y, frame)
+		}
+	}
+	return lines
+}
+
+// confettiRow is row y of the confetti at frame. Each column drops a piece
+// every few rows, offset by a hash of the column, so the pattern looks
+// random but is the same on every run.
+func confettiRow(width, y, frame int) string {
+	var b strings.Builder
+	for x := range width {
+		h := uint32(x)*2654435761 + 40503
+		gap := 6 + int(h>>8%7)
+		if (y-frame-int(h>>16%uint32(gap)))%gap != 0 {
+			b.WriteByte(' ')
+			continue
+		}
+		piece := string("*+o~."[int(h>>4)%5])
+		color := confettiColors[(int(h>>12)+y)%len(confettiColors)]
+		b.WriteString(lipgloss.NewStyle().Foreground(color).Render(piece))
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseCelebration(t *testing.T) {
+	for name, want := range map[string]celebration{"": celebrateOff, "Banner": celebrateBanner, "confetti": celebrateConfetti} {
+		if got, err := parseCelebration(name); err != nil || got != want {
+			t.Errorf("parseCelebration(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := parseCelebration("fireworks"); err == nil {
+		t.Error("unknown celebration accepted")
+	}
+}
+
+func TestCelebration(t *testing.T) {
+	running := &PRData{HeadSHA: "abc", Checks: []Check{{Name: "build", Status: Pass}, {Name: "test", Status: Running}}}
+	passed := &PRData{HeadSHA: "abc", Checks: []Check{{Name: "build", Status: Pass}, {Name: "test", Status: Pass}}}
+	fetch := func(m model, data *PRData) model {
+		updated, _ := m.Update(prDataMsg{data: data, key: prKey("o/r", "1")})
+		return updated.(model)
+	}
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 80, 24
+	m.celebration = celebrateConfetti
+
+	// Already green when opened: nothing to celebrate
+	if m = fetch(m, passed); m.celebrating != 0 {
+		t.Error("celebrated a PR that was green when opened")
+	}
+
+	m = fetch(fetch(m, running), passed)
+	if m.celebrating != celebrateFrames {
+		t.Fatalf("celebrating = %d after the checks turned green", m.celebrating)
+	}
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "All 2 checks passed") || !strings.Contains(out, "██") || strings.Contains(out, "CHECK ") {
+		t.Errorf("celebration:\n%s", out)
+	}
+	table := m
+	table.celebrating = 0
+	if got, want := strings.Count(m.View(), "\n"), strings.Count(table.View(), "\n"); got != want {
+		t.Errorf("celebration is %d lines, the table %d", got, want)
+	}
+
+	updated, cmd := m.Update(celebrateTickMsg{})
+	if m = updated.(model); m.celebrating != celebrateFrames-1 || cmd == nil {
+		t.Errorf("tick: %d frames left", m.celebrating)
+	}
+	// Any key goes back to the table without acting on the key
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if m = updated.(model); m.celebrating != 0 || m.selected != 0 {
+		t.Errorf("after a key: celebrating %d, selected %d", m.celebrating, m.selected)
+	}
+
+	// Celebrations are off by default
+	m.celebration = celebrateOff
+	if m = fetch(fetch(m, running), passed); m.celebrating != 0 {
+		t.Error("celebrated with celebrations off")
+	}
+}
+
+func TestCelebrationRunsOut(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.celebrating = 1
+	updated, cmd := m.Update(celebrateTickMsg{})
+	if m = updated.(model); m.celebrating != 0 || cmd != nil {
+		t.Errorf("last frame: %d left, cmd %v", m.celebrating, cmd != nil)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// checkOutput is the report a check run attaches to itself: coverage bots,
+// Terraform plans and linters put their whole result in its Markdown
+// summary and text.
+type checkOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Text    string `json:"text"`
+	// App is the slug of the GitHub App that reported the check run
+	App string `json:"-"`
+
+	// rendered caches lines per renderer and pane width, as View runs on
+	// every message
+	rendered map[string][]string
+}
+
+type outputMsg struct {
+	key    string // outputKey of the check the output belongs to
+	output *checkOutput
+	err    error
+}
+
+// outputKey identifies a check run's output. Bots rewrite it as they go, so
+// it includes the status and the head commit.
+func outputKey(sha string, c Check) string {
+	return sha + " " + c.Name + " " + c.Status.String()
+}
+
+// fetchCheckOutput fetches the output of the check run named c.JobName on
+// commit sha. When several apps report a check of that name, the one whose
+// details URL matches c's is picked. It returns nil when the check has no
+// output.
+func fetchCheckOutput(acct *Account, repo, sha string, c Check) (*checkOutput, error) {
+	out, err := ghAPI(acct, repo, "repos/{repo}/commits/"+sha+"/check-runs?filter=latest&check_name="+url.QueryEscape(c.JobName))
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		CheckRuns []struct {
+			DetailsURL string      `json:"details_url"`
+			HTMLURL    string      `json:"html_url"`
+			Output     checkOutput `json:"output"`
+			App        struct {
+				Slug string `json:"slug"`
+			} `json:"app"`
+		} `json:"check_runs"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+	if len(resp.CheckRuns) == 0 {
+		return nil, nil
+	}
+	run := resp.CheckRuns[0]
+	for _, r := range resp.CheckRuns {
+		if c.DetailsURL != "" && (r.DetailsURL == c.DetailsURL || r.HTMLURL == c.DetailsURL) {
+			run = r
+			break
+		}
+	}
+	o := run.Output
+	o.App = run.App.Slug
+	if o.Title == "" && strings.TrimSpace(o.Summary) == "" && strings.TrimSpace(o.Text) == "" {
+		return nil, nil
+	}
+	return &o, nil
+}
+
+// ensureOutput starts fetching the selected check run's output when the
+// details pane is showing and it isn't the one already loaded. Status
+// contexts have no output.
+func (m model) ensureOutput() (model, tea.Cmd) {
+	if !m.split || m.pane != paneDetails || m.mode != modeViewing || m.prData == nil {
+		return m, nil
+	}
+	c, ok := m.selectedCheck()
+	if !ok || c.StatusContext || m.prData.HeadSHA == "" {
+		return m, nil
+	}
+	key := outputKey(m.prData.HeadSHA, c)
+	if key == m.outputKey {
+		return m, nil
+	}
+	m.outputKey = key
+	m.output, m.outputErr, m.outputLoaded = nil, nil, false
+	acct := m.repoAccount(m.repo)
+	repo, sha := m.repo, m.prData.HeadSHA
+	return m, func() tea.Msg {
+		output, err := fetchCheckOutput(acct, repo, sha, c)
+		return outputMsg{key: key, output: output, err: err}
+	}
+}
+
+// outputLines is the details pane's section for c's check run output.
+func (m model) outputLines(c Check) []string {
+	if c.StatusContext || m.prData == nil || outputKey(m.prData.HeadSHA, c) != m.outputKey {
+		return nil
+	}
+	switch {
+	case m.outputErr != nil:
+		return []string{"", styleDim.Render("Output unavailable: " + m.outputErr.Error())}
+	case !m.outputLoaded:
+		return []string{"", styleDim.Render("Loading output...")}
+	case m.output == nil:
+		return nil
+	}
+	lines := []string{""}
+	if m.output.Title != "" {
+		lines = append(lines, styleBold.Render(m.output.Title))
+	}
+	_, width := m.splitWidths(m.width)
+	return append(lines, m.output.lines(m.outputRenderer(c, m.output), width)...)
+}
+
+// body is the output's summary and text, which bots split as they like.
+func (o *checkOutput) body() string {
+	md := strings.TrimSpace(o.Summary)
+	if text := strings.TrimSpace(o.Text); text != "" {
+		md += "\n\n" + text
+	}
+	return md
+}
+
+// lines renders the output with r for a pane width columns wide.
+func (o *checkOutput) lines(r outputRenderer, width int) []string {
+	key := fmt.Sprintf("%s %d", r.name, width)
+	if l, ok := o.rendered[key]; ok {
+		return l
+	}
+	l := r.render(o, width)
+	if o.rendered == nil {
+		o.rendered = map[string][]string{}
+	}
+	o.rendered[key] = l
+	return l
+}
+
+// renderMarkdown renders md with glamour, wrapped to width, in the colors
+// the rest of the UI uses. It falls back to the plain text.
+func renderMarkdown(md string, width int) []string {
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithColorProfile(lipgloss.ColorProfile()),
+		glamour.WithWordWrap(max(width-4, 20)),
+	)
+	if err == nil {
+		var out string
+		if out, err = r.Render(md); err == nil {
+			md = strings.Trim(out, "\n")
+		}
+	}
+	if err != nil {
+		logger.Debug("rendering check output failed", "err", err)
+	}
+	lines := strings.Split(md, "\n")
+	for i, l := range lines {
+		// glamour pads every line out to the wrap width
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFetchCheckOutput(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"check_name=coverage": `{"check_runs":[
+			{"details_url":"https://other.example.com/1","output":{"title":"Other","summary":"no"}},
+			{"details_url":"https://cov.example.com/2","output":{"title":"84% covered","summary":"| file | % |\n|---|---|\n| a.go | 84 |"}}]}`,
+		"check_name=plan+apply": `{"check_runs":[{"details_url":"","output":{"title":null,"summary":null,"text":null}}]}`,
+		"check_name=gone":       `{"check_runs":[]}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	o, err := fetchCheckOutput(nil, "o/r", "abc", Check{JobName: "coverage", DetailsURL: "https://cov.example.com/2"})
+	if err != nil || o == nil || o.Title != "84% covered" || !strings.Contains(o.Summary, "a.go") {
+		t.Errorf("coverage = %+v, %v; want the run matching the details URL", o, err)
+	}
+	for _, name := range []string{"plan apply", "gone"} {
+		if o, err := fetchCheckOutput(nil, "o/r", "abc", Check{JobName: name}); o != nil || err != nil {
+			t.Errorf("%s = %+v, %v; want no output", name, o, err)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	lines := renderMarkdown("# Plan\n\nTerraform will **create** 2 resources and destroy none of the existing ones.\n\n- aws_s3_bucket.logs\n- aws_iam_role.ci", 40)
+	text := ansi.Strip(strings.Join(lines, "\n"))
+	for _, want := range []string{"Plan", "create", "aws_s3_bucket.logs", "aws_iam_role.ci"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("rendered output is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "**") {
+		t.Errorf("Markdown wasn't rendered:\n%s", text)
+	}
+	for _, l := range lines {
+		if w := ansi.StringWidth(l); w > 40 {
+			t.Errorf("line %q is %d wide, want at most 40", l, w)
+		}
+	}
+}
+
+func TestDetailsPaneOutput(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"check_name=ext": `{"check_runs":[{"details_url":"https://ci.example.com/7","output":{"title":"All good","summary":"Coverage is *up*."}}]}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := splitTestModel()
+	m.prData.HeadSHA = "abc"
+	for i := range m.prData.Checks {
+		m.prData.Checks[i].JobName = m.prData.Checks[i].Name
+	}
+	m.prData.Checks = m.prData.Checks[2:] // ext: no Actions log to fetch
+	m, _ = press(t, m, keyV)
+	m, cmd := press(t, m, keyTab)
+	if !strings.Contains(strings.Join(m.paneLines(), "\n"), "Loading output...") {
+		t.Errorf("details pane while fetching:\n%s", strings.Join(m.paneLines(), "\n"))
+	}
+	if cmd == nil {
+		t.Fatal("the details pane should fetch the check's output")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+	details := ansi.Strip(strings.Join(m.paneLines(), "\n"))
+	if !strings.Contains(details, "All good") || !strings.Contains(details, "Coverage is up.") {
+		t.Errorf("details pane is missing the output:\n%s", details)
+	}
+
+	// Status contexts have no output to fetch
+	m = splitTestModel()
+	m.prData.HeadSHA = "abc"
+	m.prData.Checks = []Check{{Name: "ci/jenkins", Status: Pass, StatusContext: true}}
+	m, _ = press(t, m, keyV)
+	if _, cmd = press(t, m, keyTab); cmd != nil {
+		t.Error("a status context shouldn't fetch output")
+	}
+}
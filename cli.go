@@ -0,0 +1,1084 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Exit codes for the commands that report a PR's check status. 8 for
+// pending matches `gh pr checks`.
+const (
+	exitOK      = 0
+	exitFailed  = 1
+	exitPending = 8
+)
+
+// options holds every command-line flag. Global flags are accepted before
+// the command name and by every command; the rest belong to the commands
+// that register them.
+type options struct {
+	interval string
+	config   string
+	account  string
+	record   string
+	journal  string
+	replay   string
+	demo     bool
+	simulate string
+	debug    string
+	readOnly bool
+	// Interactive commands
+	attention     bool
+	mini          bool
+	inline        bool
+	onChange      string
+	watchlist     string
+	kiosk         bool
+	kioskCycle    time.Duration
+	dashboard     bool   // bare `prtop --dashboard`, same as `prtop dash`
+	query         string // dash
+	stdin         bool   // dash: PRs from stdin
+	issue         string // select and dash: owner/repo#456 or an issue URL
+	notifications bool   // select and dash: PRs from the notification feed
+	workspace     bool   // select and dash: PRs touching the working directory's changes
+	auto          bool   // select: open the first PR without showing the picker
+	mergeMethod   string // bots and dash
+	version       bool
+	socket        string // control socket, listened on by the TUI and used by ctl
+	// wait, stream, status and export
+	timeout time.Duration
+	settle  time.Duration // also the interactive commands
+	format  string
+	check   bool   // self-update
+	jsonRPC bool   // serve
+	listen  string // badge
+	since   string // stats
+}
+
+// synthetic reports whether gh's answers are made up by --demo, --replay
+// or --simulate rather than coming from GitHub.
+func (o *options) synthetic() bool {
+	return o.demo || o.replay != "" || o.simulate != ""
+}
+
+func defaultOptions() *options {
+	return &options{config: defaultConfigPath(), watchlist: defaultWatchlistPath(), socket: defaultCtlPath(), format: "json", listen: "127.0.0.1:8080", since: "30d",
+		kioskCycle: defaultKioskCycle, mergeMethod: defaultMergeMethod}
+}
+
+// globalFlags registers the flags every command takes. Each uses the
+// option's current value as its default, so a command's flag set keeps what
+// was given before the command name.
+func (o *options) globalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.interval, "interval", o.interval, "Refresh `interval`, e.g. 30s or 2m; a bare number is seconds (default 5s, minimum 2s)")
+	fs.StringVar(&o.config, "config", o.config, "Path to config `file`")
+	fs.StringVar(&o.account, "account", o.account, "Account from config to use for the PR picker")
+	fs.StringVar(&o.record, "record", o.record, "Record every gh response to `file` for later replay")
+	fs.StringVar(&o.replay, "replay", o.replay, "Play back gh responses from a `file` made with --record instead of calling gh")
+	fs.StringVar(&o.journal, "journal", o.journal, "Append every check status change to `file` as JSON lines")
+	fs.BoolVar(&o.demo, "demo", o.demo, "Run against built-in synthetic PR data (no GitHub account needed)")
+	fs.StringVar(&o.simulate, "simulate", o.simulate, "Run against a scripted PR whose checks step through the states in a YAML `file`")
+	fs.StringVar(&o.debug, "debug", o.debug, "Write a debug log of gh invocations and state changes to `file`")
+	fs.BoolVar(&o.readOnly, "read-only", o.readOnly, "Turn off everything that changes a PR or its runs (reruns, branch updates, approvals), e.g. for a shared screen")
+}
+
+// tuiFlags registers the flags of the interactive commands.
+func (o *options) tuiFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.attention, "attention", o.attention, "Blink an \"N FAILED\" banner the first time a check fails (any key clears it)")
+	fs.BoolVar(&o.inline, "inline", o.inline, "Draw below the prompt instead of full screen, leaving the final state in scrollback")
+	fs.BoolVar(&o.mini, "mini", o.mini, "Show a PR in three lines (PR, summary bar, most relevant check) for a small tmux pane")
+	fs.StringVar(&o.onChange, "on-change", o.onChange, "Shell `command` to run when a PR's overall check status changes (see PRTOP_* env vars)")
+	fs.StringVar(&o.watchlist, "watchlist", o.watchlist, "`file` of PR URLs the dashboard always includes")
+	fs.BoolVar(&o.kiosk, "kiosk", o.kiosk, "Wallboard: show the watchlist's PRs in turn, read-only, with a large summary and no key hints")
+	fs.DurationVar(&o.kioskCycle, "kiosk-cycle", o.kioskCycle, "How long --kiosk shows each PR")
+	o.settleFlag(fs)
+	o.socketFlag(fs)
+}
+
+func (o *options) socketFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.socket, "socket", o.socket, "Control socket `path` for 'prtop ctl'")
+}
+
+func (o *options) issueFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.issue, "issue", o.issue, "Only the open PRs that close `issue` (owner/repo#456 or an issue URL)")
+}
+
+func (o *options) notificationsFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&o.notifications, "notifications", o.notifications, "Only the open PRs in your GitHub notifications: review requests, mentions, CI activity")
+}
+
+func (o *options) workspaceFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&o.workspace, "workspace", o.workspace, "Only the open PRs that touch files you've changed here (uncommitted, untracked or on this branch)")
+}
+
+func (o *options) autoFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&o.auto, "auto", o.auto, "Skip the picker and view its first PR (it's skipped anyway when there's only one)")
+}
+
+func (o *options) mergeMethodFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.mergeMethod, "merge-method", o.mergeMethod, "How the dashboard merges (M in bots, E auto-merge): merge, squash or rebase")
+}
+
+func (o *options) queryFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.query, "query", o.query, "Dashboard of the open PRs matching a GitHub search `query`, e.g. 'label:release-blocker'")
+	fs.BoolVar(&o.stdin, "stdin", o.stdin, "Dashboard of the PRs read from stdin: URLs, owner/repo#123 or gh's --json output (same as a - argument)")
+}
+
+func (o *options) timeoutFlag(fs *flag.FlagSet) {
+	fs.DurationVar(&o.timeout, "timeout", o.timeout, "Give up (exit 8) after `duration`; 0 waits forever")
+	o.settleFlag(fs)
+}
+
+func (o *options) settleFlag(fs *flag.FlagSet) {
+	fs.DurationVar(&o.settle, "settle", o.settle, "Only count passing checks as done once none has run or appeared for `duration`, e.g. 30s")
+}
+
+// command is a prtop subcommand. run returns the process exit code.
+type command struct {
+	name    string
+	args    string // usage synopsis of the positional arguments
+	summary string
+	flags   func(o *options, fs *flag.FlagSet)
+	run     func(s *session, args []string) (int, error)
+	// offline commands don't talk to GitHub, so they run without gh,
+	// --record/--replay or the config
+	offline bool
+}
+
+var commands = []command{
+	{name: "view", args: "PR", summary: "Watch one PR's checks", flags: (*options).tuiFlags, run: runView},
+	{name: "select", args: "[owner/repo]", summary: "Pick from your recent open PRs, or a repo's",
+		flags: func(o *options, fs *flag.FlagSet) {
+			o.tuiFlags(fs)
+			o.issueFlag(fs)
+			o.notificationsFlag(fs)
+			o.workspaceFlag(fs)
+			o.autoFlag(fs)
+		}, run: runSelect},
+	{name: "dash", args: "[owner/repo]", summary: "Show live check counts for many PRs at once",
+		flags: func(o *options, fs *flag.FlagSet) {
+			o.tuiFlags(fs)
+			o.issueFlag(fs)
+			o.notificationsFlag(fs)
+			o.workspaceFlag(fs)
+			o.queryFlag(fs)
+			o.mergeMethodFlag(fs)
+		}, run: runDash},
+	{name: "release", args: "MANIFEST", summary: "Follow a release train: the PRs in a YAML manifest, in order, with checks, review and merge gates",
+		flags: func(o *options, fs *flag.FlagSet) {
+			o.tuiFlags(fs)
+			o.mergeMethodFlag(fs)
+		}, run: runRelease},
+	{name: "bots", args: "owner/repo", summary: "Garden a repo's Dependabot and Renovate PRs: checks, rebase (b) and merge the green ones (M)",
+		flags: func(o *options, fs *flag.FlagSet) {
+			o.tuiFlags(fs)
+			o.mergeMethodFlag(fs)
+		}, run: runBots},
+	{name: "wait", args: "PR", summary: "Wait for a PR's checks to finish; exit 0 if they passed, 1 if not",
+		flags: (*options).timeoutFlag, run: runWait},
+	{name: "stream", args: "PR", summary: "Print an NDJSON event per check change until the checks finish; exits like wait",
+		flags: (*options).timeoutFlag, run: runStream},
+	{name: "status", args: "PR", summary: "Print a PR's checks once; exit 0 passed, 1 failed, 8 pending", run: runStatus},
+	{name: "export", args: "PR", summary: "Print a PR's checks as JSON or CSV",
+		flags: func(o *options, fs *flag.FlagSet) {
+			fs.StringVar(&o.format, "format", o.format, "Output `format`: json or csv")
+		}, run: runExport},
+	{name: "stats", args: "owner/repo", summary: "Summarize a repo's recent CI: slowest, most failing and most retried checks, time to green",
+		flags: func(o *options, fs *flag.FlagSet) {
+			fs.StringVar(&o.since, "since", o.since, "Look at PRs updated in the last `period`, e.g. 30d or 12h, or since a date (2024-05-01)")
+			fs.StringVar(&o.format, "format", "table", "Output `format`: table or json")
+		}, run: runStats},
+	{name: "badge", args: "PR", summary: "Serve a live SVG badge and JSON summary of a PR's checks over HTTP",
+		flags: func(o *options, fs *flag.FlagSet) {
+			fs.StringVar(&o.listen, "listen", o.listen, "Listen on `address`; :8080 serves every interface, not just this machine")
+		}, run: runBadge},
+	{name: "ctl", args: "switch PR | refresh | pause | resume | state | quit", summary: "Control a running prtop: view another PR, refresh, pause polling, or print its state",
+		flags: (*options).socketFlag, run: runCtl, offline: true},
+	{name: "serve", summary: "Answer JSON-RPC requests on stdin for editor plugins: checks, normalize, rerun",
+		flags: func(o *options, fs *flag.FlagSet) {
+			fs.BoolVar(&o.jsonRPC, "json-rpc", o.jsonRPC, "Speak JSON-RPC 2.0, one message per line")
+		}, run: runServe},
+	{name: "mcp", summary: "Serve get_pr_checks, get_failed_check_logs and rerun_check to AI assistants over MCP (stdio)",
+		run: runMCP},
+	{name: "self-update", summary: "Update prtop to its latest GitHub release",
+		flags: func(o *options, fs *flag.FlagSet) {
+			fs.BoolVar(&o.check, "check", o.check, "Only report whether a newer release is available")
+		}, run: runSelfUpdate},
+}
+
+func init() {
+	// Added here rather than in the literal because runMan walks commands
+	commands = append(commands, command{name: "man", summary: "Print the man page (roff), e.g. prtop man > prtop.1",
+		run: runMan, offline: true})
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// defaultCommand is bare `prtop [flags] [PR-URL | owner/repo [PR-number | branch]]`
+// and `prtop issue owner/repo 456`, the forms that predate subcommands.
+var defaultCommand = command{run: runDefault}
+
+// session is what every command starts from once the global flags and the
+// config are applied.
+type session struct {
+	opts     *options
+	cfg      Config
+	account  int
+	hosts    []string
+	interval time.Duration
+	settle   time.Duration // see settler
+	readOnly bool          // --read-only or read_only in the config
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: prtop [flags] [PR-URL | owner/repo [PR-number | branch]]\n")
+	fmt.Fprintf(w, "       prtop [flags] issue owner/repo ISSUE-number\n")
+	fmt.Fprintf(w, "       prtop [flags] COMMAND [command flags] [args]\n\n")
+	fmt.Fprintf(w, "Live-updating terminal UI for GitHub PR check statuses.\n\n")
+	fmt.Fprintf(w, "When run with no arguments, shows your 5 most recent open PRs to select from.\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nPR is a PR URL, owner/repo#123, owner/repo 123 or owner/repo BRANCH. Run 'prtop COMMAND -h' for a command's flags.\n\n")
+	fmt.Fprintf(w, "Examples:\n")
+	fmt.Fprintf(w, "  prtop                                            # pick from recent PRs\n")
+	fmt.Fprintf(w, "  prtop owner/repo                                 # pick from the repo's open PRs\n")
+	fmt.Fprintf(w, "  prtop dash owner/repo                            # watch all of the repo's open PRs\n")
+	fmt.Fprintf(w, "  prtop issue owner/repo 456                       # pick from PRs that close issue 456\n")
+	fmt.Fprintf(w, "  prtop dash --query 'label:release-blocker'       # dashboard of matching PRs\n")
+	fmt.Fprintf(w, "  prtop --workspace                                # PRs touching the files you've changed\n")
+	fmt.Fprintf(w, "  gh search prs --author @me --json url | prtop -  # dashboard of PRs piped in\n")
+	fmt.Fprintf(w, "  prtop https://github.com/owner/repo/pull/123\n")
+	fmt.Fprintf(w, "  prtop owner/repo#123\n")
+	fmt.Fprintf(w, "  prtop git@github.com:owner/repo.git 123\n")
+	fmt.Fprintf(w, "  prtop owner/repo my-feature-branch               # the open PR from that branch\n")
+	fmt.Fprintf(w, "  prtop --interval 30s owner/repo 123\n")
+	fmt.Fprintf(w, "  prtop wait --timeout 30m owner/repo#123 && make deploy\n")
+	fmt.Fprintf(w, "  prtop export --format csv owner/repo#123 > checks.csv\n")
+	fmt.Fprintf(w, "  prtop --demo                                     # try it with synthetic data\n\n")
+	fmt.Fprintf(w, "Flags:\n")
+	fs.PrintDefaults()
+}
+
+func commandUsage(w io.Writer, c command, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: prtop %s [flags] %s\n\n", c.name, c.args)
+	fmt.Fprintf(w, "%s.\n\n", c.summary)
+	fmt.Fprintf(w, "Flags:\n")
+	fs.PrintDefaults()
+}
+
+// flagSet is the flag set of bare `prtop`: the global flags plus those of
+// the forms that predate subcommands.
+func (o *options) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("prtop", flag.ContinueOnError)
+	o.globalFlags(fs)
+	o.tuiFlags(fs)
+	o.notificationsFlag(fs)
+	o.workspaceFlag(fs)
+	o.autoFlag(fs)
+	o.queryFlag(fs)
+	o.mergeMethodFlag(fs)
+	fs.BoolVar(&o.dashboard, "dashboard", o.dashboard, "Show live check counts for every PR instead of the picker (same as 'prtop dash')")
+	fs.BoolVar(&o.version, "version", o.version, "Print the version and exit")
+	return fs
+}
+
+// commandFlagSet is the flag set of `prtop c.name`.
+func (o *options) commandFlagSet(c command) *flag.FlagSet {
+	fs := flag.NewFlagSet("prtop "+c.name, flag.ContinueOnError)
+	o.globalFlags(fs)
+	if c.flags != nil {
+		c.flags(o, fs)
+	}
+	return fs
+}
+
+// run parses args (without the program name), runs the command they name
+// and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	o := defaultOptions()
+	fs := o.flagSet()
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitFailed
+	}
+	if o.version {
+		fmt.Fprintln(stdout, versionString())
+		return exitOK
+	}
+
+	cmd := defaultCommand
+	args = fs.Args()
+	if len(args) > 0 {
+		if c, ok := findCommand(args[0]); ok {
+			cmd = c
+			cfs := o.commandFlagSet(c)
+			cfs.SetOutput(stderr)
+			cfs.Usage = func() { commandUsage(stderr, c, cfs) }
+			if err := cfs.Parse(args[1:]); err != nil {
+				if errors.Is(err, flag.ErrHelp) {
+					return exitOK
+				}
+				return exitFailed
+			}
+			args = cfs.Args()
+		}
+	}
+	if cmd.name == "" {
+		n := len(args)
+		if n > 0 && args[0] == "issue" {
+			n--
+		}
+		if n > 2 {
+			fs.Usage()
+			return exitFailed
+		}
+	}
+
+	if cmd.offline {
+		code, err := cmd.run(&session{opts: o, stdin: stdin, stdout: stdout, stderr: stderr}, args)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+		}
+		return code
+	}
+
+	var interval time.Duration
+	if o.interval != "" {
+		var err error
+		if interval, err = parseInterval(o.interval); err != nil {
+			fmt.Fprintf(stderr, "Error: --interval: %v\n", err)
+			return exitFailed
+		}
+	}
+
+	if o.record != "" && o.replay != "" {
+		fmt.Fprintf(stderr, "Error: --record and --replay cannot be used together\n")
+		return exitFailed
+	}
+
+	if o.demo && o.replay != "" {
+		fmt.Fprintf(stderr, "Error: --demo and --replay cannot be used together\n")
+		return exitFailed
+	}
+
+	if o.simulate != "" && (o.demo || o.replay != "") {
+		fmt.Fprintf(stderr, "Error: --simulate cannot be used with --demo or --replay\n")
+		return exitFailed
+	}
+
+	if o.demo {
+		ghOverride = newDemoSource()
+	} else if o.simulate != "" {
+		sc, err := loadScenario(o.simulate)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: --simulate: %v\n", err)
+			return exitFailed
+		}
+		ghOverride = newSimulateSource(sc)
+	} else if o.replay != "" {
+		if err := startReplay(o.replay); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitFailed
+		}
+	} else if _, err := exec.LookPath("gh"); err != nil {
+		// Without gh, a token in the environment is enough
+		if tokenClient = newAPIClient(os.Getenv); tokenClient == nil {
+			fmt.Fprintf(stderr, "Error: 'gh' CLI not found on PATH.\n")
+			fmt.Fprintf(stderr, "Install it from https://cli.github.com/, or set GITHUB_TOKEN to use the GitHub API directly\n")
+			return exitFailed
+		}
+		logger.Debug("gh not found, using the GitHub API with a token from the environment", "host", tokenClient.host)
+	}
+
+	if o.record != "" {
+		f, err := startRecording(o.record)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitFailed
+		}
+		defer f.Close()
+	}
+
+	if o.journal != "" {
+		f, err := startJournal(o.journal)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitFailed
+		}
+		defer f.Close()
+	}
+
+	if o.debug != "" {
+		f, err := setupDebugLog(o.debug)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitFailed
+		}
+		defer f.Close()
+	}
+
+	cfg, err := loadConfig(o.config)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitFailed
+	}
+	account := 0
+	if o.account != "" {
+		account = findAccount(cfg.Accounts, o.account)
+		if account < 0 {
+			fmt.Fprintf(stderr, "Error: no account named %q in config\n", o.account)
+			return exitFailed
+		}
+	}
+
+	statusOverrides, _ = parseStatusOverrides(cfg.Status) // validated by loadConfig
+	grading, _ = parseGrade(cfg.Grade)                    // validated by loadConfig
+	lumpConclusions = cfg.Display.LumpConclusions
+	retryPolicy, _ = parseRetry(cfg.Polling) // validated by loadConfig
+	if tokenClient != nil {
+		tokenClient.http.Transport, _ = apiTransport(cfg.API) // validated by loadConfig
+		if cfg.API.InsecureSkipVerify {
+			logger.Warn("api.insecure_skip_verify is set: GitHub's certificate isn't checked")
+		}
+	}
+	s := &session{opts: o, cfg: cfg, account: account, interval: defaultInterval, stdin: stdin, stdout: stdout, stderr: stderr}
+	for _, a := range cfg.Accounts {
+		s.hosts = append(s.hosts, a.Host)
+	}
+	if cfg.Polling.Interval != 0 {
+		s.interval = cfg.Polling.Interval
+	}
+	if interval != 0 {
+		s.interval = interval
+	}
+	s.readOnly = cfg.ReadOnly || o.readOnly
+	s.settle = cfg.Polling.Settle
+	if o.settle != 0 {
+		if o.settle < 0 {
+			fmt.Fprintln(stderr, "Error: --settle must not be negative")
+			return exitFailed
+		}
+		s.settle = o.settle
+	}
+
+	// Every command that talks to GitHub starts from, and falls back on,
+	// the last good responses. Replayed, demo and simulated ones must not
+	// become anyone's last-known-good.
+	if !o.synthetic() {
+		if cache, err := openResponseCache(defaultCachePath()); err != nil {
+			fmt.Fprintf(stderr, "Warning: %v\n", err)
+		} else {
+			respCache = cache
+		}
+		defer respCache.flush()
+	}
+
+	code, err := cmd.run(s, args)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+	}
+	return code
+}
+
+// runDefault picks the command a bare invocation means: the picker with no
+// arguments or a repo, the dashboard with --dashboard, --query, --stdin or
+// -, and the viewer for a PR.
+func runDefault(s *session, args []string) (int, error) {
+	switch {
+	case len(args) > 0 && args[0] == "issue":
+		s.opts.issue = strings.Join(args[1:], " ")
+		if s.opts.dashboard {
+			return runDash(s, nil)
+		}
+		return runSelect(s, nil)
+	case s.opts.dashboard || s.opts.query != "" || s.opts.stdin || len(args) == 1 && args[0] == "-":
+		return runDash(s, args)
+	case len(args) == 0:
+		return runSelect(s, nil)
+	case len(args) == 1:
+		if _, ok := parseRepo(args[0], s.hosts...); ok {
+			return runSelect(s, args)
+		}
+	}
+	return runView(s, args)
+}
+
+// prArgs parses a command's PR argument: a PR URL, owner/repo#123, or
+// owner/repo and a number or the PR's head branch.
+func (s *session) prArgs(args []string) (repo, prNumber string, err error) {
+	switch len(args) {
+	case 1:
+		repo, prNumber, ok := parsePRRef(args[0], s.hosts...)
+		if !ok {
+			return "", "", fmt.Errorf("invalid PR reference: %s\n"+
+				"Expected a PR URL (https://github.com/owner/repo/pull/123) or owner/repo#123", args[0])
+		}
+		return repo, prNumber, nil
+	case 2:
+		repo, ok := parseRepo(args[0], s.hosts...)
+		if !ok {
+			return "", "", fmt.Errorf("invalid repository: %s\n"+
+				"Expected owner/repo, a repo URL, or git@github.com:owner/repo.git", args[0])
+		}
+		prNumber := strings.TrimPrefix(args[1], "#")
+		if _, err := strconv.Atoi(prNumber); err == nil {
+			return repo, prNumber, nil
+		}
+		if prNumber != args[1] {
+			return "", "", fmt.Errorf("PR number must be numeric: %s", args[1])
+		}
+		// Anything else is the PR's head branch
+		prNumber, err = fetchBranchPR(s.acct(repo), repo, args[1])
+		if err != nil {
+			return "", "", err
+		}
+		return repo, prNumber, nil
+	}
+	return "", "", errors.New("expected a PR: a PR URL, owner/repo#123, owner/repo 123 or owner/repo BRANCH")
+}
+
+// issueArg parses --issue.
+func (s *session) issueArg() (repo string, number int, err error) {
+	repo, number, ok := parseIssueRef(strings.Fields(s.opts.issue), s.hosts...)
+	if !ok {
+		return "", 0, fmt.Errorf("invalid issue reference: %s\n"+
+			"Expected owner/repo 456, owner/repo#456 or an issue URL", s.opts.issue)
+	}
+	return repo, number, nil
+}
+
+// repoArg parses the optional owner/repo argument of select and dash.
+func (s *session) repoArg(name string, args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "", nil
+	case 1:
+		if repo, ok := parseRepo(args[0], s.hosts...); ok {
+			return repo, nil
+		}
+	}
+	return "", fmt.Errorf("%s takes an optional owner/repo, not %s", name, strings.Join(args, " "))
+}
+
+// acct is the account to fetch repo's PRs with.
+func (s *session) acct(repo string) *Account {
+	var fallback *Account
+	if s.account < len(s.cfg.Accounts) {
+		fallback = &s.cfg.Accounts[s.account]
+	}
+	return accountForRepo(s.cfg.Accounts, repo, fallback)
+}
+
+func runView(s *session, args []string) (int, error) {
+	repo, prNumber, err := s.prArgs(args)
+	if err != nil {
+		return exitFailed, err
+	}
+	return runTUI(s, newModel(repo, prNumber, s.interval))
+}
+
+func runSelect(s *session, args []string) (int, error) {
+	if s.opts.workspace {
+		if s.opts.issue != "" || s.opts.notifications {
+			return exitFailed, errors.New("--workspace can't be combined with --issue or --notifications")
+		}
+		repo, remote, err := s.workspaceArgs("select", args)
+		if err != nil {
+			return exitFailed, err
+		}
+		m := newRepoSelectModel(repo, s.interval)
+		m.workspaceRemote = remote
+		return runTUI(s, m)
+	}
+	if s.opts.notifications {
+		if s.opts.issue != "" || len(args) > 0 {
+			return exitFailed, errors.New("--notifications can't be combined with a repo or issue")
+		}
+		m := newSelectModel(s.interval)
+		m.selectNotifications = true
+		return runTUI(s, m)
+	}
+	if s.opts.issue != "" {
+		if len(args) > 0 {
+			return exitFailed, errors.New("--issue names the repo; don't pass one as well")
+		}
+		repo, number, err := s.issueArg()
+		if err != nil {
+			return exitFailed, err
+		}
+		m := newRepoSelectModel(repo, s.interval)
+		m.selectIssue = number
+		return runTUI(s, m)
+	}
+	repo, err := s.repoArg("select", args)
+	if err != nil {
+		return exitFailed, err
+	}
+	if repo == "" {
+		return runTUI(s, newSelectModel(s.interval))
+	}
+	return runTUI(s, newRepoSelectModel(repo, s.interval))
+}
+
+func runDash(s *session, args []string) (int, error) {
+	if len(args) == 1 && args[0] == "-" {
+		s.opts.stdin = true
+		args = nil
+	}
+	switch {
+	case s.opts.stdin:
+		if s.opts.query != "" || s.opts.issue != "" || s.opts.notifications || s.opts.workspace || len(args) > 0 {
+			return exitFailed, errors.New("--stdin can't be combined with a repo, PR, issue, --query, --notifications or --workspace")
+		}
+		if isTerminal(s.stdin) {
+			return exitFailed, errors.New("--stdin expects PRs piped in, e.g. gh search prs --json url | prtop -")
+		}
+		prs, err := readPRList(s.stdin, s.hosts)
+		if err != nil {
+			return exitFailed, err
+		}
+		if len(prs) == 0 {
+			return exitFailed, errors.New("no PRs on stdin")
+		}
+		m := newDashboardModel("", s.interval)
+		m.stdinPRs = prs
+		return runTUI(s, m)
+	case s.opts.workspace:
+		if s.opts.query != "" || s.opts.issue != "" || s.opts.notifications {
+			return exitFailed, errors.New("--workspace can't be combined with --issue, --query or --notifications")
+		}
+		repo, remote, err := s.workspaceArgs("the dashboard", args)
+		if err != nil {
+			return exitFailed, err
+		}
+		m := newDashboardModel(repo, s.interval)
+		m.workspaceRemote = remote
+		return runTUI(s, m)
+	case s.opts.notifications:
+		if s.opts.query != "" || s.opts.issue != "" || len(args) > 0 {
+			return exitFailed, errors.New("--notifications can't be combined with a repo, issue or --query")
+		}
+		m := newDashboardModel("", s.interval)
+		m.selectNotifications = true
+		return runTUI(s, m)
+	case s.opts.query != "":
+		if s.opts.issue != "" || len(args) > 0 {
+			return exitFailed, errors.New("--query can't be combined with a repo, PR or issue; add repo:owner/name to the query instead")
+		}
+		m := newDashboardModel("", s.interval)
+		m.selectQuery = s.opts.query
+		return runTUI(s, m)
+	case s.opts.issue != "":
+		if len(args) > 0 {
+			return exitFailed, errors.New("--issue names the repo; don't pass one as well")
+		}
+		repo, number, err := s.issueArg()
+		if err != nil {
+			return exitFailed, err
+		}
+		m := newDashboardModel(repo, s.interval)
+		m.selectIssue = number
+		return runTUI(s, m)
+	}
+	repo, err := s.repoArg("the dashboard", args)
+	if err != nil {
+		return exitFailed, err
+	}
+	return runTUI(s, newDashboardModel(repo, s.interval))
+}
+
+// runTUI applies the config and the interactive flags to m and runs it.
+func runTUI(s *session, m model) (int, error) {
+	cfg := s.cfg
+	watch, err := openWatchlist(s.opts.watchlist, s.hosts)
+	if err != nil {
+		return exitFailed, err
+	}
+	if s.opts.kiosk {
+		if s.opts.kioskCycle <= 0 {
+			return exitFailed, errors.New("--kiosk-cycle must be positive")
+		}
+		prs, err := kioskPRs(m, watch)
+		if err != nil {
+			return exitFailed, err
+		}
+		m = kioskModel(prs, s.interval, s.opts.kioskCycle)
+	}
+	if m.mergeMethod, err = parseMergeMethod(s.opts.mergeMethod); err != nil {
+		return exitFailed, fmt.Errorf("--merge-method: %w", err)
+	}
+	if m.usesSearch() && ghOverride == nil {
+		if err := requireGhSearch(); err != nil {
+			return exitFailed, err
+		}
+	}
+	m.accounts = cfg.Accounts
+	m.account = s.account
+	m.onChange = s.opts.onChange
+	m.settleWindow = s.settle
+	m.readOnly = s.readOnly || m.kiosk
+	m.configPath = s.opts.config
+	m.prSort, _ = parsePRSort(cfg.Selector.Sort) // validated by loadConfig
+	m.groupByRepo = cfg.Selector.Group
+	m.density, _ = parseDensity(cfg.Display.Density) // validated by loadConfig
+	m.glyphs, _ = parseGlyphSet(cfg.Display.Glyphs)
+	m.celebration, _ = parseCelebration(cfg.Display.Celebrate)
+	m.selectColumns, _ = parseSelectColumns(cfg.Selector.Columns)
+	if m.mode == modeSelecting {
+		m.autoSelect = autoSelectSingle
+		if s.opts.auto {
+			m.autoSelect = autoSelectFirst
+		}
+	}
+	m.columns, _ = parseTable(cfg.Table) // validated by loadConfig
+	m.mine, _ = parseMine(cfg.Filter)
+	m.hiddenProviders, _ = parseHideProviders(cfg.Filter) // validated by loadConfig
+	m.outputRules, _ = parseOutputRules(cfg.Output)
+	m.wrap = cfg.Display.Wrap
+	m.hideSkipped = !cfg.Display.ShowSkipped
+	m.attention = cfg.Display.Attention || s.opts.attention
+	m.mini = s.opts.mini
+	m.inline = s.opts.inline
+	styles, _ := cfg.Colors.statusStyles() // validated by loadConfig
+	stylePass, styleFail, styleRunning = styles[Pass], styles[Fail], styles[Running]
+	styleSkipped, styleCancelled, styleNeutral = styles[Skipped], styles[Cancelled], styles[Neutral]
+	if m.sched != nil {
+		background := defaultBackgroundInterval
+		if cfg.Polling.Background > 0 {
+			background = cfg.Polling.Background
+		}
+		m.sched = newPollScheduler(s.interval, background, cfg.Polling.PRs)
+	}
+
+	store, err := openStateStore(defaultStatePath())
+	if err != nil {
+		fmt.Fprintf(s.stderr, "Warning: %v\n", err)
+		store = &stateStore{}
+	}
+	m.store = store
+	m.watch = watch
+
+	var opts []tea.ProgramOption
+	if !m.inline && (!m.mini || m.mode != modeViewing) {
+		// --mini on a PR draws its three lines in place, not full screen
+		opts = append(opts, tea.WithAltScreen())
+	}
+	if s.opts.stdin {
+		// stdin was the PR list, so read keys from the terminal
+		opts = append(opts, tea.WithInputTTY())
+	}
+	if !isTerminal(s.stdout) {
+		// Only the exit summary goes down the pipe
+		tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		if err != nil {
+			opts = append(opts, tea.WithOutput(os.Stderr))
+		} else {
+			defer tty.Close()
+			opts = append(opts, tea.WithOutput(tty))
+		}
+	}
+	p := tea.NewProgram(m, opts...)
+	if l, err := listenCtl(s.opts.socket); err != nil {
+		fmt.Fprintf(s.stderr, "Warning: %v; 'prtop ctl' won't reach this prtop\n", err)
+	} else {
+		defer l.Close()
+		go serveCtl(l, p.Send)
+	}
+	final, err := p.Run()
+	if err != nil {
+		return exitFailed, err
+	}
+	if fm, ok := final.(model); ok {
+		s.printExitSummary(fm)
+	}
+	return exitOK, nil
+}
+
+// printExitSummary writes what the TUI was showing once it has exited, so
+// it survives the alt screen and can be piped: the viewed PR's counts and
+// failing checks, the same for each PR on the dashboard, or the picker's
+// PRs with their counts.
+func (s *session) printExitSummary(m model) {
+	switch m.mode {
+	case modeViewing:
+		if m.prData != nil {
+			s.printPRSummary(m, m.repo, m.prNumber, m.prData)
+		}
+	case modeDashboard:
+		first := true
+		for _, pr := range m.prs {
+			data := m.dashRows[summaryKey(pr)].data
+			if data == nil {
+				continue
+			}
+			if !first {
+				fmt.Fprintln(s.stdout)
+			}
+			first = false
+			s.printPRSummary(m, pr.Repo, strconv.Itoa(pr.Number), data)
+		}
+	case modeSelecting:
+		for _, pr := range m.prs {
+			line := fmt.Sprintf("%s #%d  %s", pr.Repo, pr.Number, pr.Title)
+			if c := pr.CICounts; c.total() > 0 {
+				line += fmt.Sprintf("  %d passed, %d failed, %d running", c.Pass, c.Fail, c.Running)
+			}
+			if pr.URL != "" {
+				line += "  " + pr.URL
+			}
+			fmt.Fprintln(s.stdout, line)
+		}
+	}
+}
+
+// printPRSummary writes one PR's counts, grade and failing checks for
+// printExitSummary.
+func (s *session) printPRSummary(m model, repo, prNumber string, data *PRData) {
+	glyphs, _ := parseGlyphSet(s.cfg.Display.Glyphs) // validated by loadConfig
+	fmt.Fprintf(s.stdout, "%s #%s  %s\n", repo, prNumber, data.Title)
+	fmt.Fprintln(s.stdout, countsLine(data.Checks))
+	if status, _ := m.rollupFor(repo, prNumber, data); gradeOf(status) != "" {
+		fmt.Fprintln(s.stdout, "Grade: "+gradeOf(status))
+	}
+	acks := m.store.acks(repo, prNumber)
+	for _, c := range data.Checks {
+		if c.Status != Fail {
+			continue
+		}
+		line := glyphs.glyph(Fail) + " " + c.Name
+		if acks[c.Name] {
+			line += " (acknowledged)"
+		}
+		if c.DetailsURL != "" {
+			line += "  " + c.DetailsURL
+		}
+		fmt.Fprintln(s.stdout, line)
+	}
+}
+
+// fetchChecks fetches a PR for the non-interactive commands and rolls its
+// checks up the way --on-change does, leaving out acknowledged failures,
+// ignored checks and those [grade] doesn't count.
+func (s *session) fetchChecks(args []string) (repo, prNumber string, data *PRData, status string, err error) {
+	repo, prNumber, data, checks, acked, err := s.fetchCounted(args)
+	if err != nil {
+		return "", "", nil, "", err
+	}
+	return repo, prNumber, data, rollupStatus(checks, acked), nil
+}
+
+// fetchCounted fetches a PR for the non-interactive commands, returning
+// the checks its rollup counts (not ignored, and counted by [grade]) and
+// which of their failures are acknowledged.
+func (s *session) fetchCounted(args []string) (repo, prNumber string, data *PRData, checks []Check, acked func(Check) bool, err error) {
+	repo, prNumber, err = s.prArgs(args)
+	if err != nil {
+		return "", "", nil, nil, nil, err
+	}
+	data, err = fetchPRData(s.acct(repo), repo, prNumber)
+	if err != nil {
+		return "", "", nil, nil, nil, err
+	}
+	journaling.observe(repo, prNumber, data, time.Now())
+	store, err := openStateStore(defaultStatePath())
+	if err != nil {
+		store = &stateStore{}
+	}
+	var required *requiredChecks
+	if grading.requiredOnly {
+		required = fetchRequiredChecks(s.acct(repo), repo, prNumber)
+		if required.err != nil {
+			logger.Debug("required checks failed", "repo", repo, "pr", prNumber, "err", required.err)
+		}
+	}
+	acks := store.acks(repo, prNumber)
+	checks = gradedChecks(withoutIgnored(data.Checks, store.ignored(repo)), required)
+	return repo, prNumber, data, checks, func(c Check) bool { return c.Status == Fail && acks[c.Name] }, nil
+}
+
+func rollupExitCode(status string) int {
+	switch status {
+	case rollupFailure:
+		return exitFailed
+	case rollupPending:
+		return exitPending
+	}
+	return exitOK
+}
+
+// printChecks writes the PR's checks as plain text, one per line, then
+// the counts and the grade of status.
+func (s *session) printChecks(repo, prNumber string, data *PRData, status string) {
+	glyphs, _ := parseGlyphSet(s.cfg.Display.Glyphs) // validated by loadConfig
+	fmt.Fprintf(s.stdout, "%s #%s  %s\n", repo, prNumber, data.Title)
+	for _, c := range data.Checks {
+		fmt.Fprintf(s.stdout, "%s %-9s %-9s %s\n", glyphs.glyph(c.Status), strings.ToLower(c.Status.String()), c.Duration, c.Name)
+	}
+	fmt.Fprintln(s.stdout, countsLine(data.Checks))
+	if grade := gradeOf(status); grade != "" {
+		fmt.Fprintln(s.stdout, "Grade: "+grade)
+	}
+}
+
+// countsLine is the plain-text count of checks by status, e.g.
+// "3 passed, 1 failed, 0 running, 0 skipped, 1 cancelled".
+func countsLine(checks []Check) string {
+	counts := map[CheckStatus]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+	}
+	line := fmt.Sprintf("%d passed, %d failed, %d running, %d skipped", counts[Pass], counts[Fail], counts[Running], counts[Skipped])
+	for _, st := range []CheckStatus{Cancelled, Neutral} {
+		if counts[st] > 0 {
+			line += fmt.Sprintf(", %d %s", counts[st], strings.ToLower(st.String()))
+		}
+	}
+	return line
+}
+
+func runStatus(s *session, args []string) (int, error) {
+	repo, prNumber, data, status, err := s.fetchChecks(args)
+	if err != nil {
+		return exitFailed, err
+	}
+	s.printChecks(repo, prNumber, data, status)
+	return rollupExitCode(status), nil
+}
+
+// runWait polls until no check is running, reporting progress on stderr,
+// then prints the checks like status does. With --settle, passing checks
+// must also stay settled that long.
+func runWait(s *session, args []string) (int, error) {
+	var settle settler
+	var deadline time.Time
+	if s.opts.timeout > 0 {
+		deadline = time.Now().Add(s.opts.timeout)
+	}
+	progress := ""
+	for {
+		repo, prNumber, data, status, err := s.fetchChecks(args)
+		if err != nil {
+			return exitFailed, err
+		}
+		settled := settle.observe(data.Checks, time.Now(), s.settle)
+		if status != rollupPending && (settled || status != rollupSuccess) {
+			s.printChecks(repo, prNumber, data, status)
+			return rollupExitCode(status), nil
+		}
+		running := 0
+		for _, c := range data.Checks {
+			if c.Status == Running {
+				running++
+			}
+		}
+		p := fmt.Sprintf("%d of %d checks still running", running, len(data.Checks))
+		if status == rollupSuccess {
+			p = fmt.Sprintf("%d checks passed; waiting %s for more to appear", len(data.Checks), s.settle)
+		}
+		if p != progress {
+			progress = p
+			fmt.Fprintln(s.stderr, p)
+		}
+		if !deadline.IsZero() && time.Now().Add(s.interval).After(deadline) {
+			s.printChecks(repo, prNumber, data, status)
+			return exitPending, fmt.Errorf("timed out after %s", s.opts.timeout)
+		}
+		time.Sleep(s.interval)
+	}
+}
+
+// exportCheck is one check in `prtop export` output.
+type exportCheck struct {
+	Name       string    `json:"name"`
+	Workflow   string    `json:"workflow,omitempty"`
+	Status     string    `json:"status"`
+	Duration   string    `json:"duration"`
+	StartedAt  time.Time `json:"startedAt,omitzero"`
+	Completed  bool      `json:"completed"`
+	DetailsURL string    `json:"detailsUrl,omitempty"`
+}
+
+// exportPR is `prtop export` JSON output.
+type exportPR struct {
+	Repo    string        `json:"repo"`
+	Number  int           `json:"number"`
+	Title   string        `json:"title"`
+	URL     string        `json:"url"`
+	HeadSHA string        `json:"headSha"`
+	Status  string        `json:"status"`
+	Grade   string        `json:"grade,omitempty"`
+	Checks  []exportCheck `json:"checks"`
+}
+
+func newExportPR(repo, prNumber string, data *PRData, status string) exportPR {
+	number, _ := strconv.Atoi(prNumber)
+	return exportPR{repo, number, data.Title, data.URL, data.HeadSHA, status, gradeOf(status), exportChecks(data.Checks)}
+}
+
+func exportChecks(checks []Check) []exportCheck {
+	out := make([]exportCheck, len(checks))
+	for i, c := range checks {
+		out[i] = exportCheck{
+			Name: c.Name, Workflow: c.Workflow, Status: strings.ToLower(c.Status.String()),
+			Duration: c.Duration, StartedAt: c.StartedAt, Completed: c.Completed, DetailsURL: c.DetailsURL,
+		}
+	}
+	return out
+}
+
+func runExport(s *session, args []string) (int, error) {
+	format := strings.ToLower(s.opts.format)
+	if format != "json" && format != "csv" {
+		return exitFailed, fmt.Errorf("unknown export format %q (want json or csv)", s.opts.format)
+	}
+	repo, prNumber, data, status, err := s.fetchChecks(args)
+	if err != nil {
+		return exitFailed, err
+	}
+	checks := exportChecks(data.Checks)
+
+	if format == "csv" {
+		w := csv.NewWriter(s.stdout)
+		w.Write([]string{"name", "workflow", "status", "duration", "started_at", "completed", "details_url"})
+		for _, c := range checks {
+			started := ""
+			if !c.StartedAt.IsZero() {
+				started = c.StartedAt.Format(time.RFC3339)
+			}
+			w.Write([]string{c.Name, c.Workflow, c.Status, c.Duration, started, strconv.FormatBool(c.Completed), c.DetailsURL})
+		}
+		w.Flush()
+		return exitOK, w.Error()
+	}
+
+	enc := json.NewEncoder(s.stdout)
+	enc.SetIndent("", "  ")
+	return exitOK, enc.Encode(newExportPR(repo, prNumber, data, status))
+}
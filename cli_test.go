@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runCLI runs prtop with args against an empty config and state directory.
+func runCLI(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	return runCLIStdin(t, "", args...)
+}
+
+// runCLIStdin is runCLI with stdin piped in.
+func runCLIStdin(t *testing.T, stdin string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cache := respCache
+	t.Cleanup(func() {
+		respCache = cache
+		ghOverride = nil
+		retryPolicy = retrier{attempts: 1}
+	})
+	args = append([]string{"--config", filepath.Join(t.TempDir(), "config.toml")}, args...)
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+// ---------------------------------------------------------------------------
+// Argument handling
+// ---------------------------------------------------------------------------
+
+func TestRunArgumentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{"help", []string{"-h"}, 0, "Commands:"},
+		{"command help", []string{"wait", "-h"}, 0, "Usage: prtop wait [flags] PR"},
+		{"too many args", []string{"--demo", "a", "b", "c"}, 1, "Usage: prtop"},
+		{"view without a PR", []string{"--demo", "view"}, 1, "expected a PR"},
+		{"bad PR", []string{"--demo", "status", "nope"}, 1, "invalid PR reference: nope"},
+		{"short interval", []string{"status", "--interval", "1", "o/r#1"}, 1, "--interval: interval 1s is too short"},
+		{"bad format", []string{"--demo", "export", "--format", "xml", "o/r#1"}, 1, `unknown export format "xml"`},
+		{"query with repo", []string{"--demo", "dash", "--query", "is:open", "o/r"}, 1, "--query can't be combined"},
+		{"legacy query with repo", []string{"--demo", "--query", "is:open", "o/r"}, 1, "--query can't be combined"},
+		{"issue with repo", []string{"--demo", "select", "--issue", "o/r#5", "o/r"}, 1, "--issue names the repo"},
+		{"bad issue", []string{"--demo", "issue", "o/r"}, 1, "invalid issue reference"},
+		{"dash with a PR", []string{"--demo", "dash", "o/r#1"}, 1, "the dashboard takes an optional owner/repo"},
+		{"unknown flag", []string{"status", "--nope"}, 1, "flag provided but not defined: -nope"},
+		{"man with args", []string{"man", "x"}, 1, "man takes no arguments"},
+		{"stdin with query", []string{"--demo", "dash", "--stdin", "--query", "is:open"}, 1, "--stdin can't be combined"},
+		{"stdin with repo", []string{"--demo", "--stdin", "o/r"}, 1, "--stdin can't be combined"},
+		{"nothing on stdin", []string{"--demo", "-"}, 1, "no PRs on stdin"},
+		{"bots without a repo", []string{"--demo", "bots"}, 1, "expected a repo"},
+		{"bad merge method", []string{"--demo", "bots", "--merge-method", "ff", "o/r"}, 1, `unknown merge method "ff"`},
+		{"notifications with repo", []string{"--demo", "select", "--notifications", "o/r"}, 1, "--notifications can't be combined"},
+		{"notifications with query", []string{"--demo", "dash", "--notifications", "--query", "is:open"}, 1, "--notifications can't be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(stderr, tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPRArgsBranch(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"--head my-feature": `[{"number":42}]`,
+		"--head gone":       `[]`,
+		"--head main":       `[{"number":3},{"number":8}]`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+	s, _, _ := testSession(t)
+
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{[]string{"o/r", "12"}, "12", ""},
+		{[]string{"o/r", "#12"}, "12", ""},
+		{[]string{"o/r", "my-feature"}, "42", ""},
+		{[]string{"o/r", "gone"}, "", "no open PR in o/r has the head branch gone"},
+		{[]string{"o/r", "main"}, "", "several open PRs in o/r have the head branch main (#3, #8)"},
+		{[]string{"o/r", "#x"}, "", "PR number must be numeric: #x"},
+	}
+	for _, tt := range tests {
+		repo, prNumber, err := s.prArgs(tt.args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("prArgs(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || repo != "o/r" || prNumber != tt.want {
+			t.Errorf("prArgs(%q) = %s, %s, %v; want o/r, %s", tt.args, repo, prNumber, err, tt.want)
+		}
+	}
+}
+
+func TestRunBadStdin(t *testing.T) {
+	code, _, stderr := runCLIStdin(t, "o/r#1\nnot a PR\n", "--demo", "dash", "-")
+	if code != exitFailed || !strings.Contains(stderr, "stdin line 2: not a PR reference: not a PR") {
+		t.Errorf("exit %d, stderr %q", code, stderr)
+	}
+}
+
+func TestRunOffline(t *testing.T) {
+	// Neither needs gh, which isn't installed where the tests run
+	t.Setenv("PATH", "")
+	code, stdout, _ := runCLI(t, "--version")
+	if code != 0 || !strings.HasPrefix(stdout, "prtop ") {
+		t.Errorf("--version: exit %d, stdout %q", code, stdout)
+	}
+	code, stdout, _ = runCLI(t, "man")
+	if code != 0 || !strings.HasPrefix(stdout, ".TH PRTOP 1") {
+		t.Errorf("man: exit %d, stdout starts %.40q", code, stdout)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// status, wait and export
+// ---------------------------------------------------------------------------
+
+func TestRunStatusDemo(t *testing.T) {
+	// Global flags work before and after the command name
+	for _, args := range [][]string{
+		{"--demo", "status", "prtop-demo/webapp#128"},
+		{"status", "--demo", "prtop-demo/webapp", "128"},
+	} {
+		code, stdout, stderr := runCLI(t, args...)
+		if code != exitPending {
+			t.Errorf("%v: exit code = %d, want %d (stderr %q)", args, code, exitPending, stderr)
+		}
+		if !strings.HasPrefix(stdout, "prtop-demo/webapp #128  Add dark mode toggle") ||
+			!strings.Contains(stdout, "⊘ skipped   -         windows-arm (CI)") ||
+			!strings.HasSuffix(stdout, "0 passed, 0 failed, 11 running, 1 skipped\nGrade: AMBER\n") {
+			t.Errorf("%v: stdout:\n%s", args, stdout)
+		}
+	}
+}
+
+const cliFailingPR = `{"title":"Fix it","statusCheckRollup":[
+	{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"FAILURE"},
+	{"__typename":"CheckRun","name":"test","status":"COMPLETED","conclusion":"SUCCESS"}]}`
+
+func testSession(t *testing.T) (*session, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	var out, errOut bytes.Buffer
+	return &session{opts: defaultOptions(), interval: 10 * time.Millisecond, stdout: &out, stderr: &errOut}, &out, &errOut
+}
+
+func TestRunStatusExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   string
+		want int
+	}{
+		{"failed", cliFailingPR, exitFailed},
+		{"passed", `{"statusCheckRollup":[{"__typename":"CheckRun","name":"test","status":"COMPLETED","conclusion":"SUCCESS"}]}`, exitOK},
+		{"no checks", `{"statusCheckRollup":[]}`, exitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCommand = fakeExecByArgs(map[string]string{"pr view 7": tt.pr})
+			t.Cleanup(func() { execCommand = exec.Command })
+			s, _, _ := testSession(t)
+			if code, err := runStatus(s, []string{"o/r#7"}); code != tt.want || err != nil {
+				t.Errorf("runStatus = %d, %v; want %d", code, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("acknowledged failures pass", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{"pr view 7": cliFailingPR})
+		t.Cleanup(func() { execCommand = exec.Command })
+		s, _, _ := testSession(t)
+		store, _ := openStateStore(defaultStatePath())
+		if _, err := store.toggleAck("o/r", "7", "build"); err != nil {
+			t.Fatal(err)
+		}
+		if code, err := runStatus(s, []string{"o/r#7"}); code != exitOK || err != nil {
+			t.Errorf("runStatus = %d, %v; want 0", code, err)
+		}
+	})
+}
+
+func TestPrintExitSummary(t *testing.T) {
+	s, stdout, _ := testSession(t)
+	m := newModel("o/r", "7", 5*time.Second)
+	m.store = &stateStore{}
+	m.prData = &PRData{Title: "Fix it", Checks: []Check{
+		{Name: "build", Status: Fail, DetailsURL: "https://ci/build"},
+		{Name: "lint", Status: Fail},
+		{Name: "test", Status: Pass},
+	}}
+	s.printExitSummary(m)
+	want := "o/r #7  Fix it\n" +
+		"1 passed, 2 failed, 0 running, 0 skipped\n" +
+		"Grade: RED\n" +
+		"✗ build  https://ci/build\n" +
+		"✗ lint\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+
+	// The picker lists its PRs, with counts where they're known
+	stdout.Reset()
+	picker := newSelectModel(5 * time.Second)
+	picker.prs = []PRSummary{
+		{Repo: "o/r", Number: 7, Title: "Fix it", URL: "https://github.com/o/r/pull/7", CICounts: ciCounts{Pass: 1, Fail: 2}},
+		{Repo: "o/r", Number: 8, Title: "Docs"},
+	}
+	s.printExitSummary(picker)
+	want = "o/r #7  Fix it  1 passed, 2 failed, 0 running  https://github.com/o/r/pull/7\n" +
+		"o/r #8  Docs\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("picker summary = %q, want %q", got, want)
+	}
+
+	// The dashboard summarizes each PR it has loaded
+	stdout.Reset()
+	dash := newDashboardModel("o/r", 5*time.Second)
+	dash.store = &stateStore{}
+	dash.prs = picker.prs
+	dash.dashRows[prKey("o/r", "7")] = dashRow{data: m.prData}
+	s.printExitSummary(dash)
+	want = "o/r #7  Fix it\n" +
+		"1 passed, 2 failed, 0 running, 0 skipped\n" +
+		"Grade: RED\n" +
+		"✗ build  https://ci/build\n" +
+		"✗ lint\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("dashboard summary = %q, want %q", got, want)
+	}
+}
+
+func TestRunWait(t *testing.T) {
+	t.Run("finished", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{"pr view 7": cliFailingPR})
+		t.Cleanup(func() { execCommand = exec.Command })
+		s, stdout, _ := testSession(t)
+		if code, err := runWait(s, []string{"o/r#7"}); code != exitFailed || err != nil {
+			t.Errorf("runWait = %d, %v; want 1", code, err)
+		}
+		if !strings.Contains(stdout.String(), "1 passed, 1 failed, 0 running") {
+			t.Errorf("stdout:\n%s", stdout)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{"pr view 7": `{"statusCheckRollup":[
+			{"__typename":"CheckRun","name":"test","status":"IN_PROGRESS"}]}`})
+		t.Cleanup(func() { execCommand = exec.Command })
+		s, _, stderr := testSession(t)
+		s.opts.timeout = 30 * time.Millisecond
+		code, err := runWait(s, []string{"o/r#7"})
+		if code != exitPending || err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Errorf("runWait = %d, %v; want 8 and a timeout", code, err)
+		}
+		if got := stderr.String(); got != "1 of 1 checks still running\n" {
+			t.Errorf("progress = %q, want a single line", got)
+		}
+	})
+}
+
+func TestRunExport(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{"pr view 7": cliFailingPR})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	s, stdout, _ := testSession(t)
+	if code, err := runExport(s, []string{"o/r#7"}); code != exitOK || err != nil {
+		t.Fatalf("runExport = %d, %v", code, err)
+	}
+	var got struct {
+		Repo   string
+		Number int
+		Status string
+		Checks []exportCheck
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if got.Repo != "o/r" || got.Number != 7 || got.Status != rollupFailure || len(got.Checks) != 2 ||
+		got.Checks[0].Name != "build" || got.Checks[0].Status != "fail" {
+		t.Errorf("export = %+v", got)
+	}
+
+	s, stdout, _ = testSession(t)
+	s.opts.format = "csv"
+	if code, err := runExport(s, []string{"o/r", "7"}); code != exitOK || err != nil {
+		t.Fatalf("runExport csv = %d, %v", code, err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || lines[0] != "name,workflow,status,duration,started_at,completed,details_url" ||
+		!strings.HasPrefix(lines[1], "build,,fail,") {
+		t.Errorf("csv:\n%s", stdout)
+	}
+}
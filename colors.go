@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colors overrides the style of each check status, e.g. to swap red and
+// green for colorblind users. Unset fields keep the built-in style.
+type Colors struct {
+	Pass      StatusColor `toml:"pass"`
+	Fail      StatusColor `toml:"fail"`
+	Running   StatusColor `toml:"running"`
+	Skipped   StatusColor `toml:"skipped"`
+	Cancelled StatusColor `toml:"cancelled"`
+	Neutral   StatusColor `toml:"neutral"`
+}
+
+// StatusColor is one status's style. Color is an ANSI color number (0-255),
+// a #rgb or #rrggbb truecolor value, or one of the basic color names.
+type StatusColor struct {
+	Color     string `toml:"color"`
+	Bold      *bool  `toml:"bold"`
+	Underline *bool  `toml:"underline"`
+}
+
+// colorNames maps the basic terminal color names to their ANSI numbers.
+var colorNames = map[string]string{
+	"black": "0", "red": "1", "green": "2", "yellow": "3",
+	"blue": "4", "magenta": "5", "cyan": "6", "white": "7",
+	"bright-black": "8", "bright-red": "9", "bright-green": "10", "bright-yellow": "11",
+	"bright-blue": "12", "bright-magenta": "13", "bright-cyan": "14", "bright-white": "15",
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// parseColor validates a configured color and returns it as lipgloss takes it.
+func parseColor(s string) (lipgloss.Color, error) {
+	if n, ok := colorNames[strings.ToLower(s)]; ok {
+		return lipgloss.Color(n), nil
+	}
+	if hexColor.MatchString(s) {
+		return lipgloss.Color(s), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+	return "", fmt.Errorf("invalid color %q (want 0-255, #rrggbb or a color name)", s)
+}
+
+func (c StatusColor) apply(style lipgloss.Style) (lipgloss.Style, error) {
+	if c.Color != "" {
+		color, err := parseColor(c.Color)
+		if err != nil {
+			return style, err
+		}
+		style = style.Foreground(color)
+	}
+	if c.Bold != nil {
+		style = style.Bold(*c.Bold)
+	}
+	if c.Underline != nil {
+		style = style.Underline(*c.Underline)
+	}
+	return style, nil
+}
+
+// statusStyles returns each status's style with c's overrides applied on
+// top of the current ones.
+func (c Colors) statusStyles() (map[CheckStatus]lipgloss.Style, error) {
+	styles := map[CheckStatus]lipgloss.Style{}
+	for _, s := range []struct {
+		name   string
+		status CheckStatus
+		color  StatusColor
+	}{
+		{"pass", Pass, c.Pass},
+		{"fail", Fail, c.Fail},
+		{"running", Running, c.Running},
+		{"skipped", Skipped, c.Skipped},
+		{"cancelled", Cancelled, c.Cancelled},
+		{"neutral", Neutral, c.Neutral},
+	} {
+		style, err := s.color.apply(statusStyle(s.status))
+		if err != nil {
+			return nil, fmt.Errorf("colors.%s: %w", s.name, err)
+		}
+		styles[s.status] = style
+	}
+	return styles, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ---------------------------------------------------------------------------
+// parseColor
+// ---------------------------------------------------------------------------
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    lipgloss.Color
+		wantErr bool
+	}{
+		{"34", "34", false},
+		{"255", "255", false},
+		{"#ff8700", "#ff8700", false},
+		{"#0af", "#0af", false},
+		{"Blue", "4", false},
+		{"bright-cyan", "14", false},
+		{"256", "", true},
+		{"-1", "", true},
+		{"#12345", "", true},
+		{"teal", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseColor(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseColor(%q) = %q, %v; want %q, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Colors.statusStyles
+// ---------------------------------------------------------------------------
+
+func TestStatusStyles(t *testing.T) {
+	t.Run("overrides only what is set", func(t *testing.T) {
+		off, on := false, true
+		c := Colors{
+			Pass: StatusColor{Color: "#0000ff", Bold: &off},
+			Fail: StatusColor{Color: "208", Underline: &on},
+		}
+		styles, err := c.statusStyles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pass, fail := styles[Pass], styles[Fail]
+		if pass.GetForeground() != lipgloss.Color("#0000ff") || pass.GetBold() {
+			t.Errorf("pass = %v bold=%v", pass.GetForeground(), pass.GetBold())
+		}
+		if fail.GetForeground() != lipgloss.Color("208") || !fail.GetBold() || !fail.GetUnderline() {
+			t.Errorf("fail = %v bold=%v underline=%v", fail.GetForeground(), fail.GetBold(), fail.GetUnderline())
+		}
+		if styles[Running].GetForeground() != styleRunning.GetForeground() || styles[Cancelled].GetForeground() != styleCancelled.GetForeground() {
+			t.Error("unset statuses changed")
+		}
+	})
+
+	t.Run("invalid color names the status", func(t *testing.T) {
+		_, err := Colors{Running: StatusColor{Color: "orange-ish"}}.statusStyles()
+		if err == nil || err.Error() != `colors.running: invalid color "orange-ish" (want 0-255, #rrggbb or a color name)` {
+			t.Errorf("err = %v", err)
+		}
+	})
+}
+
+func TestLoadConfigColors(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		data := "[colors]\npass = { color = \"blue\" }\n\n[colors.fail]\ncolor = \"#ff8700\"\nunderline = true\n"
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Colors.Pass.Color != "blue" || cfg.Colors.Fail.Color != "#ff8700" ||
+			cfg.Colors.Fail.Underline == nil || !*cfg.Colors.Fail.Underline {
+			t.Errorf("Colors = %+v", cfg.Colors)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte("[colors.fail]\ncolor = \"#zzz\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Table sets the check table's columns. Columns lists them in order, mixing
+// the built-in status, duration and name columns with custom ones; leaving
+// a column out hides it. Custom columns render a Go template over a Check.
+type Table struct {
+	Columns []string      `toml:"columns"`
+	Custom  []TableColumn `toml:"column"`
+}
+
+// TableColumn is a custom column, e.g.
+//
+//	[[table.column]]
+//	name = "started"
+//	header = "STARTED"
+//	template = "{{clock .StartedAt}}"
+//	width = 9
+type TableColumn struct {
+	Name     string `toml:"name"`
+	Header   string `toml:"header"`
+	Template string `toml:"template"`
+	Width    int    `toml:"width"`
+}
+
+// The built-in columns.
+const (
+	columnStatus   = "status"
+	columnDuration = "duration"
+	columnName     = "name"
+)
+
+var defaultColumns = []string{columnStatus, columnDuration, columnName}
+
+// builtinTemplates are template columns that come with prtop, so listing
+// one in columns is enough. A [[table.column]] of the same name replaces
+// it.
+var builtinTemplates = []TableColumn{
+	{Name: "provider", Header: "PROVIDER", Template: "{{.Provider}}"},
+}
+
+// tableColumn is a configured column ready to render. tmpl is nil for the
+// built-in columns.
+type tableColumn struct {
+	name   string
+	header string
+	width  int
+	tmpl   *template.Template
+}
+
+// columnFuncs are available to column templates.
+var columnFuncs = template.FuncMap{
+	// clock is the local time of day, or "" for a zero time
+	"clock": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Local().Format("15:04:05")
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseTable validates the [table] config and returns its columns in
+// order. Templates are tried on an empty Check so a misspelled field is
+// reported at startup rather than in every row.
+func parseTable(t Table) ([]tableColumn, error) {
+	custom := map[string]tableColumn{}
+	defs := t.Custom
+	for _, b := range builtinTemplates {
+		if !slices.ContainsFunc(t.Custom, func(c TableColumn) bool { return c.Name == b.Name }) {
+			defs = append(slices.Clip(defs), b)
+		}
+	}
+	for _, c := range defs {
+		switch {
+		case c.Name == "":
+			return nil, fmt.Errorf("table.column: missing name")
+		case slices.Contains(defaultColumns, c.Name):
+			return nil, fmt.Errorf("table.column %q: name is a built-in column", c.Name)
+		case custom[c.Name].name != "":
+			return nil, fmt.Errorf("table.column %q: defined twice", c.Name)
+		case c.Template == "":
+			return nil, fmt.Errorf("table.column %q: missing template", c.Name)
+		}
+		tmpl, err := template.New(c.Name).Funcs(columnFuncs).Parse(c.Template)
+		if err == nil {
+			err = tmpl.Execute(&strings.Builder{}, Check{})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("table.column %q: %w", c.Name, err)
+		}
+		header := c.Header
+		if header == "" {
+			header = strings.ToUpper(c.Name)
+		}
+		width := c.Width
+		if width == 0 {
+			width = 12
+		}
+		// Room for a gap before the next column
+		width = max(width, len(header)+1)
+		custom[c.Name] = tableColumn{name: c.Name, header: header, width: width, tmpl: tmpl}
+	}
+
+	names := t.Columns
+	if len(names) == 0 {
+		names = defaultColumns
+	}
+	var cols []tableColumn
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("table.columns: %q listed twice", name)
+		}
+		seen[name] = true
+		if slices.Contains(defaultColumns, name) {
+			cols = append(cols, tableColumn{name: name})
+			continue
+		}
+		c, ok := custom[name]
+		if !ok {
+			return nil, fmt.Errorf("table.columns: unknown column %q (want status, duration, name, provider or a [[table.column]])", name)
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// render runs a custom column's template for c. An error shows in the cell
+// instead of breaking the table.
+func (c tableColumn) render(check Check) string {
+	var b strings.Builder
+	if err := c.tmpl.Execute(&b, check); err != nil {
+		return "!" + err.Error()
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// elide shortens s to width columns, ending in "…" when it is cut.
+func elide(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:max(width, 0)])
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTable(t *testing.T) {
+	cols, err := parseTable(Table{})
+	if err != nil || len(cols) != 3 || cols[0].name != columnStatus || cols[2].name != columnName {
+		t.Fatalf("default columns = %+v, %v", cols, err)
+	}
+
+	cols, err = parseTable(Table{
+		Columns: []string{"workflow", "name"},
+		Custom:  []TableColumn{{Name: "workflow", Template: "{{.Workflow}}"}},
+	})
+	if err != nil || len(cols) != 2 || cols[0].header != "WORKFLOW" || cols[0].width != 12 {
+		t.Errorf("custom columns = %+v, %v", cols, err)
+	}
+
+	for name, table := range map[string]Table{
+		"unknown column": {Columns: []string{"status", "owner"}},
+		"listed twice":   {Columns: []string{"name", "name"}},
+		"built-in name":  {Custom: []TableColumn{{Name: "status", Template: "x"}}},
+		"no template":    {Custom: []TableColumn{{Name: "wf"}}},
+		"bad template":   {Custom: []TableColumn{{Name: "wf", Template: "{{.Workflow"}}},
+		"misspelt field": {Custom: []TableColumn{{Name: "wf", Template: "{{.Workflw}}"}}},
+		"defined twice":  {Custom: []TableColumn{{Name: "wf", Template: "x"}, {Name: "wf", Template: "y"}}},
+		"missing name":   {Custom: []TableColumn{{Template: "x"}}},
+	} {
+		if _, err := parseTable(table); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestCustomColumns(t *testing.T) {
+	started := time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 80, 20
+	m.glyphs = glyphsNone
+	m.prData = &PRData{Title: "t", Checks: []Check{
+		{Name: "build (CI)", JobName: "build", Workflow: "CI", Status: Pass, Duration: "1m0s", Completed: true, StartedAt: started},
+	}}
+	cols, err := parseTable(Table{
+		Columns: []string{"status", "workflow", "started", "name"},
+		Custom: []TableColumn{
+			{Name: "workflow", Header: "WF", Template: "{{.Workflow}}", Width: 6},
+			{Name: "started", Template: "{{clock .StartedAt}}", Width: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.columns = cols
+
+	out := m.View()
+	if !strings.Contains(out, "  STATUS  WF    STARTED   NAME") {
+		t.Errorf("header:\n%s", out)
+	}
+	if !strings.Contains(out, "> PASS    CI    09:30:00  build (CI)") {
+		t.Errorf("row:\n%s", out)
+	}
+	if strings.Contains(out, "1m0s") {
+		t.Errorf("duration column should be hidden:\n%s", out)
+	}
+}
+
+func TestElide(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"build", 10, "build"},
+		{"build", 5, "build"},
+		{"build (CI)", 6, "build…"},
+		{"build", 1, "b"},
+		{"build", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := elide(tt.s, tt.width); got != tt.want {
+			t.Errorf("elide(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestColumnAutosize(t *testing.T) {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.glyphs = glyphsNone
+	m.prData = &PRData{Checks: []Check{
+		{Name: "integration tests (Nightly)", Status: Cancelled, Duration: "1h23m45s", Completed: true},
+		{Name: "lint", Status: Pass, Duration: "9s", Completed: true},
+	}}
+
+	// Wide enough: columns fit their longest cell
+	lines := m.viewCheckTable(60, 5)
+	if lines[0] != "  STATUS     DURATION  NAME" || lines[1] != "> CANCELLED  1h23m45s  integration tests (Nightly)" {
+		t.Errorf("wide table = %q", lines)
+	}
+
+	// Narrow: the name keeps its share, the others give up space and elide
+	lines = m.viewCheckTable(30, 5)
+	if lines[1] != "> CANCE… 1h23m45s integration…" {
+		t.Errorf("narrow table = %q", lines)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// headCommit is the PR's head commit as the header describes it: which
+// commit the checks belong to, so results from an older push aren't
+// mistaken for the current one.
+type headCommit struct {
+	repo     string
+	sha      string
+	headline string // first line of the commit message
+	author   string // login, or the git author name without a GitHub account
+	err      error
+}
+
+type headCommitMsg struct {
+	commit *headCommit
+}
+
+const headCommitQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) { ... on Commit { messageHeadline author { name user { login } } } }
+  }
+}`
+
+func fetchHeadCommit(acct *Account, repo, sha string) *headCommit {
+	c := &headCommit{repo: repo, sha: sha}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+headCommitQuery,
+		"-F", "owner="+owner, "-F", "name="+name, "-F", "oid="+sha)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Object *struct {
+					MessageHeadline string `json:"messageHeadline"`
+					Author          struct {
+						Name string `json:"name"`
+						User *struct {
+							Login string `json:"login"`
+						} `json:"user"`
+					} `json:"author"`
+				} `json:"object"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		c.err = fmt.Errorf("failed to parse commit: %w", err)
+		return c
+	}
+	obj := resp.Data.Repository.Object
+	if obj == nil {
+		c.err = fmt.Errorf("commit %s not found", shortSHA(sha))
+		return c
+	}
+	c.headline = obj.MessageHeadline
+	c.author = obj.Author.Name
+	if obj.Author.User != nil && obj.Author.User.Login != "" {
+		c.author = obj.Author.User.Login
+	}
+	return c
+}
+
+// headCommitCmd fetches the head commit's message and author once per
+// SHA, so a new push updates the header and other refreshes cost nothing.
+func (m model) headCommitCmd() (model, tea.Cmd) {
+	if m.prData == nil || m.prData.HeadSHA == "" || m.commitAsked == prKey(m.repo, m.prData.HeadSHA) {
+		return m, nil
+	}
+	repo, sha := m.repo, m.prData.HeadSHA
+	m.commitAsked = prKey(repo, sha)
+	acct := m.repoAccount(repo)
+	return m, func() tea.Msg {
+		return headCommitMsg{commit: fetchHeadCommit(acct, repo, sha)}
+	}
+}
+
+// commitHeadlineWidth caps the commit message so a long one doesn't push
+// the PR's tasks, review and URL off the branch line.
+const commitHeadlineWidth = 50
+
+// commitInfo is the header's "Commit: abc1234 Fix the flake (alice)", or
+// just the short SHA until the message is known.
+func (m model) commitInfo() string {
+	if m.prData == nil || m.prData.HeadSHA == "" {
+		return ""
+	}
+	info := "Commit: " + shortSHA(m.prData.HeadSHA)
+	if c := m.headCommit; c != nil && c.err == nil && c.repo == m.repo && c.sha == m.prData.HeadSHA {
+		info += " " + truncate(c.headline, commitHeadlineWidth)
+		if c.author != "" {
+			info += " (" + c.author + ")"
+		}
+	}
+	return info
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeadCommit(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"oid=abc1234def graphql": `{"data":{"repository":{"object":{"messageHeadline":"Fix the flaky upload test",
+			"author":{"name":"Alice Example","user":{"login":"alice"}}}}}}`,
+		"oid=fed9876cba graphql": `{"data":{"repository":{"object":{"messageHeadline":"Bump deps",
+			"author":{"name":"Build Bot","user":null}}}}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 160, 20
+	m.prData = &PRData{HeadSHA: "abc1234def", HeadRefName: "fix-upload"}
+	if got := m.commitInfo(); got != "Commit: abc1234" {
+		t.Errorf("before the fetch: %q", got)
+	}
+
+	m, cmd := m.headCommitCmd()
+	if cmd == nil {
+		t.Fatal("no fetch for a new head commit")
+	}
+	if _, again := m.headCommitCmd(); again != nil {
+		t.Error("refetched the same commit")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+	if got := m.commitInfo(); got != "Commit: abc1234 Fix the flaky upload test (alice)" {
+		t.Errorf("after the fetch: %q", got)
+	}
+	if !strings.Contains(m.View(), "Branch: fix-upload    Commit: abc1234 Fix the flaky upload test (alice)") {
+		t.Errorf("header:\n%s", m.View())
+	}
+
+	// A new push: the old commit's details go away until the new one loads,
+	// and a late reply for the old commit is ignored.
+	stale := cmd
+	m.prData = &PRData{HeadSHA: "fed9876cba", HeadRefName: "fix-upload"}
+	if got := m.commitInfo(); got != "Commit: fed9876" {
+		t.Errorf("after a push: %q", got)
+	}
+	m, cmd = m.headCommitCmd()
+	if cmd == nil {
+		t.Fatal("no fetch after a push")
+	}
+	updated, _ = m.Update(stale())
+	m = updated.(model)
+	if got := m.commitInfo(); got != "Commit: fed9876" {
+		t.Errorf("stale reply applied: %q", got)
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(model)
+	if got := m.commitInfo(); got != "Commit: fed9876 Bump deps (Build Bot)" {
+		t.Errorf("author without an account: %q", got)
+	}
+}
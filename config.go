@@ -0,0 +1,305 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the user configuration loaded from config.toml.
+type Config struct {
+	// ReadOnly is the default for --read-only
+	ReadOnly bool      `toml:"read_only"`
+	Accounts []Account `toml:"accounts"`
+	Polling  Polling   `toml:"polling"`
+	Selector Selector  `toml:"selector"`
+	Display  Display   `toml:"display"`
+	Filter   Filter    `toml:"filter"`
+	Colors   Colors    `toml:"colors"`
+	Table    Table     `toml:"table"`
+	API      API       `toml:"api"`
+	Output   Output    `toml:"output"`
+	Grade    Grade     `toml:"grade"`
+	// Status overrides how raw states map to statuses (see StatusOverride)
+	Status []StatusOverride `toml:"status"`
+}
+
+// Display sets viewing mode's initial layout: Density is normal, compact
+// or comfy, and Glyphs picks the status symbols (unicode, nerd, ascii or
+// none to drop the glyph column). Attention blinks a banner the first time
+// a failure appears. LumpConclusions counts cancelled and neutral checks
+// as skipped, as prtop did before they had statuses of their own. Wrap
+// moves the cursor from the last row to the first and back. ShowSkipped
+// lists skipped checks, which are hidden by default. Celebrate is what
+// to show when the checks turn all green: off, banner or confetti.
+type Display struct {
+	Density         string `toml:"density"`
+	Glyphs          string `toml:"glyphs"`
+	Attention       bool   `toml:"attention"`
+	LumpConclusions bool   `toml:"lump_conclusions"`
+	Wrap            bool   `toml:"wrap"`
+	ShowSkipped     bool   `toml:"show_skipped"`
+	Celebrate       string `toml:"celebrate"`
+}
+
+// Selector sets the PR picker's initial order: Sort is one of updated,
+// created, ci or repo, and Group puts each repo's PRs under a header.
+// Columns picks the details shown on each row (see parseSelectColumns).
+type Selector struct {
+	Sort    string   `toml:"sort"`
+	Group   bool     `toml:"group"`
+	Columns []string `toml:"columns"`
+}
+
+// Polling tunes how often PRs are fetched. Interval is the default for
+// --interval; the selected PR always uses it, and Background applies to the
+// dashboard's other PRs. PRs maps "owner/repo#123" to an interval that
+// overrides both. Attempts and Backoff control retrying gh after a
+// network error or a GitHub 5xx (see parseRetry). Settle is the default
+// for --settle.
+type Polling struct {
+	Interval   time.Duration            `toml:"interval"`
+	Background time.Duration            `toml:"background"`
+	PRs        map[string]time.Duration `toml:"prs"`
+	Attempts   int                      `toml:"attempts"`
+	Backoff    time.Duration            `toml:"backoff"`
+	Settle     time.Duration            `toml:"settle"`
+}
+
+// API configures the connection prtop makes to GitHub itself when gh isn't
+// installed (see api.go): CAFile is a PEM bundle trusted on top of the
+// system's, for a GitHub Enterprise host or a proxy with a private CA, and
+// InsecureSkipVerify turns off certificate checks altogether. Proxies come
+// from HTTPS_PROXY and NO_PROXY.
+type API struct {
+	CAFile             string `toml:"ca_file"`
+	InsecureSkipVerify bool   `toml:"insecure_skip_verify"`
+}
+
+// Output configures the details pane's check run output: Renderers maps a
+// renderer (atlantis, terraform or markdown) to globs of the check names
+// it's used for, ahead of recognizing them by their app and content.
+type Output struct {
+	Renderers map[string][]string `toml:"renderers"`
+}
+
+// Account is a gh host/user pair. Repos whose owner appears in Owners are
+// fetched with this account's credentials.
+type Account struct {
+	Name   string   `toml:"name"`
+	Host   string   `toml:"host"`
+	User   string   `toml:"user"`
+	Owners []string `toml:"owners"`
+}
+
+// configDir returns the prtop config directory, honoring XDG_CONFIG_HOME.
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "prtop")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "prtop")
+}
+
+func defaultConfigPath() string {
+	dir := configDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.toml")
+}
+
+// loadConfig reads the config file at path. A missing file is not an error.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	if path == "" {
+		return cfg, nil
+	}
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("failed to load config %s: %w", path, err)
+	}
+	if _, err := parsePRSort(cfg.Selector.Sort); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseSelectColumns(cfg.Selector.Columns); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseDensity(cfg.Display.Density); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseGlyphSet(cfg.Display.Glyphs); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseCelebration(cfg.Display.Celebrate); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Polling.Interval != 0 {
+		if err := checkInterval(cfg.Polling.Interval); err != nil {
+			return Config{}, fmt.Errorf("invalid config %s: polling.%w", path, err)
+		}
+	}
+	if cfg.Polling.Settle < 0 {
+		return Config{}, fmt.Errorf("invalid config %s: polling.settle must not be negative", path)
+	}
+	if _, err := parseRetry(cfg.Polling); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := cfg.Colors.statusStyles(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseMine(cfg.Filter); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseHideProviders(cfg.Filter); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseTable(cfg.Table); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := apiTransport(cfg.API); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseOutputRules(cfg.Output); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseStatusOverrides(cfg.Status); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if _, err := parseGrade(cfg.Grade); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	for i, a := range cfg.Accounts {
+		if a.Host == "" {
+			cfg.Accounts[i].Host = "github.com"
+		}
+		if a.Name == "" {
+			cfg.Accounts[i].Name = cfg.Accounts[i].Host
+			if a.User != "" {
+				cfg.Accounts[i].Name = a.User + "@" + cfg.Accounts[i].Host
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// findAccount returns the index of the account with the given name, or -1.
+func findAccount(accounts []Account, name string) int {
+	for i, a := range accounts {
+		if strings.EqualFold(a.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// accountForRepo picks the account that owns repo. Repos given as
+// HOST/OWNER/REPO match on host; otherwise the owner is matched against each
+// account's Owners list. When nothing matches, fallback is returned.
+func accountForRepo(accounts []Account, repo string, fallback *Account) *Account {
+	parts := strings.Split(repo, "/")
+	var host, owner string
+	switch len(parts) {
+	case 2:
+		owner = parts[0]
+	case 3:
+		host, owner = parts[0], parts[1]
+	default:
+		return fallback
+	}
+	for i, a := range accounts {
+		if host != "" && !strings.EqualFold(a.Host, host) {
+			continue
+		}
+		for _, o := range a.Owners {
+			if strings.EqualFold(o, owner) {
+				return &accounts[i]
+			}
+		}
+	}
+	if host != "" {
+		if fallback != nil && strings.EqualFold(fallback.Host, host) {
+			return fallback
+		}
+		for i, a := range accounts {
+			if strings.EqualFold(a.Host, host) {
+				return &accounts[i]
+			}
+		}
+	}
+	return fallback
+}
+
+var tableHeader = regexp.MustCompile(`^\s*\[\[?\s*([^\]]*?)\s*\]\]?`)
+
+// saveConfigValue sets key in [table] of the config file at path to value,
+// a TOML literal such as `"5s"` or `true`. It edits the file in place so
+// the rest of it (comments included) is kept, adding the table or the key
+// when they are missing.
+func saveConfigValue(path, table, key, value string) error {
+	if path == "" {
+		return errors.New("no config file")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	setting := key + " = " + value
+	keyLine := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+
+	// Find the table: its header and where the next table starts
+	start, end := -1, len(lines)
+	for i, l := range lines {
+		if h := tableHeader.FindStringSubmatch(l); h != nil {
+			if start >= 0 {
+				end = i
+				break
+			}
+			if h[1] == table {
+				start = i
+			}
+		}
+	}
+	replaced := false
+	for i := start + 1; start >= 0 && i < end; i++ {
+		if keyLine.MatchString(lines[i]) {
+			lines[i] = setting
+			replaced = true
+			break
+		}
+	}
+	switch {
+	case start < 0:
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+table+"]", setting)
+	case !replaced:
+		lines = append(lines[:start+1], append([]string{setting}, lines[start+1:]...)...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
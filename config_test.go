@@ -0,0 +1,195 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// loadConfig
+// ---------------------------------------------------------------------------
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("missing file is empty config", func(t *testing.T) {
+		cfg, err := loadConfig(filepath.Join(t.TempDir(), "nope.toml"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Accounts) != 0 {
+			t.Errorf("got %d accounts, want 0", len(cfg.Accounts))
+		}
+	})
+
+	t.Run("accounts with defaults", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		data := `
+[[accounts]]
+name = "personal"
+owners = ["me"]
+
+[[accounts]]
+host = "ghe.corp.com"
+user = "me-corp"
+owners = ["corp"]
+`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Accounts) != 2 {
+			t.Fatalf("got %d accounts, want 2", len(cfg.Accounts))
+		}
+		if cfg.Accounts[0].Host != "github.com" {
+			t.Errorf("Accounts[0].Host = %q, want github.com", cfg.Accounts[0].Host)
+		}
+		if cfg.Accounts[1].Name != "me-corp@ghe.corp.com" {
+			t.Errorf("Accounts[1].Name = %q, want %q", cfg.Accounts[1].Name, "me-corp@ghe.corp.com")
+		}
+	})
+
+	t.Run("polling intervals", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		data := `
+[polling]
+background = "2m"
+
+[polling.prs]
+"o/r#7" = "15s"
+`
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Polling.Background != 2*time.Minute {
+			t.Errorf("Background = %s, want 2m", cfg.Polling.Background)
+		}
+		if got := cfg.Polling.PRs["o/r#7"]; got != 15*time.Second {
+			t.Errorf(`PRs["o/r#7"] = %s, want 15s`, got)
+		}
+	})
+
+	t.Run("unknown selector sort", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte("[selector]\nsort = \"stars\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("unknown display density", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte("[display]\ndensity = \"tiny\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("unknown selector column", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte("[selector]\ncolumns = [\"author\", \"labels\"]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadConfig(path)
+		if err == nil || !strings.Contains(err.Error(), `selector.columns: unknown column "labels"`) {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("interval below the minimum", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte("[polling]\ninterval = \"1s\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadConfig(path)
+		if err == nil || !strings.Contains(err.Error(), "polling.interval 1s is too short") {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("api CA file that isn't PEM", func(t *testing.T) {
+		dir := t.TempDir()
+		ca := filepath.Join(dir, "ca.pem")
+		if err := os.WriteFile(ca, []byte("not a certificate"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "config.toml")
+		if err := os.WriteFile(path, []byte("[api]\nca_file = \""+ca+"\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadConfig(path)
+		if err == nil || !strings.Contains(err.Error(), "api.ca_file: no PEM certificates") {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("invalid TOML", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte("[[accounts"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadConfig(path); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// account routing
+// ---------------------------------------------------------------------------
+
+func TestAccountForRepo(t *testing.T) {
+	accounts := []Account{
+		{Name: "personal", Host: "github.com", Owners: []string{"me"}},
+		{Name: "work", Host: "ghe.corp.com", Owners: []string{"corp"}},
+	}
+	fallback := &accounts[0]
+
+	tests := []struct {
+		name string
+		repo string
+		want string
+	}{
+		{"owner match", "corp/api", "work"},
+		{"owner match case-insensitive", "ME/dotfiles", "personal"},
+		{"no match uses fallback", "other/repo", "personal"},
+		{"host prefix", "ghe.corp.com/team/svc", "work"},
+		{"malformed repo", "nonsense", "personal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := accountForRepo(accounts, tt.repo, fallback)
+			if got == nil || got.Name != tt.want {
+				t.Errorf("accountForRepo(%q) = %v, want %q", tt.repo, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no accounts returns nil", func(t *testing.T) {
+		if got := accountForRepo(nil, "o/r", nil); got != nil {
+			t.Errorf("got %v, want nil", got)
+		}
+	})
+}
+
+func TestFindAccount(t *testing.T) {
+	accounts := []Account{{Name: "personal"}, {Name: "work"}}
+	if got := findAccount(accounts, "Work"); got != 1 {
+		t.Errorf("findAccount(Work) = %d, want 1", got)
+	}
+	if got := findAccount(accounts, "missing"); got != -1 {
+		t.Errorf("findAccount(missing) = %d, want -1", got)
+	}
+}
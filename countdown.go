@@ -0,0 +1,21 @@
+package main
+
+import "time"
+
+// refreshStatus is the footer's refresh state: the interval and the time
+// left until the next data tick, or "paused".
+func (m model) refreshStatus(now time.Time) string {
+	if m.paused {
+		return "paused"
+	}
+	s := formatInterval(m.interval)
+	if m.nextRefresh.IsZero() {
+		return s
+	}
+	left := m.nextRefresh.Sub(now)
+	if left <= 0 {
+		return s + " (refreshing)"
+	}
+	// Round up so the count never shows 0s while still waiting
+	return s + " (next in " + formatInterval((left + time.Second - 1).Truncate(time.Second)) + ")"
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefreshStatus(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 200, 20
+
+	m.nextRefresh = now.Add(2500 * time.Millisecond)
+	if got := m.refreshStatus(now); got != "5s (next in 3s)" {
+		t.Errorf("mid-interval = %q", got)
+	}
+	m.nextRefresh = now.Add(3 * time.Second)
+	if got := m.refreshStatus(now); got != "5s (next in 3s)" {
+		t.Errorf("whole seconds = %q", got)
+	}
+	m.nextRefresh = now
+	if got := m.refreshStatus(now); got != "5s (refreshing)" {
+		t.Errorf("due = %q", got)
+	}
+	m.paused = true
+	if got := m.refreshStatus(now); got != "paused" {
+		t.Errorf("paused = %q", got)
+	}
+
+	// The data tick moves the countdown on
+	m.paused = false
+	updated, cmd := m.Update(tickMsg(now))
+	m = updated.(model)
+	if !m.nextRefresh.Equal(now.Add(5*time.Second)) || cmd == nil {
+		t.Errorf("after a tick: next %v, cmd %v", m.nextRefresh, cmd)
+	}
+	m.prData = &PRData{}
+	m.nextRefresh = time.Now().Add(4 * time.Second)
+	if !strings.Contains(m.View(), "Refresh: 5s (next in 4s) |") {
+		t.Errorf("footer:\n%s", m.View())
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// coverage is what a coverage status context reports.
+type coverage struct {
+	Percent  float64
+	Delta    float64 // change against the base, when HasDelta
+	HasDelta bool
+	Patch    bool // coverage of the PR's diff rather than the whole project
+}
+
+var (
+	// Codecov: "85.32% (+0.12%) compared to abc1234"
+	codecovProject = regexp.MustCompile(`^([\d.]+)% \(([+-]?[\d.]+)%\) compared to`)
+	// Codecov: "90.00% of diff hit (target 80.00%)"
+	codecovPatch = regexp.MustCompile(`^([\d.]+)% of diff hit`)
+	// Coveralls: "Coverage increased (+0.2%) to 85.3%"
+	coverallsChange = regexp.MustCompile(`(?i)coverage (?:increased|decreased) \(([+-]?[\d.]+)%\) to ([\d.]+)%`)
+	// Coveralls: "Coverage remained the same at 85.3%"
+	coverallsSame = regexp.MustCompile(`(?i)remained the same at ([\d.]+)%`)
+	// Anything else named like coverage: "85.3%" with an optional "(+0.2%)"
+	anyCoverage = regexp.MustCompile(`([\d.]+)%(?: \(([+-][\d.]+)%\))?`)
+)
+
+// isCoverageCheck reports whether c looks like a coverage service's status.
+func isCoverageCheck(c Check) bool {
+	name := strings.ToLower(c.JobName)
+	return strings.Contains(name, "codecov") || strings.Contains(name, "coveralls") ||
+		strings.Contains(name, "coverage")
+}
+
+// parseCoverage reads the coverage percentage and delta out of a coverage
+// check's description.
+func parseCoverage(c Check) (coverage, bool) {
+	if c.Description == "" || !isCoverageCheck(c) {
+		return coverage{}, false
+	}
+	num := func(s string) float64 {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	d := c.Description
+	if m := codecovProject.FindStringSubmatch(d); m != nil {
+		return coverage{Percent: num(m[1]), Delta: num(m[2]), HasDelta: true}, true
+	}
+	if m := codecovPatch.FindStringSubmatch(d); m != nil {
+		return coverage{Percent: num(m[1]), Patch: true}, true
+	}
+	if m := coverallsChange.FindStringSubmatch(d); m != nil {
+		return coverage{Percent: num(m[2]), Delta: num(m[1]), HasDelta: true}, true
+	}
+	if m := coverallsSame.FindStringSubmatch(d); m != nil {
+		return coverage{Percent: num(m[1]), HasDelta: true}, true
+	}
+	if m := anyCoverage.FindStringSubmatch(d); m != nil {
+		cov := coverage{Percent: num(m[1]), Patch: strings.Contains(strings.ToLower(c.JobName), "patch")}
+		if m[2] != "" {
+			cov.Delta, cov.HasDelta = num(m[2]), true
+		}
+		return cov, true
+	}
+	return coverage{}, false
+}
+
+// String renders the coverage as "85.32% (+0.12%)", coloring a drop.
+func (c coverage) String() string {
+	s := fmt.Sprintf("%.2f%%", c.Percent)
+	if c.HasDelta {
+		delta := fmt.Sprintf("(%+.2f%%)", c.Delta)
+		switch {
+		case c.Delta < 0:
+			delta = styleFail.Render(delta)
+		case c.Delta > 0:
+			delta = stylePass.Render(delta)
+		}
+		s += " " + delta
+	}
+	if c.Patch {
+		s += " of diff"
+	}
+	return s
+}
+
+// prCoverage is the PR's project coverage for the summary line, taken from
+// the first coverage check that reports one (patch coverage if that is all
+// there is).
+func (m model) prCoverage() (coverage, bool) {
+	if m.prData == nil {
+		return coverage{}, false
+	}
+	var patch coverage
+	found := false
+	for _, c := range m.prData.Checks {
+		cov, ok := parseCoverage(c)
+		switch {
+		case ok && !cov.Patch:
+			return cov, true
+		case ok && !found:
+			patch, found = cov, true
+		}
+	}
+	return patch, found
+}
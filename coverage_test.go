@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// parseCoverage
+// ---------------------------------------------------------------------------
+
+func TestParseCoverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  Check
+		want   coverage
+		wantOK bool
+	}{
+		{"codecov project", Check{JobName: "codecov/project", Description: "85.32% (-0.12%) compared to abc1234"},
+			coverage{Percent: 85.32, Delta: -0.12, HasDelta: true}, true},
+		{"codecov patch", Check{JobName: "codecov/patch", Description: "90.00% of diff hit (target 80.00%)"},
+			coverage{Percent: 90, Patch: true}, true},
+		{"coveralls increased", Check{JobName: "coverage/coveralls", Description: "Coverage increased (+0.2%) to 85.3%"},
+			coverage{Percent: 85.3, Delta: 0.2, HasDelta: true}, true},
+		{"coveralls decreased", Check{JobName: "coverage/coveralls", Description: "Coverage decreased (-1.5%) to 80.1%"},
+			coverage{Percent: 80.1, Delta: -1.5, HasDelta: true}, true},
+		{"coveralls same", Check{JobName: "coverage/coveralls", Description: "Coverage remained the same at 85.3%"},
+			coverage{Percent: 85.3, HasDelta: true}, true},
+		{"generic", Check{JobName: "ci/coverage", Description: "Line coverage 71.4% (+3.0%)"},
+			coverage{Percent: 71.4, Delta: 3, HasDelta: true}, true},
+		{"not a coverage check", Check{JobName: "ci/build", Description: "50% done"}, coverage{}, false},
+		{"no numbers", Check{JobName: "codecov/project", Description: "Waiting for report"}, coverage{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCoverage(tt.check)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseCoverage() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCoverageString(t *testing.T) {
+	tests := []struct {
+		c    coverage
+		want string
+	}{
+		{coverage{Percent: 85.3, Delta: -0.4, HasDelta: true}, "85.30% (-0.40%)"},
+		{coverage{Percent: 85.3, HasDelta: true}, "85.30% (+0.00%)"},
+		{coverage{Percent: 90, Patch: true}, "90.00% of diff"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Coverage in the summary and detail pane
+// ---------------------------------------------------------------------------
+
+func TestCoverageView(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.prData = &PRData{Title: "t", Checks: []Check{
+		{Name: "codecov/patch", JobName: "codecov/patch", Status: Pass, Description: "90.00% of diff hit (target 80.00%)"},
+		{Name: "codecov/project", JobName: "codecov/project", Status: Fail, Description: "81.00% (-1.25%) compared to abc"},
+	}}
+
+	if cov, ok := m.prCoverage(); !ok || cov.Patch || cov.Percent != 81 {
+		t.Errorf("prCoverage() = %+v, %v; want the project coverage", cov, ok)
+	}
+	if view := m.View(); !strings.Contains(view, "Coverage: 81.00% (-1.25%)") {
+		t.Errorf("summary missing coverage:\n%s", view)
+	}
+
+	details := strings.Join(m.checkDetails(m.prData.Checks[0]), "\n")
+	for _, want := range []string{"Coverage:  90.00% of diff", "Message:   90.00% of diff hit"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("details missing %q:\n%s", want, details)
+		}
+	}
+}
+
+func TestParsePRViewDescription(t *testing.T) {
+	data, err := parsePRView([]byte(`{"statusCheckRollup":[{"__typename":"StatusContext","context":"codecov/project","state":"SUCCESS","description":"85.32% (+0.12%) compared to abc"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := data.Checks[0].Description; got != "85.32% (+0.12%) compared to abc" {
+		t.Errorf("Description = %q", got)
+	}
+}
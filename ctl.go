@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ctlTimeout bounds how long a control request waits for the TUI.
+const ctlTimeout = 5 * time.Second
+
+// ctlRequest is one line a `prtop ctl` client writes to the socket.
+type ctlRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// ctlResponse is the line the TUI writes back.
+type ctlResponse struct {
+	OK    bool      `json:"ok"`
+	Error string    `json:"error,omitempty"`
+	State *ctlState `json:"state,omitempty"`
+}
+
+// ctlState is what `prtop ctl state` prints.
+type ctlState struct {
+	Mode     string        `json:"mode"`
+	Repo     string        `json:"repo,omitempty"`
+	Number   int           `json:"number,omitempty"`
+	Title    string        `json:"title,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Grade    string        `json:"grade,omitempty"`
+	Paused   bool          `json:"paused"`
+	Interval string        `json:"interval"`
+	Checks   []exportCheck `json:"checks,omitempty"`
+}
+
+// ctlMsg delivers a request to the model, which answers on reply.
+type ctlMsg struct {
+	req   ctlRequest
+	reply chan ctlResponse
+}
+
+// defaultCtlPath is where the TUI listens unless --socket says otherwise.
+func defaultCtlPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "prtop.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("prtop-%d.sock", os.Getuid()))
+}
+
+// listenCtl opens the control socket at path. A socket left behind by a
+// prtop that crashed is replaced; one a running prtop answers on is not.
+func listenCtl(path string) (net.Listener, error) {
+	if c, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		c.Close()
+		return nil, fmt.Errorf("another prtop is listening on %s", path)
+	}
+	os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to open control socket: %w", err)
+	}
+	return l, nil
+}
+
+// serveCtl answers control requests until l is closed, handing each to the
+// TUI through send (tea.Program.Send).
+func serveCtl(l net.Listener, send func(tea.Msg)) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			sc := bufio.NewScanner(conn)
+			enc := json.NewEncoder(conn)
+			for sc.Scan() {
+				var req ctlRequest
+				var resp ctlResponse
+				if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+					resp.Error = fmt.Sprintf("invalid request: %v", err)
+				} else {
+					reply := make(chan ctlResponse, 1)
+					send(ctlMsg{req: req, reply: reply})
+					select {
+					case resp = <-reply:
+					case <-time.After(ctlTimeout):
+						resp.Error = "prtop didn't answer"
+					}
+				}
+				if err := enc.Encode(resp); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+// ctlCall sends one request to the prtop listening on path.
+func ctlCall(path string, req ctlRequest) (ctlResponse, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return ctlResponse{}, fmt.Errorf("no prtop is listening on %s", path)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ctlTimeout + time.Second))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return ctlResponse{}, err
+	}
+	var resp ctlResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return ctlResponse{}, fmt.Errorf("failed to read prtop's answer: %w", err)
+	}
+	return resp, nil
+}
+
+// handleCtl carries out a control request and answers it.
+func (m model) handleCtl(msg ctlMsg) (model, tea.Cmd) {
+	resp := ctlResponse{OK: true}
+	var cmd tea.Cmd
+	switch msg.req.Command {
+	case "switch":
+		repo, prNumber, err := m.ctlPRArgs(msg.req.Args)
+		if err != nil {
+			resp = ctlResponse{Error: err.Error()}
+			break
+		}
+		m, cmd = m.viewPR(repo, prNumber)
+	case "refresh":
+		switch m.mode {
+		case modeViewing:
+			cmd = m.refreshCmd()
+		case modeDashboard:
+			m.sched.pokeAll(time.Now())
+			cmd = m.fetchPRListCmd()
+		default:
+			m.loading = true
+			cmd = m.fetchPRListCmd()
+		}
+	case "pause", "resume":
+		m.paused = msg.req.Command == "pause"
+		if m.paused {
+			m.flash = "Paused by prtop ctl"
+		} else {
+			m.flash = "Resumed by prtop ctl"
+		}
+	case "state":
+		resp.State = m.ctlState()
+	case "quit":
+		m, cmd = m.quit()
+	default:
+		resp = ctlResponse{Error: fmt.Sprintf("unknown command %q", msg.req.Command)}
+	}
+	msg.reply <- resp
+	return m, cmd
+}
+
+// ctlPRArgs parses the PR argument of `prtop ctl switch` like the CLI does.
+func (m model) ctlPRArgs(args []string) (repo, prNumber string, err error) {
+	var hosts []string
+	for _, a := range m.accounts {
+		hosts = append(hosts, a.Host)
+	}
+	switch len(args) {
+	case 1:
+		if repo, prNumber, ok := parsePRRef(args[0], hosts...); ok {
+			return repo, prNumber, nil
+		}
+	case 2:
+		if repo, ok := parseRepo(args[0], hosts...); ok {
+			prNumber := strings.TrimPrefix(args[1], "#")
+			if _, err := strconv.Atoi(prNumber); err == nil {
+				return repo, prNumber, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("switch expects a PR URL, owner/repo#123 or owner/repo 123, not %q", strings.Join(args, " "))
+}
+
+func (m model) ctlState() *ctlState {
+	st := &ctlState{Paused: m.paused, Interval: formatInterval(m.interval)}
+	switch m.mode {
+	case modeSelecting:
+		st.Mode = "selecting"
+		return st
+	case modeDashboard:
+		st.Mode = "dashboard"
+		return st
+	}
+	st.Mode = "viewing"
+	st.Repo = m.repo
+	st.Number, _ = strconv.Atoi(m.prNumber)
+	if m.prData != nil {
+		st.Title = m.prData.Title
+		st.Status, _ = m.rollupFor(m.repo, m.prNumber, m.prData)
+		st.Grade = gradeOf(st.Status)
+		st.Checks = exportChecks(m.prData.Checks)
+	}
+	return st
+}
+
+func runCtl(s *session, args []string) (int, error) {
+	if len(args) == 0 {
+		return exitFailed, errors.New("expected a command: switch, refresh, pause, resume, state or quit")
+	}
+	resp, err := ctlCall(s.opts.socket, ctlRequest{Command: args[0], Args: args[1:]})
+	if err != nil {
+		return exitFailed, err
+	}
+	if resp.Error != "" {
+		return exitFailed, errors.New(resp.Error)
+	}
+	if resp.State != nil {
+		enc := json.NewEncoder(s.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resp.State); err != nil {
+			return exitFailed, err
+		}
+	}
+	return exitOK, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ctlDo sends req to m and returns the updated model and the answer.
+func ctlDo(t *testing.T, m model, command string, args ...string) (model, ctlResponse) {
+	t.Helper()
+	reply := make(chan ctlResponse, 1)
+	updated, _ := m.Update(ctlMsg{req: ctlRequest{Command: command, Args: args}, reply: reply})
+	select {
+	case resp := <-reply:
+		return updated.(model), resp
+	default:
+		t.Fatalf("%s: no answer", command)
+		return m, ctlResponse{}
+	}
+}
+
+// ctlSocketPath is a socket path short enough for sun_path.
+func ctlSocketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "prtop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "ctl.sock")
+}
+
+// ---------------------------------------------------------------------------
+// Commands
+// ---------------------------------------------------------------------------
+
+func TestCtlSwitch(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.prs = []PRSummary{{Repo: "o/r", Number: 1}}
+
+	m, resp := ctlDo(t, m, "switch", "https://github.com/o/r/pull/42")
+	if !resp.OK || m.mode != modeViewing || m.repo != "o/r" || m.prNumber != "42" {
+		t.Fatalf("switch: resp %+v, mode %v, PR %s#%s", resp, m.mode, m.repo, m.prNumber)
+	}
+	m, resp = ctlDo(t, m, "switch", "x/y", "#7")
+	if !resp.OK || m.repo != "x/y" || m.prNumber != "7" {
+		t.Errorf("switch owner/repo N: resp %+v, PR %s#%s", resp, m.repo, m.prNumber)
+	}
+	if !m.dashboard {
+		t.Error("esc should still return to the dashboard")
+	}
+
+	m, resp = ctlDo(t, m, "switch", "nope")
+	if resp.OK || !strings.Contains(resp.Error, "switch expects a PR") || m.prNumber != "7" {
+		t.Errorf("bad switch: resp %+v, PR %s#%s", resp, m.repo, m.prNumber)
+	}
+
+	// A fetch for the PR switched away from must not land on the new one
+	updated, _ := m.Update(prDataMsg{data: &PRData{Title: "Old PR"}, key: prKey("o/r", "42")})
+	if m = updated.(model); m.prData != nil {
+		t.Errorf("stale fetch applied: %+v", m.prData)
+	}
+}
+
+func TestCtlPauseAndState(t *testing.T) {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 120, 30
+	updated, _ := m.Update(prDataMsg{key: prKey("o/r", "7"), data: &PRData{Title: "Fix it", Checks: []Check{
+		{Name: "build", Status: Fail},
+		{Name: "test", Status: Running},
+	}}})
+	m = updated.(model)
+
+	m, resp := ctlDo(t, m, "pause")
+	if !resp.OK || !m.paused || !strings.Contains(m.View(), "Refresh: paused") {
+		t.Errorf("pause: resp %+v, paused %v", resp, m.paused)
+	}
+	if _, cmd := m.Update(tickMsg(time.Now())); cmd == nil {
+		t.Error("a paused viewer should keep ticking")
+	}
+
+	m, resp = ctlDo(t, m, "state")
+	st := resp.State
+	if !resp.OK || st == nil || st.Mode != "viewing" || st.Repo != "o/r" || st.Number != 7 || st.Title != "Fix it" ||
+		st.Status != rollupFailure || !st.Paused || st.Interval != "5s" || len(st.Checks) != 2 {
+		t.Errorf("state = %+v", st)
+	}
+
+	m, _ = ctlDo(t, m, "resume")
+	if m.paused {
+		t.Error("resume should unpause")
+	}
+	if _, resp = ctlDo(t, m, "frobnicate"); resp.OK || resp.Error != `unknown command "frobnicate"` {
+		t.Errorf("unknown command: %+v", resp)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Socket
+// ---------------------------------------------------------------------------
+
+func TestCtlSocket(t *testing.T) {
+	path := ctlSocketPath(t)
+	// A socket file left by a prtop that crashed is replaced
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	l, err := listenCtl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %v, %v; want 0600", info.Mode(), err)
+	}
+	if _, err := listenCtl(path); err == nil || !strings.Contains(err.Error(), "another prtop is listening") {
+		t.Errorf("second listener: err = %v", err)
+	}
+
+	var mu sync.Mutex
+	m := newModel("o/r", "7", 5*time.Second)
+	go serveCtl(l, func(msg tea.Msg) {
+		mu.Lock()
+		defer mu.Unlock()
+		updated, _ := m.Update(msg)
+		m = updated.(model)
+	})
+
+	s, stdout, _ := testSession(t)
+	s.opts.socket = path
+	if code, err := runCtl(s, []string{"switch", "x/y#9"}); code != exitOK || err != nil {
+		t.Fatalf("ctl switch = %d, %v", code, err)
+	}
+	if code, err := runCtl(s, []string{"state"}); code != exitOK || err != nil {
+		t.Fatalf("ctl state = %d, %v", code, err)
+	}
+	var st ctlState
+	if err := json.Unmarshal(stdout.Bytes(), &st); err != nil || st.Repo != "x/y" || st.Number != 9 {
+		t.Errorf("state = %+v, %v\n%s", st, err, stdout)
+	}
+	if code, err := runCtl(s, []string{"bogus"}); code != exitFailed || err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("ctl bogus = %d, %v", code, err)
+	}
+}
+
+func TestCtlNotRunning(t *testing.T) {
+	s, _, _ := testSession(t)
+	s.opts.socket = ctlSocketPath(t)
+	code, err := runCtl(s, []string{"refresh"})
+	if code != exitFailed || err == nil || !strings.Contains(err.Error(), "no prtop is listening") {
+		t.Errorf("runCtl = %d, %v", code, err)
+	}
+	if code, err := runCtl(s, nil); code != exitFailed || err == nil {
+		t.Errorf("runCtl without a command = %d, %v", code, err)
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dashTickInterval is how often the dashboard asks the scheduler for due
+// PRs. It only decides; actual polling rates come from pollScheduler.
+const dashTickInterval = time.Second
+
+// dashCountsW is the width of the CHECKS column ("✗ 1   ● 2   ✓ 10 ").
+const dashCountsW = 17
+
+// dashRow is the latest fetch result for one dashboard PR.
+type dashRow struct {
+	data   *PRData
+	err    error
+	at     time.Time
+	rollup string // last rollupStatus, for --on-change
+	settle settler
+}
+
+type dashResult struct {
+	key  string
+	data *PRData
+	err  error
+	at   time.Time
+}
+
+type dashTickMsg time.Time
+
+func newDashboardModel(repo string, interval time.Duration) model {
+	m := newRepoSelectModel(repo, interval)
+	m.mode = modeDashboard
+	m.dashboard = true
+	m.dashRows = map[string]dashRow{}
+	m.sched = newPollScheduler(interval, defaultBackgroundInterval, nil)
+	m.pool = newFetchPool(fetchWorkers, fetchDashRow)
+	return m
+}
+
+func fetchDashRow(j fetchJob) dashResult {
+	data, err := fetchPRData(j.acct, j.repo, j.number)
+	return dashResult{key: j.key, data: data, err: err, at: time.Now()}
+}
+
+func dashTickCmd() tea.Cmd {
+	return tea.Tick(dashTickInterval, func(t time.Time) tea.Msg {
+		return dashTickMsg(t)
+	})
+}
+
+func summaryKey(pr PRSummary) string {
+	return prKey(pr.Repo, fmt.Sprintf("%d", pr.Number))
+}
+
+// activeKey is the PR the scheduler polls at the fast rate: the one under
+// the cursor.
+func (m model) activeKey() string {
+	if m.selected < 0 || m.selected >= len(m.prs) {
+		return ""
+	}
+	return summaryKey(m.prs[m.selected])
+}
+
+// pollDueCmd hands the PRs the scheduler says are due to the fetch pool.
+func (m model) pollDueCmd(now time.Time) tea.Cmd {
+	if m.sched == nil {
+		return nil
+	}
+	keys := m.sched.due(now, m.activeKey())
+	if len(keys) == 0 {
+		return nil
+	}
+	byKey := map[string]PRSummary{}
+	for _, pr := range m.prs {
+		byKey[summaryKey(pr)] = pr
+	}
+	var jobs []fetchJob
+	for _, k := range keys {
+		pr := byKey[k]
+		jobs = append(jobs, fetchJob{
+			key:    k,
+			repo:   pr.Repo,
+			number: fmt.Sprintf("%d", pr.Number),
+			acct:   m.repoAccount(pr.Repo),
+		})
+	}
+	logger.Debug("dashboard poll", "prs", len(jobs))
+	return m.pool.submitCmd(jobs)
+}
+
+// applyDashResult stores a fetched row and reschedules its PR. It returns
+// the --on-change command when the PR's rollup status changed.
+func (m model) applyDashResult(r dashResult) tea.Cmd {
+	var cmd tea.Cmd
+	row := m.dashRows[r.key]
+	row.at = r.at
+	row.err = r.err
+	if r.err == nil {
+		row.data = r.data
+		repo, number, _ := strings.Cut(r.key, "#")
+		row.rollup, cmd = m.rollupHook(repo, number, r.data, row.rollup, &row.settle)
+		journaling.observe(repo, number, r.data, r.at)
+	} else {
+		logger.Debug("dashboard fetch failed", "pr", r.key, "err", r.err)
+	}
+	m.dashRows[r.key] = row
+	m.sched.done(r.key, r.at, r.key == m.activeKey())
+	return cmd
+}
+
+// syncDashboard registers the current PR list with the scheduler and fills
+// rows that have never been fetched from the response cache.
+func (m model) syncDashboard(now time.Time) {
+	keys := make([]string, len(m.prs))
+	for i, pr := range m.prs {
+		keys[i] = summaryKey(pr)
+		if _, ok := m.dashRows[keys[i]]; !ok {
+			if data := peekPRData(pr.Repo, fmt.Sprintf("%d", pr.Number)); data != nil {
+				m.dashRows[keys[i]] = dashRow{data: data, at: data.CachedAt}
+			}
+		}
+	}
+	m.sched.sync(keys, now)
+}
+
+// dashCounts renders a row's check counts, excluding acknowledged failures
+// and ignored checks.
+func (m model) dashCounts(pr PRSummary, row dashRow) string {
+	if row.data == nil {
+		if row.err != nil {
+			return styleFail.Render(fmt.Sprintf("%-*s", dashCountsW, "error"))
+		}
+		return styleDim.Render(fmt.Sprintf("%-*s", dashCountsW, "…"))
+	}
+	acks, ignored := m.store.acks(pr.Repo, fmt.Sprintf("%d", pr.Number)), m.store.ignored(pr.Repo)
+	counts := map[CheckStatus]int{}
+	for _, c := range row.data.Checks {
+		if c.Status == Fail && acks[c.Name] || ignored[c.Name] {
+			continue
+		}
+		counts[c.Status]++
+	}
+	cell := func(s CheckStatus, glyph string) string {
+		text := fmt.Sprintf("%s %-3d", glyph, counts[s])
+		if counts[s] == 0 {
+			return styleDim.Render(text)
+		}
+		return statusStyle(s).Render(text)
+	}
+	g := m.glyphs.glyph
+	return cell(Fail, g(Fail)) + " " + cell(Running, g(Running)) + " " + cell(Pass, g(Pass))
+}
+
+func (m model) viewDashboard() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	if m.release != nil {
+		return m.viewRelease()
+	}
+
+	var b strings.Builder
+	maxWidth := m.width
+
+	title := "  prtop dashboard"
+	if m.bots {
+		title = "  prtop bots"
+	}
+	b.WriteString(styleHeader.Render(title))
+	b.WriteString("\n")
+	b.WriteString(styleDim.Render(m.listSubtitle()))
+	b.WriteString("\n")
+	if m.flash != "" {
+		b.WriteString(styleRunning.Render(truncate(m.flash, maxWidth)))
+	}
+	b.WriteString("\n")
+
+	if m.err != nil {
+		b.WriteString(viewError(m.err, maxWidth))
+		return b.String()
+	}
+	if m.loading {
+		b.WriteString("Fetching open PRs...")
+		return b.String()
+	}
+	if len(m.prs) == 0 {
+		b.WriteString("No open PRs found.")
+		b.WriteString("\n\n")
+		b.WriteString(styleDim.Render("r: retry | q: quit"))
+		return b.String()
+	}
+
+	refW := 0
+	for _, pr := range m.prs {
+		refW = max(refW, len(fmt.Sprintf("%s #%d", pr.Repo, pr.Number)))
+	}
+	hdr := fmt.Sprintf("  %-*s  %-*s  TITLE", refW, "PR", dashCountsW, "CHECKS")
+	b.WriteString(styleUnder.Render(truncate(hdr, maxWidth)))
+	b.WriteString("\n")
+
+	maxRows := m.height - 8
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	rows := 0
+	for idx := m.scrollOff; idx < len(m.prs) && idx < m.scrollOff+maxRows; idx++ {
+		rows++
+		pr := m.prs[idx]
+		marker := m.rowMarker(idx, pr)
+		ref := fmt.Sprintf("%s #%d", pr.Repo, pr.Number)
+		ref += strings.Repeat(" ", refW-len(ref))
+		row := m.dashRows[summaryKey(pr)]
+		line := marker + styleRepo.Render(ref) + "  " + m.dashCounts(pr, row) + "  "
+		title := pr.Title
+		if title == "" && row.data != nil {
+			title = row.data.Title
+		}
+		if m.watch.has(pr.Repo, pr.Number) {
+			title = "★ " + title
+		}
+		if pr.Draft {
+			title = "[draft] " + title
+		}
+		title = truncate(title, max(maxWidth-4-refW-dashCountsW-2, 1))
+		if idx == m.selected {
+			b.WriteString(styleSelectedBg.Render(line + styleTitle.Render(title)))
+		} else {
+			b.WriteString(line + styleTitle.Render(title))
+		}
+		b.WriteString("\n")
+	}
+
+	// Pad to bottom — header uses 3 lines, table header 1, one line per PR
+	for i := 4 + rows; i < m.height-1; i++ {
+		b.WriteString("\n")
+	}
+
+	footer := "up/down: select | enter: view PR | space: mark | w: watch/unwatch | r: refresh all | q: quit"
+	if m.bots {
+		footer = fmt.Sprintf("up/down: select | enter: view PR | b: rebase | M: merge green (%d) | r: refresh all | q: quit", len(m.greenBotPRs()))
+	}
+	if bulk := m.bulkFooter(); bulk != "" {
+		footer = bulk
+	}
+	b.WriteString(styleDim.Render(truncate(footer, maxWidth)))
+	return b.String()
+}
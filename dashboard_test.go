@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDashboardPollsAndRenders(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"pr view 1": `{"title":"One","statusCheckRollup":[
+			{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"FAILURE"},
+			{"__typename":"CheckRun","name":"test","status":"IN_PROGRESS"}]}`,
+		"pr view 2": `{"title":"Two","statusCheckRollup":[
+			{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"}]}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newDashboardModel("o/r", 5*time.Second)
+	m.sched.rand = func() float64 { return 0 }
+	m.width = 100
+	m.height = 20
+
+	updated, cmd := m.Update(prListMsg{prs: []PRSummary{
+		{Repo: "o/r", Number: 1, Title: "One"},
+		{Repo: "o/r", Number: 2, Title: "Two"},
+	}})
+	m = updated.(model)
+	if cmd == nil {
+		t.Fatal("loading the PR list should start a poll")
+	}
+	if out := m.View(); !strings.Contains(out, "o/r #1") || !strings.Contains(out, "…") {
+		t.Errorf("rows should be listed as pending before the poll returns, got %q", out)
+	}
+
+	cmd() // submit to the pool
+	for range 2 {
+		updated, cmd = m.Update(m.pool.next()())
+		m = updated.(model)
+		if cmd == nil {
+			t.Fatal("each result should wait for the next one")
+		}
+	}
+	out := m.View()
+	for _, want := range []string{"✗ 1", "● 1", "✓ 1", "Two"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() missing %q:\n%s", want, out)
+		}
+	}
+
+	// Nothing is due again until the active interval has passed.
+	if keys := m.sched.due(time.Now().Add(time.Second), m.activeKey()); len(keys) != 0 {
+		t.Errorf("due one second after a poll = %v, want none", keys)
+	}
+	if _, cmd = m.Update(dashTickMsg(time.Now())); cmd == nil {
+		t.Error("dashTickMsg should schedule the next tick")
+	}
+}
+
+func TestDashboardEnterAndEsc(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.loading = false
+	m.prs = []PRSummary{{Repo: "o/r", Number: 1}, {Repo: "o/r", Number: 2}}
+	m.sched.sync([]string{"o/r#1", "o/r#2"}, time.Now())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(model)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.mode != modeViewing || m.prNumber != "2" || cmd == nil {
+		t.Fatalf("enter should view PR 2, got mode %v pr %q", m.mode, m.prNumber)
+	}
+
+	updated, _ = m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(model)
+	if m.mode != modeDashboard {
+		t.Fatalf("esc should return to the dashboard, got mode %v", m.mode)
+	}
+	if m.selected != 1 {
+		t.Errorf("selected = %d, want 1 (the PR we came from)", m.selected)
+	}
+	if cmd == nil {
+		t.Error("returning to the dashboard should restart its tick")
+	}
+}
+
+func TestQueryDashboardSubtitle(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.selectQuery = "label:release-blocker"
+	m.width = 100
+	m.height = 20
+	if out := m.View(); !strings.Contains(out, "matching label:release-blocker") {
+		t.Errorf("query dashboard should name its query, got %q", out)
+	}
+}
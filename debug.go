@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// logger receives debug events. It discards everything unless --debug is set.
+var logger = slog.New(slog.DiscardHandler)
+
+// setupDebugLog points logger at path. The returned closer flushes the file.
+func setupDebugLog(path string) (io.Closer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open debug log: %w", err)
+	}
+	// Redacted so the log can be attached to a bug report
+	logger = slog.New(redactHandler{slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})})
+	logger.Info("prtop started", "pid", os.Getpid())
+	return f, nil
+}
+
+// fetchStats describes the most recent PR data fetch for the debug overlay.
+type fetchStats struct {
+	at      time.Time
+	latency time.Duration
+	bytes   int
+	err     error
+}
+
+func (s fetchStats) String() string {
+	if s.at.IsZero() {
+		return "debug: no fetch yet"
+	}
+	line := fmt.Sprintf("debug: last fetch %s at %s, %s",
+		s.latency.Round(time.Millisecond), s.at.Format("15:04:05"), formatBytes(s.bytes))
+	if s.err != nil {
+		line += " (failed)"
+	}
+	return line
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
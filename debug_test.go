@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{2048, "2.0 KB"},
+		{3 << 20, "3.0 MB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFetchStatsString(t *testing.T) {
+	t.Run("no fetch yet", func(t *testing.T) {
+		if got := (fetchStats{}).String(); got != "debug: no fetch yet" {
+			t.Errorf("got %q", got)
+		}
+	})
+
+	t.Run("successful fetch", func(t *testing.T) {
+		s := fetchStats{at: time.Now(), latency: 412 * time.Millisecond, bytes: 2048}
+		got := s.String()
+		if !strings.Contains(got, "412ms") || !strings.Contains(got, "2.0 KB") {
+			t.Errorf("got %q, should contain latency and size", got)
+		}
+		if strings.Contains(got, "failed") {
+			t.Errorf("got %q, should not be marked failed", got)
+		}
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		s := fetchStats{at: time.Now(), err: errors.New("boom")}
+		if got := s.String(); !strings.Contains(got, "(failed)") {
+			t.Errorf("got %q, should be marked failed", got)
+		}
+	})
+}
+
+func TestSetupDebugLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug.log")
+	f, err := setupDebugLog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+		logger = slog.New(slog.DiscardHandler)
+	})
+
+	execCommand = fakeExecCommand("{}", "", 0)
+	t.Cleanup(func() { execCommand = exec.Command })
+	if _, err := runGh(nil, "pr", "view", "1"); err != nil {
+		t.Fatalf("runGh: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	if !strings.Contains(log, "gh invocation") {
+		t.Errorf("log should record gh invocation, got %q", log)
+	}
+	if !strings.Contains(log, "exit=0") {
+		t.Errorf("log should record exit code, got %q", log)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// demoCycle is how long one simulated CI run lasts before it starts over.
+const demoCycle = 150 * time.Second
+
+// demoCheck is a scripted check: it starts start after the cycle begins,
+// runs for dur, then concludes with conclusion.
+type demoCheck struct {
+	name       string
+	workflow   string
+	start      time.Duration
+	dur        time.Duration
+	conclusion string
+}
+
+var demoChecks = []demoCheck{
+	{"lint", "CI", 0, 20 * time.Second, "SUCCESS"},
+	{"typecheck", "CI", 0, 35 * time.Second, "SUCCESS"},
+	{"unit-tests (ubuntu-latest)", "CI", 5 * time.Second, 70 * time.Second, "SUCCESS"},
+	{"unit-tests (macos-latest)", "CI", 5 * time.Second, 95 * time.Second, "SUCCESS"},
+	{"unit-tests (windows-latest)", "CI", 5 * time.Second, 110 * time.Second, "FAILURE"},
+	{"integration", "CI", 40 * time.Second, 80 * time.Second, "SUCCESS"},
+	{"build-docker", "Release", 10 * time.Second, 60 * time.Second, "SUCCESS"},
+	{"deploy-preview", "Release", 75 * time.Second, 45 * time.Second, "SUCCESS"},
+	{"e2e (chromium)", "E2E", 30 * time.Second, 100 * time.Second, "SUCCESS"},
+	{"e2e (firefox)", "E2E", 30 * time.Second, 105 * time.Second, "FAILURE"},
+	{"codeql", "CodeQL", 2 * time.Second, 50 * time.Second, "SUCCESS"},
+	{"windows-arm", "CI", 0, 0, "SKIPPED"},
+}
+
+var demoPRs = []PRSummary{
+	{Repo: "prtop-demo/webapp", Number: 128, Title: "Add dark mode toggle to settings", URL: "https://github.com/prtop-demo/webapp/pull/128"},
+	{Repo: "prtop-demo/api", Number: 57, Title: "Paginate /v2/orders endpoint", URL: "https://github.com/prtop-demo/api/pull/57"},
+	{Repo: "prtop-demo/infra", Number: 9, Title: "Bump terraform provider to 5.x", URL: "https://github.com/prtop-demo/infra/pull/9"},
+}
+
+// demoSource serves synthetic gh output for --demo. Check states advance with
+// wall-clock time so the UI animates through a full CI run.
+type demoSource struct {
+	start time.Time
+	now   func() time.Time
+}
+
+func newDemoSource() *demoSource {
+	return &demoSource{start: time.Now(), now: time.Now}
+}
+
+func (d *demoSource) run(args []string) ([]byte, error) {
+	if len(args) >= 2 && args[0] == "search" && args[1] == "prs" {
+		return d.searchPRs()
+	}
+	if len(args) >= 2 && args[0] == "pr" && args[1] == "list" {
+		return d.listPRs()
+	}
+	if len(args) >= 3 && args[0] == "pr" && args[1] == "view" {
+		return d.viewPR(args[2])
+	}
+	return nil, fmt.Errorf("demo mode does not support gh %s", strings.Join(args, " "))
+}
+
+func (d *demoSource) searchPRs() ([]byte, error) {
+	type repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	}
+	type item struct {
+		Number     int        `json:"number"`
+		Title      string     `json:"title"`
+		Repository repository `json:"repository"`
+		URL        string     `json:"url"`
+		UpdatedAt  string     `json:"updatedAt"`
+	}
+	now := d.now().UTC()
+	items := make([]item, len(demoPRs))
+	for i, pr := range demoPRs {
+		items[i] = item{
+			Number:     pr.Number,
+			Title:      pr.Title,
+			Repository: repository{pr.Repo},
+			URL:        pr.URL,
+			UpdatedAt:  now.Add(-time.Duration(i*47+3) * time.Minute).Format(time.RFC3339),
+		}
+	}
+	return json.Marshal(items)
+}
+
+func (d *demoSource) listPRs() ([]byte, error) {
+	type item struct {
+		Number    int    `json:"number"`
+		Title     string `json:"title"`
+		URL       string `json:"url"`
+		UpdatedAt string `json:"updatedAt"`
+	}
+	now := d.now().UTC()
+	items := make([]item, len(demoPRs))
+	for i, pr := range demoPRs {
+		items[i] = item{pr.Number, pr.Title, pr.URL, now.Add(-time.Duration(i*47+3) * time.Minute).Format(time.RFC3339)}
+	}
+	return json.Marshal(items)
+}
+
+func (d *demoSource) viewPR(number string) ([]byte, error) {
+	now := d.now().UTC()
+	elapsed := now.Sub(d.start) % demoCycle
+	cycleStart := now.Add(-elapsed)
+
+	title := "Add dark mode toggle to settings"
+	for _, pr := range demoPRs {
+		if fmt.Sprint(pr.Number) == number {
+			title = pr.Title
+		}
+	}
+
+	resp := ghPRResponse{
+		Title:       title,
+		HeadRefName: "feature/demo",
+		URL:         "https://github.com/prtop-demo/webapp/pull/" + number,
+	}
+	for _, c := range demoChecks {
+		item := ghCheckItem{
+			Typename:     "CheckRun",
+			Name:         c.name,
+			WorkflowName: c.workflow,
+			DetailsURL:   "https://github.com/prtop-demo/webapp/actions",
+		}
+		switch {
+		case c.conclusion == "SKIPPED":
+			item.Status = "COMPLETED"
+			item.Conclusion = "SKIPPED"
+		case elapsed < c.start:
+			item.Status = "QUEUED"
+		case elapsed < c.start+c.dur:
+			item.Status = "IN_PROGRESS"
+			item.StartedAt = cycleStart.Add(c.start).Format(time.RFC3339)
+		default:
+			item.Status = "COMPLETED"
+			item.Conclusion = looseString(c.conclusion)
+			item.StartedAt = cycleStart.Add(c.start).Format(time.RFC3339)
+			item.CompletedAt = cycleStart.Add(c.start + c.dur).Format(time.RFC3339)
+		}
+		resp.StatusCheckRollup = append(resp.StatusCheckRollup, item)
+	}
+	return json.Marshal(resp)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestDemoSourceAnimatesChecks(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	d := &demoSource{start: start, now: func() time.Time { return now }}
+	ghOverride = d
+	t.Cleanup(func() { ghOverride = nil })
+	execCommand = fakeExecCommand("", "gh should not run in demo mode", 1)
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	counts := func() map[CheckStatus]int {
+		data, err := fetchPRData(nil, "prtop-demo/webapp", "128")
+		if err != nil {
+			t.Fatalf("fetchPRData: %v", err)
+		}
+		c := map[CheckStatus]int{}
+		for _, check := range data.Checks {
+			c[check.Status]++
+		}
+		return c
+	}
+
+	early := counts()
+	if early[Running] == 0 {
+		t.Errorf("at cycle start, want running checks, got %v", early)
+	}
+	if early[Pass] != 0 || early[Fail] != 0 {
+		t.Errorf("at cycle start, want no finished checks, got %v", early)
+	}
+
+	now = start.Add(demoCycle - time.Second)
+	late := counts()
+	if late[Running] != 0 {
+		t.Errorf("at cycle end, want no running checks, got %v", late)
+	}
+	if late[Fail] == 0 || late[Pass] == 0 {
+		t.Errorf("at cycle end, want both passes and failures, got %v", late)
+	}
+}
+
+func TestDemoSourcePRLists(t *testing.T) {
+	ghOverride = newDemoSource()
+	t.Cleanup(func() { ghOverride = nil })
+
+	prs, err := fetchRecentPRs(nil)
+	if err != nil {
+		t.Fatalf("fetchRecentPRs: %v", err)
+	}
+	if len(prs) != len(demoPRs) {
+		t.Errorf("got %d PRs, want %d", len(prs), len(demoPRs))
+	}
+
+	prs, err = fetchRepoPRs(nil, "prtop-demo/webapp")
+	if err != nil {
+		t.Fatalf("fetchRepoPRs: %v", err)
+	}
+	if len(prs) == 0 {
+		t.Error("repo-scoped demo list should not be empty")
+	}
+
+	if _, err := runGh(nil, "run", "rerun", "1"); err == nil {
+		t.Error("unsupported commands should return an error")
+	}
+}
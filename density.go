@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// density is how tightly viewing mode packs the screen.
+type density int
+
+const (
+	densityNormal  density = iota
+	densityCompact         // one-line header, single-character status glyphs
+	densityComfy           // a blank line between checks
+)
+
+var densityNames = []string{"normal", "compact", "comfy"}
+
+func (d density) String() string {
+	return densityNames[d]
+}
+
+// parseDensity parses a density name from config. An empty name is
+// densityNormal.
+func parseDensity(name string) (density, error) {
+	if name == "" {
+		return densityNormal, nil
+	}
+	for i, n := range densityNames {
+		if strings.EqualFold(name, n) {
+			return density(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown display density %q (want one of %s)", name, strings.Join(densityNames, ", "))
+}
+
+// headerLines is how many lines viewing mode puts above the check table:
+// header, title, branch, status line, summary and a blank line, or just the
+// header in compact mode.
+func (m model) headerLines() int {
+	if m.density == densityCompact {
+		return 1
+	}
+	if m.kiosk {
+		return 8 // the summary's box takes three lines
+	}
+	return 6
+}
+
+// bodyRows is how many lines are left for the check table's rows (or an
+// overlay's) after the header, the table header and the footer.
+func (m model) bodyRows() int {
+	return max(1, m.height-m.headerLines()-2)
+}
+
+// tableRows is how many checks fit in bodyRows, or PRs in the picker.
+func (m model) tableRows() int {
+	if m.mode == modeSelecting {
+		// The picker's PRs take 3 lines each below a 3-line header
+		return max(1, (m.height-4)/3)
+	}
+	if m.density == densityComfy {
+		return max(1, m.bodyRows()/2)
+	}
+	return m.bodyRows()
+}
+
+// viewCompactHeader fits the PR, its failure/running/pass counts and the
+// clock on one line.
+func (m model) viewCompactHeader() string {
+	now := timeNow().Format("15:04:05")
+	left := fmt.Sprintf("%s #%s", m.repo, m.prNumber)
+	if m.prData != nil {
+		counts, _ := m.checkCounts()
+		g := m.glyphs.glyph
+		left += fmt.Sprintf("  %s%d %s%d %s%d", g(Fail), counts[Fail], g(Running), counts[Running], g(Pass), counts[Pass])
+		if m.prData.Mergeable == "CONFLICTING" {
+			left += " CONFLICTS"
+		}
+		if len(m.awaitingApproval()) > 0 {
+			left += " AWAITING APPROVAL"
+		}
+		left += m.mergeQueueBadge()
+		if m.prData.Title != "" {
+			left += "  " + m.prData.Title
+		}
+	}
+	left = truncate(left, max(0, m.width-len(now)-1))
+	pad := max(1, m.width-lipgloss.Width(left)-len(now))
+	if banner := m.attentionBanner(); banner != "" {
+		return banner + styleBold.Render(truncate(left, max(0, m.width-lipgloss.Width(banner))))
+	}
+	return styleBold.Render(truncate(left+strings.Repeat(" ", pad)+now, m.width))
+}
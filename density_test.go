@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ---------------------------------------------------------------------------
+// parseDensity
+// ---------------------------------------------------------------------------
+
+func TestParseDensity(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    density
+		wantErr bool
+	}{
+		{"", densityNormal, false},
+		{"compact", densityCompact, false},
+		{"Comfy", densityComfy, false},
+		{"tiny", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDensity(tt.name)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseDensity(%q) = %v, %v; want %v, err=%v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Density layouts
+// ---------------------------------------------------------------------------
+
+func densityTestModel(d density, checks int) model {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 80, 20
+	m.density = d
+	m.prData = &PRData{Title: "Fix it", HeadRefName: "fix"}
+	for i := range checks {
+		status := Pass
+		if i == 0 {
+			status = Fail
+		}
+		m.prData.Checks = append(m.prData.Checks, Check{Name: "check-" + string(rune('a'+i)), Status: status})
+	}
+	return m
+}
+
+func TestDensityView(t *testing.T) {
+	t.Run("z cycles density", func(t *testing.T) {
+		m := densityTestModel(densityNormal, 1)
+		want := []density{densityCompact, densityComfy, densityNormal}
+		for _, d := range want {
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+			m = updated.(model)
+			if m.density != d {
+				t.Fatalf("density = %v, want %v", m.density, d)
+			}
+		}
+	})
+
+	t.Run("rows per density", func(t *testing.T) {
+		tests := []struct {
+			d    density
+			want int
+		}{
+			{densityNormal, 12},
+			{densityCompact, 17},
+			{densityComfy, 6},
+		}
+		for _, tt := range tests {
+			if got := densityTestModel(tt.d, 0).tableRows(); got != tt.want {
+				t.Errorf("%v: tableRows() = %d, want %d", tt.d, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("compact fills the screen with glyph rows", func(t *testing.T) {
+		m := densityTestModel(densityCompact, 30)
+		view := m.View()
+		lines := strings.Split(view, "\n")
+		if len(lines) != m.height {
+			t.Errorf("view has %d lines, want %d", len(lines), m.height)
+		}
+		if !strings.Contains(lines[0], "o/r #1  ✗1 ●0 ✓29  Fix it") {
+			t.Errorf("header = %q", lines[0])
+		}
+		if !strings.Contains(lines[2], "✗") || strings.Contains(view, "FAIL") {
+			t.Errorf("first row = %q, want a glyph", lines[2])
+		}
+		if strings.Contains(view, "Branch:") {
+			t.Error("compact view shows the branch line")
+		}
+	})
+
+	t.Run("compact shows messages in the footer", func(t *testing.T) {
+		m := densityTestModel(densityCompact, 1)
+		m.flash = "Requested rerun"
+		lines := strings.Split(m.View(), "\n")
+		if last := lines[len(lines)-1]; !strings.Contains(last, "Requested rerun") {
+			t.Errorf("footer = %q", last)
+		}
+	})
+
+	t.Run("comfy spaces rows", func(t *testing.T) {
+		m := densityTestModel(densityComfy, 30)
+		lines := strings.Split(m.View(), "\n")
+		if len(lines) != m.height {
+			t.Errorf("view has %d lines, want %d", len(lines), m.height)
+		}
+		// The blank line between rows only has the scrollbar
+		if !strings.Contains(lines[7], "check-a") || strings.Trim(ansi.Strip(lines[8]), " │┃") != "" ||
+			!strings.Contains(lines[9], "check-b") {
+			t.Errorf("rows = %q", lines[7:10])
+		}
+	})
+}
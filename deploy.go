@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deployment is the latest deployment of the head commit to one
+// environment.
+type deployment struct {
+	environment string
+	state       string // GitHub's DeploymentState, e.g. WAITING or ACTIVE
+	url         string // the environment's URL, once deployed
+	logURL      string // usually the Actions job that deployed
+}
+
+// deployments are the head commit's deployments, newest first, one per
+// environment.
+type deployments struct {
+	repo string
+	sha  string
+	list []deployment
+	err  error
+}
+
+type deploymentsMsg struct {
+	deploys *deployments
+}
+
+// deployStateLabels describe GitHub's deployment states the way the
+// environments page does.
+var deployStateLabels = map[string]string{
+	"WAITING":     "pending approval",
+	"QUEUED":      "queued",
+	"PENDING":     "pending",
+	"IN_PROGRESS": "in progress",
+	"ACTIVE":      "active",
+	"SUCCESS":     "active",
+	"INACTIVE":    "inactive",
+	"FAILURE":     "failed",
+	"ERROR":       "failed",
+	"ABANDONED":   "abandoned",
+	"DESTROYED":   "destroyed",
+}
+
+// label is d's state for the UI.
+func (d deployment) label() string {
+	if l, ok := deployStateLabels[d.state]; ok {
+		return l
+	}
+	return strings.ToLower(strings.ReplaceAll(d.state, "_", " "))
+}
+
+// settled reports whether d's state can only change with a new
+// deployment.
+func (d deployment) settled() bool {
+	switch d.state {
+	case "WAITING", "QUEUED", "PENDING", "IN_PROGRESS":
+		return false
+	}
+	return true
+}
+
+const deploymentsQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) { ... on Commit {
+      deployments(first: 30, orderBy: {field: CREATED_AT, direction: DESC}) { nodes {
+        environment state
+        latestStatus { state environmentUrl logUrl }
+      } }
+    } }
+  }
+}`
+
+// fetchDeployments asks for the deployments of a commit. gh pr view doesn't
+// report them, so this is a separate query.
+func fetchDeployments(acct *Account, repo, sha string) *deployments {
+	d := &deployments{repo: repo, sha: sha}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+deploymentsQuery,
+		"-F", "owner="+owner, "-F", "name="+name, "-F", "oid="+sha)
+	if err != nil {
+		d.err = err
+		return d
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Object *struct {
+					Deployments struct {
+						Nodes []struct {
+							Environment  string `json:"environment"`
+							State        string `json:"state"`
+							LatestStatus *struct {
+								State          string `json:"state"`
+								EnvironmentURL string `json:"environmentUrl"`
+								LogURL         string `json:"logUrl"`
+							} `json:"latestStatus"`
+						} `json:"nodes"`
+					} `json:"deployments"`
+				} `json:"object"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		d.err = fmt.Errorf("failed to parse deployments: %w", err)
+		return d
+	}
+	if resp.Data.Repository.Object == nil {
+		return d
+	}
+	for _, n := range resp.Data.Repository.Object.Deployments.Nodes {
+		if slices.ContainsFunc(d.list, func(e deployment) bool { return e.environment == n.Environment }) {
+			continue // an older deployment to the same environment
+		}
+		dep := deployment{environment: n.Environment, state: n.State}
+		if s := n.LatestStatus; s != nil {
+			// The status is more current than the deployment's own state,
+			// except that a waiting deployment reports its status as pending
+			if s.State != "" && !(n.State == "WAITING" && s.State == "PENDING") {
+				dep.state = s.State
+			}
+			dep.url, dep.logURL = s.EnvironmentURL, s.LogURL
+		}
+		d.list = append(d.list, dep)
+	}
+	return d
+}
+
+// deploymentsCmd fetches the head commit's deployments: once per head
+// commit, then on each refresh while one may still change.
+func (m model) deploymentsCmd() (model, tea.Cmd) {
+	if m.prData == nil || m.prData.HeadSHA == "" || m.deploysBusy {
+		return m, nil
+	}
+	key := prKey(m.repo, m.prData.HeadSHA)
+	if m.deploysAsked == key && !m.deploysUnsettled() {
+		return m, nil
+	}
+	repo, sha := m.repo, m.prData.HeadSHA
+	m.deploysAsked, m.deploysBusy = key, true
+	acct := m.repoAccount(repo)
+	return m, func() tea.Msg {
+		return deploymentsMsg{deploys: fetchDeployments(acct, repo, sha)}
+	}
+}
+
+// deploysUnsettled reports whether the head commit's deployments may still
+// change: one is waiting for approval or under way, or a job that looks
+// like it deploys is still running and may yet start one. Other running
+// checks don't count, so a PR without deployments isn't asked about them
+// on every refresh.
+func (m model) deploysUnsettled() bool {
+	d := m.deploys
+	if d == nil || d.repo != m.repo || d.sha != m.prData.HeadSHA {
+		return false
+	}
+	for _, dep := range d.list {
+		if !dep.settled() {
+			return true
+		}
+	}
+	for _, c := range m.prData.Checks {
+		if c.Status != Running {
+			continue
+		}
+		name := strings.ToLower(c.JobName)
+		if strings.Contains(name, "deploy") || slices.ContainsFunc(d.list, func(dep deployment) bool {
+			return dep.environment != "" && strings.Contains(name, strings.ToLower(dep.environment))
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDeployment is the deployment c made, if it is a deployment job: the
+// one whose log is c's Actions job, or else one to an environment c's name
+// mentions.
+func (m model) checkDeployment(c Check) (deployment, bool) {
+	d := m.deploys
+	if d == nil || m.prData == nil || d.repo != m.repo || d.sha != m.prData.HeadSHA {
+		return deployment{}, false
+	}
+	if _, jobID := actionsRunID(c.DetailsURL); jobID != "" {
+		for _, dep := range d.list {
+			if _, depJob := actionsRunID(dep.logURL); depJob == jobID {
+				return dep, true
+			}
+		}
+	}
+	name := strings.ToLower(c.JobName)
+	for _, dep := range d.list {
+		if dep.environment != "" && strings.Contains(name, strings.ToLower(dep.environment)) {
+			return dep, true
+		}
+	}
+	return deployment{}, false
+}
+
+// deployNote is the table's note for a deployment job, e.g.
+// "→ staging: active".
+func (m model) deployNote(c Check) string {
+	dep, ok := m.checkDeployment(c)
+	if !ok {
+		return ""
+	}
+	return "→ " + dep.environment + ": " + dep.label()
+}
+
+// openEnvironmentCommand is the palette entry that opens the selected
+// deployment job's environment.
+func (m model) openEnvironmentCommand() paletteCommand {
+	c, _ := m.selectedCheck()
+	dep, ok := m.checkDeployment(c)
+	disabled := ""
+	switch {
+	case !ok:
+		disabled = "not a deployment job"
+	case dep.url == "":
+		disabled = dep.environment + " has no URL yet"
+	}
+	return paletteCommand{
+		label:    "Open deployment environment",
+		disabled: disabled,
+		run: func(m model) (model, tea.Cmd) {
+			openBrowser(dep.url)
+			m.flash = "Opened " + dep.environment + ": " + dep.url
+			return m, nil
+		},
+	}
+}
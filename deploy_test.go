@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDeployments(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"oid=abc123 graphql": `{"data":{"repository":{"object":{"deployments":{"nodes":[
+			{"environment":"production","state":"WAITING","latestStatus":{"state":"PENDING","environmentUrl":"","logUrl":"https://github.com/o/r/actions/runs/7/job/72"}},
+			{"environment":"staging","state":"ACTIVE","latestStatus":{"state":"SUCCESS","environmentUrl":"https://staging.example.com","logUrl":"https://github.com/o/r/actions/runs/7/job/71"}},
+			{"environment":"staging","state":"INACTIVE","latestStatus":{"state":"INACTIVE","environmentUrl":"","logUrl":""}}]}}}}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 140, 20
+	m.prData = &PRData{HeadSHA: "abc123", Checks: []Check{
+		{Name: "ship (Deploy)", JobName: "ship", Workflow: "Deploy", Status: Pass, DetailsURL: "https://github.com/o/r/actions/runs/7/job/71"},
+		{Name: "approve-prod (Deploy)", JobName: "approve-prod", Workflow: "Deploy", Status: Running, DetailsURL: "https://github.com/o/r/actions/runs/7/job/72"},
+		{Name: "smoke-staging (Deploy)", JobName: "smoke-staging", Workflow: "Deploy", Status: Pass},
+		{Name: "build (CI)", JobName: "build", Workflow: "CI", Status: Pass},
+	}}
+
+	m, cmd := m.deploymentsCmd()
+	if cmd == nil {
+		t.Fatal("no fetch for a new head commit")
+	}
+	if _, again := m.deploymentsCmd(); again != nil {
+		t.Error("fetched again while the first fetch was in flight")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+	if len(m.deploys.list) != 2 {
+		t.Fatalf("deployments = %+v", m.deploys.list)
+	}
+
+	notes := map[string]string{}
+	for _, c := range m.prData.Checks {
+		notes[c.JobName] = m.deployNote(c)
+	}
+	want := map[string]string{
+		"ship":          "→ staging: active",
+		"approve-prod":  "→ production: pending approval",
+		"smoke-staging": "→ staging: active",
+		"build":         "",
+	}
+	for job, w := range want {
+		if notes[job] != w {
+			t.Errorf("%s: note %q, want %q", job, notes[job], w)
+		}
+	}
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "ship (Deploy)  (→ staging: active)") {
+		t.Errorf("table:\n%s", out)
+	}
+	for i, c := range m.filteredChecks() {
+		if c.JobName == "ship" {
+			m.selected = i
+		}
+	}
+	c, _ := m.selectedCheck()
+	if details := strings.Join(m.checkDetails(c), "\n"); !strings.Contains(details, "Deploy:    staging, active\nEnv URL:   https://staging.example.com") {
+		t.Errorf("details:\n%s", details)
+	}
+	if cmd := m.openEnvironmentCommand(); cmd.disabled != "" {
+		t.Errorf("open environment disabled: %s", cmd.disabled)
+	}
+
+	// Production is waiting for approval, so the next refresh asks again
+	if _, cmd := m.deploymentsCmd(); cmd == nil {
+		t.Error("waiting deployment not refetched")
+	}
+	m.deploys.list[0].state = "ACTIVE"
+	m.prData.Checks[1].Status = Pass
+	if _, cmd := m.deploymentsCmd(); cmd != nil {
+		t.Error("refetched settled deployments")
+	}
+	// A running job that looks like a deploy may yet start one
+	m.prData.Checks = append(m.prData.Checks, Check{Name: "deploy-canary", JobName: "deploy-canary", Status: Running})
+	if _, cmd := m.deploymentsCmd(); cmd == nil {
+		t.Error("running deploy job not watched")
+	}
+}
+
+func TestOpenEnvironmentDisabled(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.prData = &PRData{HeadSHA: "abc123", Checks: []Check{{Name: "deploy-prod", JobName: "deploy-prod", Status: Running}}}
+	if got := m.openEnvironmentCommand().disabled; got != "not a deployment job" {
+		t.Errorf("no deployments: %q", got)
+	}
+	m.deploys = &deployments{repo: "o/r", sha: "abc123", list: []deployment{{environment: "prod", state: "WAITING"}}}
+	if got := m.openEnvironmentCommand().disabled; got != "prod has no URL yet" {
+		t.Errorf("no URL: %q", got)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// depJob is a job from a workflow file and the jobs it `needs:`.
+type depJob struct {
+	ID    string
+	Name  string
+	Needs []string
+}
+
+// depGraph is the job dependency graph of one workflow run.
+type depGraph struct {
+	Workflow string
+	Jobs     []depJob // in workflow file order
+}
+
+// actionsRunID extracts the run and job IDs from a GitHub Actions details URL
+// such as https://github.com/o/r/actions/runs/123/job/456.
+func actionsRunID(detailsURL string) (runID, jobID string) {
+	u, err := url.Parse(detailsURL)
+	if err != nil {
+		return "", ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		switch parts[i] {
+		case "runs":
+			runID = parts[i+1]
+		case "job":
+			jobID = parts[i+1]
+		}
+	}
+	return runID, jobID
+}
+
+// parseWorkflowNeeds reads the workflow name and its jobs' needs: lists from
+// a workflow YAML file, keeping jobs in file order.
+func parseWorkflowNeeds(data []byte) (string, []depJob, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", nil, fmt.Errorf("failed to parse workflow: not a mapping")
+	}
+	root := doc.Content[0]
+
+	var name string
+	var jobs []depJob
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i].Value, root.Content[i+1]
+		switch key {
+		case "name":
+			name = val.Value
+		case "jobs":
+			if val.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(val.Content); j += 2 {
+				job := depJob{ID: val.Content[j].Value}
+				var spec struct {
+					Name  string    `yaml:"name"`
+					Needs yaml.Node `yaml:"needs"`
+				}
+				if err := val.Content[j+1].Decode(&spec); err != nil {
+					return "", nil, fmt.Errorf("failed to parse job %s: %w", job.ID, err)
+				}
+				job.Name = spec.Name
+				switch spec.Needs.Kind {
+				case yaml.ScalarNode:
+					job.Needs = []string{spec.Needs.Value}
+				case yaml.SequenceNode:
+					for _, n := range spec.Needs.Content {
+						job.Needs = append(job.Needs, n.Value)
+					}
+				}
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	return name, jobs, nil
+}
+
+// fetchDepGraphs loads the workflow definition behind every Actions run
+// referenced by checks and returns one dependency graph per run.
+func fetchDepGraphs(acct *Account, repo string, checks []Check) ([]depGraph, error) {
+	var runIDs []string
+	seen := map[string]bool{}
+	for _, c := range checks {
+		runID, _ := actionsRunID(c.DetailsURL)
+		if runID == "" || seen[runID] {
+			continue
+		}
+		seen[runID] = true
+		runIDs = append(runIDs, runID)
+	}
+
+	var graphs []depGraph
+	for _, id := range runIDs {
+		out, err := ghAPI(acct, repo, "repos/{repo}/actions/runs/"+id)
+		if err != nil {
+			return nil, err
+		}
+		var run struct {
+			Name    string `json:"name"`
+			Path    string `json:"path"`
+			HeadSHA string `json:"head_sha"`
+		}
+		if err := json.Unmarshal(out, &run); err != nil {
+			return nil, fmt.Errorf("failed to parse workflow run: %w", err)
+		}
+		if run.Path == "" {
+			continue
+		}
+		// Dynamic workflows (e.g. "dynamic/dependabot/...") have no file.
+		path, _, _ := strings.Cut(run.Path, "@")
+		content, err := ghAPI(acct, repo, "repos/{repo}/contents/"+path+"?ref="+run.HeadSHA,
+			"-H", "Accept: application/vnd.github.raw")
+		if err != nil {
+			continue
+		}
+		name, jobs, err := parseWorkflowNeeds(content)
+		if err != nil {
+			return nil, err
+		}
+		if run.Name != "" {
+			name = run.Name
+		}
+		graphs = append(graphs, depGraph{Workflow: name, Jobs: jobs})
+	}
+	return graphs, nil
+}
+
+// displayName is the job name as it appears in check runs, with any
+// expression (e.g. a matrix value) cut off.
+func (j depJob) displayName() string {
+	if j.Name == "" {
+		return j.ID
+	}
+	name, _, _ := strings.Cut(j.Name, "${{")
+	return strings.TrimSpace(name)
+}
+
+// jobChecks returns the checks produced by job: an exact name match, or the
+// matrix ("name (x)") and reusable workflow ("name / x") expansions of it.
+func (g depGraph) jobChecks(job depJob, checks []Check) []Check {
+	var result []Check
+	for _, c := range checks {
+		if g.ran(job, c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ran reports whether c is one of job's checks.
+func (g depGraph) ran(job depJob, c Check) bool {
+	name := job.displayName()
+	return c.Workflow == g.Workflow && (c.JobName == name || c.JobName == job.ID ||
+		strings.HasPrefix(c.JobName, name+" (") || strings.HasPrefix(c.JobName, name+" / "))
+}
+
+// jobFor finds the workflow job that produced c.
+func jobFor(graphs []depGraph, c Check) (depGraph, depJob, bool) {
+	for _, g := range graphs {
+		for _, j := range g.Jobs {
+			if g.ran(j, c) {
+				return g, j, true
+			}
+		}
+	}
+	return depGraph{}, depJob{}, false
+}
+
+// jobState summarizes a job's checks. ok is false when the job has no check
+// run yet, which for jobs with needs: means it is waiting on dependencies.
+func jobState(checks []Check) (status CheckStatus, ok bool) {
+	if len(checks) == 0 {
+		return Running, false
+	}
+	status = Skipped
+	for _, c := range checks {
+		if c.Status < status {
+			status = c.Status
+		}
+	}
+	return status, true
+}
+
+// renderDepGraphs draws each workflow's jobs as a tree. Jobs hang under
+// their first need; extra needs and unmet dependencies are annotated.
+func renderDepGraphs(graphs []depGraph, checks []Check) []string {
+	var lines []string
+	for gi, g := range graphs {
+		if gi > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, styleBold.Render(g.Workflow))
+
+		byID := map[string]depJob{}
+		for _, j := range g.Jobs {
+			byID[j.ID] = j
+		}
+		children := map[string][]depJob{}
+		var roots []depJob
+		for _, j := range g.Jobs {
+			if len(j.Needs) > 0 {
+				if _, ok := byID[j.Needs[0]]; ok {
+					children[j.Needs[0]] = append(children[j.Needs[0]], j)
+					continue
+				}
+			}
+			roots = append(roots, j)
+		}
+
+		var walk func(j depJob, prefix string, last bool)
+		walk = func(j depJob, prefix string, last bool) {
+			branch, next := "├─ ", "│  "
+			if last {
+				branch, next = "└─ ", "   "
+			}
+			lines = append(lines, prefix+branch+g.describeJob(j, checks))
+			kids := children[j.ID]
+			for i, k := range kids {
+				walk(k, prefix+next, i == len(kids)-1)
+			}
+		}
+		for i, r := range roots {
+			walk(r, "", i == len(roots)-1)
+		}
+	}
+	return lines
+}
+
+func (g depGraph) describeJob(j depJob, checks []Check) string {
+	jc := g.jobChecks(j, checks)
+	status, ok := jobState(jc)
+	started := false
+	for _, c := range jc {
+		if c.Status != Running || !c.StartedAt.IsZero() {
+			started = true
+		}
+	}
+	line := j.displayName() + "  "
+	if ok {
+		line += statusStyle(status).Render(status.String())
+	} else {
+		line += styleDim.Render("WAITING")
+	}
+
+	var failed, pending []string
+	for _, n := range j.Needs {
+		dep, known := g.findJob(n)
+		if !known {
+			continue
+		}
+		depStatus, depOK := jobState(g.jobChecks(dep, checks))
+		switch {
+		case depOK && depStatus == Fail:
+			failed = append(failed, dep.displayName())
+		case !depOK || depStatus == Running:
+			pending = append(pending, dep.displayName())
+		}
+	}
+	switch {
+	case !started && len(failed) > 0:
+		line += styleFail.Render("  blocked: " + strings.Join(failed, ", ") + " failed")
+	case !started && len(pending) > 0:
+		line += styleDim.Render("  waiting on " + strings.Join(pending, ", "))
+	case len(j.Needs) > 1:
+		line += styleDim.Render("  needs " + strings.Join(j.Needs, ", "))
+	}
+	return line
+}
+
+func (g depGraph) findJob(id string) (depJob, bool) {
+	for _, j := range g.Jobs {
+		if j.ID == id {
+			return j, true
+		}
+	}
+	return depJob{}, false
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWorkflow = `
+name: CI
+on: pull_request
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+  test:
+    name: unit-tests
+    strategy:
+      matrix:
+        os: [ubuntu-latest, macos-latest]
+    runs-on: ${{ matrix.os }}
+  test-integration:
+    needs: [lint, test]
+  deploy:
+    needs: test-integration
+`
+
+func TestActionsRunID(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantRun string
+		wantJob string
+	}{
+		{"https://github.com/o/r/actions/runs/123/job/456", "123", "456"},
+		{"https://github.com/o/r/actions/runs/123", "123", ""},
+		{"https://ci.example.com/build/9", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		run, job := actionsRunID(tt.url)
+		if run != tt.wantRun || job != tt.wantJob {
+			t.Errorf("actionsRunID(%q) = %q, %q; want %q, %q", tt.url, run, job, tt.wantRun, tt.wantJob)
+		}
+	}
+}
+
+func TestParseWorkflowNeeds(t *testing.T) {
+	name, jobs, err := parseWorkflowNeeds([]byte(testWorkflow))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "CI" {
+		t.Errorf("name = %q, want CI", name)
+	}
+	if len(jobs) != 4 {
+		t.Fatalf("got %d jobs, want 4", len(jobs))
+	}
+	if jobs[1].Name != "unit-tests" {
+		t.Errorf("jobs[1].Name = %q, want unit-tests", jobs[1].Name)
+	}
+	if got := strings.Join(jobs[2].Needs, ","); got != "lint,test" {
+		t.Errorf("test-integration needs = %q, want lint,test", got)
+	}
+	if got := strings.Join(jobs[3].Needs, ","); got != "test-integration" {
+		t.Errorf("deploy needs = %q, want test-integration (scalar form)", got)
+	}
+
+	if _, _, err := parseWorkflowNeeds([]byte("- just\n- a list\n")); err == nil {
+		t.Error("expected error for non-mapping workflow")
+	}
+}
+
+func TestDepJobDisplayName(t *testing.T) {
+	tests := []struct {
+		job  depJob
+		want string
+	}{
+		{depJob{ID: "lint"}, "lint"},
+		{depJob{ID: "test", Name: "unit-tests"}, "unit-tests"},
+		{depJob{ID: "test", Name: "test ${{ matrix.os }}"}, "test"},
+	}
+	for _, tt := range tests {
+		if got := tt.job.displayName(); got != tt.want {
+			t.Errorf("displayName(%+v) = %q, want %q", tt.job, got, tt.want)
+		}
+	}
+}
+
+func TestJobFor(t *testing.T) {
+	_, jobs, err := parseWorkflowNeeds([]byte(testWorkflow))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphs := []depGraph{{Workflow: "CI", Jobs: jobs}}
+	if _, j, ok := jobFor(graphs, Check{JobName: "unit-tests (macos-latest)", Workflow: "CI"}); !ok || j.ID != "test" {
+		t.Errorf("matrix check: job %+v, ok %v", j, ok)
+	}
+	if _, _, ok := jobFor(graphs, Check{JobName: "lint", Workflow: "Other"}); ok {
+		t.Error("a check from another workflow matched")
+	}
+}
+
+func TestRenderDepGraphs(t *testing.T) {
+	_, jobs, err := parseWorkflowNeeds([]byte(testWorkflow))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphs := []depGraph{{Workflow: "CI", Jobs: jobs}}
+	checks := []Check{
+		{JobName: "lint", Workflow: "CI", Status: Pass},
+		{JobName: "unit-tests (ubuntu-latest)", Workflow: "CI", Status: Pass},
+		{JobName: "unit-tests (macos-latest)", Workflow: "CI", Status: Running, StartedAt: time.Now()},
+		{JobName: "lint", Workflow: "Other", Status: Fail},
+	}
+
+	out := strings.Join(renderDepGraphs(graphs, checks), "\n")
+	if !strings.Contains(out, "unit-tests  RUNNING") {
+		t.Errorf("matrix job should aggregate to RUNNING, got:\n%s", out)
+	}
+	if !strings.Contains(out, "test-integration  WAITING") || !strings.Contains(out, "waiting on unit-tests") {
+		t.Errorf("test-integration should be waiting on unit-tests, got:\n%s", out)
+	}
+	if !strings.Contains(out, "└─ deploy") {
+		t.Errorf("deploy should hang under test-integration, got:\n%s", out)
+	}
+
+	checks[2].Status = Fail
+	out = strings.Join(renderDepGraphs(graphs, checks), "\n")
+	if !strings.Contains(out, "blocked: unit-tests failed") {
+		t.Errorf("test-integration should be blocked by failure, got:\n%s", out)
+	}
+}
+
+func TestFetchDepGraphs(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"actions/runs/123":                          `{"name":"CI","path":".github/workflows/ci.yml","head_sha":"abc"}`,
+		"contents/.github/workflows/ci.yml?ref=abc": testWorkflow,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	checks := []Check{
+		{DetailsURL: "https://github.com/o/r/actions/runs/123/job/1"},
+		{DetailsURL: "https://github.com/o/r/actions/runs/123/job/2"},
+		{DetailsURL: "https://jenkins.example.com/job/x"},
+	}
+	graphs, err := fetchDepGraphs(nil, "o/r", checks)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(graphs) != 1 {
+		t.Fatalf("got %d graphs, want 1 (one per run)", len(graphs))
+	}
+	if graphs[0].Workflow != "CI" || len(graphs[0].Jobs) != 4 {
+		t.Errorf("graph = %+v, want CI with 4 jobs", graphs[0])
+	}
+}
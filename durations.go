@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// baseDurationsTTL is how long the base branch's check durations are
+// reused. Averages over several commits barely move between refreshes.
+const baseDurationsTTL = 15 * time.Minute
+
+// baseDurationCommits is how many of the base branch's latest commits the
+// averages cover.
+const baseDurationCommits = 10
+
+// A check is flagged as a regression when it is at least slowdownPct
+// percent and slowdownMin slower than the base branch average; smaller
+// differences are runner noise.
+const (
+	slowdownPct = 25
+	slowdownMin = 30 * time.Second
+)
+
+// deltaMinPct hides deltas too small to be worth a glance.
+const deltaMinPct = 10
+
+// baseDurations holds the average duration of each check over the base
+// branch's latest successful runs, keyed by Check.Name.
+type baseDurations struct {
+	repo   string
+	branch string
+	avg    map[string]time.Duration
+	err    error
+}
+
+type baseDurationsMsg struct {
+	durations *baseDurations
+}
+
+const baseDurationsQuery = `query($owner: String!, $name: String!, $ref: String!, $commits: Int!) {
+  repository(owner: $owner, name: $name) {
+    ref(qualifiedName: $ref) {
+      target { ... on Commit { history(first: $commits) { nodes {
+        checkSuites(first: 30) { nodes {
+          workflowRun { workflow { name } }
+          checkRuns(first: 100) { nodes { name conclusion startedAt completedAt } }
+        } }
+      } } } }
+    }
+  }
+}`
+
+// fetchBaseDurations averages the duration of each check that succeeded on
+// branch's latest commits. Failed and cancelled runs are left out, since
+// they stop early.
+func fetchBaseDurations(acct *Account, repo, branch string) *baseDurations {
+	d := &baseDurations{repo: repo, branch: branch}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+baseDurationsQuery,
+		"-f", "owner="+owner, "-f", "name="+name, "-f", "ref=refs/heads/"+branch,
+		"-F", fmt.Sprintf("commits=%d", baseDurationCommits))
+	if err != nil {
+		d.err = err
+		return d
+	}
+	type checkRun struct {
+		Name        string    `json:"name"`
+		Conclusion  string    `json:"conclusion"`
+		StartedAt   time.Time `json:"startedAt"`
+		CompletedAt time.Time `json:"completedAt"`
+	}
+	var resp struct {
+		Data struct {
+			Repository *struct {
+				Ref *struct {
+					Target struct {
+						History struct {
+							Nodes []struct {
+								CheckSuites struct {
+									Nodes []struct {
+										WorkflowRun *struct {
+											Workflow struct {
+												Name string `json:"name"`
+											} `json:"workflow"`
+										} `json:"workflowRun"`
+										CheckRuns struct {
+											Nodes []checkRun `json:"nodes"`
+										} `json:"checkRuns"`
+									} `json:"nodes"`
+								} `json:"checkSuites"`
+							} `json:"nodes"`
+						} `json:"history"`
+					} `json:"target"`
+				} `json:"ref"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		d.err = fmt.Errorf("failed to parse base branch durations: %w", err)
+		return d
+	}
+	if resp.Data.Repository == nil || resp.Data.Repository.Ref == nil {
+		d.err = fmt.Errorf("branch %s not found", branch)
+		return d
+	}
+
+	total := map[string]time.Duration{}
+	runs := map[string]int{}
+	for _, commit := range resp.Data.Repository.Ref.Target.History.Nodes {
+		for _, suite := range commit.CheckSuites.Nodes {
+			for _, r := range suite.CheckRuns.Nodes {
+				if r.Conclusion != "SUCCESS" || r.StartedAt.IsZero() || r.CompletedAt.Before(r.StartedAt) {
+					continue
+				}
+				// The same name parsePRView gives the check
+				name := r.Name
+				if suite.WorkflowRun != nil && suite.WorkflowRun.Workflow.Name != "" {
+					name = fmt.Sprintf("%s (%s)", name, suite.WorkflowRun.Workflow.Name)
+				}
+				total[name] += r.CompletedAt.Sub(r.StartedAt)
+				runs[name]++
+			}
+		}
+	}
+	d.avg = make(map[string]time.Duration, len(total))
+	for name, sum := range total {
+		d.avg[name] = sum / time.Duration(runs[name])
+	}
+	return d
+}
+
+// baseDurationsCmd fetches the base branch's durations unless they were
+// last asked for less than baseDurationsTTL ago.
+func (m model) baseDurationsCmd(now time.Time) (model, tea.Cmd) {
+	if m.prData == nil || m.prData.BaseRefName == "" {
+		return m, nil
+	}
+	repo, branch := m.repo, m.prData.BaseRefName
+	key := baseKey(repo, branch)
+	if key == m.baseDurKey && now.Sub(m.baseDurAsked) < baseDurationsTTL {
+		return m, nil
+	}
+	if key != m.baseDurKey {
+		m.baseDur = nil
+	}
+	m.baseDurKey, m.baseDurAsked = key, now
+	acct := m.repoAccount(repo)
+	return m, func() tea.Msg {
+		return baseDurationsMsg{durations: fetchBaseDurations(acct, repo, branch)}
+	}
+}
+
+// durationDelta compares c's duration with its base branch average and
+// returns the difference in percent. A running check is compared once it
+// has taken longer than the average. ok is false when there is nothing to
+// compare or the difference is under deltaMinPct.
+func (d *baseDurations) durationDelta(c Check, now time.Time) (pct int, regression, ok bool) {
+	if d == nil || d.err != nil || c.StartedAt.IsZero() {
+		return 0, false, false
+	}
+	avg, found := d.avg[c.Name]
+	if !found || avg < time.Second {
+		return 0, false, false
+	}
+	var took time.Duration
+	switch {
+	case !c.CompletedAt.IsZero():
+		took = c.CompletedAt.Sub(c.StartedAt)
+	case c.Completed:
+		return 0, false, false // a status context; we don't know when it ended
+	default:
+		if took = now.Sub(c.StartedAt); took <= avg {
+			return 0, false, false
+		}
+	}
+	pct = int((took - avg) * 100 / avg)
+	if pct > -deltaMinPct && pct < deltaMinPct {
+		return 0, false, false
+	}
+	return pct, pct >= slowdownPct && took-avg >= slowdownMin, true
+}
+
+// baseDurationsFor returns the durations if they belong to the PR's
+// current base branch.
+func (m model) baseDurationsFor() *baseDurations {
+	d := m.baseDur
+	if d == nil || d.err != nil || m.prData == nil || baseKey(d.repo, d.branch) != baseKey(m.repo, m.prData.BaseRefName) {
+		return nil
+	}
+	return d
+}
+
+// durationRegressions counts the PR's checks flagged as slower than the
+// base branch.
+func (m model) durationRegressions(now time.Time) int {
+	d := m.baseDurationsFor()
+	if d == nil {
+		return 0
+	}
+	n := 0
+	for _, c := range m.prData.Checks {
+		if _, regression, _ := d.durationDelta(c, now); regression {
+			n++
+		}
+	}
+	return n
+}
+
+// formatDelta renders a duration delta as "+40%" or "-12%".
+func formatDelta(pct int) string {
+	return fmt.Sprintf("%+d%%", pct)
+}
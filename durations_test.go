@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Two commits on main: build took 2m and 4m (a 3m average), lint failed
+// once and took 30s the other time, and a non-Actions check took 10s.
+const baseDurationsResponse = `{"data":{"repository":{"ref":{"target":{"history":{"nodes":[
+  {"checkSuites":{"nodes":[
+    {"workflowRun":{"workflow":{"name":"CI"}},"checkRuns":{"nodes":[
+      {"name":"build","conclusion":"SUCCESS","startedAt":"2024-05-01T10:00:00Z","completedAt":"2024-05-01T10:02:00Z"},
+      {"name":"lint","conclusion":"FAILURE","startedAt":"2024-05-01T10:00:00Z","completedAt":"2024-05-01T10:00:05Z"}]}},
+    {"workflowRun":null,"checkRuns":{"nodes":[
+      {"name":"codecov","conclusion":"SUCCESS","startedAt":"2024-05-01T10:00:00Z","completedAt":"2024-05-01T10:00:10Z"}]}}]}},
+  {"checkSuites":{"nodes":[
+    {"workflowRun":{"workflow":{"name":"CI"}},"checkRuns":{"nodes":[
+      {"name":"build","conclusion":"SUCCESS","startedAt":"2024-05-01T09:00:00Z","completedAt":"2024-05-01T09:04:00Z"},
+      {"name":"lint","conclusion":"SUCCESS","startedAt":"2024-05-01T09:00:00Z","completedAt":"2024-05-01T09:00:30Z"},
+      {"name":"deploy","conclusion":"SUCCESS","startedAt":"2024-05-01T09:00:00Z","completedAt":null}]}}]}}
+]}}}}}}`
+
+// ---------------------------------------------------------------------------
+// fetchBaseDurations
+// ---------------------------------------------------------------------------
+
+func TestFetchBaseDurations(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{"ref=refs/heads/main": baseDurationsResponse})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	d := fetchBaseDurations(nil, "o/r", "main")
+	if d.err != nil {
+		t.Fatal(d.err)
+	}
+	want := map[string]time.Duration{
+		"build (CI)": 3 * time.Minute,
+		"lint (CI)":  30 * time.Second,
+		"codecov":    10 * time.Second,
+	}
+	if len(d.avg) != len(want) {
+		t.Errorf("averages = %v, want %v", d.avg, want)
+	}
+	for name, avg := range want {
+		if d.avg[name] != avg {
+			t.Errorf("avg[%q] = %v, want %v", name, d.avg[name], avg)
+		}
+	}
+
+	execCommand = fakeExecByArgs(map[string]string{"ref=refs/heads/gone": `{"data":{"repository":{"ref":null}}}`})
+	if d := fetchBaseDurations(nil, "o/r", "gone"); d.err == nil {
+		t.Error("missing branch: no error")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// durationDelta
+// ---------------------------------------------------------------------------
+
+func TestDurationDelta(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	d := &baseDurations{avg: map[string]time.Duration{"build": 100 * time.Second, "lint": 20 * time.Second}}
+	ran := func(name string, secs int) Check {
+		return Check{Name: name, StartedAt: t0, Completed: true, CompletedAt: t0.Add(time.Duration(secs) * time.Second)}
+	}
+	now := t0.Add(150 * time.Second)
+	tests := []struct {
+		name       string
+		check      Check
+		pct        int
+		regression bool
+		ok         bool
+	}{
+		{"much slower", ran("build", 140), 40, true, true},
+		{"faster", ran("build", 80), -20, false, true},
+		{"within noise", ran("build", 105), 0, false, false},
+		{"slower but only by seconds", ran("lint", 30), 50, false, true},
+		{"no average", ran("deploy", 30), 0, false, false},
+		{"running past the average", Check{Name: "build", StartedAt: t0}, 50, true, true},
+		{"running under the average", Check{Name: "build", StartedAt: t0.Add(100 * time.Second)}, 0, false, false},
+		{"status context", Check{Name: "build", StartedAt: t0, Completed: true}, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pct, regression, ok := d.durationDelta(tt.check, now)
+			if pct != tt.pct || regression != tt.regression || ok != tt.ok {
+				t.Errorf("durationDelta = %d, %v, %v; want %d, %v, %v", pct, regression, ok, tt.pct, tt.regression, tt.ok)
+			}
+		})
+	}
+	if _, _, ok := (*baseDurations)(nil).durationDelta(ran("build", 140), now); ok {
+		t.Error("nil durations compared")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// View
+// ---------------------------------------------------------------------------
+
+func TestDurationDeltaColumn(t *testing.T) {
+	t0 := time.Now().Add(-time.Hour)
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 100, 20
+	m.prData = &PRData{Title: "t", BaseRefName: "main", Checks: []Check{
+		{Name: "build", Status: Pass, Duration: "2m20s", StartedAt: t0, Completed: true, CompletedAt: t0.Add(140 * time.Second)},
+		{Name: "lint", Status: Pass, Duration: "20s", StartedAt: t0, Completed: true, CompletedAt: t0.Add(20 * time.Second)},
+	}}
+	m.baseDur = &baseDurations{repo: "o/r", branch: "main", avg: map[string]time.Duration{"build": 100 * time.Second, "lint": 20 * time.Second}}
+
+	out := m.View()
+	if !strings.Contains(out, "2m20s +40%  build") || !strings.Contains(out, "20s         lint") {
+		t.Errorf("delta column:\n%s", out)
+	}
+	if !strings.Contains(out, "2 passed, 1 slower than main") {
+		t.Errorf("summary should count the regression:\n%s", out)
+	}
+
+	// Averages for another base branch are ignored
+	m.prData.BaseRefName = "release"
+	if out := m.View(); strings.Contains(out, "+40%") || strings.Contains(out, "slower than") {
+		t.Errorf("stale averages shown:\n%s", out)
+	}
+}
+
+func TestBaseDurationsCmd(t *testing.T) {
+	now := time.Now()
+	m := newModel("o/r", "7", 5*time.Second)
+	m.prData = &PRData{BaseRefName: "main"}
+	m, cmd := m.baseDurationsCmd(now)
+	if cmd == nil {
+		t.Fatal("first refresh didn't fetch")
+	}
+	if _, cmd = m.baseDurationsCmd(now.Add(baseStatusTTL)); cmd != nil {
+		t.Error("refresh within the TTL fetched again")
+	}
+	if _, cmd = m.baseDurationsCmd(now.Add(baseDurationsTTL)); cmd == nil {
+		t.Error("refresh after the TTL didn't fetch")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ghErrorKind is what went wrong with a gh call, as far as the user can do
+// something about it.
+type ghErrorKind int
+
+const (
+	ghErrOther ghErrorKind = iota
+	ghErrAuth
+	ghErrNotFound
+	ghErrPermission
+	ghErrRateLimit
+	ghErrNetwork
+	ghErrServer // a GitHub 5xx
+)
+
+// ghErrorPatterns classify gh's stderr. They are tried in order: a rate
+// limit comes back as HTTP 403, so it is checked before permissions.
+var ghErrorPatterns = []struct {
+	kind      ghErrorKind
+	fragments []string
+}{
+	{ghErrRateLimit, []string{"rate limit", "Rate limit"}},
+	{ghErrAuth, []string{"HTTP 401", "Bad credentials", "gh auth login", "authentication required"}},
+	{ghErrNotFound, []string{"HTTP 404", "Not Found", "Could not resolve to", "no pull requests found"}},
+	{ghErrPermission, []string{"HTTP 403", "Resource not accessible", "must have admin rights"}},
+	{ghErrServer, []string{"HTTP 500", "HTTP 502", "HTTP 503", "HTTP 504", "Bad Gateway", "Service Unavailable",
+		"Gateway Timeout", "Something went wrong while executing your query"}},
+	{ghErrNetwork, []string{"connection reset", "connection refused", "i/o timeout", "TLS handshake timeout",
+		"no such host", "network is unreachable", "unexpected EOF", "stream error", "timed out"}},
+}
+
+// ghError is a failed gh invocation. Its message is gh's stderr, as it
+// always was; kind picks the guidance shown with it.
+type ghError struct {
+	kind ghErrorKind
+	msg  string
+}
+
+func (e *ghError) Error() string { return "gh CLI error: " + e.msg }
+
+// newGhError classifies gh's stderr. Tokens in it are redacted.
+func newGhError(msg string) *ghError {
+	return &ghError{kind: classifyGh(msg), msg: redact(msg)}
+}
+
+func classifyGh(msg string) ghErrorKind {
+	for _, p := range ghErrorPatterns {
+		for _, f := range p.fragments {
+			if strings.Contains(msg, f) {
+				return p.kind
+			}
+		}
+	}
+	return ghErrOther
+}
+
+// ghErrKind is err's kind. Errors that lost their type on the way, such as
+// replayed ones, are classified by their text.
+func ghErrKind(err error) ghErrorKind {
+	if err == nil {
+		return ghErrOther
+	}
+	var ge *ghError
+	if errors.As(err, &ge) {
+		return ge.kind
+	}
+	return classifyGh(err.Error())
+}
+
+// errorLines renders err for the UI: what went wrong, then what to do
+// about it when prtop knows.
+func errorLines(err error) (summary, hint string) {
+	raw := strings.TrimPrefix(redact(err.Error()), "gh CLI error: ")
+	if first, _, ok := strings.Cut(raw, "\n"); ok {
+		raw = first
+	}
+	switch ghErrKind(err) {
+	case ghErrAuth:
+		if tokenClient != nil {
+			return "Not signed in to GitHub: " + raw, "Check that GITHUB_TOKEN (or GH_TOKEN, GH_ENTERPRISE_TOKEN) is set, valid and not expired"
+		}
+		return "Not signed in to GitHub: " + raw, "Run `gh auth login`, or `gh auth status` to see which account gh is using"
+	case ghErrNotFound:
+		return "Not found: " + raw, "Check the repo and PR number, and that your gh account can see the repo"
+	case ghErrPermission:
+		return "No permission: " + raw, "Your gh account can't do this; ask for access, or run `gh auth refresh -s repo` if the token lacks a scope"
+	case ghErrRateLimit:
+		return "Rate limited: " + raw, "Wait for the limit to reset, or raise the refresh interval with + (see `gh api rate_limit`)"
+	case ghErrNetwork:
+		return "Can't reach GitHub: " + raw, "Check your network connection; prtop tries again on the next refresh"
+	case ghErrServer:
+		return "GitHub error: " + raw, "GitHub is having trouble; prtop tries again on the next refresh (https://www.githubstatus.com/)"
+	}
+	return "Error: " + raw, ""
+}
+
+// viewError is the error screen's body: the error, its hint, and the keys.
+func viewError(err error, maxWidth int) string {
+	summary, hint := errorLines(err)
+	var b strings.Builder
+	b.WriteString(styleFail.Render(truncate(summary, maxWidth)))
+	b.WriteString("\n")
+	if hint != "" {
+		b.WriteString(truncate(hint, maxWidth))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styleDim.Render("r: retry | q: quit"))
+	return b.String()
+}
+
+// errorFlash is err as a one-line status message.
+func errorFlash(action string, err error) string {
+	summary, hint := errorLines(err)
+	if hint == "" {
+		return fmt.Sprintf("%s failed: %s", action, strings.TrimPrefix(summary, "Error: "))
+	}
+	return fmt.Sprintf("%s failed: %s. %s", action, summary, hint)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestClassifyGh(t *testing.T) {
+	tests := map[string]ghErrorKind{
+		"To get started with GitHub CLI, please run:  gh auth login":                                   ghErrAuth,
+		"HTTP 401: Bad credentials (https://api.github.com/graphql)":                                   ghErrAuth,
+		"GraphQL: Could not resolve to a PullRequest with the number of 999. (repository.pullRequest)": ghErrNotFound,
+		"HTTP 404: Not Found (https://api.github.com/repos/o/r/actions/runs/1)":                        ghErrNotFound,
+		"HTTP 403: Resource not accessible by integration":                                             ghErrPermission,
+		"HTTP 403: API rate limit exceeded for user ID 1.":                                             ghErrRateLimit,
+		"GraphQL: API rate limit already exceeded for user ID 1.":                                      ghErrRateLimit,
+		"Post \"https://api.github.com/graphql\": dial tcp: lookup api.github.com: no such host":       ghErrNetwork,
+		"HTTP 502: Bad Gateway":       ghErrServer,
+		"HTTP 422: Validation Failed": ghErrOther,
+	}
+	for msg, want := range tests {
+		if got := classifyGh(msg); got != want {
+			t.Errorf("classifyGh(%q) = %d, want %d", msg, got, want)
+		}
+	}
+
+	// The kind survives wrapping, and untyped errors are read by their text
+	wrapped := fmt.Errorf("fetching: %w", newGhError("HTTP 401: Bad credentials"))
+	if ghErrKind(wrapped) != ghErrAuth || ghErrKind(errors.New("gh CLI error: HTTP 404: Not Found")) != ghErrNotFound {
+		t.Error("ghErrKind")
+	}
+}
+
+func TestExecGhTypedError(t *testing.T) {
+	execCommand = fakeExecCommand("", "GraphQL: Could not resolve to a PullRequest with the number of 999.", 1)
+	t.Cleanup(func() { execCommand = exec.Command })
+	_, err := runGh(nil, "pr", "view", "999", "--repo", "o/r")
+	var ge *ghError
+	if !errors.As(err, &ge) || ge.kind != ghErrNotFound || !strings.HasPrefix(err.Error(), "gh CLI error: GraphQL: Could not") {
+		t.Errorf("err = %#v", err)
+	}
+}
+
+func TestErrorScreen(t *testing.T) {
+	m := newModel("o/r", "999", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.err = newGhError("GraphQL: Could not resolve to a PullRequest with the number of 999.\nmore detail")
+	out := ansi.Strip(m.View())
+	for _, want := range []string{
+		"Not found: GraphQL: Could not resolve to a PullRequest with the number of 999.\n",
+		"Check the repo and PR number",
+		"r: retry | q: quit",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "more detail") || strings.Contains(out, "gh CLI error") {
+		t.Errorf("raw stderr shown:\n%s", out)
+	}
+
+	m.err = errors.New("boom")
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "Error: boom\n\nr: retry") {
+		t.Errorf("unclassified:\n%s", out)
+	}
+
+	if got := errorFlash("Rerun", newGhError("gh auth login")); got != "Rerun failed: Not signed in to GitHub: gh auth login. Run `gh auth login`, or `gh auth status` to see which account gh is using" {
+		t.Errorf("flash = %q", got)
+	}
+}
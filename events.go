@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxEvents caps the in-memory event log.
+const maxEvents = 500
+
+// checkEvent is an entry in the event log: a status transition of one check,
+// or a PR-level event such as a new push (Check empty).
+type checkEvent struct {
+	At    time.Time
+	Check string
+	From  CheckStatus
+	To    CheckStatus
+	New   bool   // check appeared for the first time
+	Rerun bool   // the check's Actions job was rerun
+	Text  string // PR-level event description
+}
+
+func (e checkEvent) String() string {
+	switch {
+	case e.Check == "":
+		return e.Text
+	case e.New:
+		return fmt.Sprintf("%s appeared as %s", e.Check, e.To)
+	case e.Rerun:
+		return fmt.Sprintf("%s %s → %s (rerun)", e.Check, e.From, e.To)
+	}
+	return fmt.Sprintf("%s %s → %s", e.Check, e.From, e.To)
+}
+
+// diffChecks returns the transitions between two snapshots of the same
+// commit's checks. Checks that disappear are not reported. A check whose
+// Actions job ID changed was rerun, which is noted even if its status
+// came out the same.
+func diffChecks(old, cur []Check, at time.Time) []checkEvent {
+	prev := make(map[string]Check, len(old))
+	for _, c := range old {
+		prev[c.Name] = c
+	}
+	var events []checkEvent
+	for _, c := range cur {
+		p, ok := prev[c.Name]
+		if !ok {
+			events = append(events, checkEvent{At: at, Check: c.Name, To: c.Status, New: true})
+			continue
+		}
+		_, oldJob := actionsRunID(p.DetailsURL)
+		_, newJob := actionsRunID(c.DetailsURL)
+		rerun := oldJob != "" && newJob != "" && oldJob != newJob
+		if p.Status != c.Status || rerun {
+			events = append(events, checkEvent{At: at, Check: c.Name, From: p.Status, To: c.Status, Rerun: rerun})
+		}
+	}
+	return events
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// recordSnapshot updates the event log for newly fetched data. A changed head
+// SHA starts a fresh log, since the old checks belong to a different commit.
+func (m model) recordSnapshot(data *PRData, at time.Time) model {
+	if m.prData == nil {
+		m.events = []checkEvent{{At: at, Text: "watching " + shortSHA(data.HeadSHA)}}
+		m.newPush = ""
+		return m
+	}
+	if data.HeadSHA != "" && m.prData.HeadSHA != "" && data.HeadSHA != m.prData.HeadSHA {
+		logger.Debug("new push detected", "from", m.prData.HeadSHA, "to", data.HeadSHA)
+		m.events = []checkEvent{{At: at, Text: fmt.Sprintf("new push %s → %s",
+			shortSHA(m.prData.HeadSHA), shortSHA(data.HeadSHA))}}
+		m.newPush = data.HeadSHA
+		m.selected = 0
+		m.scrollOff = 0
+		return m
+	}
+	m.events = append(m.events, diffChecks(m.prData.Checks, data.Checks, at)...)
+	if len(m.events) > maxEvents {
+		m.events = m.events[len(m.events)-maxEvents:]
+	}
+	return m
+}
+
+// newPushDivider is the line between the summary and the check table
+// after a push while watching, or "" (a blank line) without one.
+func (m model) newPushDivider(width int) string {
+	if m.newPush == "" || m.prData == nil || m.prData.HeadSHA != m.newPush {
+		return ""
+	}
+	text := fmt.Sprintf("── new push detected (%s) — results below are for the new commit ", shortSHA(m.newPush))
+	if pad := width - lipgloss.Width(text); pad > 0 {
+		text += strings.Repeat("─", pad)
+	}
+	return styleRunning.Render(truncate(text, width))
+}
+
+// clearNewPush drops the new push divider on a key press once every check
+// of the new commit has finished, so it stays up until the new results
+// have been on screen.
+func (m model) clearNewPush() model {
+	if m.newPush == "" || m.prData == nil {
+		return m
+	}
+	if m.prData.HeadSHA == m.newPush {
+		if len(m.prData.Checks) == 0 {
+			return m
+		}
+		for _, c := range m.prData.Checks {
+			if c.Status == Running {
+				return m
+			}
+		}
+	}
+	m.newPush = ""
+	return m
+}
+
+// renderEvents lists the event log, newest first.
+func renderEvents(events []checkEvent) []string {
+	lines := make([]string, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		text := e.String()
+		switch {
+		case e.Check == "":
+			text = styleBold.Render(text)
+		case !e.New:
+			text = e.Check + " " + statusStyle(e.From).Render(e.From.String()) + " → " + statusStyle(e.To).Render(e.To.String())
+			if e.Rerun {
+				text += styleDim.Render(" (rerun)")
+			}
+		}
+		lines = append(lines, styleDim.Render(e.At.Format("15:04:05"))+"  "+text)
+	}
+	return lines
+}
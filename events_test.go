@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDiffChecks(t *testing.T) {
+	at := time.Now()
+	old := []Check{
+		{Name: "build", Status: Running},
+		{Name: "lint", Status: Pass},
+		{Name: "gone", Status: Running},
+	}
+	cur := []Check{
+		{Name: "build", Status: Fail},
+		{Name: "lint", Status: Pass},
+		{Name: "deploy", Status: Running},
+	}
+	events := diffChecks(old, cur, at)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %v", len(events), events)
+	}
+	if got := events[0].String(); got != "build RUNNING → FAIL" {
+		t.Errorf("events[0] = %q", got)
+	}
+	if got := events[1].String(); got != "deploy appeared as RUNNING" {
+		t.Errorf("events[1] = %q", got)
+	}
+}
+
+func TestDiffChecksRerun(t *testing.T) {
+	job := func(id string) string { return "https://github.com/o/r/actions/runs/1/job/" + id }
+	old := []Check{
+		{Name: "build", Status: Fail, DetailsURL: job("10")},
+		{Name: "lint", Status: Pass, DetailsURL: job("11")},
+		{Name: "test", Status: Pass, DetailsURL: job("12")},
+	}
+	cur := []Check{
+		{Name: "build", Status: Running, DetailsURL: job("20")},
+		{Name: "lint", Status: Pass, DetailsURL: job("21")},
+		{Name: "test", Status: Pass, DetailsURL: job("12")},
+	}
+	var got []string
+	for _, e := range diffChecks(old, cur, time.Now()) {
+		got = append(got, e.String())
+	}
+	if strings.Join(got, "; ") != "build FAIL → RUNNING (rerun); lint PASS → PASS (rerun)" {
+		t.Errorf("events = %q", got)
+	}
+}
+
+func TestRecordSnapshot(t *testing.T) {
+	at := time.Now()
+	m := newModel("o/r", "1", 5*time.Second)
+
+	m = m.recordSnapshot(&PRData{HeadSHA: "aaaaaaa111", Checks: []Check{{Name: "build", Status: Running}}}, at)
+	m.prData = &PRData{HeadSHA: "aaaaaaa111", Checks: []Check{{Name: "build", Status: Running}}}
+	if len(m.events) != 1 || !strings.Contains(m.events[0].Text, "watching aaaaaaa") {
+		t.Fatalf("first snapshot events = %v", m.events)
+	}
+
+	m = m.recordSnapshot(&PRData{HeadSHA: "aaaaaaa111", Checks: []Check{{Name: "build", Status: Pass}}}, at)
+	if len(m.events) != 2 {
+		t.Fatalf("transition should be logged, events = %v", m.events)
+	}
+
+	m.selected = 3
+	m = m.recordSnapshot(&PRData{HeadSHA: "bbbbbbb222", Checks: []Check{{Name: "build", Status: Running}}}, at)
+	if len(m.events) != 1 || !strings.Contains(m.events[0].Text, "new push aaaaaaa → bbbbbbb") {
+		t.Errorf("new push should reset the event log, events = %v", m.events)
+	}
+	if m.newPush != "bbbbbbb222" {
+		t.Errorf("newPush = %q, want the new head", m.newPush)
+	}
+	if m.selected != 0 {
+		t.Errorf("selected = %d, want 0 after new push", m.selected)
+	}
+}
+
+func TestNewPushDivider(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 100, 20
+	m.store = &stateStore{}
+	m.prData = &PRData{HeadSHA: "aaaaaaa111", Checks: []Check{{Name: "build", Status: Pass}}}
+	data := &PRData{HeadSHA: "bbbbbbb222", Checks: []Check{{Name: "build", Status: Running}}}
+	m = m.recordSnapshot(data, time.Now())
+	m.prData = data
+	divider := "── new push detected (bbbbbbb) — results below are for the new commit"
+	if out := m.View(); !strings.Contains(out, divider) {
+		t.Fatalf("no divider:\n%s", out)
+	}
+
+	// Keys and flashes don't clear it while the new checks are running
+	m, _ = press(t, m, keyJ)
+	m.flash = "Copied"
+	if out := m.View(); !strings.Contains(out, divider) {
+		t.Errorf("divider gone while running:\n%s", out)
+	}
+
+	// Once they're all in, the next key does
+	m.prData = &PRData{HeadSHA: "bbbbbbb222", Checks: []Check{{Name: "build", Status: Pass}}}
+	if out := m.View(); !strings.Contains(out, divider) {
+		t.Errorf("divider gone before a key press:\n%s", out)
+	}
+	m, _ = press(t, m, keyJ)
+	if out := m.View(); strings.Contains(out, "new push detected") {
+		t.Errorf("divider still shown:\n%s", out)
+	}
+}
+
+func TestEventsOverlay(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width = 120
+	m.height = 30
+
+	updated, _ := m.Update(prDataMsg{data: &PRData{HeadSHA: "abc", Checks: []Check{{Name: "build", Status: Running}}}})
+	m = updated.(model)
+	updated, _ = m.Update(prDataMsg{data: &PRData{HeadSHA: "abc", Checks: []Check{{Name: "build", Status: Pass}}}})
+	m = updated.(model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(model)
+	if m.overlay != overlayEvents {
+		t.Fatalf("overlay = %v, want overlayEvents", m.overlay)
+	}
+	out := m.View()
+	if !strings.Contains(out, "EVENTS") || !strings.Contains(out, "build") || !strings.Contains(out, "PASS") {
+		t.Errorf("events overlay should show the transition, got %q", out)
+	}
+}
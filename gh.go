@@ -1,16 +1,156 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 var execCommand = exec.Command
 
+// ghTimeout bounds a single gh invocation. A hung gh is killed and its
+// call fails instead of stalling whatever is waiting on it.
+var ghTimeout = 30 * time.Second
+
+// ghSource answers gh invocations without running gh. When ghOverride is set
+// (--replay, --demo) runGh consults it instead of executing gh.
+type ghSource interface {
+	run(args []string) ([]byte, error)
+}
+
+var ghOverride ghSource
+
+// tokenCache holds per-account tokens looked up via `gh auth token`.
+var tokenCache = struct {
+	sync.Mutex
+	tokens map[string]string
+}{tokens: map[string]string{}}
+
+// accountEnv returns the environment overrides that point gh at acct.
+// A nil account leaves gh on its currently active login.
+func accountEnv(acct *Account) ([]string, error) {
+	if acct == nil {
+		return nil, nil
+	}
+	env := []string{"GH_HOST=" + acct.Host}
+	if acct.User == "" {
+		return env, nil
+	}
+
+	key := acct.User + "@" + acct.Host
+	tokenCache.Lock()
+	token, ok := tokenCache.tokens[key]
+	tokenCache.Unlock()
+	if !ok {
+		out, err := execCommand("gh", "auth", "token", "--hostname", acct.Host, "--user", acct.User).Output()
+		if err != nil {
+			return nil, fmt.Errorf("no gh login for %s on %s (run `gh auth login --hostname %s`)", acct.User, acct.Host, acct.Host)
+		}
+		token = strings.TrimSpace(string(out))
+		addSecret(token)
+		tokenCache.Lock()
+		tokenCache.tokens[key] = token
+		tokenCache.Unlock()
+	}
+	return append(env, "GH_TOKEN="+token, "GH_ENTERPRISE_TOKEN="+token), nil
+}
+
+// runGh executes gh with args using acct's credentials and returns stdout.
+func runGh(acct *Account, args ...string) ([]byte, error) {
+	if ghOverride != nil {
+		return ghOverride.run(args)
+	}
+	out, err := runWithRetry(args, func() ([]byte, error) {
+		if tokenClient != nil {
+			return tokenClient.run(acct, args)
+		}
+		return execGh(acct, args...)
+	})
+	if recording != nil {
+		recording.add(args, out, err)
+	}
+	return out, err
+}
+
+// splitRepoHost splits a HOST/OWNER/REPO reference into its host and
+// OWNER/REPO parts. Plain OWNER/REPO returns an empty host.
+func splitRepoHost(repo string) (host, ownerRepo string) {
+	if parts := strings.SplitN(repo, "/", 3); len(parts) == 3 {
+		return parts[0], parts[1] + "/" + parts[2]
+	}
+	return "", repo
+}
+
+// ghAPI calls `gh api` for endpoint, substituting {repo} with the repo's
+// OWNER/REPO and targeting its host. extra args go before the endpoint.
+func ghAPI(acct *Account, repo, endpoint string, extra ...string) ([]byte, error) {
+	host, ownerRepo := splitRepoHost(repo)
+	args := []string{"api"}
+	if host != "" {
+		args = append(args, "--hostname", host)
+	}
+	args = append(args, extra...)
+	args = append(args, strings.ReplaceAll(endpoint, "{repo}", ownerRepo))
+	return runGh(acct, args...)
+}
+
+func execGh(acct *Account, args ...string) ([]byte, error) {
+	env, err := accountEnv(acct)
+	if err != nil {
+		return nil, err
+	}
+	cmd := execCommand("gh", args...)
+	if len(env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err = cmd.Start()
+	timedOut := false
+	if err == nil {
+		timer := time.AfterFunc(ghTimeout, func() { cmd.Process.Kill() })
+		err = cmd.Wait()
+		timedOut = !timer.Stop()
+	}
+	out := stdout.Bytes()
+	exitCode := 0
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	logger.Debug("gh invocation",
+		"args", args,
+		"duration", time.Since(start),
+		"exit", exitCode,
+		"bytes", len(out),
+	)
+	if timedOut {
+		return nil, &ghError{kind: ghErrNetwork, msg: fmt.Sprintf("timed out after %s", ghTimeout)}
+	}
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			msg := strings.TrimSpace(stderr.String())
+			logger.Debug("gh error", "args", args, "stderr", msg)
+			return nil, newGhError(msg)
+		}
+		return nil, fmt.Errorf("gh CLI error: %w", err)
+	}
+	return out, nil
+}
+
 // CheckStatus represents the normalized status of a check.
 // The iota ordering matches the desired sort order.
 type CheckStatus int
@@ -18,18 +158,28 @@ type CheckStatus int
 const (
 	Running CheckStatus = iota
 	Fail
+	Cancelled
 	Pass
+	Neutral
 	Skipped
 )
 
+// lumpConclusions counts CANCELLED and NEUTRAL conclusions as Skipped, as
+// prtop used to; set from the display.lump_conclusions config option.
+var lumpConclusions bool
+
 func (s CheckStatus) String() string {
 	switch s {
 	case Running:
 		return "RUNNING"
 	case Fail:
 		return "FAIL"
+	case Cancelled:
+		return "CANCELLED"
 	case Pass:
 		return "PASS"
+	case Neutral:
+		return "NEUTRAL"
 	case Skipped:
 		return "SKIPPED"
 	}
@@ -37,44 +187,86 @@ func (s CheckStatus) String() string {
 }
 
 type Check struct {
-	Name       string
+	Name       string // display name, including the workflow
+	JobName    string // check run name or status context as reported
+	Workflow   string // Actions workflow name, empty for other providers
 	Status     CheckStatus
 	Duration   string
 	DetailsURL string
 	StartedAt  time.Time
 	Completed  bool
+	// CompletedAt is when the check finished; zero while it runs and for
+	// status contexts, which don't report it.
+	CompletedAt time.Time
+	// Description is a status context's one-line description (e.g.
+	// Codecov's "85.32% (+0.12%) compared to abc123"); empty for check runs.
+	Description string
+	// Provider is the CI system or GitHub App that reported the check, e.g.
+	// GitHub Actions or CircleCI; empty when it can't be told.
+	Provider string
+	// StatusContext is set for commit statuses, which unlike check runs
+	// have no output to show.
+	StatusContext bool
 }
 
 type PRData struct {
 	Title       string
 	HeadRefName string
+	HeadSHA     string
 	URL         string
 	Checks      []Check
+	TasksDone   int // checked items in the PR description's task list
+	TasksTotal  int
+	// ReviewDecision is APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED or empty.
+	ReviewDecision string
+	BaseRefName    string
+	// Mergeable is MERGEABLE, CONFLICTING or UNKNOWN (still being computed).
+	Mergeable string
+	// State is OPEN, CLOSED or MERGED.
+	State string
+	// CachedAt is when the data was fetched if it came from the response
+	// cache instead of a live gh call; zero for live data.
+	CachedAt time.Time
+
+	payloadBytes int    // size of the raw gh response
+	raw          []byte // the gh response itself, for the JSON overlay
 }
 
 type ghPRResponse struct {
-	Title              string        `json:"title"`
-	HeadRefName        string        `json:"headRefName"`
-	URL                string        `json:"url"`
-	StatusCheckRollup  []ghCheckItem `json:"statusCheckRollup"`
+	Title             string        `json:"title"`
+	Body              string        `json:"body"`
+	ReviewDecision    looseString   `json:"reviewDecision"`
+	BaseRefName       string        `json:"baseRefName"`
+	Mergeable         looseString   `json:"mergeable"`
+	State             looseString   `json:"state"`
+	HeadRefName       string        `json:"headRefName"`
+	HeadRefOid        string        `json:"headRefOid"`
+	URL               string        `json:"url"`
+	StatusCheckRollup []ghCheckItem `json:"statusCheckRollup"`
 }
 
 type ghCheckItem struct {
-	Typename     string `json:"__typename"`
-	Name         string `json:"name"`
-	Context      string `json:"context"`
-	Status       string `json:"status"`
-	Conclusion   string `json:"conclusion"`
-	State        string `json:"state"`
-	StartedAt    string `json:"startedAt"`
-	CompletedAt  string `json:"completedAt"`
-	DetailsURL   string `json:"detailsUrl"`
-	TargetURL    string `json:"targetUrl"`
-	WorkflowName string `json:"workflowName"`
+	Typename     string      `json:"__typename"`
+	Name         string      `json:"name"`
+	Context      string      `json:"context"`
+	Status       looseString `json:"status"`
+	Conclusion   looseString `json:"conclusion"`
+	State        looseString `json:"state"`
+	StartedAt    string      `json:"startedAt"`
+	CompletedAt  string      `json:"completedAt"`
+	DetailsURL   string      `json:"detailsUrl"`
+	TargetURL    string      `json:"targetUrl"`
+	Description  string      `json:"description"`
+	WorkflowName string      `json:"workflowName"`
 }
 
+// normalizeStatus maps a state or conclusion GitHub reports to a status,
+// going by the config's [[status]] entries for every check first.
 func normalizeStatus(raw string) CheckStatus {
 	raw = strings.ToUpper(strings.TrimSpace(raw))
+	if status, ok := overriddenStatus(raw); ok {
+		return status
+	}
 	switch raw {
 	case "SUCCESS", "PASS":
 		return Pass
@@ -82,11 +274,22 @@ func normalizeStatus(raw string) CheckStatus {
 		return Fail
 	case "IN_PROGRESS", "RUNNING", "PENDING", "QUEUED", "WAITING", "REQUESTED":
 		return Running
-	case "SKIPPED", "CANCELLED", "NEUTRAL", "STALE":
+	case "CANCELLED":
+		if lumpConclusions {
+			return Skipped
+		}
+		return Cancelled
+	case "NEUTRAL":
+		if lumpConclusions {
+			return Skipped
+		}
+		return Neutral
+	case "SKIPPED", "STALE":
 		return Skipped
 	case "":
 		return Running
 	}
+	logger.Debug("unknown check state, counted as running", "raw", raw)
 	return Running
 }
 
@@ -133,22 +336,53 @@ type PRSummary struct {
 	Title     string
 	URL       string
 	UpdatedAt string
+	CreatedAt string
+	Draft     bool
+	// CIState is the head commit's rollup state (SUCCESS, FAILURE, ERROR,
+	// PENDING, EXPECTED) or empty when unknown or there are no checks.
+	// SkipCI is set when there are no checks and the head commit message
+	// asks CI to skip it. CICounts are its checks by outcome. All three
+	// are filled in by fetchPRCIStates.
+	CIState  string
+	SkipCI   bool
+	CICounts ciCounts
+	// ReviewDecision (APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED or
+	// empty) and BaseRef are also filled in by fetchPRCIStates, as gh
+	// search can't return them.
+	ReviewDecision string
+	BaseRef        string
+	// Reason is why the PR is in the notification feed ("review
+	// requested", "mentioned"...), set by fetchNotificationPRs.
+	Reason string
+	// Author is the PR author's login, set by fetchBotPRs and
+	// fetchPRCIStates.
+	Author string
+	// Overlap lists the files the PR changes that the working directory
+	// changes too ("a.go, b.go"), set by fetchWorkspacePRs.
+	Overlap string
 }
 
-func fetchRecentPRs() ([]PRSummary, error) {
-	cmd := execCommand("gh", "search", "prs",
-		"--author=@me",
-		"--state=open",
-		"--sort=updated",
-		"--limit=5",
-		"--json", "number,title,repository,url,updatedAt",
-	)
-	out, err := cmd.Output()
+func fetchRecentPRs(acct *Account) ([]PRSummary, error) {
+	return searchPRs(acct, []string{"--author=@me", "--state=open", "--sort=updated", "--limit=5"}, "")
+}
+
+// fetchQueryPRs lists the open PRs matching a GitHub search query such as
+// "label:release-blocker milestone:v2.0", most recently updated first.
+func fetchQueryPRs(acct *Account, query string) ([]PRSummary, error) {
+	return searchPRs(acct, []string{"--state=open", "--sort=updated", "--limit=50"}, query)
+}
+
+// searchPRs runs `gh search prs` with flags and an optional search query.
+func searchPRs(acct *Account, flags []string, query string) ([]PRSummary, error) {
+	args := append([]string{"search", "prs"}, flags...)
+	args = append(args, "--json", "number,title,repository,url,updatedAt,createdAt,isDraft")
+	if query != "" {
+		// After "--" so qualifiers like -label:wip aren't taken as flags.
+		args = append(args, "--", query)
+	}
+	out, err := runGh(acct, args...)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh CLI error: %s", strings.TrimSpace(string(exitErr.Stderr)))
-		}
-		return nil, fmt.Errorf("gh CLI error: %w", err)
+		return nil, err
 	}
 
 	var raw []struct {
@@ -159,6 +393,8 @@ func fetchRecentPRs() ([]PRSummary, error) {
 		} `json:"repository"`
 		URL       string `json:"url"`
 		UpdatedAt string `json:"updatedAt"`
+		CreatedAt string `json:"createdAt"`
+		IsDraft   bool   `json:"isDraft"`
 	}
 	if err := json.Unmarshal(out, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse gh output: %w", err)
@@ -172,31 +408,401 @@ func fetchRecentPRs() ([]PRSummary, error) {
 			Title:     r.Title,
 			URL:       r.URL,
 			UpdatedAt: r.UpdatedAt,
+			CreatedAt: r.CreatedAt,
+			Draft:     r.IsDraft,
 		}
 	}
 	return prs, nil
 }
 
-func fetchPRData(repo string, prNumber string) (*PRData, error) {
-	cmd := execCommand("gh", "pr", "view", prNumber,
+// fetchRepoPRs lists the open PRs of a single repo, most recently updated first.
+func fetchRepoPRs(acct *Account, repo string) ([]PRSummary, error) {
+	out, err := runGh(acct, "pr", "list",
 		"--repo", repo,
-		"--json", "statusCheckRollup,title,headRefName,url",
+		"--state=open",
+		"--limit=30",
+		"--json", "number,title,url,updatedAt,createdAt,isDraft",
 	)
-	out, err := cmd.Output()
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh CLI error: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		return nil, err
+	}
+
+	var raw []struct {
+		Number    int    `json:"number"`
+		Title     string `json:"title"`
+		URL       string `json:"url"`
+		UpdatedAt string `json:"updatedAt"`
+		CreatedAt string `json:"createdAt"`
+		IsDraft   bool   `json:"isDraft"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+
+	prs := make([]PRSummary, len(raw))
+	for i, r := range raw {
+		prs[i] = PRSummary{
+			Repo:      repo,
+			Number:    r.Number,
+			Title:     r.Title,
+			URL:       r.URL,
+			UpdatedAt: r.UpdatedAt,
+			CreatedAt: r.CreatedAt,
+			Draft:     r.IsDraft,
 		}
-		return nil, fmt.Errorf("gh CLI error: %w", err)
 	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		return prs[i].UpdatedAt > prs[j].UpdatedAt
+	})
+	return prs, nil
+}
 
+// fetchBranchPR finds the number of repo's open PR whose head is branch.
+// Branches of the same name in several forks are ambiguous.
+func fetchBranchPR(acct *Account, repo, branch string) (string, error) {
+	out, err := runGh(acct, "pr", "list",
+		"--repo", repo,
+		"--head", branch,
+		"--state=open",
+		"--limit=5",
+		"--json", "number",
+	)
+	if err != nil {
+		return "", err
+	}
+	var raw []struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return "", fmt.Errorf("failed to parse gh output: %w", err)
+	}
+	switch len(raw) {
+	case 0:
+		return "", fmt.Errorf("no open PR in %s has the head branch %s", repo, branch)
+	case 1:
+		return strconv.Itoa(raw[0].Number), nil
+	}
+	numbers := make([]string, len(raw))
+	for i, r := range raw {
+		numbers[i] = "#" + strconv.Itoa(r.Number)
+	}
+	return "", fmt.Errorf("several open PRs in %s have the head branch %s (%s); give the number instead",
+		repo, branch, strings.Join(numbers, ", "))
+}
+
+// skipCIMarker matches the commit message markers that make GitHub Actions
+// skip a push: [skip ci], [ci skip], [no ci], [skip actions], [actions skip]
+// and a skip-checks: true trailer.
+var skipCIMarker = regexp.MustCompile(`(?i)\[(skip ci|ci skip|no ci|skip actions|actions skip)\]|(?m)^skip-checks:\s*true\s*$`)
+
+// prCI is what the picker shows about a PR beyond what the list gave:
+// its head commit's CI, and its author, review decision and base branch.
+type prCI struct {
+	State          string
+	SkipCI         bool
+	Counts         ciCounts
+	Author         string
+	ReviewDecision string
+	BaseRef        string
+}
+
+// ciCounts are a PR's checks by outcome, shown beside it in the picker.
+// Cancelled, neutral and skipped checks aren't counted.
+type ciCounts struct {
+	Pass, Fail, Running int
+}
+
+func (c ciCounts) total() int { return c.Pass + c.Fail + c.Running }
+
+// add counts n checks in a CheckRunState or StatusState.
+func (c *ciCounts) add(state string, n int) {
+	switch state {
+	case "SUCCESS":
+		c.Pass += n
+	case "FAILURE", "ERROR", "TIMED_OUT", "STARTUP_FAILURE", "ACTION_REQUIRED":
+		c.Fail += n
+	case "IN_PROGRESS", "PENDING", "QUEUED", "WAITING", "REQUESTED", "EXPECTED":
+		c.Running += n
+	}
+}
+
+// ciBatchSize is how many PRs one CI state query asks about. Longer lists
+// are split into batches fetched in parallel.
+const ciBatchSize = 20
+
+// fetchPRCIStates looks up the head commit CI state and check counts of
+// every PR, keyed by prKey, in parallel batches of ciBatchSize per host
+// and account. Each batch is sent with the account accountForRepo picks
+// for its repos, falling back to acct. Each PR's summary is cached for
+// peekPRCIStates. Batches that fail leave their PRs out, and the errors
+// are returned with the rest.
+func fetchPRCIStates(accounts []Account, acct *Account, prs []PRSummary) (map[string]prCI, error) {
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	type batchKey struct {
+		host string
+		acct *Account
+	}
+	var batches [][]PRSummary
+	var batchAccts []*Account
+	open := map[batchKey]int{}
+	for _, pr := range prs {
+		host, _ := splitRepoHost(pr.Repo)
+		key := batchKey{host, accountForRepo(accounts, pr.Repo, acct)}
+		i, ok := open[key]
+		if !ok || len(batches[i]) == ciBatchSize {
+			i = len(batches)
+			open[key] = i
+			batches = append(batches, nil)
+			batchAccts = append(batchAccts, key.acct)
+		}
+		batches[i] = append(batches[i], pr)
+	}
+
+	results := make([]map[string]prCI, len(batches))
+	errs := make([]error, len(batches))
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fetchPRCIBatch(batchAccts[i], batch)
+		}()
+	}
+	wg.Wait()
+
+	states := map[string]prCI{}
+	for _, r := range results {
+		for key, ci := range r {
+			states[key] = ci
+			if data, err := json.Marshal(ci); err == nil {
+				respCache.put(key+" ci", data)
+			}
+		}
+	}
+	return states, errors.Join(errs...)
+}
+
+// peekPRCIStates returns the cached CI summaries of prs, shown while
+// fetchPRCIStates runs.
+func peekPRCIStates(prs []PRSummary) map[string]prCI {
+	states := map[string]prCI{}
+	for _, pr := range prs {
+		key := summaryKey(pr)
+		e, _, ok := respCache.get(key + " ci")
+		if !ok {
+			continue
+		}
+		var ci prCI
+		if json.Unmarshal([]byte(e.Data), &ci) == nil {
+			states[key] = ci
+		}
+	}
+	return states
+}
+
+// fetchPRCIBatch asks about prs in a single GraphQL request. All PRs must
+// live on the same host.
+func fetchPRCIBatch(acct *Account, prs []PRSummary) (map[string]prCI, error) {
+	var q strings.Builder
+	q.WriteString("query {\n")
+	for i, pr := range prs {
+		_, ownerRepo := splitRepoHost(pr.Repo)
+		owner, name, _ := strings.Cut(ownerRepo, "/")
+		fmt.Fprintf(&q, "  pr%d: repository(owner: %q, name: %q) { pullRequest(number: %d) { author { login } reviewDecision baseRefName commits(last: 1) { nodes { commit { message statusCheckRollup { state contexts { checkRunCountsByState { state count } statusContextCountsByState { state count } } } } } } } }\n",
+			i, owner, name, pr.Number)
+	}
+	q.WriteString("}")
+	out, err := ghAPI(acct, prs[0].Repo, "graphql", "-f", "query="+q.String())
+	if err != nil {
+		return nil, err
+	}
+
+	type stateCount struct {
+		State string `json:"state"`
+		Count int    `json:"count"`
+	}
+	var resp struct {
+		Data map[string]*struct {
+			PullRequest *struct {
+				Author *struct {
+					Login string `json:"login"`
+				} `json:"author"`
+				ReviewDecision string `json:"reviewDecision"`
+				BaseRefName    string `json:"baseRefName"`
+				Commits        struct {
+					Nodes []struct {
+						Commit struct {
+							Message           string `json:"message"`
+							StatusCheckRollup *struct {
+								State    string `json:"state"`
+								Contexts struct {
+									CheckRuns      []stateCount `json:"checkRunCountsByState"`
+									StatusContexts []stateCount `json:"statusContextCountsByState"`
+								} `json:"contexts"`
+							} `json:"statusCheckRollup"`
+						} `json:"commit"`
+					} `json:"nodes"`
+				} `json:"commits"`
+			} `json:"pullRequest"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse CI states: %w", err)
+	}
+
+	states := map[string]prCI{}
+	for i, pr := range prs {
+		repo := resp.Data[fmt.Sprintf("pr%d", i)]
+		if repo == nil || repo.PullRequest == nil || len(repo.PullRequest.Commits.Nodes) == 0 {
+			continue
+		}
+		commit := repo.PullRequest.Commits.Nodes[0].Commit
+		ci := prCI{ReviewDecision: repo.PullRequest.ReviewDecision, BaseRef: repo.PullRequest.BaseRefName}
+		if a := repo.PullRequest.Author; a != nil {
+			ci.Author = a.Login
+		}
+		if rollup := commit.StatusCheckRollup; rollup != nil {
+			ci.State = rollup.State
+			for _, c := range append(rollup.Contexts.CheckRuns, rollup.Contexts.StatusContexts...) {
+				ci.Counts.add(c.State, c.Count)
+			}
+		} else {
+			ci.SkipCI = skipCIMarker.MatchString(commit.Message)
+		}
+		states[prKey(pr.Repo, fmt.Sprintf("%d", pr.Number))] = ci
+	}
+	return states, nil
+}
+
+// rerunWorkflow re-runs an Actions workflow run: only jobID when set, only
+// the failed jobs when failedOnly, otherwise the entire run.
+func rerunWorkflow(acct *Account, repo, runID, jobID string, failedOnly bool) error {
+	args := []string{"run", "rerun"}
+	switch {
+	case jobID != "":
+		args = append(args, "--job", jobID)
+	case failedOnly:
+		args = append(args, runID, "--failed")
+	default:
+		args = append(args, runID)
+	}
+	args = append(args, "--repo", repo)
+	_, err := runGh(acct, args...)
+	return err
+}
+
+// taskItem matches a Markdown task list item: "- [ ]", "* [x]", "1. [X]".
+var taskItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s`)
+
+// parseTaskList counts the checked and total task list items in a Markdown
+// body, ignoring fenced code blocks.
+func parseTaskList(body string) (done, total int) {
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := taskItem.FindStringSubmatch(line + " ")
+		if m == nil {
+			continue
+		}
+		total++
+		if m[1] != " " {
+			done++
+		}
+	}
+	return done, total
+}
+
+// updateBranch brings the PR branch up to date with its base on GitHub,
+// by merge or (with rebase) by rebasing.
+func updateBranch(acct *Account, repo, prNumber string, rebase bool) error {
+	args := []string{"pr", "update-branch", prNumber, "--repo", repo}
+	if rebase {
+		args = append(args, "--rebase")
+	}
+	_, err := runGh(acct, args...)
+	return err
+}
+
+func fetchPRData(acct *Account, repo string, prNumber string) (*PRData, error) {
+	out, cachedAt, err := cachedGh(acct, cacheKey(repo, prNumber, "pr view"), "pr", "view", prNumber,
+		"--repo", repo,
+		"--json", prViewJSON(),
+	)
+	if field := unknownJSONField(err); field != "" {
+		// An older gh: go without the field if the PR can be shown without it
+		if !dropPRViewField(field) {
+			return nil, ghTooOld(field)
+		}
+		v, _ := detectGh()
+		logger.Debug("gh lacks a pr view field", "field", field, "gh", v)
+		return fetchPRData(acct, repo, prNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsePRView(out)
+	if err != nil {
+		return nil, err
+	}
+	data.CachedAt = cachedAt
+	return data, nil
+}
+
+// peekPRData returns the cached PR data without calling gh, or nil if
+// nothing is cached. It is shown while the first live fetch is in flight.
+func peekPRData(repo, prNumber string) *PRData {
+	e, _, ok := respCache.get(cacheKey(repo, prNumber, "pr view"))
+	if !ok {
+		return nil
+	}
+	data, err := parsePRView([]byte(e.Data))
+	if err != nil {
+		return nil
+	}
+	data.CachedAt = e.At
+	return data
+}
+
+// parsePRView converts `gh pr view --json` output into PRData.
+func parsePRView(out []byte) (*PRData, error) {
 	var resp ghPRResponse
 	if err := json.Unmarshal(out, &resp); err != nil {
 		return nil, fmt.Errorf("failed to parse gh output: %w", err)
 	}
 
-	checks := make([]Check, 0, len(resp.StatusCheckRollup))
-	for _, item := range resp.StatusCheckRollup {
+	checks := parseCheckItems(resp.StatusCheckRollup)
+	done, total := parseTaskList(resp.Body)
+	return &PRData{
+		Title:          resp.Title,
+		HeadRefName:    resp.HeadRefName,
+		HeadSHA:        resp.HeadRefOid,
+		URL:            resp.URL,
+		Checks:         checks,
+		TasksDone:      done,
+		TasksTotal:     total,
+		ReviewDecision: string(resp.ReviewDecision),
+		BaseRefName:    resp.BaseRefName,
+		Mergeable:      string(resp.Mergeable),
+		State:          string(resp.State),
+		payloadBytes:   len(out),
+		raw:            out,
+	}, nil
+}
+
+// parseCheckItems converts statusCheckRollup items into checks, failures
+// first.
+func parseCheckItems(items []ghCheckItem) []Check {
+	checks := make([]Check, 0, len(items))
+	for _, item := range items {
 		name := item.Name
 		if name == "" {
 			name = item.Context
@@ -204,18 +810,19 @@ func fetchPRData(repo string, prNumber string) (*PRData, error) {
 		if name == "" {
 			name = "unknown"
 		}
+		jobName := name
 		if item.WorkflowName != "" {
 			name = fmt.Sprintf("%s (%s)", name, item.WorkflowName)
 		}
 
-		var status CheckStatus
-		if item.Conclusion != "" {
-			status = normalizeStatus(item.Conclusion)
-		} else if item.Status != "" {
-			status = normalizeStatus(item.Status)
-		} else {
-			status = normalizeStatus(item.State)
+		raw := string(item.Conclusion)
+		if raw == "" {
+			raw = string(item.Status)
 		}
+		if raw == "" {
+			raw = string(item.State)
+		}
+		status := checkStatus(raw, name, jobName)
 
 		completedAt := item.CompletedAt
 		if strings.HasPrefix(completedAt, "0001") {
@@ -229,6 +836,10 @@ func fetchPRData(repo string, prNumber string) (*PRData, error) {
 		}
 
 		dur, startedAt, completed := parseDuration(item.StartedAt, completedAt)
+		var completedTime time.Time
+		if completed {
+			completedTime, _ = time.Parse(time.RFC3339, completedAt)
+		}
 		if forceCompleted {
 			completed = true
 			dur = "???"
@@ -240,12 +851,18 @@ func fetchPRData(repo string, prNumber string) (*PRData, error) {
 		}
 
 		checks = append(checks, Check{
-			Name:       name,
-			Status:     status,
-			Duration:   dur,
-			DetailsURL: detailsURL,
-			StartedAt:  startedAt,
-			Completed:  completed,
+			Name:          name,
+			JobName:       jobName,
+			Workflow:      item.WorkflowName,
+			Status:        status,
+			Duration:      dur,
+			DetailsURL:    detailsURL,
+			StartedAt:     startedAt,
+			Completed:     completed,
+			CompletedAt:   completedTime,
+			Description:   item.Description,
+			Provider:      checkProvider(item, detailsURL),
+			StatusContext: item.Typename == "StatusContext",
 		})
 	}
 
@@ -255,11 +872,5 @@ func fetchPRData(repo string, prNumber string) (*PRData, error) {
 		}
 		return checks[i].Name < checks[j].Name
 	})
-
-	return &PRData{
-		Title:       resp.Title,
-		HeadRefName: resp.HeadRefName,
-		URL:         resp.URL,
-		Checks:      checks,
-	}, nil
+	return checks
 }
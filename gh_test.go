@@ -1,10 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -20,7 +23,9 @@ func TestCheckStatusString(t *testing.T) {
 	}{
 		{Running, "RUNNING"},
 		{Fail, "FAIL"},
+		{Cancelled, "CANCELLED"},
 		{Pass, "PASS"},
+		{Neutral, "NEUTRAL"},
 		{Skipped, "SKIPPED"},
 		{CheckStatus(99), "UNKNOWN"},
 	}
@@ -59,10 +64,11 @@ func TestNormalizeStatus(t *testing.T) {
 		{"QUEUED", Running},
 		{"WAITING", Running},
 		{"REQUESTED", Running},
+		// Cancelled, Neutral
+		{"CANCELLED", Cancelled},
+		{"NEUTRAL", Neutral},
 		// Skipped
 		{"SKIPPED", Skipped},
-		{"CANCELLED", Skipped},
-		{"NEUTRAL", Skipped},
 		{"STALE", Skipped},
 		// Case insensitivity
 		{"success", Pass},
@@ -86,6 +92,16 @@ func TestNormalizeStatus(t *testing.T) {
 	}
 }
 
+func TestNormalizeStatusLumped(t *testing.T) {
+	lumpConclusions = true
+	t.Cleanup(func() { lumpConclusions = false })
+	for _, raw := range []string{"CANCELLED", "NEUTRAL", "SKIPPED"} {
+		if got := normalizeStatus(raw); got != Skipped {
+			t.Errorf("normalizeStatus(%q) = %v, want SKIPPED", raw, got)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // parseDuration
 // ---------------------------------------------------------------------------
@@ -161,6 +177,35 @@ func TestParseDuration(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// parseTaskList
+// ---------------------------------------------------------------------------
+
+func TestParseTaskList(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantDone  int
+		wantTotal int
+	}{
+		{"empty", "", 0, 0},
+		{"no tasks", "Fixes #12\n\n- just a bullet", 0, 0},
+		{"mixed", "- [x] tests\n- [ ] docs\n* [X] changelog\n+ [ ] release notes", 2, 4},
+		{"numbered and nested", "1. [x] one\n   - [ ] nested", 1, 2},
+		{"crlf", "- [x] a\r\n- [ ] b\r\n", 1, 2},
+		{"fenced code ignored", "- [x] real\n```\n- [ ] example\n```", 1, 1},
+		{"not a task", "- [y] nope\n-[x] no space", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, total := parseTaskList(tt.body)
+			if done != tt.wantDone || total != tt.wantTotal {
+				t.Errorf("parseTaskList = %d/%d, want %d/%d", done, total, tt.wantDone, tt.wantTotal)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // formatDuration
 // ---------------------------------------------------------------------------
@@ -208,12 +253,34 @@ func fakeExecCommand(stdout string, stderr string, exitCode int) func(string, ..
 	}
 }
 
+// fakeExecByArgs is like fakeExecCommand but picks stdout by matching the gh
+// arguments: the longest key contained in the space-joined args wins. Unmatched
+// invocations fail with exit code 1.
+func fakeExecByArgs(responses map[string]string) func(string, ...string) *exec.Cmd {
+	return func(command string, args ...string) *exec.Cmd {
+		joined := strings.Join(args, " ")
+		best := ""
+		for key := range responses {
+			if strings.Contains(joined, key) && len(key) > len(best) {
+				best = key
+			}
+		}
+		if best == "" {
+			return fakeExecCommand("", "unexpected gh call: "+joined, 1)(command, args...)
+		}
+		return fakeExecCommand(responses[best], "", 0)(command, args...)
+	}
+}
+
 // TestHelperProcess is the subprocess entry point used by fakeExecCommand.
 // It is not a real test.
 func TestHelperProcess(t *testing.T) {
 	if os.Getenv("GO_TEST_HELPER_PROCESS") != "1" {
 		return
 	}
+	if d, err := time.ParseDuration(os.Getenv("GO_TEST_HELPER_SLEEP")); err == nil {
+		time.Sleep(d)
+	}
 	stdout := os.Getenv("GO_TEST_HELPER_STDOUT")
 	stderr := os.Getenv("GO_TEST_HELPER_STDERR")
 	exitCode := 0
@@ -224,20 +291,256 @@ func TestHelperProcess(t *testing.T) {
 	os.Exit(exitCode)
 }
 
+// ---------------------------------------------------------------------------
+// accountEnv
+// ---------------------------------------------------------------------------
+
+func TestExecGhTimeout(t *testing.T) {
+	slow := fakeExecCommand("late", "", 0)
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		cmd := slow(command, args...)
+		cmd.Env = append(cmd.Env, "GO_TEST_HELPER_SLEEP=5s")
+		return cmd
+	}
+	ghTimeout = 100 * time.Millisecond
+	t.Cleanup(func() {
+		execCommand = exec.Command
+		ghTimeout = 30 * time.Second
+	})
+
+	start := time.Now()
+	_, err := execGh(nil, "pr", "view", "1")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+	if d := time.Since(start); d > 3*time.Second {
+		t.Errorf("execGh took %s, want it killed near the timeout", d)
+	}
+}
+
+func TestAccountEnv(t *testing.T) {
+	t.Run("nil account", func(t *testing.T) {
+		env, err := accountEnv(nil)
+		if err != nil || env != nil {
+			t.Errorf("accountEnv(nil) = %v, %v; want nil, nil", env, err)
+		}
+	})
+
+	t.Run("host only", func(t *testing.T) {
+		env, err := accountEnv(&Account{Host: "ghe.corp.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(env) != 1 || env[0] != "GH_HOST=ghe.corp.com" {
+			t.Errorf("env = %v, want [GH_HOST=ghe.corp.com]", env)
+		}
+	})
+
+	t.Run("user looks up token", func(t *testing.T) {
+		execCommand = fakeExecCommand("tok123\n", "", 0)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		env, err := accountEnv(&Account{Host: "example.test", User: "alice"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		found := false
+		for _, e := range env {
+			if e == "GH_TOKEN=tok123" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("env = %v, should contain GH_TOKEN=tok123", env)
+		}
+	})
+
+	t.Run("missing login", func(t *testing.T) {
+		execCommand = fakeExecCommand("", "no account", 1)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		_, err := accountEnv(&Account{Host: "example.test", User: "nobody"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "gh auth login") {
+			t.Errorf("error = %q, should suggest gh auth login", err)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// fetchPRCIStates
+// ---------------------------------------------------------------------------
+
+func TestFetchPRCIStates(t *testing.T) {
+	resetRespCache(t)
+	execCommand = fakeExecByArgs(map[string]string{
+		"graphql": `{"data":{
+			"pr0":{"pullRequest":{"author":{"login":"alice"},"reviewDecision":"APPROVED","baseRefName":"main","commits":{"nodes":[{"commit":{"message":"fix","statusCheckRollup":{"state":"FAILURE","contexts":{
+				"checkRunCountsByState":[{"state":"SUCCESS","count":3},{"state":"FAILURE","count":1},{"state":"IN_PROGRESS","count":1},{"state":"SKIPPED","count":4}],
+				"statusContextCountsByState":[{"state":"PENDING","count":1},{"state":"ERROR","count":1}]}}}}]}}},
+			"pr1":{"pullRequest":{"commits":{"nodes":[{"commit":{"message":"docs only [skip ci]","statusCheckRollup":null}}]}}},
+			"pr2":{"pullRequest":{"commits":{"nodes":[{"commit":{"message":"wip","statusCheckRollup":null}}]}}},
+			"pr3":null}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	prs := []PRSummary{
+		{Repo: "o/r", Number: 1},
+		{Repo: "o/r", Number: 2},
+		{Repo: "x/y", Number: 3},
+		{Repo: "gone/repo", Number: 4},
+	}
+	states, err := fetchPRCIStates(nil, nil, prs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		key  string
+		want prCI
+	}{
+		{"o/r#1", prCI{State: "FAILURE", Counts: ciCounts{Pass: 3, Fail: 2, Running: 2}, Author: "alice", ReviewDecision: "APPROVED", BaseRef: "main"}},
+		{"o/r#2", prCI{SkipCI: true}},
+		{"x/y#3", prCI{}},
+	}
+	for _, tt := range tests {
+		if got := states[tt.key]; got != tt.want {
+			t.Errorf("states[%s] = %+v, want %+v", tt.key, got, tt.want)
+		}
+	}
+	if _, ok := states["gone/repo#4"]; ok {
+		t.Error("unresolvable PRs should be left out")
+	}
+	if got := peekPRCIStates(prs[:1])["o/r#1"]; got != states["o/r#1"] {
+		t.Errorf("cached CI state = %+v, want %+v", got, states["o/r#1"])
+	}
+}
+
+func TestFetchPRCIStatesBatches(t *testing.T) {
+	resetRespCache(t)
+	var mu sync.Mutex
+	var queries []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		query := strings.Join(args, " ")
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+		if strings.Contains(query, `"broken"`) {
+			return nil, errors.New("HTTP 502")
+		}
+		var data []string
+		for i := 0; strings.Contains(query, fmt.Sprintf("pr%d:", i)); i++ {
+			data = append(data, fmt.Sprintf(`"pr%d":{"pullRequest":{"commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"SUCCESS"}}}]}}}`, i))
+		}
+		return []byte(`{"data":{` + strings.Join(data, ",") + `}}`), nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	var prs []PRSummary
+	for i := range ciBatchSize + 5 {
+		prs = append(prs, PRSummary{Repo: "o/r", Number: i + 1})
+	}
+	prs = append(prs, PRSummary{Repo: "ghe.example.com/corp/app", Number: 1}, PRSummary{Repo: "ghe.example.com/corp/broken", Number: 2})
+	states, err := fetchPRCIStates(nil, nil, prs)
+	if err == nil {
+		t.Error("a failed batch should be reported")
+	}
+	if len(queries) != 3 {
+		t.Fatalf("made %d queries, want 2 for github.com and 1 for the other host", len(queries))
+	}
+	for _, q := range queries {
+		if strings.Contains(q, "ghe.example.com") != strings.Contains(q, "--hostname ghe.example.com") {
+			t.Errorf("query mixes hosts: %s", q)
+		}
+	}
+	if len(states) != ciBatchSize+5 {
+		t.Errorf("got %d states, want the %d from the good batches", len(states), ciBatchSize+5)
+	}
+}
+
+func TestFetchPRCIStatesPerHostAccount(t *testing.T) {
+	resetRespCache(t)
+	var mu sync.Mutex
+	var logins []string
+	fake := fakeExecByArgs(map[string]string{
+		"auth token": "tok\n",
+		"graphql":    `{"data":{"pr0":{"pullRequest":{"commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"SUCCESS"}}}]}}}}}`,
+	})
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		if joined := strings.Join(args, " "); strings.HasPrefix(joined, "auth token") {
+			mu.Lock()
+			logins = append(logins, joined)
+			mu.Unlock()
+		}
+		return fake(command, args...)
+	}
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	accounts := []Account{
+		{Name: "public", Host: "github.com", User: "ci-states-public"},
+		{Name: "corp", Host: "ghe.example.com", User: "ci-states-corp"},
+	}
+	prs := []PRSummary{{Repo: "o/r", Number: 1}, {Repo: "ghe.example.com/corp/app", Number: 2}}
+	if _, err := fetchPRCIStates(accounts, &accounts[0], prs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slices.Sort(logins)
+	want := []string{
+		"auth token --hostname ghe.example.com --user ci-states-corp",
+		"auth token --hostname github.com --user ci-states-public",
+	}
+	if !slices.Equal(logins, want) {
+		t.Errorf("token lookups = %q, want %q", logins, want)
+	}
+}
+
+func TestSkipCIMarker(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"Update README [skip ci]":          true,
+		"[CI SKIP] bump":                   true,
+		"chore [skip actions]":             true,
+		"tidy\n\nskip-checks: true":        true,
+		"skip ci please":                   false,
+		"mention skip-checks: true inline": false,
+	} {
+		if got := skipCIMarker.MatchString(msg); got != want {
+			t.Errorf("skipCIMarker(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // fetchRecentPRs
 // ---------------------------------------------------------------------------
 
+func TestFetchQueryPRs(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"search prs --state=open --sort=updated --limit=50 --json number,title,repository,url,updatedAt,createdAt,isDraft -- -label:wip milestone:v2.0": `[
+			{"number":7,"title":"Blocker","repository":{"nameWithOwner":"o/r"}}
+		]`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	prs, err := fetchQueryPRs(nil, "-label:wip milestone:v2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Repo != "o/r" || prs[0].Number != 7 {
+		t.Errorf("prs = %+v", prs)
+	}
+}
+
 func TestFetchRecentPRs(t *testing.T) {
 	t.Run("success with 2 PRs", func(t *testing.T) {
 		json := `[
 			{"number":42,"title":"Add feature","repository":{"nameWithOwner":"owner/repo"},"url":"https://github.com/owner/repo/pull/42","updatedAt":"2024-01-01T00:00:00Z"},
-			{"number":99,"title":"Fix bug","repository":{"nameWithOwner":"other/project"},"url":"https://github.com/other/project/pull/99","updatedAt":"2024-01-02T00:00:00Z"}
+			{"number":99,"title":"Fix bug","repository":{"nameWithOwner":"other/project"},"url":"https://github.com/other/project/pull/99","updatedAt":"2024-01-02T00:00:00Z","isDraft":true}
 		]`
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		prs, err := fetchRecentPRs()
+		prs, err := fetchRecentPRs(nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -256,13 +559,16 @@ func TestFetchRecentPRs(t *testing.T) {
 		if prs[1].Repo != "other/project" {
 			t.Errorf("prs[1].Repo = %q, want %q", prs[1].Repo, "other/project")
 		}
+		if prs[0].Draft || !prs[1].Draft {
+			t.Errorf("Draft = %v, %v; want false, true", prs[0].Draft, prs[1].Draft)
+		}
 	})
 
 	t.Run("empty list", func(t *testing.T) {
 		execCommand = fakeExecCommand("[]", "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		prs, err := fetchRecentPRs()
+		prs, err := fetchRecentPRs(nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -275,7 +581,7 @@ func TestFetchRecentPRs(t *testing.T) {
 		execCommand = fakeExecCommand("", "gh: not logged in", 1)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		_, err := fetchRecentPRs()
+		_, err := fetchRecentPRs(nil)
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -288,7 +594,7 @@ func TestFetchRecentPRs(t *testing.T) {
 		execCommand = fakeExecCommand("{invalid json", "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		_, err := fetchRecentPRs()
+		_, err := fetchRecentPRs(nil)
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -298,6 +604,48 @@ func TestFetchRecentPRs(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// fetchRepoPRs
+// ---------------------------------------------------------------------------
+
+func TestFetchRepoPRs(t *testing.T) {
+	t.Run("success sorted by updatedAt", func(t *testing.T) {
+		json := `[
+			{"number":1,"title":"Old","url":"https://github.com/o/r/pull/1","updatedAt":"2024-01-01T00:00:00Z"},
+			{"number":2,"title":"New","url":"https://github.com/o/r/pull/2","updatedAt":"2024-02-01T00:00:00Z"}
+		]`
+		execCommand = fakeExecCommand(json, "", 0)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		prs, err := fetchRepoPRs(nil, "o/r")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prs) != 2 {
+			t.Fatalf("got %d PRs, want 2", len(prs))
+		}
+		if prs[0].Number != 2 {
+			t.Errorf("prs[0].Number = %d, want 2 (most recent first)", prs[0].Number)
+		}
+		if prs[0].Repo != "o/r" {
+			t.Errorf("prs[0].Repo = %q, want %q", prs[0].Repo, "o/r")
+		}
+	})
+
+	t.Run("gh CLI error", func(t *testing.T) {
+		execCommand = fakeExecCommand("", "could not resolve to a Repository", 1)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		_, err := fetchRepoPRs(nil, "o/missing")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "could not resolve") {
+			t.Errorf("error = %q, should contain stderr message", err)
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // fetchPRData
 // ---------------------------------------------------------------------------
@@ -334,7 +682,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("owner/repo", "1")
+		data, err := fetchPRData(nil, "owner/repo", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -386,7 +734,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -406,6 +754,9 @@ func TestFetchPRData(t *testing.T) {
 		if !c.Completed {
 			t.Error("Completed should be true for StatusContext with non-Running status")
 		}
+		if !c.CompletedAt.IsZero() {
+			t.Errorf("CompletedAt = %v, want zero when the status context doesn't report it", c.CompletedAt)
+		}
 	})
 
 	t.Run("workflow name appended", func(t *testing.T) {
@@ -427,7 +778,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -462,7 +813,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -495,7 +846,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -523,7 +874,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -550,7 +901,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -579,7 +930,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -614,18 +965,40 @@ func TestFetchPRData(t *testing.T) {
 					"conclusion": "FAILURE",
 					"startedAt": "2024-01-01T10:00:00Z",
 					"completedAt": "2024-01-01T10:00:05Z"
+				},
+				{
+					"__typename": "CheckRun",
+					"name": "gamma",
+					"conclusion": "CANCELLED",
+					"startedAt": "2024-01-01T10:00:00Z",
+					"completedAt": "2024-01-01T10:00:05Z"
+				},
+				{
+					"__typename": "CheckRun",
+					"name": "delta",
+					"conclusion": "NEUTRAL",
+					"startedAt": "2024-01-01T10:00:00Z",
+					"completedAt": "2024-01-01T10:00:05Z"
+				},
+				{
+					"__typename": "CheckRun",
+					"name": "epsilon",
+					"conclusion": "SKIPPED",
+					"startedAt": "2024-01-01T10:00:00Z",
+					"completedAt": "2024-01-01T10:00:05Z"
 				}
 			]
 		}`
 		execCommand = fakeExecCommand(json, "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		data, err := fetchPRData("o/r", "1")
+		data, err := fetchPRData(nil, "o/r", "1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		// Expected order: Fail(beta), Pass(alpha), Pass(zebra)
-		expected := []string{"beta", "alpha", "zebra"}
+		// Expected order: Fail(beta), Cancelled(gamma), Pass(alpha), Pass(zebra),
+		// Neutral(delta), Skipped(epsilon)
+		expected := []string{"beta", "gamma", "alpha", "zebra", "delta", "epsilon"}
 		for i, name := range expected {
 			if data.Checks[i].Name != name {
 				t.Errorf("checks[%d].Name = %q, want %q", i, data.Checks[i].Name, name)
@@ -634,10 +1007,11 @@ func TestFetchPRData(t *testing.T) {
 	})
 
 	t.Run("gh CLI error", func(t *testing.T) {
+		resetRespCache(t)
 		execCommand = fakeExecCommand("", "not found", 1)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		_, err := fetchPRData("o/r", "1")
+		_, err := fetchPRData(nil, "o/r", "1")
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -650,7 +1024,7 @@ func TestFetchPRData(t *testing.T) {
 		execCommand = fakeExecCommand("not json", "", 0)
 		t.Cleanup(func() { execCommand = exec.Command })
 
-		_, err := fetchPRData("o/r", "1")
+		_, err := fetchPRData(nil, "o/r", "1")
 		if err == nil {
 			t.Fatal("expected error, got nil")
 		}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// githubRollup is GitHub's own verdict on the head commit's checks, which
+// is what branch protection enforces, and the status checks the base
+// branch requires.
+type githubRollup struct {
+	repo     string
+	sha      string
+	state    string   // SUCCESS, FAILURE, ERROR, PENDING or EXPECTED; "" with no checks
+	required []string // required contexts; nil when protection can't be read
+	err      error
+}
+
+type githubRollupMsg struct {
+	rollup *githubRollup
+}
+
+const githubRollupQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      commits(last: 1) { nodes { commit { oid statusCheckRollup { state } } } }
+    }
+  }
+}`
+
+// requiredContextsQuery is separate because reading branch protection
+// takes more access than reading the PR; without it the rollup state is
+// still shown.
+const requiredContextsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      baseRef { branchProtectionRule { requiredStatusCheckContexts } }
+    }
+  }
+}`
+
+func fetchGithubRollup(acct *Account, repo, prNumber string) *githubRollup {
+	r := &githubRollup{repo: repo}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	vars := []string{"-F", "owner=" + owner, "-F", "name=" + name, "-F", "number=" + prNumber}
+	out, err := ghAPI(acct, repo, "graphql", append([]string{"-f", "query=" + githubRollupQuery}, vars...)...)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					Commits struct {
+						Nodes []struct {
+							Commit struct {
+								OID               string `json:"oid"`
+								StatusCheckRollup *struct {
+									State string `json:"state"`
+								} `json:"statusCheckRollup"`
+							} `json:"commit"`
+						} `json:"nodes"`
+					} `json:"commits"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		r.err = fmt.Errorf("failed to parse status rollup: %w", err)
+		return r
+	}
+	for _, n := range resp.Data.Repository.PullRequest.Commits.Nodes {
+		r.sha = n.Commit.OID
+		if n.Commit.StatusCheckRollup != nil {
+			r.state = n.Commit.StatusCheckRollup.State
+		}
+	}
+
+	out, err = ghAPI(acct, repo, "graphql", append([]string{"-f", "query=" + requiredContextsQuery}, vars...)...)
+	if err != nil {
+		logger.Debug("required contexts unavailable", "repo", repo, "err", err)
+		return r
+	}
+	var protection struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					BaseRef *struct {
+						BranchProtectionRule *struct {
+							RequiredStatusCheckContexts []string `json:"requiredStatusCheckContexts"`
+						} `json:"branchProtectionRule"`
+					} `json:"baseRef"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &protection); err == nil {
+		if ref := protection.Data.Repository.PullRequest.BaseRef; ref != nil && ref.BranchProtectionRule != nil {
+			r.required = ref.BranchProtectionRule.RequiredStatusCheckContexts
+		}
+	}
+	return r
+}
+
+// githubRollupKey changes whenever a check does, which is when GitHub's
+// rollup can.
+func (m model) githubRollupKey() string {
+	var b strings.Builder
+	b.WriteString(prKey(m.repo, m.prData.HeadSHA))
+	for _, c := range m.prData.Checks {
+		fmt.Fprintf(&b, "|%s=%d", c.Name, c.Status)
+	}
+	return b.String()
+}
+
+// githubRollupCmd asks for GitHub's rollup when the PR's checks changed
+// since it last asked.
+func (m model) githubRollupCmd() (model, tea.Cmd) {
+	if m.prData == nil || m.prData.HeadSHA == "" {
+		return m, nil
+	}
+	key := m.githubRollupKey()
+	if m.ghRollupAsked == key {
+		return m, nil
+	}
+	m.ghRollupAsked = key
+	acct := m.repoAccount(m.repo)
+	repo, prNumber := m.repo, m.prNumber
+	return m, func() tea.Msg {
+		return githubRollupMsg{rollup: fetchGithubRollup(acct, repo, prNumber)}
+	}
+}
+
+// currentGithubRollup is GitHub's rollup for the head commit, or nil.
+func (m model) currentGithubRollup() *githubRollup {
+	r := m.ghRollup
+	if r == nil || r.err != nil || m.prData == nil || r.repo != m.repo || r.sha != m.prData.HeadSHA {
+		return nil
+	}
+	return r
+}
+
+// githubRollupStatus maps GitHub's rollup state onto prtop's rollup
+// statuses. EXPECTED means a required check hasn't reported yet.
+func githubRollupStatus(state string) string {
+	switch state {
+	case "SUCCESS":
+		return rollupSuccess
+	case "FAILURE", "ERROR":
+		return rollupFailure
+	case "PENDING", "EXPECTED":
+		return rollupPending
+	}
+	return ""
+}
+
+// missingRequired are the required checks that haven't reported at all,
+// so they aren't in the table.
+func (m model) missingRequired() []string {
+	r := m.currentGithubRollup()
+	if r == nil {
+		return nil
+	}
+	var missing []string
+	for _, name := range r.required {
+		if !slices.ContainsFunc(m.prData.Checks, func(c Check) bool { return c.JobName == name || c.Name == name }) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// githubRollupNote is the summary line's "GitHub: SUCCESS".
+func (m model) githubRollupNote() string {
+	r := m.currentGithubRollup()
+	if r == nil || r.state == "" {
+		return ""
+	}
+	return "GitHub: " + r.state
+}
+
+// rollupDiscrepancy explains how GitHub's rollup differs from what the
+// check list implies, or is "" when they agree. Acknowledged and ignored
+// checks count here, since GitHub doesn't know about them.
+func (m model) rollupDiscrepancy() string {
+	r := m.currentGithubRollup()
+	if r == nil {
+		return ""
+	}
+	if missing := m.missingRequired(); len(missing) > 0 {
+		return fmt.Sprintf("GitHub is waiting for required checks that haven't reported: %s", strings.Join(missing, ", "))
+	}
+	ours := rollupStatus(m.prData.Checks, func(Check) bool { return false })
+	theirs := githubRollupStatus(r.state)
+	if theirs == "" || ours == "" || ours == theirs {
+		return ""
+	}
+	return fmt.Sprintf("GitHub's rollup is %s, but the checks listed add up to %s", r.state, ours)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestGithubRollup(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"statusCheckRollup": `{"data":{"repository":{"pullRequest":{"commits":{"nodes":[
+			{"commit":{"oid":"abc123","statusCheckRollup":{"state":"FAILURE"}}}]}}}}}`,
+		"branchProtectionRule": `{"data":{"repository":{"pullRequest":{"baseRef":{"branchProtectionRule":
+			{"requiredStatusCheckContexts":["build","e2e"]}}}}}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 140, 20
+	m.prData = &PRData{HeadSHA: "abc123", Checks: []Check{
+		{Name: "build (CI)", JobName: "build", Workflow: "CI", Status: Pass},
+		{Name: "deploy (CD)", JobName: "deploy", Workflow: "CD", Status: Cancelled},
+	}}
+	m, cmd := m.githubRollupCmd()
+	if cmd == nil {
+		t.Fatal("no fetch")
+	}
+	if _, again := m.githubRollupCmd(); again != nil {
+		t.Error("asked again with no check changed")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "GitHub: FAILURE") {
+		t.Errorf("summary missing GitHub's state:\n%s", out)
+	}
+	if !strings.Contains(out, "GitHub is waiting for required checks that haven't reported: e2e") {
+		t.Errorf("missing required check not flagged:\n%s", out)
+	}
+
+	// With e2e reported, what's left is the cancelled check GitHub counts
+	// as a failure
+	m.prData.Checks = append(m.prData.Checks, Check{Name: "e2e", JobName: "e2e", Status: Pass})
+	if got := m.rollupDiscrepancy(); got != "GitHub's rollup is FAILURE, but the checks listed add up to success" {
+		t.Errorf("discrepancy = %q", got)
+	}
+	if _, cmd := m.githubRollupCmd(); cmd == nil {
+		t.Error("not asked again after a check changed")
+	}
+	m.ghRollup.state = "SUCCESS"
+	if got := m.rollupDiscrepancy(); got != "" {
+		t.Errorf("agreeing rollups flagged: %q", got)
+	}
+
+	// A push makes the old rollup stale
+	m.prData.HeadSHA = "def456"
+	if m.githubRollupNote() != "" || m.rollupDiscrepancy() != "" {
+		t.Error("stale rollup shown")
+	}
+}
+
+func TestGithubRollupWithoutProtection(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"statusCheckRollup": `{"data":{"repository":{"pullRequest":{"commits":{"nodes":[
+			{"commit":{"oid":"abc123","statusCheckRollup":{"state":"PENDING"}}}]}}}}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	r := fetchGithubRollup(nil, "o/r", "1")
+	if r.err != nil || r.state != "PENDING" || r.sha != "abc123" || r.required != nil {
+		t.Errorf("rollup = %+v", r)
+	}
+}
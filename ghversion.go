@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ghVersion is a gh release such as 2.40.1.
+type ghVersion [3]int
+
+func (v ghVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+func (v ghVersion) less(o ghVersion) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+var ghVersionRe = regexp.MustCompile(`gh version (\d+)\.(\d+)\.(\d+)`)
+
+// parseGhVersion reads `gh --version` output ("gh version 2.40.1 (2023-12-13)").
+func parseGhVersion(out string) (ghVersion, bool) {
+	m := ghVersionRe.FindStringSubmatch(out)
+	if m == nil {
+		return ghVersion{}, false
+	}
+	var v ghVersion
+	for i := range v {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, true
+}
+
+// ghSearchPRs is the first gh release with `gh search prs`, which the
+// picker and dashboard use to find PRs across repos.
+var ghSearchPRs = ghVersion{2, 10, 0}
+
+// installedGh caches the installed gh's version; ok is false until
+// detectGh has run, and when gh's output couldn't be read.
+var installedGh = struct {
+	sync.Mutex
+	version ghVersion
+	ok      bool
+}{}
+
+// detectGh asks gh for its version. Failures are only logged: the version
+// is used to explain errors, not to refuse a gh prtop doesn't recognize.
+func detectGh() (ghVersion, bool) {
+	installedGh.Lock()
+	defer installedGh.Unlock()
+	if installedGh.ok {
+		return installedGh.version, true
+	}
+	out, err := execCommand("gh", "--version").Output()
+	if err != nil {
+		logger.Debug("gh --version failed", "err", err)
+		return ghVersion{}, false
+	}
+	v, ok := parseGhVersion(string(out))
+	if !ok {
+		logger.Debug("unrecognized gh --version output", "out", string(out))
+		return ghVersion{}, false
+	}
+	installedGh.version, installedGh.ok = v, true
+	return v, true
+}
+
+// requireGhSearch fails when the installed gh is known to predate
+// `gh search prs`.
+func requireGhSearch() error {
+	v, ok := detectGh()
+	if ok && v.less(ghSearchPRs) {
+		return fmt.Errorf("gh >= %d.%d required for `gh search prs` (found %s)\n"+
+			"Upgrade it from https://cli.github.com/, or pass a repo or PR to skip the search", ghSearchPRs[0], ghSearchPRs[1], v)
+	}
+	return nil
+}
+
+// usesSearch reports whether fetchPRListCmd will run `gh search prs`.
+func (m model) usesSearch() bool {
+	if m.mode != modeSelecting && m.mode != modeDashboard || m.stdinPRs != nil || m.selectNotifications {
+		return false
+	}
+	return m.selectQuery != "" || m.selectRepo == "" && m.selectIssue == 0
+}
+
+var unknownFieldRe = regexp.MustCompile(`Unknown JSON field: "([^"]+)"`)
+
+// unknownJSONField returns the field gh rejected in err, as older releases
+// do for --json fields added after them, or "" for any other error.
+func unknownJSONField(err error) string {
+	if err == nil {
+		return ""
+	}
+	if m := unknownFieldRe.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// prViewFields tracks the `gh pr view --json` fields to ask for. The
+// essential ones are needed to show a PR at all; the others are dropped
+// for the rest of the session once gh says it doesn't know them.
+var prViewFields = struct {
+	sync.Mutex
+	all       []string
+	essential map[string]bool
+	dropped   map[string]bool
+}{
+	all: []string{"statusCheckRollup", "title", "body", "headRefName", "headRefOid",
+		"baseRefName", "url", "reviewDecision", "mergeable", "state"},
+	essential: map[string]bool{"statusCheckRollup": true, "title": true, "headRefName": true, "url": true},
+	dropped:   map[string]bool{},
+}
+
+// prViewFieldSince is the first gh release whose `gh pr view --json`
+// reports each of prViewFields.
+var prViewFieldSince = map[string]ghVersion{
+	"statusCheckRollup": {1, 9, 0},
+	"title":             {1, 9, 0},
+	"body":              {1, 9, 0},
+	"headRefName":       {1, 9, 0},
+	"headRefOid":        {2, 0, 0},
+	"baseRefName":       {1, 9, 0},
+	"url":               {1, 9, 0},
+	"reviewDecision":    {1, 9, 0},
+	"mergeable":         {1, 9, 0},
+	"state":             {1, 9, 0},
+}
+
+// ghRequiredFor is the oldest gh ("2.0") that reports every one of fields
+// prViewFieldSince knows, or false when it knows none of them.
+func ghRequiredFor(fields ...string) (string, bool) {
+	var need ghVersion
+	known := false
+	for _, f := range fields {
+		if v, ok := prViewFieldSince[f]; ok {
+			known = true
+			if need.less(v) {
+				need = v
+			}
+		}
+	}
+	return fmt.Sprintf("%d.%d", need[0], need[1]), known
+}
+
+// prViewJSON is the --json argument for `gh pr view`.
+func prViewJSON() string {
+	prViewFields.Lock()
+	defer prViewFields.Unlock()
+	var fields []string
+	for _, f := range prViewFields.all {
+		if !prViewFields.dropped[f] {
+			fields = append(fields, f)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// dropPRViewField stops asking for field, reporting false for an essential
+// field or one already dropped.
+func dropPRViewField(field string) bool {
+	prViewFields.Lock()
+	defer prViewFields.Unlock()
+	if prViewFields.essential[field] || prViewFields.dropped[field] {
+		return false
+	}
+	prViewFields.dropped[field] = true
+	return true
+}
+
+// droppedPRViewFields lists the fields the installed gh doesn't report.
+func droppedPRViewFields() []string {
+	prViewFields.Lock()
+	defer prViewFields.Unlock()
+	var fields []string
+	for _, f := range prViewFields.all {
+		if prViewFields.dropped[f] {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// ghDroppedNote says which PR details are missing because of an old gh,
+// or "" when there are none.
+func ghDroppedNote() string {
+	fields := droppedPRViewFields()
+	if len(fields) == 0 {
+		return ""
+	}
+	// fetchPRData looked the version up when it dropped the fields, so
+	// this doesn't run gh from Update
+	found := "an older gh"
+	installedGh.Lock()
+	if installedGh.ok {
+		found = "gh " + installedGh.version.String()
+	}
+	installedGh.Unlock()
+	need, _ := ghRequiredFor(fields...) // prViewFieldSince has every field
+	return fmt.Sprintf("gh >= %s required for %s (found %s); upgrade gh to see them", need, strings.Join(fields, ", "), found)
+}
+
+// ghTooOld explains a field the installed gh doesn't support.
+func ghTooOld(field string) error {
+	found := "your gh"
+	if v, ok := detectGh(); ok {
+		found = "gh " + v.String()
+	}
+	if need, ok := ghRequiredFor(field); ok {
+		return fmt.Errorf("gh >= %s required for the %s field of `gh pr view --json` (found %s); upgrade it from https://cli.github.com/", need, field, found)
+	}
+	return fmt.Errorf("%s doesn't support the %s field of `gh pr view --json`; upgrade it from https://cli.github.com/", found, field)
+}
+
+// looseString is a gh JSON enum that may not be a string in every gh
+// release. A null is "", anything else is kept as its JSON text, instead
+// of failing the whole response.
+type looseString string
+
+func (s *looseString) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		*s = looseString(str)
+		return nil
+	}
+	*s = looseString(b)
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOldGh answers like a gh release whose pr view lacks the unknown
+// fields.
+func fakeOldGh(t *testing.T, version string, unknown ...string) {
+	t.Helper()
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		joined := strings.Join(args, " ")
+		if joined == "--version" {
+			return fakeExecCommand("gh version "+version+" (2022-01-01)\nhttps://github.com/cli/cli/releases/latest\n", "", 0)(command, args...)
+		}
+		for _, f := range unknown {
+			if strings.Contains(joined, f) {
+				return fakeExecCommand("", "Unknown JSON field: \""+f+"\"\nAvailable fields:\n  title\n  url", 1)(command, args...)
+			}
+		}
+		return fakeExecCommand(`{"title":"Old gh","headRefName":"b","url":"u","reviewDecision":"APPROVED",
+			"statusCheckRollup":[{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"}]}`, "", 0)(command, args...)
+	}
+	resetRespCache(t)
+	t.Cleanup(func() {
+		execCommand = exec.Command
+		installedGh.Lock()
+		installedGh.ok = false
+		installedGh.Unlock()
+		prViewFields.Lock()
+		prViewFields.dropped = map[string]bool{}
+		prViewFields.Unlock()
+	})
+}
+
+func TestParseGhVersion(t *testing.T) {
+	v, ok := parseGhVersion("gh version 2.40.1 (2023-12-13)\nhttps://github.com/cli/cli/releases/tag/v2.40.1\n")
+	if !ok || v != (ghVersion{2, 40, 1}) || v.String() != "2.40.1" {
+		t.Errorf("parseGhVersion = %v, %v", v, ok)
+	}
+	if _, ok := parseGhVersion("gh: command not found"); ok {
+		t.Error("parsed garbage")
+	}
+	if !(ghVersion{2, 9, 5}).less(ghSearchPRs) || ghSearchPRs.less(ghVersion{2, 9, 5}) || ghSearchPRs.less(ghSearchPRs) {
+		t.Error("less")
+	}
+}
+
+func TestRequireGhSearch(t *testing.T) {
+	fakeOldGh(t, "2.4.0")
+	err := requireGhSearch()
+	if err == nil || !strings.Contains(err.Error(), "gh >= 2.10 required for `gh search prs` (found 2.4.0)") {
+		t.Errorf("old gh: %v", err)
+	}
+
+	installedGh.version = ghVersion{2, 40, 1}
+	if err := requireGhSearch(); err != nil {
+		t.Errorf("new gh: %v", err)
+	}
+
+	m := newSelectModel(5 * time.Second)
+	if !m.usesSearch() {
+		t.Error("the picker doesn't search")
+	}
+	if newRepoSelectModel("o/r", 5*time.Second).usesSearch() || newModel("o/r", "1", 5*time.Second).usesSearch() {
+		t.Error("a repo or PR searches")
+	}
+	m = newDashboardModel("", 5*time.Second)
+	m.selectQuery = "label:bug"
+	if !m.usesSearch() {
+		t.Error("--query doesn't search")
+	}
+}
+
+func TestFetchPRDataOldGh(t *testing.T) {
+	fakeOldGh(t, "1.14.0", "headRefOid")
+	data, err := fetchPRData(nil, "o/r", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "Old gh" || data.ReviewDecision != "APPROVED" || data.HeadSHA != "" || len(data.Checks) != 1 {
+		t.Errorf("data = %+v", data)
+	}
+	if got := prViewJSON(); strings.Contains(got, "headRefOid") || !strings.Contains(got, "reviewDecision") {
+		t.Errorf("still asking for %s", got)
+	}
+	if got := ghDroppedNote(); got != "gh >= 2.0 required for headRefOid (found gh 1.14.0); upgrade gh to see them" {
+		t.Errorf("note = %q", got)
+	}
+
+	m := newModel("o/r", "1", 5*time.Second)
+	updated, _ := m.Update(prDataMsg{data: data, key: prKey("o/r", "1")})
+	if m = updated.(model); !strings.Contains(m.flash, "required for headRefOid") {
+		t.Errorf("flash = %q", m.flash)
+	}
+}
+
+func TestFetchPRDataTooOldGh(t *testing.T) {
+	fakeOldGh(t, "1.2.0", "statusCheckRollup")
+	_, err := fetchPRData(nil, "o/r", "1")
+	if err == nil || !strings.Contains(err.Error(), "gh >= 1.9 required for the statusCheckRollup field of `gh pr view --json` (found gh 1.2.0)") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestParsePRViewLooseEnums(t *testing.T) {
+	data, err := parsePRView([]byte(`{"title":"t","mergeable":false,"state":null,
+		"statusCheckRollup":[{"name":"build","status":null,"conclusion":"SUCCESS","state":0}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Mergeable != "false" || data.State != "" || data.Checks[0].Status != Pass || data.Checks[0].Name != "build" {
+		t.Errorf("data = %+v", data)
+	}
+}
+
+func TestPRViewFieldSince(t *testing.T) {
+	for _, f := range prViewFields.all {
+		if _, ok := prViewFieldSince[f]; !ok {
+			t.Errorf("no minimum gh for %s", f)
+		}
+	}
+}
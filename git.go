@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+)
+
+// localRemote returns the name of the git remote in the current directory
+// that points at repo, or "" when the working directory is not a clone of it.
+func localRemote(repo string, hosts []string) string {
+	out, err := execCommand("git", "remote", "-v").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if r, ok := parseRepo(fields[1], hosts...); ok && strings.EqualFold(r, repo) {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// currentBranch returns the checked-out branch in the current directory.
+func currentBranch() string {
+	out, err := execCommand("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// branchHead is where the checked-out branch pushes to: the owner of its
+// upstream's remote and the branch's name there. ok is false when the
+// branch has no upstream, so no PR can be open from it.
+func branchHead() (owner, ref string, ok bool) {
+	out, err := execCommand("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}").Output()
+	if err != nil {
+		return "", "", false
+	}
+	remote, ref, found := strings.Cut(strings.TrimSpace(string(out)), "/")
+	if !found {
+		return "", "", false
+	}
+	url, err := execCommand("git", "remote", "get-url", remote).Output()
+	if err != nil {
+		return "", "", false
+	}
+	_, path := splitRemote(string(url))
+	owner, _, _ = strings.Cut(strings.Trim(path, "/"), "/")
+	return owner, ref, owner != ""
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestLocalRemote(t *testing.T) {
+	remotes := "origin\thttps://github.com/me/fork.git (fetch)\n" +
+		"origin\thttps://github.com/me/fork.git (push)\n" +
+		"upstream\tgit@github.com:o/r.git (fetch)\n"
+
+	execCommand = fakeExecCommand(remotes, "", 0)
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	if got := localRemote("o/r", nil); got != "upstream" {
+		t.Errorf("localRemote(o/r) = %q, want upstream", got)
+	}
+	if got := localRemote("other/repo", nil); got != "" {
+		t.Errorf("localRemote(other/repo) = %q, want empty", got)
+	}
+
+	execCommand = fakeExecCommand("", "fatal: not a git repository", 128)
+	if got := localRemote("o/r", nil); got != "" {
+		t.Errorf("outside a repo localRemote = %q, want empty", got)
+	}
+}
+
+func TestCurrentBranch(t *testing.T) {
+	execCommand = fakeExecCommand("feature\n", "", 0)
+	t.Cleanup(func() { execCommand = exec.Command })
+	if got := currentBranch(); got != "feature" {
+		t.Errorf("currentBranch = %q, want feature", got)
+	}
+}
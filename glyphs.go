@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// glyphSet is the set of status symbols drawn in front of status words, in
+// compact mode's status column, and in the selector's and dashboard's
+// summaries.
+type glyphSet int
+
+const (
+	glyphsUnicode glyphSet = iota // ✓ ✗ ● ⊘ ⊗ ○ (default)
+	glyphsNerd                    // Nerd Font icons
+	glyphsASCII                   // plain ASCII for terminals without the symbols
+	glyphsNone                    // no glyph column; summaries fall back to ASCII
+)
+
+var glyphSetNames = []string{"unicode", "nerd", "ascii", "none"}
+
+// glyphTable is indexed by glyphSet, then CheckStatus.
+var glyphTable = [][Skipped + 1]string{
+	glyphsUnicode: {Running: "●", Fail: "✗", Cancelled: "⊗", Pass: "✓", Neutral: "○", Skipped: "⊘"},
+	// nf-fa-spinner, times, stop, check, circle_o, ban
+	glyphsNerd:  {Running: "\uf110", Fail: "\uf00d", Cancelled: "\uf04d", Pass: "\uf00c", Neutral: "\uf10c", Skipped: "\uf05e"},
+	glyphsASCII: {Running: "*", Fail: "x", Cancelled: "!", Pass: "+", Neutral: "o", Skipped: "-"},
+}
+
+func (g glyphSet) String() string {
+	return glyphSetNames[g]
+}
+
+// parseGlyphSet parses a glyph set name from config. An empty name is
+// glyphsUnicode.
+func parseGlyphSet(name string) (glyphSet, error) {
+	if name == "" {
+		return glyphsUnicode, nil
+	}
+	for i, n := range glyphSetNames {
+		if strings.EqualFold(name, n) {
+			return glyphSet(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown glyph set %q (want one of %s)", name, strings.Join(glyphSetNames, ", "))
+}
+
+// glyph is the one-character symbol for s.
+func (g glyphSet) glyph(s CheckStatus) string {
+	if g == glyphsNone {
+		g = glyphsASCII
+	}
+	if s < Running || s > Skipped {
+		s = Skipped
+	}
+	return glyphTable[g][s]
+}
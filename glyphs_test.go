@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// parseGlyphSet / glyph
+// ---------------------------------------------------------------------------
+
+func TestParseGlyphSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    glyphSet
+		wantErr bool
+	}{
+		{"", glyphsUnicode, false},
+		{"nerd", glyphsNerd, false},
+		{"ASCII", glyphsASCII, false},
+		{"none", glyphsNone, false},
+		{"emoji", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGlyphSet(tt.name)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseGlyphSet(%q) = %v, %v; want %v, err=%v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGlyph(t *testing.T) {
+	tests := []struct {
+		set    glyphSet
+		status CheckStatus
+		want   string
+	}{
+		{glyphsUnicode, Pass, "✓"},
+		{glyphsUnicode, Fail, "✗"},
+		{glyphsUnicode, Running, "●"},
+		{glyphsUnicode, Skipped, "⊘"},
+		{glyphsUnicode, Cancelled, "⊗"},
+		{glyphsNerd, Neutral, "\uf10c"},
+		{glyphsASCII, Cancelled, "!"},
+		{glyphsNerd, Pass, "\uf00c"},
+		{glyphsASCII, Fail, "x"},
+		{glyphsNone, Pass, "+"},
+		{glyphsUnicode, CheckStatus(99), "⊘"},
+	}
+	for _, tt := range tests {
+		if got := tt.set.glyph(tt.status); got != tt.want {
+			t.Errorf("%v.glyph(%v) = %q, want %q", tt.set, tt.status, got, tt.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Glyph column
+// ---------------------------------------------------------------------------
+
+func TestGlyphColumn(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 80, 20
+	m.prData = &PRData{Title: "t", Checks: []Check{{Name: "build", Status: Fail}}}
+
+	t.Run("glyph before the status word", func(t *testing.T) {
+		lines := m.viewCheckTable(m.width, 5)
+		if !strings.Contains(lines[0], "    STATUS") || !strings.Contains(lines[1], "> ✗ FAIL") {
+			t.Errorf("table = %q", lines)
+		}
+	})
+
+	t.Run("ascii", func(t *testing.T) {
+		m := m
+		m.glyphs = glyphsASCII
+		if lines := m.viewCheckTable(m.width, 5); !strings.Contains(lines[1], "> x FAIL") {
+			t.Errorf("row = %q", lines[1])
+		}
+	})
+
+	t.Run("none drops the column", func(t *testing.T) {
+		m := m
+		m.glyphs = glyphsNone
+		lines := m.viewCheckTable(m.width, 5)
+		if !strings.Contains(lines[0], "  STATUS  DURATION") || !strings.Contains(lines[1], "> FAIL    ") {
+			t.Errorf("table = %q", lines)
+		}
+	})
+
+	t.Run("selector badge uses the set", func(t *testing.T) {
+		if got := ciBadge(PRSummary{CIState: "FAILURE"}, glyphsASCII); !strings.Contains(got, "x") {
+			t.Errorf("ciBadge = %q", got)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Grade is the [grade] config section: which checks decide whether a PR
+// passes. Checks is "all" (the default) or "required", counting only the
+// checks branch protection requires; Ignore lists globs of check names that
+// never count.
+type Grade struct {
+	Checks string   `toml:"checks"`
+	Ignore []string `toml:"ignore"`
+}
+
+type gradePolicy struct {
+	requiredOnly bool
+	ignore       []string // lower-case globs
+}
+
+// grading is the [grade] section of the config, applied wherever a PR's
+// checks are rolled up: the header, --on-change, wait, status and the rest.
+var grading gradePolicy
+
+// Grades summarize a rollup in one word.
+const (
+	gradeGreen = "GREEN"
+	gradeAmber = "AMBER"
+	gradeRed   = "RED"
+)
+
+// parseGrade validates the config's [grade] section.
+func parseGrade(g Grade) (gradePolicy, error) {
+	var p gradePolicy
+	switch strings.ToLower(strings.TrimSpace(g.Checks)) {
+	case "", "all":
+	case "required":
+		p.requiredOnly = true
+	default:
+		return gradePolicy{}, fmt.Errorf("grade.checks: unknown value %q (want all or required)", g.Checks)
+	}
+	for _, glob := range g.Ignore {
+		if _, err := path.Match(glob, ""); err != nil || glob == "" {
+			return gradePolicy{}, fmt.Errorf("grade.ignore: bad pattern %q", glob)
+		}
+		p.ignore = append(p.ignore, strings.ToLower(glob))
+	}
+	return p, nil
+}
+
+// gradedChecks are the checks that count toward the grade: those not
+// matching an ignore glob, with or without the workflow, and with checks =
+// "required" only the required ones. While the required checks aren't
+// known, or when branch protection requires none, every check counts.
+func gradedChecks(checks []Check, required *requiredChecks) []Check {
+	requiredOnly := grading.requiredOnly && required != nil && required.err == nil && len(required.names) > 0
+	if !requiredOnly && len(grading.ignore) == 0 {
+		return checks
+	}
+	var out []Check
+	for _, c := range checks {
+		if requiredOnly && !required.names[requiredKey(c.JobName, c.Workflow)] {
+			continue
+		}
+		if gradeIgnores(c) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func gradeIgnores(c Check) bool {
+	for _, glob := range grading.ignore {
+		for _, name := range []string{c.Name, c.JobName} {
+			if ok, _ := path.Match(glob, strings.ToLower(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// gradeOf is a rollup status's grade: GREEN when it passes, AMBER while
+// checks are running and RED when one failed. No checks has no grade.
+func gradeOf(status string) string {
+	switch status {
+	case rollupSuccess:
+		return gradeGreen
+	case rollupPending:
+		return gradeAmber
+	case rollupFailure:
+		return gradeRed
+	}
+	return ""
+}
+
+func gradeStyle(grade string) lipgloss.Style {
+	switch grade {
+	case gradeGreen:
+		return stylePass
+	case gradeAmber:
+		return styleRunning
+	}
+	return styleFail
+}
+
+// requiredFor is the viewed PR's required checks if they were fetched for
+// commit sha, for grading.
+func (m model) requiredFor(sha string) *requiredChecks {
+	if m.required == nil || m.required.sha != sha {
+		return nil
+	}
+	return m.required
+}
+
+// rollupFor is the rollup status of a PR's checks everywhere the UI shows or
+// acts on one: the header's grade, the kiosk, ctl, --on-change, bulk and bot
+// merges and the release train's checks gate. It leaves out ignored checks
+// and those [grade] doesn't count, and doesn't let acknowledged or muted
+// failures fail it. failing are the failures that do.
+func (m model) rollupFor(repo, prNumber string, data *PRData) (status string, failing []Check) {
+	acks, muted := m.store.acks(repo, prNumber), m.mutedFor(repo, prNumber, data.HeadSHA)
+	acked := func(c Check) bool { return acks[c.Name] || muted[c.Name] }
+	checks := gradedChecks(withoutIgnored(data.Checks, m.store.ignored(repo)), m.requiredFor(data.HeadSHA))
+	for _, c := range checks {
+		if c.Status == Fail && !acked(c) {
+			failing = append(failing, c)
+		}
+	}
+	return rollupStatus(checks, acked), failing
+}
+
+// grade is the viewed PR's grade, as the header shows it.
+func (m model) grade() string {
+	if m.prData == nil {
+		return ""
+	}
+	status, _ := m.rollupFor(m.repo, m.prNumber, m.prData)
+	return gradeOf(status)
+}
+
+// viewGrade is the right end of the header: the grade in its color, then
+// the clock if there's room for it.
+func viewGrade(grade, now string) string {
+	switch {
+	case grade == "":
+		return styleBold.Render(now)
+	case now == "":
+		return gradeStyle(grade).Bold(true).Render(grade)
+	}
+	return gradeStyle(grade).Bold(true).Render(grade) + styleBold.Render("  "+now)
+}
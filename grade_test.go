@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseGrade(t *testing.T) {
+	for _, g := range []Grade{{Checks: "some"}, {Ignore: []string{"["}}, {Ignore: []string{""}}} {
+		if _, err := parseGrade(g); err == nil {
+			t.Errorf("parseGrade(%+v) should fail", g)
+		}
+	}
+	p, err := parseGrade(Grade{Checks: "Required", Ignore: []string{"Codecov/*"}})
+	if err != nil || !p.requiredOnly || len(p.ignore) != 1 || p.ignore[0] != "codecov/*" {
+		t.Errorf("parseGrade = %+v, %v", p, err)
+	}
+}
+
+func TestGradedChecks(t *testing.T) {
+	checks := []Check{
+		{Name: "build (CI)", JobName: "build", Workflow: "CI", Status: Pass},
+		{Name: "e2e (CI)", JobName: "e2e", Workflow: "CI", Status: Fail},
+		{Name: "codecov/patch", JobName: "codecov/patch", Status: Running},
+	}
+	required := &requiredChecks{names: map[string]bool{requiredKey("build", "CI"): true, requiredKey("codecov/patch", ""): true}}
+	tests := []struct {
+		name     string
+		grade    Grade
+		required *requiredChecks
+		want     string
+	}{
+		{"all", Grade{}, required, gradeRed},
+		{"required", Grade{Checks: "required"}, required, gradeAmber},
+		{"required and ignored", Grade{Checks: "required", Ignore: []string{"codecov/*"}}, required, gradeGreen},
+		{"required unknown", Grade{Checks: "required"}, nil, gradeRed},
+		{"none required", Grade{Checks: "required"}, &requiredChecks{names: map[string]bool{}}, gradeRed},
+		{"ignored by job name", Grade{Ignore: []string{"e2e", "codecov/*"}}, nil, gradeGreen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grading, _ = parseGrade(tt.grade)
+			t.Cleanup(func() { grading = gradePolicy{} })
+			got := gradeOf(rollupStatus(gradedChecks(checks, tt.required), func(Check) bool { return false }))
+			if got != tt.want {
+				t.Errorf("grade = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := gradeOf(rollupStatus(nil, nil)); got != "" {
+		t.Errorf("no checks graded %q", got)
+	}
+}
+
+func TestGradeHeader(t *testing.T) {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.store = &stateStore{}
+	m.width = 80
+	if header := ansi.Strip(m.viewHeader()); strings.Contains(header, "GREEN") || strings.Contains(header, "RED") {
+		t.Errorf("header before the PR loads: %q", header)
+	}
+	m.prData = &PRData{HeadSHA: "abc", Checks: []Check{
+		{Name: "build", JobName: "build", Status: Pass},
+		{Name: "flaky", JobName: "flaky", Status: Fail},
+	}}
+	if header := ansi.Strip(m.viewHeader()); !strings.Contains(header, "RED  ") {
+		t.Errorf("header = %q, want RED before the clock", header)
+	}
+
+	// Only the required checks count once they're known for the head commit
+	grading = gradePolicy{requiredOnly: true}
+	t.Cleanup(func() { grading = gradePolicy{} })
+	m.required = &requiredChecks{sha: "old", names: map[string]bool{requiredKey("build", ""): true}}
+	if got := m.grade(); got != gradeRed {
+		t.Errorf("grade with a stale required list = %q, want RED", got)
+	}
+	if m.requiredCmd() == nil {
+		t.Error("grading by required checks should fetch them")
+	}
+	m.required.sha = "abc"
+	if got := m.grade(); got != gradeGreen {
+		t.Errorf("grade = %q, want GREEN", got)
+	}
+
+	// A narrow terminal drops the clock before the grade
+	m.width = 30
+	if header := ansi.Strip(m.viewHeader()); !strings.HasSuffix(header, "GREEN") {
+		t.Errorf("narrow header = %q", header)
+	}
+}
+
+func TestGradeStatusCommand(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"pr view 7": `{"title":"Fix it","headRefOid":"abc","statusCheckRollup":[
+			{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"},
+			{"__typename":"CheckRun","name":"nightly","status":"COMPLETED","conclusion":"FAILURE"}]}`,
+		"graphql": `{"data":{"repository":{"pullRequest":{"commits":{"nodes":[{"commit":{"oid":"abc","statusCheckRollup":{"contexts":{"nodes":[
+			{"__typename":"CheckRun","name":"build","isRequired":true},
+			{"__typename":"CheckRun","name":"nightly","isRequired":false}]}}}}]}}}}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	s, stdout, _ := testSession(t)
+	if code, _ := runStatus(s, []string{"o/r#7"}); code != exitFailed || !strings.HasSuffix(stdout.String(), "Grade: RED\n") {
+		t.Errorf("every check counting: exit %d, stdout:\n%s", code, stdout)
+	}
+
+	grading = gradePolicy{requiredOnly: true}
+	t.Cleanup(func() { grading = gradePolicy{} })
+	s, stdout, _ = testSession(t)
+	if code, _ := runStatus(s, []string{"o/r#7"}); code != exitOK || !strings.HasSuffix(stdout.String(), "Grade: GREEN\n") {
+		t.Errorf("required checks only: exit %d, stdout:\n%s", code, stdout)
+	}
+}
+
+func TestGradeMutedEverywhere(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 100, 20
+	m.store = &stateStore{}
+	m.prData = &PRData{HeadSHA: "abc", Checks: []Check{
+		{Name: "flaky", Status: Fail},
+		{Name: "build", Status: Pass},
+	}}
+	m = m.toggleMute(m.prData.Checks[0])
+
+	if got := m.grade(); got != gradeGreen {
+		t.Errorf("header grade = %q, want GREEN", got)
+	}
+	if st := m.ctlState(); st.Status != rollupSuccess || st.Grade != gradeGreen {
+		t.Errorf("ctl state = %q/%q, want success/GREEN", st.Status, st.Grade)
+	}
+	m.kiosk = true
+	if summary := ansi.Strip(m.viewKioskSummary(80)); !strings.Contains(summary, "PASSING") {
+		t.Errorf("kiosk summary:\n%s", summary)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Rollup statuses passed to --on-change as PRTOP_STATUS.
+const (
+	rollupSuccess = "success"
+	rollupFailure = "failure"
+	rollupPending = "pending"
+)
+
+// rollupStatus summarizes checks the way GitHub's combined status does:
+// any failure wins, then anything still running, otherwise success. Failures
+// for which acked returns true are ignored. No checks at all is "".
+func rollupStatus(checks []Check, acked func(Check) bool) string {
+	if len(checks) == 0 {
+		return ""
+	}
+	status := rollupSuccess
+	for _, c := range checks {
+		switch {
+		case c.Status == Fail && !acked(c):
+			return rollupFailure
+		case c.Status == Running:
+			status = rollupPending
+		}
+	}
+	return status
+}
+
+// hookEvent describes a rollup change for the --on-change command.
+type hookEvent struct {
+	repo     string
+	prNumber string
+	data     *PRData
+	status   string
+	previous string
+	failed   []string
+}
+
+func (e hookEvent) env() []string {
+	return []string{
+		"PRTOP_STATUS=" + e.status,
+		"PRTOP_GRADE=" + gradeOf(e.status),
+		"PRTOP_PREVIOUS_STATUS=" + e.previous,
+		"PRTOP_FAILED_CHECKS=" + strings.Join(e.failed, "\n"),
+		"PRTOP_REPO=" + e.repo,
+		"PRTOP_PR=" + e.prNumber,
+		"PRTOP_TITLE=" + e.data.Title,
+		"PRTOP_URL=" + e.data.URL,
+		"PRTOP_SHA=" + e.data.HeadSHA,
+	}
+}
+
+// rollupHook computes the rollup for a fresh fetch and, if it differs from
+// previous (and previous is known), returns the --on-change command to run.
+// Success isn't reported until settle says the checks have settled, so
+// the rollup stays at previous until then.
+func (m model) rollupHook(repo, prNumber string, data *PRData, previous string, settle *settler) (string, tea.Cmd) {
+	status, failing := m.rollupFor(repo, prNumber, data)
+	if !settle.observe(data.Checks, timeNow(), m.settleWindow) && status == rollupSuccess {
+		logger.Debug("rollup unsettled", "repo", repo, "pr", prNumber, "from", previous)
+		return previous, nil
+	}
+	if m.onChange == "" || previous == "" || status == "" || status == previous {
+		return status, nil
+	}
+	ev := hookEvent{repo: repo, prNumber: prNumber, data: data, status: status, previous: previous}
+	for _, c := range failing {
+		ev.failed = append(ev.failed, c.Name)
+	}
+	logger.Debug("rollup changed", "repo", repo, "pr", prNumber, "from", previous, "to", status)
+	return status, runHookCmd(m.onChange, ev)
+}
+
+// runHookCmd runs command through the shell with ev in its environment.
+// Output is discarded; a failure is reported in the status line.
+func runHookCmd(command string, ev hookEvent) tea.Cmd {
+	return func() tea.Msg {
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		cmd := execCommand(shell, flag, command)
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, ev.env()...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logger.Debug("on-change hook failed", "err", err, "output", string(out))
+			return actionMsg{text: "on-change hook", err: err}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// rollupStatus
+// ---------------------------------------------------------------------------
+
+func TestRollupStatus(t *testing.T) {
+	none := func(Check) bool { return false }
+	tests := []struct {
+		name   string
+		checks []Check
+		acked  func(Check) bool
+		want   string
+	}{
+		{"no checks", nil, none, ""},
+		{"all pass", []Check{{Name: "a", Status: Pass}, {Name: "b", Status: Skipped}}, none, rollupSuccess},
+		{"running", []Check{{Name: "a", Status: Pass}, {Name: "b", Status: Running}}, none, rollupPending},
+		{"fail beats running", []Check{{Name: "a", Status: Running}, {Name: "b", Status: Fail}}, none, rollupFailure},
+		{"acked failure ignored", []Check{{Name: "a", Status: Pass}, {Name: "b", Status: Fail}},
+			func(c Check) bool { return c.Name == "b" }, rollupSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollupStatus(tt.checks, tt.acked); got != tt.want {
+				t.Errorf("rollupStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// rollupHook / runHookCmd
+// ---------------------------------------------------------------------------
+
+func TestRollupHook(t *testing.T) {
+	failing := &PRData{Title: "t", URL: "u", HeadSHA: "abc", Checks: []Check{
+		{Name: "lint", Status: Fail}, {Name: "test", Status: Fail}, {Name: "build", Status: Pass},
+	}}
+	passing := &PRData{Checks: []Check{{Name: "build", Status: Pass}}}
+
+	t.Run("no command", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		status, cmd := m.rollupHook("o/r", "1", failing, rollupSuccess, &settler{})
+		if status != rollupFailure || cmd != nil {
+			t.Errorf("got (%q, %v), want (failure, nil)", status, cmd)
+		}
+	})
+
+	t.Run("first fetch does not fire", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.onChange = "notify"
+		if _, cmd := m.rollupHook("o/r", "1", failing, "", &settler{}); cmd != nil {
+			t.Error("hook fired without a previous status")
+		}
+	})
+
+	t.Run("unchanged does not fire", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.onChange = "notify"
+		if _, cmd := m.rollupHook("o/r", "1", passing, rollupSuccess, &settler{}); cmd != nil {
+			t.Error("hook fired without a change")
+		}
+	})
+
+	t.Run("change runs command with env", func(t *testing.T) {
+		var ran *exec.Cmd
+		fake := fakeExecCommand("", "", 0)
+		execCommand = func(command string, args ...string) *exec.Cmd {
+			ran = fake(command, args...)
+			return ran
+		}
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		m := newModel("o/r", "1", 5*time.Second)
+		m.onChange = "notify"
+		status, cmd := m.rollupHook("o/r", "1", failing, rollupSuccess, &settler{})
+		if status != rollupFailure || cmd == nil {
+			t.Fatalf("got (%q, %v), want (failure, cmd)", status, cmd)
+		}
+		if msg := cmd(); msg != nil {
+			t.Errorf("hook returned %#v, want nil", msg)
+		}
+		if ran == nil || ran.Args[len(ran.Args)-1] != "notify" {
+			t.Fatalf("command not run: %v", ran)
+		}
+		for _, want := range []string{
+			"PRTOP_STATUS=failure",
+			"PRTOP_GRADE=RED",
+			"PRTOP_PREVIOUS_STATUS=success",
+			"PRTOP_FAILED_CHECKS=lint\ntest",
+			"PRTOP_REPO=o/r",
+			"PRTOP_PR=1",
+			"PRTOP_SHA=abc",
+		} {
+			if !slices.Contains(ran.Env, want) {
+				t.Errorf("env missing %q", want)
+			}
+		}
+	})
+
+	t.Run("failing command is reported", func(t *testing.T) {
+		execCommand = fakeExecCommand("", "boom", 1)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		m := newModel("o/r", "1", 5*time.Second)
+		m.onChange = "notify"
+		_, cmd := m.rollupHook("o/r", "1", passing, rollupFailure, &settler{})
+		msg, ok := cmd().(actionMsg)
+		if !ok || msg.err == nil {
+			t.Errorf("got %#v, want actionMsg with err", msg)
+		}
+	})
+}
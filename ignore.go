@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// withoutIgnored drops the checks named in ignored, so a check someone
+// chose to hide can't hold a rollup at pending or failure.
+func withoutIgnored(checks []Check, ignored map[string]bool) []Check {
+	if len(ignored) == 0 {
+		return checks
+	}
+	kept := make([]Check, 0, len(checks))
+	for _, c := range checks {
+		if !ignored[c.Name] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// ignoredCount is how many of the PR's checks are on the repo's ignore
+// list.
+func (m model) ignoredCount() int {
+	if m.prData == nil {
+		return 0
+	}
+	return len(m.prData.Checks) - len(withoutIgnored(m.prData.Checks, m.store.ignored(m.repo)))
+}
+
+// toggleIgnore adds c to the repo's ignore list (I), or takes it off. An
+// ignored check disappears from the table unless ignored checks are shown,
+// so the cursor is kept in range.
+func (m model) toggleIgnore(c Check) model {
+	ignored, err := m.store.toggleIgnore(m.repo, c.Name)
+	switch {
+	case err != nil:
+		m.flash = fmt.Sprintf("Error: %s", err)
+	case ignored:
+		m.flash = fmt.Sprintf("Ignoring %s in %s (: Show ignored checks to undo)", c.Name, m.repo)
+	default:
+		m.flash = "No longer ignoring " + c.Name
+	}
+	return m.clampSelection()
+}
+
+// clampSelection keeps the cursor on a row after rows were hidden.
+func (m model) clampSelection() model {
+	if n := len(m.filteredChecks()); m.selected >= n {
+		m.selected = max(n-1, 0)
+	}
+	return m
+}
+
+// showIgnoredCommand is the palette entry that lists ignored checks again,
+// greyed out, so they can be un-ignored with I.
+func (m model) showIgnoredCommand() paletteCommand {
+	if m.showIgnored {
+		return paletteCommand{label: "Hide ignored checks", run: func(m model) (model, tea.Cmd) {
+			m.showIgnored = false
+			return m.clampSelection(), nil
+		}}
+	}
+	c := paletteCommand{label: "Show ignored checks (I on one to un-ignore)"}
+	if m.ignoredCount() == 0 {
+		c.disabled = "no checks ignored in " + m.repo
+	}
+	c.run = func(m model) (model, tea.Cmd) {
+		m.showIgnored = true
+		return m, nil
+	}
+	return c
+}
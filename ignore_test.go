@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIgnoreCheck(t *testing.T) {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 100, 20
+	m.store = &stateStore{}
+	m.prData = &PRData{Title: "t", Checks: []Check{
+		{Name: "build", Status: Pass},
+		{Name: "codecov", Status: Fail},
+		{Name: "docs", Status: Running},
+	}}
+	m.onChange = "true"
+	m, _ = press(t, m, keyJ, runeKey('I'))
+	if !strings.HasPrefix(m.flash, "Ignoring codecov in o/r") {
+		t.Errorf("flash = %q", m.flash)
+	}
+	out := m.View()
+	table := strings.Join(m.viewCheckTable(m.width, 5), "\n")
+	if strings.Contains(table, "codecov") || !strings.Contains(out, "Checks: 2 total - 1 passed, 1 running, 1 ignored") {
+		t.Errorf("ignored check still shown or counted:\n%s", out)
+	}
+	if len(m.failingChecks()) != 0 {
+		t.Error("an ignored failure still counts as failing")
+	}
+	if status, _ := m.rollupHook("o/r", "7", m.prData, "", &settler{}); status != rollupPending {
+		t.Errorf("rollup = %q, want pending without the ignored failure", status)
+	}
+
+	// Shown again from the palette, greyed out, and un-ignored with I
+	m, _ = m.showIgnoredCommand().run(m)
+	if out := m.View(); !strings.Contains(out, "codecov  (ignored)") {
+		t.Errorf("ignored check not listed:\n%s", out)
+	}
+	m.selected = 1
+	m, _ = press(t, m, runeKey('I'))
+	if m.ignoredCount() != 0 || m.flash != "No longer ignoring codecov" {
+		t.Errorf("un-ignore: %d ignored, flash %q", m.ignoredCount(), m.flash)
+	}
+	if cmd := m.showIgnoredCommand(); cmd.label != "Hide ignored checks" {
+		t.Errorf("palette entry = %q", cmd.label)
+	}
+}
+
+func TestShowIgnoredCommandDisabled(t *testing.T) {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.store = &stateStore{}
+	m.prData = &PRData{Checks: []Check{{Name: "build", Status: Pass}}}
+	if c := m.showIgnoredCommand(); c.disabled == "" {
+		t.Error("nothing to show, but the command is enabled")
+	}
+}
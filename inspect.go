@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openJSON opens the JSON overlay on the selected check, or on the whole PR
+// when whole is set or no check is selected.
+func (m model) openJSON(whole bool) (model, tea.Cmd) {
+	if _, ok := m.selectedCheck(); !ok {
+		whole = true
+	}
+	m.jsonWhole = whole
+	if m.overlay == overlayJSON {
+		m.overlayOff = 0
+		return m, nil
+	}
+	return m.toggleOverlay(overlayJSON)
+}
+
+// jsonTitle says which payload the JSON overlay shows.
+func (m model) jsonTitle() string {
+	if c, ok := m.selectedCheck(); ok && !m.jsonWhole {
+		return "RAW JSON: " + c.Name
+	}
+	return "RAW JSON: " + m.repo + "#" + m.prNumber
+}
+
+// jsonLines is the JSON overlay: what gh returned for the selected check
+// (its statusCheckRollup item) or for the whole PR, so a check prtop shows
+// differently from GitHub can be traced to the fields it was read from.
+func (m model) jsonLines() []string {
+	if m.prData == nil || m.prData.raw == nil {
+		return []string{"Loading PR..."}
+	}
+	raw := json.RawMessage(m.prData.raw)
+	var lines []string
+	if c, ok := m.selectedCheck(); ok && !m.jsonWhole {
+		item, found := rawCheckItem(m.prData.raw, c)
+		if !found {
+			return []string{"This check isn't in gh's response."}
+		}
+		raw = item
+		lines = append(lines, styleDim.Render("prtop reads this as ")+statusStyle(c.Status).Render(c.Status.String()), "")
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, raw, "", "  "); err != nil {
+		return append(lines, strings.Split(string(raw), "\n")...)
+	}
+	for _, l := range strings.Split(b.String(), "\n") {
+		lines = append(lines, highlightJSON(l))
+	}
+	return lines
+}
+
+// rawCheckItem finds c's item in the statusCheckRollup of a `gh pr view`
+// response, matching it the way parseCheckItems named it.
+func rawCheckItem(raw []byte, c Check) (json.RawMessage, bool) {
+	var resp struct {
+		StatusCheckRollup []json.RawMessage `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, false
+	}
+	for _, r := range resp.StatusCheckRollup {
+		var item ghCheckItem
+		if err := json.Unmarshal(r, &item); err != nil {
+			continue
+		}
+		name, url := item.Name, item.DetailsURL
+		if name == "" {
+			name = item.Context
+		}
+		if url == "" {
+			url = item.TargetURL
+		}
+		if name == c.JobName && url == c.DetailsURL && item.WorkflowName == c.Workflow {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// jsonToken matches the tokens of an indented JSON line worth coloring: a
+// string, and whether it's a key, or a number, boolean or null.
+var jsonToken = regexp.MustCompile(`"(?:[^"\\]|\\.)*"(\s*:)?|-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?|\btrue\b|\bfalse\b|\bnull\b`)
+
+// highlightJSON colors one line of indented JSON: keys bold, strings green,
+// numbers and booleans yellow, null dim.
+func highlightJSON(line string) string {
+	return jsonToken.ReplaceAllStringFunc(line, func(tok string) string {
+		switch {
+		case strings.HasSuffix(tok, ":"):
+			key := strings.TrimRight(tok, ": \t")
+			return styleBold.Render(key) + tok[len(key):]
+		case strings.HasPrefix(tok, `"`):
+			return stylePass.Render(tok)
+		case tok == "null":
+			return styleDim.Render(tok)
+		}
+		return styleRunning.Render(tok)
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const inspectPR = `{"title":"Fix it","headRefOid":"abc","statusCheckRollup":[
+	{"__typename":"CheckRun","name":"build","workflowName":"CI","status":"COMPLETED","conclusion":"NEUTRAL","detailsUrl":"https://github.com/o/r/actions/runs/1/job/2"},
+	{"__typename":"StatusContext","context":"ci/jenkins","state":"PENDING","targetUrl":"https://jenkins.example.com/9"}]}`
+
+func inspectModel(t *testing.T) model {
+	t.Helper()
+	data, err := parsePRView([]byte(inspectPR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 100, 40
+	m.hideSkipped = false
+	m.prData = data
+	return m
+}
+
+func TestRawCheckItem(t *testing.T) {
+	m := inspectModel(t)
+	for _, c := range m.prData.Checks {
+		item, ok := rawCheckItem(m.prData.raw, c)
+		if !ok {
+			t.Errorf("%s: no raw item", c.Name)
+			continue
+		}
+		if !strings.Contains(string(item), c.DetailsURL) {
+			t.Errorf("%s: found %s", c.Name, item)
+		}
+	}
+	if _, ok := rawCheckItem(m.prData.raw, Check{JobName: "gone"}); ok {
+		t.Error("found an item for a check that isn't there")
+	}
+}
+
+func TestJSONOverlay(t *testing.T) {
+	m := inspectModel(t)
+	m.selected = slices.IndexFunc(m.filteredChecks(), func(c Check) bool { return c.Name == "ci/jenkins" })
+	m, _ = press(t, m, runeKey('J'))
+	if m.overlay != overlayJSON {
+		t.Fatalf("J opened overlay %v", m.overlay)
+	}
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"RAW JSON: ci/jenkins", "prtop reads this as RUNNING", `"state": "PENDING"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("check JSON is missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, `"name": "build"`) {
+		t.Errorf("check JSON shows other checks:\n%s", view)
+	}
+
+	m, _ = press(t, m, keyTab)
+	view = ansi.Strip(m.View())
+	if !strings.Contains(view, "RAW JSON: o/r#7") || !strings.Contains(view, `"title": "Fix it"`) {
+		t.Errorf("tab should show the whole PR:\n%s", view)
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.overlayOff == 0 {
+		t.Error("pgdown didn't scroll the overlay")
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.overlay != overlayNone {
+		t.Errorf("esc left overlay %v open", m.overlay)
+	}
+}
+
+func TestHighlightJSON(t *testing.T) {
+	for _, line := range []string{`  "name": "build \"x\"",`, `  "count": -1.5e3,`, `  "ok": true,`, `  "url": null`, `[`} {
+		if got := ansi.Strip(highlightJSON(line)); got != line {
+			t.Errorf("highlightJSON(%q) reads %q", line, got)
+		}
+	}
+}
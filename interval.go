@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	// minInterval keeps a typo like --interval 0 from hammering the API.
+	minInterval = 2 * time.Second
+)
+
+// intervalSteps are the refresh intervals + and - step through.
+var intervalSteps = []time.Duration{
+	2 * time.Second, 5 * time.Second, 10 * time.Second, 15 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute,
+}
+
+// parseInterval parses --interval: a duration such as "30s" or "2m", or a
+// bare number of seconds as older versions took.
+func parseInterval(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	if n, err := strconv.Atoi(s); err == nil {
+		d = time.Duration(n) * time.Second
+	} else if d, err = time.ParseDuration(s); err != nil {
+		return 0, fmt.Errorf("invalid interval %q (want a duration like 30s or 2m)", s)
+	}
+	if err := checkInterval(d); err != nil {
+		return 0, err
+	}
+	return d, nil
+}
+
+func checkInterval(d time.Duration) error {
+	if d < minInterval {
+		return fmt.Errorf("interval %s is too short (minimum %s)", d, minInterval)
+	}
+	return nil
+}
+
+// stepInterval returns the next step above (or below, when up is false) d,
+// or d itself at the end of the range.
+func stepInterval(d time.Duration, up bool) time.Duration {
+	if up {
+		for _, s := range intervalSteps {
+			if s > d {
+				return s
+			}
+		}
+		return d
+	}
+	for i := len(intervalSteps) - 1; i >= 0; i-- {
+		if intervalSteps[i] < d {
+			return intervalSteps[i]
+		}
+	}
+	return d
+}
+
+// formatInterval renders d as it would be written in config: "5s", "2m",
+// "1m30s".
+func formatInterval(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// saveInterval sets [polling] interval in the config file at path.
+func saveInterval(path string, d time.Duration) error {
+	return saveConfigValue(path, "polling", "interval", strconv.Quote(formatInterval(d)))
+}
+
+// adjustInterval steps the refresh interval up or down and saves it to the
+// config file so the next session starts with it.
+func (m model) adjustInterval(up bool) model {
+	d := stepInterval(m.interval, up)
+	if d == m.interval {
+		m.flash = "Refresh interval: " + formatInterval(d) + " (limit)"
+		return m
+	}
+	m.interval = d
+	if m.sched != nil {
+		m.sched.setFast(d)
+	}
+	m.flash = "Refresh interval: " + formatInterval(d)
+	if err := saveInterval(m.configPath, d); err != nil {
+		m.flash += fmt.Sprintf(" (not saved: %s)", err)
+	}
+	return m
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// parseInterval
+// ---------------------------------------------------------------------------
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr string
+	}{
+		{"5", 5 * time.Second, ""},
+		{"30s", 30 * time.Second, ""},
+		{"2m", 2 * time.Minute, ""},
+		{"1m30s", 90 * time.Second, ""},
+		{"0", 0, "too short"},
+		{"-5", 0, "too short"},
+		{"500ms", 0, "too short"},
+		{"1.5", 0, "invalid interval"},
+		{"soon", 0, "invalid interval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseInterval(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("parseInterval(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepAndFormatInterval(t *testing.T) {
+	if got := stepInterval(5*time.Second, true); got != 10*time.Second {
+		t.Errorf("up from 5s = %v", got)
+	}
+	if got := stepInterval(7*time.Second, false); got != 5*time.Second {
+		t.Errorf("down from 7s = %v", got)
+	}
+	if got := stepInterval(minInterval, false); got != minInterval {
+		t.Errorf("down from the minimum = %v", got)
+	}
+	if got := stepInterval(5*time.Minute, true); got != 5*time.Minute {
+		t.Errorf("up from the maximum = %v", got)
+	}
+	for d, want := range map[time.Duration]string{
+		30 * time.Second: "30s",
+		2 * time.Minute:  "2m",
+		90 * time.Second: "1m30s",
+		time.Hour:        "1h",
+	} {
+		if got := formatInterval(d); got != want {
+			t.Errorf("formatInterval(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// saveInterval
+// ---------------------------------------------------------------------------
+
+func TestSaveInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"missing file", "", "[polling]\ninterval = \"10s\"\n"},
+		{
+			"no polling table",
+			"# mine\n[display]\ndensity = \"compact\"\n",
+			"# mine\n[display]\ndensity = \"compact\"\n\n[polling]\ninterval = \"10s\"\n",
+		},
+		{
+			"polling table without interval",
+			"[polling]\nbackground = \"1m\"\n\n[polling.prs]\n\"o/r#1\" = \"5s\"\n",
+			"[polling]\ninterval = \"10s\"\nbackground = \"1m\"\n\n[polling.prs]\n\"o/r#1\" = \"5s\"\n",
+		},
+		{
+			"replaces interval",
+			"[polling]\ninterval = \"5s\" # fast\n[display]\ninterval = \"x\"\n",
+			"[polling]\ninterval = \"10s\"\n[display]\ninterval = \"x\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prtop", "config.toml")
+			if tt.in != "" {
+				os.MkdirAll(filepath.Dir(path), 0o755)
+				if err := os.WriteFile(path, []byte(tt.in), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := saveInterval(path, 10*time.Second); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("config =\n%s\nwant\n%s", got, tt.want)
+			}
+			cfg, err := loadConfig(path)
+			if err != nil || cfg.Polling.Interval != 10*time.Second {
+				t.Errorf("reloaded interval = %v, %v", cfg.Polling.Interval, err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// + and - keys
+// ---------------------------------------------------------------------------
+
+func TestAdjustIntervalKeys(t *testing.T) {
+	m := splitTestModel()
+	m.configPath = filepath.Join(t.TempDir(), "config.toml")
+	m.interval = 5 * time.Second
+
+	m, _ = press(t, m, runeKey('+'))
+	if m.interval != 10*time.Second || m.flash != "Refresh interval: 10s" {
+		t.Errorf("after +: interval = %v, flash = %q", m.interval, m.flash)
+	}
+	if cfg, _ := loadConfig(m.configPath); cfg.Polling.Interval != 10*time.Second {
+		t.Errorf("saved interval = %v", cfg.Polling.Interval)
+	}
+
+	m, _ = press(t, m, runeKey('-'), runeKey('-'), runeKey('-'))
+	if m.interval != minInterval || !strings.Contains(m.flash, "limit") {
+		t.Errorf("after -: interval = %v, flash = %q", m.interval, m.flash)
+	}
+
+	m.configPath = ""
+	m, _ = press(t, m, runeKey('+'))
+	if !strings.Contains(m.flash, "not saved") {
+		t.Errorf("flash without a config file = %q", m.flash)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const issuePRsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    issue(number: $number) {
+      closedByPullRequestsReferences(first: 50, includeClosedPrs: false) {
+        nodes {
+          number title url updatedAt createdAt isDraft
+          repository { nameWithOwner }
+        }
+      }
+    }
+  }
+}`
+
+// fetchIssuePRs lists the open PRs that will close issue number in repo,
+// most recently updated first. Linked PRs may live in other repos.
+func fetchIssuePRs(acct *Account, repo string, number int) ([]PRSummary, error) {
+	host, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql",
+		"-f", "query="+issuePRsQuery,
+		"-F", "owner="+owner,
+		"-F", "name="+name,
+		"-F", "number="+strconv.Itoa(number),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Issue *struct {
+					ClosedBy struct {
+						Nodes []struct {
+							Number     int    `json:"number"`
+							Title      string `json:"title"`
+							URL        string `json:"url"`
+							UpdatedAt  string `json:"updatedAt"`
+							CreatedAt  string `json:"createdAt"`
+							IsDraft    bool   `json:"isDraft"`
+							Repository struct {
+								NameWithOwner string `json:"nameWithOwner"`
+							} `json:"repository"`
+						} `json:"nodes"`
+					} `json:"closedByPullRequestsReferences"`
+				} `json:"issue"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse linked PRs: %w", err)
+	}
+	if resp.Data.Repository.Issue == nil {
+		return nil, fmt.Errorf("issue %s#%d not found", repo, number)
+	}
+
+	var prs []PRSummary
+	for _, n := range resp.Data.Repository.Issue.ClosedBy.Nodes {
+		prRepo := n.Repository.NameWithOwner
+		if host != "" {
+			prRepo = host + "/" + prRepo
+		}
+		prs = append(prs, PRSummary{
+			Repo:      prRepo,
+			Number:    n.Number,
+			Title:     n.Title,
+			URL:       n.URL,
+			UpdatedAt: n.UpdatedAt,
+			CreatedAt: n.CreatedAt,
+			Draft:     n.IsDraft,
+		})
+	}
+	sortPRs(prs, sortUpdated, false)
+	return prs, nil
+}
+
+// parseIssueRef parses the arguments of `prtop issue`: owner/repo 456,
+// owner/repo#456 or an issue URL.
+func parseIssueRef(args []string, hosts ...string) (repo string, number int, ok bool) {
+	var ref string
+	switch len(args) {
+	case 1:
+		ref = args[0]
+		if host, path := splitRemote(ref); host != "" {
+			path, _, _ = strings.Cut(path, "#")
+			path, _, _ = strings.Cut(path, "?")
+			parts := strings.Split(strings.Trim(path, "/"), "/")
+			if len(parts) >= 4 && parts[2] == "issues" {
+				ref = host + "/" + parts[0] + "/" + parts[1] + "#" + parts[3]
+			}
+		}
+	case 2:
+		ref = args[0] + "#" + strings.TrimPrefix(args[1], "#")
+	default:
+		return "", 0, false
+	}
+	r, n, found := strings.Cut(ref, "#")
+	if !found {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(n)
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	repo, ok = parseRepo(r, hosts...)
+	return repo, number, ok
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchIssuePRs(t *testing.T) {
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	t.Run("linked PRs, newest first, host kept", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{
+			"closedByPullRequestsReferences": `{"data":{"repository":{"issue":{"closedByPullRequestsReferences":{"nodes":[
+				{"number":3,"title":"Old fix","updatedAt":"2024-01-01T00:00:00Z","repository":{"nameWithOwner":"team/svc"}},
+				{"number":9,"title":"Other repo","updatedAt":"2024-02-01T00:00:00Z","isDraft":true,"repository":{"nameWithOwner":"team/lib"}}
+			]}}}}}`,
+		})
+		prs, err := fetchIssuePRs(nil, "ghe.corp.com/team/svc", 456)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prs) != 2 {
+			t.Fatalf("got %d PRs, want 2", len(prs))
+		}
+		if prs[0].Repo != "ghe.corp.com/team/lib" || prs[0].Number != 9 || !prs[0].Draft {
+			t.Errorf("prs[0] = %+v", prs[0])
+		}
+		if prs[1].Repo != "ghe.corp.com/team/svc" || prs[1].Number != 3 {
+			t.Errorf("prs[1] = %+v", prs[1])
+		}
+	})
+
+	t.Run("missing issue", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{
+			"closedByPullRequestsReferences": `{"data":{"repository":{"issue":null}}}`,
+		})
+		_, err := fetchIssuePRs(nil, "o/r", 1)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("err = %v, want not found", err)
+		}
+	})
+}
+
+func TestParseIssueRef(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantRepo string
+		wantNum  int
+		wantOK   bool
+	}{
+		{[]string{"o/r", "456"}, "o/r", 456, true},
+		{[]string{"o/r", "#456"}, "o/r", 456, true},
+		{[]string{"o/r#456"}, "o/r", 456, true},
+		{[]string{"https://github.com/o/r/issues/456"}, "o/r", 456, true},
+		{[]string{"https://ghe.corp.com/t/s/issues/7#issuecomment-1"}, "ghe.corp.com/t/s", 7, true},
+		{[]string{"o/r"}, "", 0, false},
+		{[]string{"o/r", "abc"}, "", 0, false},
+		{[]string{"https://github.com/o/r/pull/1"}, "", 0, false},
+		{nil, "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			repo, n, ok := parseIssueRef(tt.args, "ghe.corp.com")
+			if ok != tt.wantOK || repo != tt.wantRepo || n != tt.wantNum {
+				t.Errorf("parseIssueRef(%v) = %q, %d, %v; want %q, %d, %v",
+					tt.args, repo, n, ok, tt.wantRepo, tt.wantNum, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIssueSelectorSubtitle(t *testing.T) {
+	m := newRepoSelectModel("o/r", 5*time.Second)
+	m.selectIssue = 456
+	m.width = 80
+	m.loading = false
+	m.prs = []PRSummary{}
+	out := m.viewSelecting()
+	if !strings.Contains(out, "linked to o/r#456") || !strings.Contains(out, "No open PRs are linked to this issue.") {
+		t.Errorf("issue selector view = %q", out)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journalEntry is one line of the --journal file. Event is:
+//
+//   - "seen": the first snapshot of a check, when prtop starts watching the
+//     PR or after a push
+//   - "changed": the check's status changed
+//   - "rerun": the check's Actions job was rerun
+//   - "push": the PR's head moved to SHA (no check)
+//
+// Unlike the event pane, the journal spans pushes and covers every PR
+// prtop polls, including the dashboard's.
+type journalEntry struct {
+	At          time.Time  `json:"at"`
+	Repo        string     `json:"repo"`
+	PR          string     `json:"pr"`
+	SHA         string     `json:"sha,omitempty"`
+	Event       string     `json:"event"`
+	Check       string     `json:"check,omitempty"`
+	From        string     `json:"from,omitempty"`
+	To          string     `json:"to,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// journal appends status transitions to a JSONL file. It keeps the last
+// snapshot of each PR so it can diff fetches on its own, whichever view
+// made them.
+type journal struct {
+	mu   sync.Mutex
+	enc  *json.Encoder
+	last map[string]*PRData // by prKey
+}
+
+var journaling *journal
+
+func newJournal(w io.Writer) *journal {
+	return &journal{enc: json.NewEncoder(w), last: map[string]*PRData{}}
+}
+
+// startJournal opens path for appending, so one file can collect many
+// sessions, and journals every PR fetched from then on.
+func startJournal(path string) (io.Closer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	journaling = newJournal(f)
+	return f, nil
+}
+
+func statusName(s CheckStatus) string {
+	return strings.ToLower(s.String())
+}
+
+// observe journals the transitions between the PR's last snapshot and data.
+// Responses served from the cache after a failed fetch are skipped, since
+// they say nothing about the PR's state now.
+func (j *journal) observe(repo, prNumber string, data *PRData, at time.Time) {
+	if j == nil || data == nil || !data.CachedAt.IsZero() {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	key := prKey(repo, prNumber)
+	prev := j.last[key]
+	j.last[key] = data
+
+	var old []Check
+	var entries []journalEntry
+	switch {
+	case prev == nil:
+	case data.HeadSHA != "" && prev.HeadSHA != "" && data.HeadSHA != prev.HeadSHA:
+		entries = append(entries, journalEntry{Event: "push", From: prev.HeadSHA, To: data.HeadSHA})
+	default:
+		old = prev.Checks
+	}
+	byName := make(map[string]Check, len(data.Checks))
+	for _, c := range data.Checks {
+		byName[c.Name] = c
+	}
+	for _, e := range diffChecks(old, data.Checks, at) {
+		c := byName[e.Check]
+		entry := journalEntry{Event: "changed", Check: e.Check, To: statusName(e.To), URL: c.DetailsURL}
+		switch {
+		case e.New:
+			entry.Event = "seen"
+		case e.Rerun:
+			entry.Event = "rerun"
+		}
+		if !e.New {
+			entry.From = statusName(e.From)
+		}
+		if !c.StartedAt.IsZero() {
+			entry.StartedAt = &c.StartedAt
+		}
+		if !c.CompletedAt.IsZero() {
+			entry.CompletedAt = &c.CompletedAt
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, e := range entries {
+		e.At, e.Repo, e.PR, e.SHA = at.UTC(), repo, prNumber, data.HeadSHA
+		if err := j.enc.Encode(e); err != nil {
+			logger.Debug("journal failed", "err", err)
+			return
+		}
+	}
+}
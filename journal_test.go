@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJournalObserve(t *testing.T) {
+	var buf bytes.Buffer
+	j := newJournal(&buf)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	started := at.Add(-time.Minute)
+	job := func(id string) string { return "https://github.com/o/r/actions/runs/1/job/" + id }
+
+	j.observe("o/r", "7", &PRData{HeadSHA: "aaa", Checks: []Check{
+		{Name: "build", Status: Running, StartedAt: started, DetailsURL: job("11")},
+		{Name: "lint", Status: Pass},
+	}}, at)
+	// Unchanged checks and cached responses are not journaled
+	j.observe("o/r", "7", &PRData{HeadSHA: "aaa", Checks: []Check{
+		{Name: "build", Status: Running, StartedAt: started, DetailsURL: job("11")},
+		{Name: "lint", Status: Pass},
+	}}, at.Add(time.Minute))
+	j.observe("o/r", "7", &PRData{HeadSHA: "aaa", CachedAt: at, Checks: []Check{{Name: "lint", Status: Fail}}}, at.Add(time.Minute))
+	j.observe("o/r", "7", &PRData{HeadSHA: "aaa", Checks: []Check{
+		{Name: "build", Status: Fail, StartedAt: started, CompletedAt: at.Add(2 * time.Minute), DetailsURL: job("11")},
+		{Name: "lint", Status: Pass},
+	}}, at.Add(2*time.Minute))
+	j.observe("o/r", "7", &PRData{HeadSHA: "aaa", Checks: []Check{
+		{Name: "build", Status: Running, DetailsURL: job("12")},
+		{Name: "lint", Status: Pass},
+	}}, at.Add(3*time.Minute))
+	j.observe("o/r", "7", &PRData{HeadSHA: "bbb", Checks: []Check{{Name: "build", Status: Running}}}, at.Add(4*time.Minute))
+
+	var got []journalEntry
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var e journalEntry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	want := []struct{ event, check, from, to, sha string }{
+		{"seen", "build", "", "running", "aaa"},
+		{"seen", "lint", "", "pass", "aaa"},
+		{"changed", "build", "running", "fail", "aaa"},
+		{"rerun", "build", "fail", "running", "aaa"},
+		{"push", "", "aaa", "bbb", "bbb"},
+		{"seen", "build", "", "running", "bbb"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		e := got[i]
+		if e.Event != w.event || e.Check != w.check || e.From != w.from || e.To != w.to || e.SHA != w.sha ||
+			e.Repo != "o/r" || e.PR != "7" {
+			t.Errorf("entry %d = %+v, want %+v", i, e, w)
+		}
+	}
+	if e := got[2]; !e.At.Equal(at.Add(2*time.Minute)) || e.StartedAt == nil || !e.StartedAt.Equal(started) ||
+		e.CompletedAt == nil || e.URL != job("11") {
+		t.Errorf("failure entry = %+v", e)
+	}
+	if got[0].CompletedAt != nil {
+		t.Errorf("running check has completed_at: %+v", got[0])
+	}
+
+	var nilJournal *journal
+	nilJournal.observe("o/r", "7", &PRData{}, at) // journaling off
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultKioskCycle is how long --kiosk shows each PR.
+const defaultKioskCycle = 30 * time.Second
+
+type kioskTickMsg struct{}
+
+func kioskTickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return kioskTickMsg{}
+	})
+}
+
+// kioskModel is --kiosk: a read-only view of each of prs in turn, starting
+// with the first.
+func kioskModel(prs []PRSummary, interval, cycle time.Duration) model {
+	m := newModel(prs[0].Repo, strconv.Itoa(prs[0].Number), interval)
+	m.kiosk = true
+	m.kioskPRs = prs
+	m.kioskCycle = cycle
+	m.readOnly = true
+	return m
+}
+
+// kioskPRs are the PRs --kiosk cycles through: the PRs given on stdin or in
+// a release manifest, else the watchlist, after the PR being viewed if
+// there is one.
+func kioskPRs(m model, watch *watchlist) ([]PRSummary, error) {
+	prs := m.stdinPRs
+	if len(prs) == 0 {
+		prs = watch.prs
+	}
+	if m.mode == modeViewing {
+		n, _ := strconv.Atoi(m.prNumber)
+		prs = (&watchlist{prs: prs}).withWatched([]PRSummary{{Repo: m.repo, Number: n}})
+	}
+	if len(prs) == 0 {
+		return nil, errors.New("--kiosk cycles through the watchlist, which is empty: add PRs to it with w, or give a PR")
+	}
+	return prs, nil
+}
+
+// nextKioskPR moves on to the next PR in the rotation.
+func (m model) nextKioskPR() (model, tea.Cmd) {
+	if len(m.kioskPRs) < 2 {
+		return m, nil
+	}
+	m.kioskAt = (m.kioskAt + 1) % len(m.kioskPRs)
+	pr := m.kioskPRs[m.kioskAt]
+	return m.viewPR(pr.Repo, strconv.Itoa(pr.Number))
+}
+
+// kioskPosition is the header's "(2/5)" while cycling.
+func (m model) kioskPosition() string {
+	if !m.kiosk || len(m.kioskPRs) < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d/%d)", m.kioskAt+1, len(m.kioskPRs))
+}
+
+// viewKioskSummary is the summary line enlarged for a screen read from
+// across the room: the rollup and the counts in a box in its color.
+func (m model) viewKioskSummary(width int) string {
+	counts, _ := m.checkCounts()
+	status, _ := m.rollupFor(m.repo, m.prNumber, m.prData)
+	word, color := "NO CHECKS", styleNeutral
+	switch status {
+	case rollupSuccess:
+		word, color = "PASSING", stylePass
+	case rollupFailure:
+		word, color = "FAILING", styleFail
+	case rollupPending:
+		word, color = "RUNNING", styleRunning
+	}
+	var parts []string
+	for _, st := range []CheckStatus{Fail, Running, Cancelled, Pass} {
+		if n := counts[st]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d %s", m.glyphs.glyph(st), n, strings.ToLower(st.String())))
+		}
+	}
+	text := word
+	if len(parts) > 0 {
+		text += "   " + strings.Join(parts, "   ")
+	}
+	box := lipgloss.NewStyle().Border(lipgloss.ThickBorder()).BorderForeground(color.GetForeground()).
+		Width(max(width-2, 1)).Align(lipgloss.Center)
+	return box.Render(color.Render(truncate(text, max(width-2, 1))))
+}
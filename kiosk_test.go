@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestKioskPRs(t *testing.T) {
+	watch := &watchlist{prs: []PRSummary{{Repo: "o/a", Number: 1}, {Repo: "o/b", Number: 2}}}
+
+	prs, err := kioskPRs(newModel("o/b", "2", 5*time.Second), watch)
+	if err != nil || len(prs) != 2 || prs[0].Repo != "o/b" || prs[1].Repo != "o/a" {
+		t.Errorf("viewing o/b#2: %v, %v", prs, err)
+	}
+	prs, _ = kioskPRs(newModel("o/c", "3", 5*time.Second), watch)
+	if len(prs) != 3 || prs[0].Repo != "o/c" {
+		t.Errorf("viewing a PR off the watchlist: %v", prs)
+	}
+	dash := newDashboardModel("", 5*time.Second)
+	if prs, _ = kioskPRs(dash, watch); len(prs) != 2 || prs[0].Repo != "o/a" {
+		t.Errorf("dashboard: %v", prs)
+	}
+	dash.stdinPRs = []PRSummary{{Repo: "o/z", Number: 9}}
+	if prs, _ = kioskPRs(dash, watch); len(prs) != 1 || prs[0].Repo != "o/z" {
+		t.Errorf("PRs from stdin: %v", prs)
+	}
+	if _, err := kioskPRs(newDashboardModel("", 5*time.Second), &watchlist{}); err == nil {
+		t.Error("empty watchlist accepted")
+	}
+}
+
+func TestKiosk(t *testing.T) {
+	m := kioskModel([]PRSummary{{Repo: "o/a", Number: 1}, {Repo: "o/b", Number: 2}}, 5*time.Second, time.Minute)
+	m.width, m.height = 100, 20
+	m.store = &stateStore{}
+	m.prData = &PRData{Title: "Fix it", HeadSHA: "abc", Checks: []Check{
+		{Name: "build", Status: Fail},
+		{Name: "lint", Status: Pass},
+	}}
+	if !m.readOnly {
+		t.Error("kiosk isn't read-only")
+	}
+
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "PR Checks - o/a #1 (1/2)") || !strings.Contains(out, "FAILING   ✗ 1 fail   ✓ 1 pass") || !strings.Contains(out, "┏") {
+		t.Errorf("kiosk view:\n%s", out)
+	}
+	if strings.Contains(out, "q: quit") {
+		t.Errorf("key hints shown:\n%s", out)
+	}
+	if got := strings.Count(out, "\n"); got > m.height-1 {
+		t.Errorf("%d lines for a height of %d", got+1, m.height)
+	}
+
+	// Errors stay off the wall; the last data stays up
+	m.err = errors.New("HTTP 502")
+	if out := ansi.Strip(m.View()); strings.Contains(out, "502") || !strings.Contains(out, "build") {
+		t.Errorf("error shown:\n%s", out)
+	}
+
+	updated, cmd := m.Update(kioskTickMsg{})
+	m = updated.(model)
+	if m.repo != "o/b" || m.prNumber != "2" || m.kioskAt != 1 || cmd == nil {
+		t.Errorf("after a cycle: %s#%s at %d", m.repo, m.prNumber, m.kioskAt)
+	}
+	updated, _ = m.Update(kioskTickMsg{})
+	if m = updated.(model); m.repo != "o/a" || m.kioskAt != 0 {
+		t.Errorf("didn't wrap around: %s at %d", m.repo, m.kioskAt)
+	}
+}
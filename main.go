@@ -1,91 +1,125 @@
 package main
 
 import (
-	"flag"
-	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
-	"time"
-
-	tea "github.com/charmbracelet/bubbletea"
 )
 
-func parsePRURL(url string) (repo string, prNumber string, ok bool) {
-	// https://github.com/owner/repo/pull/123
-	url = strings.TrimRight(url, "/")
-	parts := strings.Split(url, "/")
-	// Expected: ["https:", "", "github.com", "owner", "repo", "pull", "123"]
-	if len(parts) < 7 {
+// splitRemote separates a repo reference into host and path. It understands
+// https URLs, URLs without a scheme, ssh:// URLs and scp-style SSH remotes
+// (git@github.com:owner/repo.git). References without a host return "".
+func splitRemote(s string) (host, path string) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+		if at := strings.Index(s, "@"); at >= 0 && at < strings.Index(s+"/", "/") {
+			s = s[at+1:]
+		}
+		host, path, _ = strings.Cut(s, "/")
+		host, _, _ = strings.Cut(host, ":")
+		return host, path
+	}
+	if at := strings.Index(s, "@"); at >= 0 {
+		if h, p, ok := strings.Cut(s[at+1:], ":"); ok {
+			return h, p
+		}
+	}
+	first, rest, ok := strings.Cut(s, "/")
+	if ok && strings.Contains(first, ".") {
+		return first, rest
+	}
+	return "", s
+}
+
+// knownHost reports whether host is github.com or one of hosts.
+func knownHost(host string, hosts []string) bool {
+	if strings.EqualFold(host, "github.com") || strings.EqualFold(host, "www.github.com") {
+		return true
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifyRepo prefixes owner/repo with host for non-github.com hosts, which
+// is the HOST/OWNER/REPO form gh's --repo flag accepts.
+func qualifyRepo(host, owner, name string) string {
+	if host == "" || strings.EqualFold(host, "github.com") || strings.EqualFold(host, "www.github.com") {
+		return owner + "/" + name
+	}
+	return host + "/" + owner + "/" + name
+}
+
+// parseRepo accepts owner/repo, HOST/owner/repo, a repo URL or an SSH remote
+// and returns the normalized repo. hosts lists extra (Enterprise) hosts.
+func parseRepo(s string, hosts ...string) (string, bool) {
+	host, path := splitRemote(s)
+	if host != "" && !knownHost(host, hosts) {
+		return "", false
+	}
+	if strings.ContainsAny(path, "#?") {
+		return "", false
+	}
+	path = strings.TrimSuffix(strings.TrimRight(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if host == "" && len(parts) == 3 && knownHost(parts[0], hosts) {
+		host, parts = parts[0], parts[1:]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return qualifyRepo(host, parts[0], parts[1]), true
+}
+
+// parsePRURL parses a PR URL such as https://github.com/owner/repo/pull/123.
+// The scheme is optional and anything after the PR number (/files, query
+// strings, fragments) is ignored. hosts lists extra (Enterprise) hosts.
+func parsePRURL(url string, hosts ...string) (repo string, prNumber string, ok bool) {
+	url, _, _ = strings.Cut(url, "#")
+	url, _, _ = strings.Cut(url, "?")
+	host, path := splitRemote(url)
+	if host == "" || !knownHost(host, hosts) {
 		return "", "", false
 	}
-	if parts[2] != "github.com" || parts[5] != "pull" {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	// Expected: ["owner", "repo", "pull", "123", ...]
+	if len(parts) < 4 || parts[2] != "pull" {
 		return "", "", false
 	}
-	repo = parts[3] + "/" + parts[4]
-	prNumber = parts[6]
-	if prNumber == "" {
+	if parts[0] == "" || parts[1] == "" {
 		return "", "", false
 	}
+	prNumber = parts[3]
 	if _, err := strconv.Atoi(prNumber); err != nil {
 		return "", "", false
 	}
-	return repo, prNumber, true
+	return qualifyRepo(host, parts[0], parts[1]), prNumber, true
 }
 
-func main() {
-	interval := flag.Int("interval", 5, "Refresh interval in seconds")
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: prtop [--interval N] [PR-URL | owner/repo PR-number]\n\n")
-		fmt.Fprintf(os.Stderr, "Live-updating terminal UI for GitHub PR check statuses.\n\n")
-		fmt.Fprintf(os.Stderr, "When run with no arguments, shows your 5 most recent open PRs to select from.\n\n")
-		fmt.Fprintf(os.Stderr, "Examples:\n")
-		fmt.Fprintf(os.Stderr, "  prtop                                            # pick from recent PRs\n")
-		fmt.Fprintf(os.Stderr, "  prtop https://github.com/owner/repo/pull/123\n")
-		fmt.Fprintf(os.Stderr, "  prtop owner/repo 123\n")
-		fmt.Fprintf(os.Stderr, "  prtop --interval 10 owner/repo 123\n\n")
-		fmt.Fprintf(os.Stderr, "Flags:\n")
-		flag.PrintDefaults()
-	}
-	flag.Parse()
-
-	args := flag.Args()
-	if len(args) > 2 {
-		flag.Usage()
-		os.Exit(1)
+// parsePRRef parses a single-argument PR reference: a PR URL or the
+// owner/repo#123 shorthand.
+func parsePRRef(s string, hosts ...string) (repo string, prNumber string, ok bool) {
+	if repo, prNumber, ok := parsePRURL(s, hosts...); ok {
+		return repo, prNumber, true
 	}
-
-	// Check gh is available
-	if _, err := exec.LookPath("gh"); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: 'gh' CLI not found on PATH.\n")
-		fmt.Fprintf(os.Stderr, "Install it from https://cli.github.com/\n")
-		os.Exit(1)
+	r, n, found := strings.Cut(s, "#")
+	if !found {
+		return "", "", false
 	}
-
-	var m model
-	dur := time.Duration(*interval) * time.Second
-	switch len(args) {
-	case 0:
-		m = newSelectModel(dur)
-	case 1:
-		repo, prNumber, ok := parsePRURL(args[0])
-		if !ok {
-			fmt.Fprintf(os.Stderr, "Error: invalid PR URL: %s\n", args[0])
-			fmt.Fprintf(os.Stderr, "Expected format: https://github.com/owner/repo/pull/123\n")
-			os.Exit(1)
-		}
-		m = newModel(repo, prNumber, dur)
-	default:
-		if _, err := strconv.Atoi(args[1]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: PR number must be numeric: %s\n", args[1])
-			os.Exit(1)
-		}
-		m = newModel(args[0], args[1], dur)
+	if _, err := strconv.Atoi(n); err != nil {
+		return "", "", false
 	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	repo, ok = parseRepo(r, hosts...)
+	if !ok {
+		return "", "", false
 	}
+	return repo, n, true
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
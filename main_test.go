@@ -4,11 +4,11 @@ import "testing"
 
 func TestParsePRURL(t *testing.T) {
 	tests := []struct {
-		name       string
-		url        string
-		wantRepo   string
-		wantPR     string
-		wantOK     bool
+		name     string
+		url      string
+		wantRepo string
+		wantPR   string
+		wantOK   bool
 	}{
 		{
 			name:     "valid URL",
@@ -49,6 +49,27 @@ func TestParsePRURL(t *testing.T) {
 			url:    "https://github.com/owner/repo/pull/abc",
 			wantOK: false,
 		},
+		{
+			name:     "no scheme",
+			url:      "github.com/owner/repo/pull/7",
+			wantRepo: "owner/repo",
+			wantPR:   "7",
+			wantOK:   true,
+		},
+		{
+			name:     "files tab with fragment",
+			url:      "https://github.com/owner/repo/pull/7/files#diff-abc",
+			wantRepo: "owner/repo",
+			wantPR:   "7",
+			wantOK:   true,
+		},
+		{
+			name:     "query string",
+			url:      "https://github.com/owner/repo/pull/7?notification_referrer_id=x",
+			wantRepo: "owner/repo",
+			wantPR:   "7",
+			wantOK:   true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -69,3 +90,78 @@ func TestParsePRURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePRURLEnterpriseHost(t *testing.T) {
+	repo, pr, ok := parsePRURL("https://ghe.corp.com/team/svc/pull/5", "ghe.corp.com")
+	if !ok {
+		t.Fatal("expected configured host to be accepted")
+	}
+	if repo != "ghe.corp.com/team/svc" || pr != "5" {
+		t.Errorf("got %q #%s, want ghe.corp.com/team/svc #5", repo, pr)
+	}
+	if _, _, ok := parsePRURL("https://ghe.corp.com/team/svc/pull/5"); ok {
+		t.Error("unconfigured host should be rejected")
+	}
+}
+
+func TestParseRepo(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"owner/repo", "owner/repo", true},
+		{"git@github.com:owner/repo.git", "owner/repo", true},
+		{"git@github.com:owner/repo", "owner/repo", true},
+		{"ssh://git@github.com/owner/repo.git", "owner/repo", true},
+		{"https://github.com/owner/repo.git", "owner/repo", true},
+		{"https://github.com/owner/repo/", "owner/repo", true},
+		{"github.com/owner/repo", "owner/repo", true},
+		{"git@ghe.corp.com:team/svc.git", "ghe.corp.com/team/svc", true},
+		{"ghe.corp.com/team/svc", "ghe.corp.com/team/svc", true},
+		{"git@gitlab.com:owner/repo.git", "", false},
+		{"owner", "", false},
+		{"owner/", "", false},
+		{"a/b/c", "", false},
+		{"owner/repo#12", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := parseRepo(tt.input, "ghe.corp.com")
+			if ok != tt.wantOK {
+				t.Fatalf("parseRepo(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("parseRepo(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePRRef(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantRepo string
+		wantPR   string
+		wantOK   bool
+	}{
+		{"https://github.com/owner/repo/pull/123", "owner/repo", "123", true},
+		{"owner/repo#123", "owner/repo", "123", true},
+		{"github.com/owner/repo#9", "owner/repo", "9", true},
+		{"owner/repo#abc", "", "", false},
+		{"owner/repo", "", "", false},
+		{"#123", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repo, pr, ok := parsePRRef(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("parsePRRef(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if repo != tt.wantRepo || pr != tt.wantPR {
+				t.Errorf("parsePRRef(%q) = %q, %q; want %q, %q", tt.input, repo, pr, tt.wantRepo, tt.wantPR)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// keyBinding is one row of the man page's KEYS section.
+type keyBinding struct {
+	keys   string
+	action string
+}
+
+var keyBindings = []keyBinding{
+	{"q, ctrl+c", "Quit"},
+	{"r", "Refresh now"},
+	{"up, k / down, j", "Move the selection; a count in front moves further, e.g. 5j"},
+	{"g, home / G, end", "First or last row; 20G goes to row 20"},
+	{"ctrl+u / ctrl+d", "Move half a page up or down"},
+	{"pgup / pgdown", "Move a page up or down"},
+	{"n / N", "Next or previous failing or running check"},
+	{"enter", "Open the selected PR, or the selected check in the browser"},
+	{"esc", "Close an overlay or go back to the picker or dashboard"},
+	{"a", "Switch account (picker)"},
+	{"o", "Cycle sort order (picker)"},
+	{"R", "Group by repo (picker)"},
+	{"w", "Watch or unwatch the PR (dashboard)"},
+	{"s", "Show or hide skipped checks"},
+	{"A", "Acknowledge or un-acknowledge the selected failure"},
+	{"I", "Ignore the selected check in all of the repo's PRs, or stop ignoring it"},
+	{"M", "Show only the checks matching [filter] mine and the required ones, or all checks"},
+	{":", "Open the command palette"},
+	{",", "Open the settings pane; changes are saved to the config file"},
+	{"ctrl+y", "Copy a Markdown report of the checks (or write it to a file)"},
+	{"d", "Show the job dependency tree"},
+	{"u", "List unresolved review threads"},
+	{"e", "Show the check state event log"},
+	{"t", "Show the checks on a timeline, with the critical path"},
+	{"p", "Show earlier attempts of rerun checks"},
+	{"S", "Show security alerts the PR introduces"},
+	{"D", "Toggle the debug status line"},
+	{"+ / -", "Lengthen or shorten the refresh interval (saved to the config)"},
+	{"v", "Toggle the split view"},
+	{"tab", "Cycle the split view's pane: job log, details, events"},
+	{"ctrl+w w, h, l", "Move focus between the table and the pane"},
+	{"ctrl+w < / >, =", "Narrow or widen the table, or reset the split"},
+	{"z", "Cycle display density: normal, compact, comfy"},
+}
+
+// roffEscape escapes text for a roff line: backslashes, hyphens (so they
+// stay ASCII in copy-paste) and a leading control character.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// writeManTag starts a tagged paragraph for name in bold followed by arg
+// in italics.
+func writeManTag(w io.Writer, name, arg string) {
+	if arg == "" {
+		fmt.Fprintf(w, ".TP\n.B %s\n", roffEscape(name))
+		return
+	}
+	fmt.Fprintf(w, ".TP\n.BI %s \" %s\"\n", roffEscape(name), roffEscape(arg))
+}
+
+// writeManFlags lists fs's flags as tagged paragraphs, skipping those in
+// skip.
+func writeManFlags(w io.Writer, fs *flag.FlagSet, skip map[string]bool) {
+	fs.VisitAll(func(f *flag.Flag) {
+		if skip[f.Name] {
+			return
+		}
+		name, usage := flag.UnquoteUsage(f)
+		writeManTag(w, "--"+f.Name, name)
+		fmt.Fprintf(w, "%s\n", roffEscape(usage))
+	})
+}
+
+// writeManPage renders prtop(1) from the command table, the flag sets and
+// keyBindings, so it can't drift from the CLI. It is dated with the build
+// date when there is one, so the output is reproducible.
+func writeManPage(w io.Writer, now time.Time) {
+	v, _, d := buildInfo()
+	if t, err := time.Parse(time.RFC3339, d); err == nil {
+		now = t
+	} else if t, err := time.Parse(time.DateOnly, d); err == nil {
+		now = t
+	}
+	fmt.Fprintf(w, ".TH PRTOP 1 \"%s\" \"prtop %s\" \"User Commands\"\n", now.Format(time.DateOnly), roffEscape(v))
+	fmt.Fprintf(w, ".SH NAME\nprtop \\- live\\-updating terminal UI for GitHub PR check statuses\n")
+
+	fmt.Fprintf(w, ".SH SYNOPSIS\n")
+	fmt.Fprintf(w, ".B prtop\n[\\fIflags\\fR] [\\fIPR\\-URL\\fR | \\fIowner/repo\\fR [\\fIPR\\-number\\fR | \\fIbranch\\fR]]\n.br\n")
+	fmt.Fprintf(w, ".B prtop\n[\\fIflags\\fR] \\fBissue\\fR \\fIowner/repo\\fR \\fIISSUE\\-number\\fR\n.br\n")
+	fmt.Fprintf(w, ".B prtop\n[\\fIflags\\fR] \\fICOMMAND\\fR [\\fIcommand flags\\fR] [\\fIargs\\fR]\n")
+
+	fmt.Fprintf(w, ".SH DESCRIPTION\n")
+	fmt.Fprintf(w, "prtop shows the checks of a GitHub pull request and refreshes them every few seconds.\n")
+	fmt.Fprintf(w, "With no arguments it lists your most recent open PRs to pick from;\n")
+	fmt.Fprintf(w, "given a repository it lists that repository's open PRs.\n")
+	fmt.Fprintf(w, "It uses the \\fBgh\\fR(1) CLI for all GitHub requests.\n")
+	fmt.Fprintf(w, ".PP\n\\fIPR\\fR is a PR URL, \\fIowner/repo#123\\fR, \\fIowner/repo 123\\fR or \\fIowner/repo BRANCH\\fR, the open PR from that head branch.\n")
+
+	o := defaultOptions()
+	globalFlags := o.commandFlagSet(command{})
+	global := map[string]bool{}
+	globalFlags.VisitAll(func(f *flag.Flag) { global[f.Name] = true })
+
+	fmt.Fprintf(w, ".SH COMMANDS\n")
+	for _, c := range commands {
+		writeManTag(w, c.name, c.args)
+		fmt.Fprintf(w, "%s.\n", roffEscape(c.summary))
+		if c.flags != nil {
+			fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+			c.flags(defaultOptions(), fs)
+			fmt.Fprintf(w, ".RS\n")
+			writeManFlags(w, fs, nil)
+			fmt.Fprintf(w, ".RE\n")
+		}
+	}
+
+	fmt.Fprintf(w, ".SH OPTIONS\n")
+	fmt.Fprintf(w, "These flags are accepted before the command name and by every command.\n")
+	writeManFlags(w, globalFlags, nil)
+	fmt.Fprintf(w, ".PP\nWithout a command, prtop also takes:\n")
+	writeManFlags(w, o.flagSet(), global)
+
+	fmt.Fprintf(w, ".SH KEYS\n")
+	for _, k := range keyBindings {
+		fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roffEscape(k.keys), roffEscape(k.action))
+	}
+
+	fmt.Fprintf(w, ".SH EXIT STATUS\n")
+	fmt.Fprintf(w, "\\fBwait\\fR, \\fBstream\\fR and \\fBstatus\\fR exit 0 when every check passed or was skipped, cancelled or neutral,\n")
+	fmt.Fprintf(w, "1 when a check failed (or on error) and 8 while checks are still running.\n")
+	fmt.Fprintf(w, "Other commands exit 0 on success and 1 on error.\n")
+
+	fmt.Fprintf(w, ".SH FILES\n")
+	for _, f := range []struct{ path, what string }{
+		{"~/.config/prtop/config.toml", "Configuration: accounts, polling, display, colors."},
+		{"~/.config/prtop/watchlist", "PR URLs the dashboard always includes."},
+		{"~/.local/state/prtop/state.json", "Acknowledged failures and ignored checks."},
+		{"~/.cache/prtop/responses.json", "Last good responses, shown while offline."},
+		{"$XDG_RUNTIME_DIR/prtop.sock", "Control socket for prtop ctl, while the TUI runs."},
+	} {
+		fmt.Fprintf(w, ".TP\n.I %s\n%s\n", roffEscape(f.path), roffEscape(f.what))
+	}
+	fmt.Fprintf(w, ".PP\nThe XDG_CONFIG_HOME, XDG_STATE_HOME and XDG_CACHE_HOME variables move these directories.\n")
+
+	fmt.Fprintf(w, ".SH SEE ALSO\n.BR gh (1)\n")
+}
+
+func runMan(s *session, args []string) (int, error) {
+	if len(args) > 0 {
+		return exitFailed, fmt.Errorf("man takes no arguments")
+	}
+	writeManPage(s.stdout, time.Now())
+	return exitOK, nil
+}
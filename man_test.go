@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// writeManPage
+// ---------------------------------------------------------------------------
+
+func TestWriteManPage(t *testing.T) {
+	var b strings.Builder
+	writeManPage(&b, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	page := b.String()
+
+	if !strings.HasPrefix(page, `.TH PRTOP 1 "2024-05-01" "prtop dev" "User Commands"`) {
+		t.Errorf("title line = %q", strings.SplitN(page, "\n", 2)[0])
+	}
+	for _, want := range []string{
+		".SH COMMANDS\n",
+		".BI wait \" PR\"\n",
+		".BI \\-\\-timeout \" duration\"\n",
+		".SH OPTIONS\n",
+		".BI \\-\\-interval \" interval\"\n",
+		".B \\-\\-dashboard\n",
+		".SH KEYS\n.TP\n.B q, ctrl+c\nQuit\n",
+		".B ctrl+w < / >, =\n",
+		".SH EXIT STATUS\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("man page is missing %q", want)
+		}
+	}
+	// Global flags are listed once, not again under the bare-prtop flags
+	if n := strings.Count(page, ".BI \\-\\-interval "); n != 1 {
+		t.Errorf("--interval listed %d times", n)
+	}
+	for i, line := range strings.Split(page, "\n") {
+		if strings.HasPrefix(line, "'") {
+			t.Errorf("line %d starts with a control character: %q", i+1, line)
+		}
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"--on-change", `\-\-on\-change`},
+		{`C:\path`, `C:\epath`},
+		{".hidden", `\&.hidden`},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := roffEscape(tt.in); got != tt.want {
+			t.Errorf("roffEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// mcpProtocolVersions are the Model Context Protocol revisions prtop
+// speaks, newest first.
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// mcpLogLines is how much of each failed job's log get_failed_check_logs
+// returns by default: the end, where the failure usually is.
+const mcpLogLines = 100
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// mcpSchema is a JSON Schema object with the given properties.
+func mcpSchema(required []string, props map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var mcpPRProp = map[string]any{
+	"type":        "string",
+	"description": "PR URL or owner/repo#123. Defaults to the PR of the branch checked out where prtop runs.",
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name: "get_pr_checks",
+		Description: "Get a GitHub pull request's CI checks: each check's status (pass, fail, running, skipped, cancelled, neutral), " +
+			"duration and details URL, and the overall status (success, failure, pending).",
+		InputSchema: mcpSchema(nil, map[string]any{"pr": mcpPRProp}),
+	},
+	{
+		Name: "get_failed_check_logs",
+		Description: "Get the end of the GitHub Actions log of each failing check on a pull request, " +
+			"with the failing tests when prtop can find them.",
+		InputSchema: mcpSchema(nil, map[string]any{
+			"pr":    mcpPRProp,
+			"check": map[string]any{"type": "string", "description": "Only this check, by name as get_pr_checks reports it"},
+			"lines": map[string]any{"type": "integer", "description": fmt.Sprintf("Log lines per check, from the end (default %d)", mcpLogLines)},
+		}),
+	},
+	{
+		Name:        "rerun_check",
+		Description: "Rerun the GitHub Actions job behind a check, the failed jobs of its workflow run, or the whole run.",
+		InputSchema: mcpSchema([]string{"check"}, map[string]any{
+			"pr":    mcpPRProp,
+			"check": map[string]any{"type": "string", "description": "Check name, as get_pr_checks reports it"},
+			"scope": map[string]any{"type": "string", "enum": []string{"job", "failed", "run"}, "description": "What to rerun (default job)"},
+		}),
+	},
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+// mcpCall runs one MCP method.
+func (s *session) mcpCall(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(params, &p)
+		version := mcpProtocolVersions[0]
+		if slices.Contains(mcpProtocolVersions, p.ProtocolVersion) {
+			version = p.ProtocolVersion
+		}
+		v, _, _ := buildInfo()
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "prtop", "version": v},
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]any{"tools": mcpTools}, nil
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(mcpTools, func(t mcpTool) bool { return t.Name == p.Name }) {
+			return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("unknown tool %q", p.Name)}
+		}
+		if len(p.Arguments) == 0 {
+			p.Arguments = json.RawMessage("{}")
+		}
+		text, err := s.mcpTool(p.Name, p.Arguments)
+		if err != nil {
+			// Tool failures go back to the model, which may be able to fix
+			// its arguments
+			return mcpToolResult{Content: []mcpContent{{"text", err.Error()}}, IsError: true}, nil
+		}
+		return mcpToolResult{Content: []mcpContent{{"text", text}}}, nil
+	}
+	if strings.HasPrefix(method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("unknown method %q", method)}
+}
+
+// mcpTool runs a tool and returns its text output.
+func (s *session) mcpTool(name string, args json.RawMessage) (string, error) {
+	var p struct {
+		PR    string `json:"pr"`
+		Check string `json:"check"`
+		Lines int    `json:"lines"`
+		Scope string `json:"scope"`
+	}
+	if err := json.Unmarshal(args, &p); err != nil {
+		return "", fmt.Errorf("invalid arguments: %v", err)
+	}
+	if p.PR == "" {
+		pr, err := currentBranchPR()
+		if err != nil {
+			return "", err
+		}
+		p.PR = pr
+	}
+
+	switch name {
+	case "get_pr_checks":
+		repo, prNumber, data, status, err := s.fetchChecks([]string{p.PR})
+		if err != nil {
+			return "", err
+		}
+		out, err := json.MarshalIndent(newExportPR(repo, prNumber, data, status), "", "  ")
+		return string(out), err
+	case "get_failed_check_logs":
+		if p.Lines <= 0 {
+			p.Lines = mcpLogLines
+		}
+		return s.failedCheckLogs(p.PR, p.Check, p.Lines)
+	}
+	if p.Check == "" {
+		return "", errors.New("rerun_check needs a check name")
+	}
+	if err := s.rerun(rpcRerunParams{PR: p.PR, Check: p.Check, Scope: p.Scope}); err != nil {
+		return "", err
+	}
+	scope := p.Scope
+	if scope == "" {
+		scope = "job"
+	}
+	return fmt.Sprintf("Requested a rerun (%s) of %s on %s", scope, p.Check, p.PR), nil
+}
+
+// currentBranchPR is the URL of the PR for the branch checked out in the
+// working directory, as gh finds it.
+func currentBranchPR() (string, error) {
+	out, err := runGh(nil, "pr", "view", "--json", "url")
+	if err != nil {
+		return "", fmt.Errorf("no pr given and no PR found for the current branch: %w", err)
+	}
+	var pr struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(out, &pr); err != nil || pr.URL == "" {
+		return "", errors.New("no pr given and no PR found for the current branch")
+	}
+	return pr.URL, nil
+}
+
+// failedCheckLogs is get_failed_check_logs: for each failing check (or
+// just check), the failing tests and the last lines of its job log.
+func (s *session) failedCheckLogs(pr, check string, lines int) (string, error) {
+	repo, prNumber, data, _, err := s.fetchChecks([]string{pr})
+	if err != nil {
+		return "", err
+	}
+	acct := s.acct(repo)
+	var b strings.Builder
+	found := false
+	for _, c := range data.Checks {
+		if check != "" && c.Name != check || check == "" && c.Status != Fail {
+			continue
+		}
+		found = true
+		fmt.Fprintf(&b, "== %s (%s) ==\n", c.Name, strings.ToLower(c.Status.String()))
+		runID, jobID := actionsRunID(c.DetailsURL)
+		if jobID == "" {
+			fmt.Fprintf(&b, "No log: not a GitHub Actions job. Details: %s\n\n", c.DetailsURL)
+			continue
+		}
+		log, err := fetchJobLog(acct, repo, jobID)
+		if err != nil {
+			fmt.Fprintf(&b, "Failed to fetch the log: %v\n\n", err)
+			continue
+		}
+		report := parseGoTestLog(log)
+		if report == nil && c.Status == Fail && runID != "" {
+			report, _ = fetchJUnitReport(acct, repo, runID)
+		}
+		if report != nil && len(report.Failing) > 0 {
+			fmt.Fprintf(&b, "Failing tests (%s): %s\n", report.Source, strings.Join(report.Failing, ", "))
+		}
+		if len(log) > lines {
+			fmt.Fprintf(&b, "[last %d of %d log lines]\n", lines, len(log))
+			log = log[len(log)-lines:]
+		}
+		b.WriteString(strings.Join(log, "\n"))
+		b.WriteString("\n\n")
+	}
+	switch {
+	case !found && check != "":
+		return "", fmt.Errorf("%s#%s has no check named %q", repo, prNumber, check)
+	case !found:
+		return fmt.Sprintf("%s#%s has no failing checks.", repo, prNumber), nil
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func runMCP(s *session, args []string) (int, error) {
+	if len(args) > 0 {
+		return exitFailed, errors.New("mcp takes no arguments")
+	}
+	if err := serveRPC(s.stdin, s.stdout, s.mcpCall); err != nil {
+		return exitFailed, err
+	}
+	return exitOK, nil
+}
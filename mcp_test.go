@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const mcpJobLog = "2024-05-01T10:00:00.0000000Z Run go test ./...\n" +
+	"2024-05-01T10:00:01.0000000Z --- FAIL: TestParse (0.00s)\n" +
+	"2024-05-01T10:00:01.0000000Z FAIL\n"
+
+// fakeMCPGh serves a PR o/r#7 (the current branch's) with a failing
+// Actions check and a failing external status, and records reruns.
+func fakeMCPGh(t *testing.T) *[]string {
+	t.Helper()
+	var reruns []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		joined := strings.Join(args, " ")
+		switch {
+		case joined == "pr view --json url":
+			return []byte(`{"url":"https://github.com/o/r/pull/7"}`), nil
+		case strings.HasPrefix(joined, "pr view 7 "):
+			return []byte(streamPR("abc",
+				`{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"FAILURE","detailsUrl":"https://github.com/o/r/actions/runs/11/job/22"}`,
+				`{"__typename":"CheckRun","name":"test","status":"COMPLETED","conclusion":"SUCCESS","detailsUrl":"https://github.com/o/r/actions/runs/11/job/23"}`,
+				`{"__typename":"StatusContext","context":"ci/legacy","state":"FAILURE","targetUrl":"https://ci.example.com/1"}`)), nil
+		case strings.HasSuffix(joined, "repos/o/r/actions/jobs/22/logs"):
+			return []byte(mcpJobLog), nil
+		case strings.HasPrefix(joined, "run rerun"):
+			reruns = append(reruns, joined)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unexpected gh %v", args)
+	})
+	t.Cleanup(func() { ghOverride = nil })
+	return &reruns
+}
+
+// mcpToolCall calls a tool and returns its text and isError.
+func mcpToolCall(t *testing.T, s *session, tool, args string) (string, bool) {
+	t.Helper()
+	params := fmt.Sprintf(`{"name":%q,"arguments":%s}`, tool, args)
+	result, err := s.mcpCall("tools/call", json.RawMessage(params))
+	if err != nil {
+		t.Fatalf("%s: %v", tool, err)
+	}
+	r := result.(mcpToolResult)
+	if len(r.Content) != 1 || r.Content[0].Type != "text" {
+		t.Fatalf("%s: content = %+v", tool, r.Content)
+	}
+	return r.Content[0].Text, r.IsError
+}
+
+func TestMCPHandshake(t *testing.T) {
+	s, _, _ := testSession(t)
+	answers := rpcExchange(t, s.mcpCall,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+	)
+	if len(answers) != 6 {
+		t.Errorf("got %d answers, want 6", len(answers))
+	}
+
+	var init struct {
+		ProtocolVersion string
+		Capabilities    map[string]any
+		ServerInfo      struct{ Name string }
+	}
+	json.Unmarshal(answers["1"].Result.(json.RawMessage), &init)
+	if init.ProtocolVersion != "2025-03-26" || init.Capabilities["tools"] == nil || init.ServerInfo.Name != "prtop" {
+		t.Errorf("initialize = %+v", init)
+	}
+	json.Unmarshal(answers["4"].Result.(json.RawMessage), &init)
+	if init.ProtocolVersion != mcpProtocolVersions[0] {
+		t.Errorf("unknown client version answered with %q, want the latest", init.ProtocolVersion)
+	}
+
+	var list struct{ Tools []mcpTool }
+	json.Unmarshal(answers["2"].Result.(json.RawMessage), &list)
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		if tool.InputSchema["type"] != "object" || tool.Description == "" {
+			t.Errorf("tool %s: schema %v", tool.Name, tool.InputSchema)
+		}
+	}
+	if strings.Join(names, " ") != "get_pr_checks get_failed_check_logs rerun_check" {
+		t.Errorf("tools = %v", names)
+	}
+
+	if e := answers["5"].Error; e == nil || e.Code != rpcInvalidParams || !strings.Contains(e.Message, `unknown tool "nope"`) {
+		t.Errorf("unknown tool: %+v", e)
+	}
+	if e := answers["6"].Error; e == nil || e.Code != rpcMethodNotFound {
+		t.Errorf("unknown method: %+v", e)
+	}
+}
+
+func TestMCPGetPRChecks(t *testing.T) {
+	fakeMCPGh(t)
+	s, _, _ := testSession(t)
+	// No pr: the current branch's
+	text, isErr := mcpToolCall(t, s, "get_pr_checks", `{}`)
+	var pr exportPR
+	if err := json.Unmarshal([]byte(text), &pr); err != nil || isErr || pr.Number != 7 || pr.Status != rollupFailure || len(pr.Checks) != 3 {
+		t.Errorf("get_pr_checks = %s (isError %v)", text, isErr)
+	}
+
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		return nil, errors.New("no pull requests found for branch \"main\"")
+	})
+	text, isErr = mcpToolCall(t, s, "get_pr_checks", `{}`)
+	if !isErr || !strings.Contains(text, "no PR found for the current branch") {
+		t.Errorf("no current PR: %q, isError %v", text, isErr)
+	}
+}
+
+func TestMCPFailedCheckLogs(t *testing.T) {
+	fakeMCPGh(t)
+	s, _, _ := testSession(t)
+	text, isErr := mcpToolCall(t, s, "get_failed_check_logs", `{"pr":"o/r#7"}`)
+	for _, want := range []string{
+		"== build (fail) ==\nFailing tests (go test): TestParse\nRun go test ./...\n--- FAIL: TestParse (0.00s)\nFAIL",
+		"== ci/legacy (fail) ==\nNo log: not a GitHub Actions job. Details: https://ci.example.com/1",
+	} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("logs missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "== test") {
+		t.Errorf("passing checks should be left out:\n%s", text)
+	}
+
+	text, _ = mcpToolCall(t, s, "get_failed_check_logs", `{"pr":"o/r#7","check":"build","lines":1}`)
+	if !strings.HasSuffix(text, "[last 1 of 3 log lines]\nFAIL") || strings.Contains(text, "ci/legacy") {
+		t.Errorf("one check, one line:\n%s", text)
+	}
+	if text, isErr = mcpToolCall(t, s, "get_failed_check_logs", `{"pr":"o/r#7","check":"nope"}`); !isErr {
+		t.Errorf("unknown check: %q", text)
+	}
+}
+
+func TestMCPRerunCheck(t *testing.T) {
+	reruns := fakeMCPGh(t)
+	s, _, _ := testSession(t)
+	text, isErr := mcpToolCall(t, s, "rerun_check", `{"pr":"o/r#7","check":"build","scope":"failed"}`)
+	if isErr || text != "Requested a rerun (failed) of build on o/r#7" {
+		t.Errorf("rerun_check = %q, isError %v", text, isErr)
+	}
+	if len(*reruns) != 1 || (*reruns)[0] != "run rerun 11 --failed --repo o/r" {
+		t.Errorf("reruns = %v", *reruns)
+	}
+
+	for _, args := range []string{`{"pr":"o/r#7"}`, `{"pr":"o/r#7","check":"ci/legacy"}`, `{"check":5}`} {
+		if text, isErr := mcpToolCall(t, s, "rerun_check", args); !isErr {
+			t.Errorf("rerun_check %s = %q, want an error", args, text)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mergeQueueRecheck is how often a PR that isn't in a merge queue is asked
+// about again, to notice it being queued. A queued PR is asked on every
+// refresh.
+const mergeQueueRecheck = time.Minute
+
+// mergeQueue is where a PR stands in its base branch's merge queue. The
+// checks that gate the merge run on the queue's temporary branch, not the
+// PR's, so they're fetched too.
+type mergeQueue struct {
+	repo     string
+	prNumber string
+	at       time.Time
+	queued   bool
+	position int    // 1 is the head of the queue
+	size     int    // PRs in the queue
+	state    string // QUEUED, AWAITING_CHECKS, MERGEABLE, UNMERGEABLE or LOCKED
+	eta      int    // estimated seconds until merged, 0 when unknown
+	head     int    // the PR at the head of the queue
+	headSHA  string // the temporary branch's commit
+	checks   []Check
+	err      error
+}
+
+type mergeQueueMsg struct {
+	queue *mergeQueue
+}
+
+const mergeQueueQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      mergeQueueEntry {
+        position state estimatedTimeToMerge
+        headCommit {
+          oid
+          statusCheckRollup {
+            contexts(first: 100) {
+              nodes {
+                __typename
+                ... on CheckRun {
+                  name status conclusion startedAt completedAt detailsUrl
+                  checkSuite { workflowRun { workflow { name } } }
+                }
+                ... on StatusContext { context state targetUrl description }
+              }
+            }
+          }
+        }
+        mergeQueue { entries(first: 1) { totalCount nodes { pullRequest { number } } } }
+      }
+    }
+  }
+}`
+
+func fetchMergeQueue(acct *Account, repo, prNumber string) *mergeQueue {
+	q := &mergeQueue{repo: repo, prNumber: prNumber, at: time.Now()}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+mergeQueueQuery,
+		"-F", "owner="+owner, "-F", "name="+name, "-F", "number="+prNumber)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					MergeQueueEntry *struct {
+						Position             int    `json:"position"`
+						State                string `json:"state"`
+						EstimatedTimeToMerge *int   `json:"estimatedTimeToMerge"`
+						HeadCommit           *struct {
+							OID               string `json:"oid"`
+							StatusCheckRollup *struct {
+								Contexts struct {
+									Nodes []struct {
+										ghCheckItem
+										CheckSuite *struct {
+											WorkflowRun *struct {
+												Workflow struct {
+													Name string `json:"name"`
+												} `json:"workflow"`
+											} `json:"workflowRun"`
+										} `json:"checkSuite"`
+									} `json:"nodes"`
+								} `json:"contexts"`
+							} `json:"statusCheckRollup"`
+						} `json:"headCommit"`
+						MergeQueue *struct {
+							Entries struct {
+								TotalCount int `json:"totalCount"`
+								Nodes      []struct {
+									PullRequest struct {
+										Number int `json:"number"`
+									} `json:"pullRequest"`
+								} `json:"nodes"`
+							} `json:"entries"`
+						} `json:"mergeQueue"`
+					} `json:"mergeQueueEntry"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		q.err = fmt.Errorf("failed to parse merge queue: %w", err)
+		return q
+	}
+	entry := resp.Data.Repository.PullRequest.MergeQueueEntry
+	if entry == nil {
+		return q
+	}
+	q.queued = true
+	q.position = entry.Position
+	q.state = entry.State
+	if entry.EstimatedTimeToMerge != nil {
+		q.eta = *entry.EstimatedTimeToMerge
+	}
+	if mq := entry.MergeQueue; mq != nil {
+		q.size = mq.Entries.TotalCount
+		if len(mq.Entries.Nodes) > 0 {
+			q.head = mq.Entries.Nodes[0].PullRequest.Number
+		}
+	}
+	if c := entry.HeadCommit; c != nil {
+		q.headSHA = c.OID
+		if c.StatusCheckRollup != nil {
+			items := make([]ghCheckItem, 0, len(c.StatusCheckRollup.Contexts.Nodes))
+			for _, n := range c.StatusCheckRollup.Contexts.Nodes {
+				item := n.ghCheckItem
+				if n.CheckSuite != nil && n.CheckSuite.WorkflowRun != nil {
+					item.WorkflowName = n.CheckSuite.WorkflowRun.Workflow.Name
+				}
+				items = append(items, item)
+			}
+			q.checks = parseCheckItems(items)
+		}
+	}
+	return q
+}
+
+// mergeQueueCmd asks where the PR stands in the merge queue: on every
+// refresh while it's queued, else every mergeQueueRecheck. A host or repo
+// without merge queues answers with an error, and the PR isn't asked
+// again; after any other error it is, on the same timer.
+func (m model) mergeQueueCmd() (model, tea.Cmd) {
+	if m.prData == nil || m.queueBusy || m.prData.State == "MERGED" || m.prData.State == "CLOSED" {
+		return m, nil
+	}
+	if q := m.queue; q != nil && q.repo == m.repo && q.prNumber == m.prNumber &&
+		(mergeQueueUnsupported(q.err) || !q.queued && timeNow().Sub(q.at) < mergeQueueRecheck) {
+		return m, nil
+	}
+	m.queueBusy = true
+	acct := m.repoAccount(m.repo)
+	repo, prNumber := m.repo, m.prNumber
+	return m, func() tea.Msg {
+		return mergeQueueMsg{queue: fetchMergeQueue(acct, repo, prNumber)}
+	}
+}
+
+// mergeQueueUnsupported reports whether err means the PR can't have a
+// merge queue entry to ask about: GitHub doesn't know the field (an older
+// GitHub Enterprise Server), can't find the PR or won't let the token see
+// it.
+func mergeQueueUnsupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch ghErrKind(err) {
+	case ghErrNotFound, ghErrPermission:
+		return true
+	}
+	return strings.Contains(err.Error(), "doesn't exist on type")
+}
+
+// currentMergeQueue is the PR's merge queue entry, or nil when it isn't
+// queued.
+func (m model) currentMergeQueue() *mergeQueue {
+	q := m.queue
+	if q == nil || q.err != nil || !q.queued || q.repo != m.repo || q.prNumber != m.prNumber {
+		return nil
+	}
+	return q
+}
+
+// mergeQueueBadge is the header's "MERGE QUEUE 2/5".
+func (m model) mergeQueueBadge() string {
+	q := m.currentMergeQueue()
+	if q == nil {
+		return ""
+	}
+	if q.size > 0 {
+		return fmt.Sprintf(" MERGE QUEUE %d/%d", q.position, q.size)
+	}
+	return " MERGE QUEUE"
+}
+
+// mergeQueueLine is the status line while the PR is queued: its position,
+// the queue's head and the counts of the checks on the queue's branch.
+func (m model) mergeQueueLine() string {
+	q := m.currentMergeQueue()
+	if q == nil {
+		return ""
+	}
+	line := fmt.Sprintf("Merge queue: position %d", q.position)
+	if q.size > 0 {
+		line += fmt.Sprintf(" of %d", q.size)
+	}
+	switch {
+	case q.position == 1:
+		line += " (head)"
+	case q.head != 0:
+		line += fmt.Sprintf(", head #%d", q.head)
+	}
+	line += ", " + strings.ToLower(strings.ReplaceAll(q.state, "_", " "))
+	if len(q.checks) > 0 {
+		counts := map[CheckStatus]int{}
+		for _, c := range q.checks {
+			counts[c.Status]++
+		}
+		g := m.glyphs.glyph
+		line += fmt.Sprintf(" | queue checks %s%d %s%d %s%d", g(Fail), counts[Fail], g(Running), counts[Running], g(Pass), counts[Pass])
+	}
+	if q.eta > 0 {
+		line += " | merges in ~" + formatDuration(q.eta)
+	}
+	return line + " (Q: details)"
+}
+
+// renderMergeQueue is the merge queue overlay: the PR's entry and the
+// checks running on the queue's temporary branch.
+func (m model) renderMergeQueue() []string {
+	if m.queue != nil && m.queue.err != nil && m.queue.repo == m.repo && m.queue.prNumber == m.prNumber {
+		return []string{styleFail.Render(fmt.Sprintf("Error: %s", m.queue.err))}
+	}
+	q := m.currentMergeQueue()
+	if q == nil {
+		return []string{"This PR isn't in a merge queue."}
+	}
+	lines := []string{strings.TrimSuffix(m.mergeQueueLine(), " (Q: details)")}
+	if q.headSHA != "" {
+		lines = append(lines, styleDim.Render("Queue branch commit "+shortSHA(q.headSHA)))
+	}
+	lines = append(lines, "")
+	if len(q.checks) == 0 {
+		return append(lines, "No checks have started on the queue's branch yet.")
+	}
+	g := m.glyphs.glyph
+	for _, c := range q.checks {
+		lines = append(lines, fmt.Sprintf("%s  %-8s  %s",
+			statusStyle(c.Status).Render(fmt.Sprintf("%s %-9s", g(c.Status), c.Status)), c.Duration, c.Name))
+	}
+	return lines
+}
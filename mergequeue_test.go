@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const queuedResponse = `{"data":{"repository":{"pullRequest":{"mergeQueueEntry":{
+	"position":2,"state":"AWAITING_CHECKS","estimatedTimeToMerge":600,
+	"headCommit":{"oid":"feedface1234","statusCheckRollup":{"contexts":{"nodes":[
+		{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS",
+		 "startedAt":"2024-01-01T00:00:00Z","completedAt":"2024-01-01T00:02:00Z","checkSuite":{"workflowRun":{"workflow":{"name":"CI"}}}},
+		{"__typename":"CheckRun","name":"e2e","status":"IN_PROGRESS","startedAt":"2024-01-01T00:00:00Z","checkSuite":{"workflowRun":{"workflow":{"name":"CI"}}}},
+		{"__typename":"StatusContext","context":"ci/jenkins","state":"FAILURE"}
+	]}}},
+	"mergeQueue":{"entries":{"totalCount":5,"nodes":[{"pullRequest":{"number":40}}]}}
+}}}}}`
+
+func TestFetchMergeQueue(t *testing.T) {
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	execCommand = fakeExecByArgs(map[string]string{"mergeQueueEntry": queuedResponse})
+	q := fetchMergeQueue(nil, "o/r", "42")
+	if q.err != nil || !q.queued || q.position != 2 || q.size != 5 || q.head != 40 || q.eta != 600 || q.headSHA != "feedface1234" {
+		t.Fatalf("queue = %+v", q)
+	}
+	var names []string
+	for _, c := range q.checks {
+		names = append(names, c.Name+"="+c.Status.String())
+	}
+	if got := strings.Join(names, " "); got != "e2e (CI)=RUNNING ci/jenkins=FAIL build (CI)=PASS" {
+		t.Errorf("queue checks = %s", got)
+	}
+
+	execCommand = fakeExecByArgs(map[string]string{"mergeQueueEntry": `{"data":{"repository":{"pullRequest":{"mergeQueueEntry":null}}}}`})
+	if q := fetchMergeQueue(nil, "o/r", "42"); q.err != nil || q.queued {
+		t.Errorf("unqueued PR = %+v", q)
+	}
+}
+
+func TestMergeQueueView(t *testing.T) {
+	t.Cleanup(func() { execCommand = exec.Command })
+	execCommand = fakeExecByArgs(map[string]string{"mergeQueueEntry": queuedResponse})
+
+	m := newModel("o/r", "42", 5*time.Second)
+	m.width, m.height = 160, 20
+	m.prData = &PRData{State: "OPEN", HeadSHA: "abc", Checks: []Check{{Name: "build (CI)", Status: Pass}}}
+	m, cmd := m.mergeQueueCmd()
+	if cmd == nil {
+		t.Fatal("no fetch")
+	}
+	if _, again := m.mergeQueueCmd(); again != nil {
+		t.Error("asked again while the first fetch is in flight")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+
+	out := ansi.Strip(m.View())
+	for _, want := range []string{
+		"MERGE QUEUE 2/5",
+		"Merge queue: position 2 of 5, head #40, awaiting checks | queue checks ✗1 ●1 ✓1 | merges in ~10m00s (Q: details)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() missing %q:\n%s", want, out)
+		}
+	}
+	if _, again := m.mergeQueueCmd(); again == nil {
+		t.Error("a queued PR should be asked on every refresh")
+	}
+
+	m, _ = press(t, m, runeKey('Q'))
+	out = ansi.Strip(m.View())
+	for _, want := range []string{"MERGE QUEUE", "Queue branch commit feedfac", "e2e (CI)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("overlay missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMergeQueueRecheck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	m := newModel("o/r", "42", 5*time.Second)
+	m.prData = &PRData{State: "OPEN"}
+	m.queue = &mergeQueue{repo: "o/r", prNumber: "42", at: now.Add(-30 * time.Second)}
+	if _, cmd := m.mergeQueueCmd(); cmd != nil {
+		t.Error("an unqueued PR was asked again within a minute")
+	}
+	m.queue.at = now.Add(-2 * time.Minute)
+	if _, cmd := m.mergeQueueCmd(); cmd == nil {
+		t.Error("an unqueued PR wasn't asked again after a minute")
+	}
+	m.queue.err = errors.New("Field 'mergeQueueEntry' doesn't exist on type 'PullRequest'")
+	if _, cmd := m.mergeQueueCmd(); cmd != nil {
+		t.Error("asked again on a host without merge queues")
+	}
+	m.queue.err = newGhError("GraphQL: Resource not accessible by integration (repository.pullRequest)")
+	if _, cmd := m.mergeQueueCmd(); cmd != nil {
+		t.Error("asked again without permission")
+	}
+	m.queue.err = newGhError("HTTP 502: Bad Gateway")
+	if _, cmd := m.mergeQueueCmd(); cmd == nil {
+		t.Error("a 502 stopped the merge queue lookups")
+	}
+	m.queue.at = now.Add(-30 * time.Second)
+	if _, cmd := m.mergeQueueCmd(); cmd != nil {
+		t.Error("a failed lookup was retried within a minute")
+	}
+	m.queue = nil
+	m.prData.State = "MERGED"
+	if _, cmd := m.mergeQueueCmd(); cmd != nil {
+		t.Error("asked about a merged PR")
+	}
+}
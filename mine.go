@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Filter narrows the check table. Mine is a regular expression matching
+// the checks you own, e.g. "^(payments|billing)-" in a monorepo whose
+// pipeline prefixes checks with the team or area. M toggles between all
+// checks and those plus the ones branch protection requires.
+// HideProviders lists providers whose checks are left out of the table,
+// e.g. ["Codecov", "license"]; H shows them again.
+type Filter struct {
+	Mine          string   `toml:"mine"`
+	HideProviders []string `toml:"hide_providers"`
+}
+
+// parseMine compiles [filter] mine; nil when it isn't set.
+func parseMine(f Filter) (*regexp.Regexp, error) {
+	if f.Mine == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(f.Mine)
+	if err != nil {
+		return nil, fmt.Errorf("filter.mine: %w", err)
+	}
+	return re, nil
+}
+
+// requiredChecks are the head commit's checks that branch protection
+// requires, keyed by requiredKey.
+type requiredChecks struct {
+	sha   string
+	names map[string]bool
+	err   error
+}
+
+type requiredMsg struct {
+	key    string // prKey
+	checks *requiredChecks
+}
+
+// requiredKey identifies a check the way GitHub reports it: the check run
+// name or status context, and the Actions workflow if any.
+func requiredKey(jobName, workflow string) string {
+	return jobName + "\x00" + workflow
+}
+
+const requiredChecksQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      commits(last: 1) { nodes { commit {
+        oid
+        statusCheckRollup { contexts(first: 100) { nodes {
+          __typename
+          ... on CheckRun { name isRequired(pullRequestNumber: $number) checkSuite { workflowRun { workflow { name } } } }
+          ... on StatusContext { context isRequired(pullRequestNumber: $number) }
+        } } }
+      } } }
+    }
+  }
+}`
+
+// fetchRequiredChecks asks GitHub which of the PR's checks are required.
+// gh pr view doesn't report it, so this is a separate query, made once per
+// head commit and only while the filter is on.
+func fetchRequiredChecks(acct *Account, repo, prNumber string) *requiredChecks {
+	r := &requiredChecks{}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+requiredChecksQuery,
+		"-F", "owner="+owner, "-F", "name="+name, "-F", "number="+prNumber)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					Commits struct {
+						Nodes []struct {
+							Commit struct {
+								OID               string `json:"oid"`
+								StatusCheckRollup *struct {
+									Contexts struct {
+										Nodes []struct {
+											Name       string `json:"name"`
+											Context    string `json:"context"`
+											IsRequired bool   `json:"isRequired"`
+											CheckSuite *struct {
+												WorkflowRun *struct {
+													Workflow struct {
+														Name string `json:"name"`
+													} `json:"workflow"`
+												} `json:"workflowRun"`
+											} `json:"checkSuite"`
+										} `json:"nodes"`
+									} `json:"contexts"`
+								} `json:"statusCheckRollup"`
+							} `json:"commit"`
+						} `json:"nodes"`
+					} `json:"commits"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		r.err = fmt.Errorf("failed to parse required checks: %w", err)
+		return r
+	}
+	r.names = map[string]bool{}
+	for _, c := range resp.Data.Repository.PullRequest.Commits.Nodes {
+		r.sha = c.Commit.OID
+		if c.Commit.StatusCheckRollup == nil {
+			continue
+		}
+		for _, n := range c.Commit.StatusCheckRollup.Contexts.Nodes {
+			if !n.IsRequired {
+				continue
+			}
+			workflow := ""
+			if n.CheckSuite != nil && n.CheckSuite.WorkflowRun != nil {
+				workflow = n.CheckSuite.WorkflowRun.Workflow.Name
+			}
+			r.names[requiredKey(n.Name+n.Context, workflow)] = true
+		}
+	}
+	return r
+}
+
+// requiredCmd fetches the PR's required checks when the filter is on or
+// [grade] only counts them, and they aren't known for the head commit yet.
+func (m model) requiredCmd() tea.Cmd {
+	if !m.mineOnly && !grading.requiredOnly || m.prData == nil || m.required != nil && m.required.sha == m.prData.HeadSHA {
+		return nil
+	}
+	acct := m.repoAccount(m.repo)
+	repo, prNumber := m.repo, m.prNumber
+	return func() tea.Msg {
+		return requiredMsg{key: prKey(repo, prNumber), checks: fetchRequiredChecks(acct, repo, prNumber)}
+	}
+}
+
+// isMine reports whether c matches [filter] mine or is required.
+func (m model) isMine(c Check) bool {
+	if m.mine != nil && m.mine.MatchString(c.Name) {
+		return true
+	}
+	return m.required != nil && m.required.names[requiredKey(c.JobName, c.Workflow)]
+}
+
+// toggleMine switches the check table between all checks and mine.
+func (m model) toggleMine() (model, tea.Cmd) {
+	if m.mine == nil {
+		m.flash = "Set mine under [filter] in the config to a regexp matching your checks"
+		return m, nil
+	}
+	m.mineOnly = !m.mineOnly
+	m.selected = 0
+	m.scrollOff = 0
+	if !m.mineOnly {
+		m.flash = "Showing all checks"
+		return m, nil
+	}
+	m.flash = fmt.Sprintf("Showing my checks (%s) and required ones", m.mine)
+	return m, m.requiredCmd()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMine(t *testing.T) {
+	if re, err := parseMine(Filter{}); re != nil || err != nil {
+		t.Errorf("unset = %v, %v", re, err)
+	}
+	if re, err := parseMine(Filter{Mine: "^(payments|billing)-"}); err != nil || !re.MatchString("billing-unit") {
+		t.Errorf("parseMine = %v, %v", re, err)
+	}
+	if _, err := parseMine(Filter{Mine: "("}); err == nil || !strings.Contains(err.Error(), "filter.mine") {
+		t.Errorf("bad regexp: %v", err)
+	}
+}
+
+func TestToggleMine(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{"graphql": `{"data":{"repository":{"pullRequest":{"commits":{"nodes":[{"commit":{
+		"oid":"abc","statusCheckRollup":{"contexts":{"nodes":[
+			{"__typename":"CheckRun","name":"build","isRequired":true,"checkSuite":{"workflowRun":{"workflow":{"name":"CI"}}}},
+			{"__typename":"CheckRun","name":"payments-e2e","isRequired":false,"checkSuite":{"workflowRun":{"workflow":{"name":"CI"}}}},
+			{"__typename":"StatusContext","context":"legal/cla","isRequired":true}]}}}}]}}}}}`})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.store = &stateStore{}
+	m.width, m.height = 100, 20
+	m.prData = &PRData{HeadSHA: "abc", Checks: []Check{
+		{Name: "build (CI)", JobName: "build", Workflow: "CI", Status: Pass},
+		{Name: "payments-e2e (CI)", JobName: "payments-e2e", Workflow: "CI", Status: Fail},
+		{Name: "search-e2e (CI)", JobName: "search-e2e", Workflow: "CI", Status: Fail},
+		{Name: "legal/cla", JobName: "legal/cla", Status: Pass},
+	}}
+
+	m, _ = press(t, m, runeKey('M'))
+	if m.mineOnly || !strings.Contains(m.flash, "[filter]") {
+		t.Errorf("without [filter] mine: mineOnly %v, flash %q", m.mineOnly, m.flash)
+	}
+
+	m.mine = regexp.MustCompile("^payments-")
+	m.selected = 2
+	m, cmd := press(t, m, runeKey('M'))
+	if !m.mineOnly || m.selected != 0 || cmd == nil {
+		t.Fatalf("mineOnly %v, selected %d, cmd %v", m.mineOnly, m.selected, cmd)
+	}
+	names := func() []string {
+		var names []string
+		for _, c := range m.filteredChecks() {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+	if got := strings.Join(names(), ", "); got != "payments-e2e (CI)" {
+		t.Errorf("before the required checks load: %s", got)
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+	if got := strings.Join(names(), ", "); got != "build (CI), payments-e2e (CI), legal/cla" {
+		t.Errorf("mine and required: %s", got)
+	}
+	if m.requiredCmd() != nil {
+		t.Error("required checks refetched for the same commit")
+	}
+	if !strings.Contains(m.View(), "M: all checks") {
+		t.Errorf("footer:\n%s", m.View())
+	}
+
+	m, _ = press(t, m, runeKey('M'))
+	if m.mineOnly || len(m.filteredChecks()) != 4 {
+		t.Errorf("after M again: mineOnly %v, %d checks", m.mineOnly, len(m.filteredChecks()))
+	}
+}
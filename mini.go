@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// miniBarOrder is the order of the summary bar's segments, most urgent
+// first.
+var miniBarOrder = []CheckStatus{Fail, Cancelled, Running, Pass, Neutral, Skipped}
+
+// viewMini is --mini's three lines: the PR, a bar of check counts, and the
+// check that most needs attention.
+func (m model) viewMini() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	header := fmt.Sprintf("%s #%s", m.repo, m.prNumber)
+	if m.prData != nil && m.prData.Title != "" {
+		header += "  " + m.prData.Title
+	}
+	lines := []string{truncate(header, m.width)}
+
+	switch {
+	case m.err != nil:
+		summary, _ := errorLines(m.err)
+		lines = append(lines, "", styleFail.Render(truncate(summary, m.width)))
+	case m.prData == nil:
+		lines = append(lines, "", styleDim.Render("Fetching PR data..."))
+	default:
+		lines = append(lines, m.attentionBanner()+m.miniBar(m.width-lipgloss.Width(m.attentionBanner())), m.miniFocus())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// miniBar draws each status's share of the checks as a colored bar
+// followed by the counts, e.g. "██████░░ ✗1 ●2 ✓10", width columns wide.
+func (m model) miniBar(width int) string {
+	counts, _ := m.checkCounts()
+	g := m.glyphs.glyph
+	var text []string
+	total := 0
+	for _, s := range miniBarOrder {
+		if counts[s] == 0 || s == Skipped && m.hideSkipped {
+			continue
+		}
+		total += counts[s]
+		text = append(text, statusStyle(s).Render(fmt.Sprintf("%s%d", g(s), counts[s])))
+	}
+	if total == 0 {
+		return styleDim.Render("No checks")
+	}
+	label := strings.Join(text, " ")
+	barW := width - lipgloss.Width(label) - 1
+	if barW < 4 {
+		return label
+	}
+
+	var bar strings.Builder
+	used, cum := 0, 0
+	for _, s := range miniBarOrder {
+		if counts[s] == 0 || s == Skipped && m.hideSkipped {
+			continue
+		}
+		// Segments end where their cumulative share does, so the bar
+		// fills its width; every status present gets at least one cell
+		cum += counts[s]
+		end := min(max(used+1, cum*barW/total), barW)
+		bar.WriteString(statusStyle(s).Render(strings.Repeat("█", end-used)))
+		used = end
+	}
+	return bar.String() + " " + label
+}
+
+// miniFocus is the check that most needs attention: the first failure,
+// else the longest-running check, else a note that everything finished.
+// Status messages take its place while they are shown.
+func (m model) miniFocus() string {
+	if m.flash != "" {
+		return styleRunning.Render(truncate(m.flash, m.width))
+	}
+	g := m.glyphs.glyph
+	if failing := m.failingChecks(); len(failing) > 0 {
+		line := fmt.Sprintf("%s %s", g(Fail), failing[0].Name)
+		if len(failing) > 1 {
+			line += fmt.Sprintf(" (+%d more)", len(failing)-1)
+		}
+		return styleFail.Render(truncate(line, m.width))
+	}
+	var longest *Check
+	for i, c := range m.prData.Checks {
+		if c.Status == Running && (longest == nil || !c.StartedAt.IsZero() &&
+			(longest.StartedAt.IsZero() || c.StartedAt.Before(longest.StartedAt))) {
+			longest = &m.prData.Checks[i]
+		}
+	}
+	if longest != nil {
+		line := fmt.Sprintf("%s %s", g(Running), longest.Name)
+		if !longest.StartedAt.IsZero() {
+			line += " " + formatDuration(int(timeNow().Sub(longest.StartedAt).Seconds()))
+		}
+		return styleRunning.Render(truncate(line, m.width))
+	}
+	return stylePass.Render(truncate(g(Pass)+" All checks finished", m.width))
+}
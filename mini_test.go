@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func miniModel(checks ...Check) model {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.mini = true
+	m.width, m.height = 40, 3
+	m.prData = &PRData{Title: "Add dark mode", Checks: checks}
+	return m
+}
+
+func TestViewMini(t *testing.T) {
+	m := miniModel(
+		Check{Name: "lint", Status: Fail},
+		Check{Name: "e2e", Status: Fail},
+		Check{Name: "build", Status: Running, StartedAt: time.Now().Add(-90 * time.Second)},
+		Check{Name: "unit", Status: Pass},
+		Check{Name: "docs", Status: Skipped},
+	)
+	lines := strings.Split(m.View(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), m.View())
+	}
+	if lines[0] != "o/r #7  Add dark mode" {
+		t.Errorf("header = %q", lines[0])
+	}
+	// 40 columns minus "✗2 ●1 ✓1" and a space; skipped checks are hidden
+	if lines[1] != strings.Repeat("█", 31)+" ✗2 ●1 ✓1" {
+		t.Errorf("bar = %q", lines[1])
+	}
+	if lines[2] != "✗ lint (+1 more)" {
+		t.Errorf("focus = %q", lines[2])
+	}
+
+	m.store = &stateStore{}
+	m.prData.Checks = m.prData.Checks[2:]
+	if got := strings.Split(m.View(), "\n")[2]; got != "● build 1m30s" {
+		t.Errorf("running focus = %q", got)
+	}
+	m.prData.Checks = m.prData.Checks[1:]
+	if got := strings.Split(m.View(), "\n")[2]; got != "✓ All checks finished" {
+		t.Errorf("finished focus = %q", got)
+	}
+	m.flash = "Refreshing..."
+	if got := strings.Split(m.View(), "\n")[2]; got != "Refreshing..." {
+		t.Errorf("flash = %q", got)
+	}
+}
+
+func TestViewMiniStates(t *testing.T) {
+	m := miniModel()
+	m.prData = nil
+	if got := m.View(); got != "o/r #7\n\nFetching PR data..." {
+		t.Errorf("loading = %q", got)
+	}
+	m.err = errors.New("boom")
+	if got := m.View(); !strings.HasSuffix(got, "Error: boom") {
+		t.Errorf("error = %q", got)
+	}
+	m = miniModel()
+	if got := strings.Split(m.View(), "\n")[1]; got != "No checks" {
+		t.Errorf("no checks = %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"maps"
+)
+
+// mutedChecks are the checks muted (m) on one commit of a PR. Unlike
+// acknowledgements and the ignore list they aren't saved: a push brings
+// them back, since it's usually the push that fixes them.
+type mutedChecks struct {
+	repo     string
+	prNumber string
+	sha      string
+	names    map[string]bool
+}
+
+// mutedFor is the checks muted on the given commit of a PR.
+func (m model) mutedFor(repo, prNumber, sha string) map[string]bool {
+	mu := m.muted
+	if mu.repo != repo || mu.prNumber != prNumber || mu.sha != sha || sha == "" {
+		return nil
+	}
+	return mu.names
+}
+
+// isMuted reports whether c is muted until the next push. Muted checks are
+// greyed out and left out of the failure banner, n/N and --on-change, but
+// still counted.
+func (m model) isMuted(c Check) bool {
+	if m.prData == nil {
+		return false
+	}
+	return m.mutedFor(m.repo, m.prNumber, m.prData.HeadSHA)[c.Name]
+}
+
+// mutedCount is how many of the PR's checks are muted.
+func (m model) mutedCount() int {
+	n := 0
+	if m.prData != nil {
+		for _, c := range m.prData.Checks {
+			if m.isMuted(c) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// toggleMute mutes c until the head commit changes (m), or unmutes it.
+func (m model) toggleMute(c Check) model {
+	if m.prData == nil || m.prData.HeadSHA == "" {
+		m.flash = "Can't mute before the head commit is known"
+		return m
+	}
+	names := maps.Clone(m.mutedFor(m.repo, m.prNumber, m.prData.HeadSHA))
+	if names == nil {
+		names = map[string]bool{}
+	}
+	if names[c.Name] {
+		delete(names, c.Name)
+		m.flash = "Unmuted " + c.Name
+	} else {
+		names[c.Name] = true
+		m.flash = "Muted " + c.Name + " until the next push (m again to undo)"
+	}
+	m.muted = mutedChecks{repo: m.repo, prNumber: m.prNumber, sha: m.prData.HeadSHA, names: names}
+	return m
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestMute(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.store = &stateStore{}
+	m.attention = true
+	m.onChange = "true"
+	m.prData = &PRData{HeadSHA: "abc", Checks: []Check{
+		{Name: "flaky", Status: Fail},
+		{Name: "build", Status: Pass},
+	}}
+
+	m = m.toggleMute(m.prData.Checks[0])
+	if !m.isMuted(m.prData.Checks[0]) || m.isMuted(m.prData.Checks[1]) {
+		t.Fatal("muted the wrong check")
+	}
+	if len(m.failingChecks()) != 0 {
+		t.Error("a muted failure still needs attention")
+	}
+	if m, _ = m.checkAttention(); m.alert {
+		t.Error("a muted failure raised the banner")
+	}
+	if got := m.nextAttention(1); got.flash != "No failing or running checks" {
+		t.Errorf("n stopped on a muted check: %q", got.flash)
+	}
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "flaky  (muted)") || !strings.Contains(out, "1 failed, 1 muted") {
+		t.Errorf("view:\n%s", out)
+	}
+
+	// Notifications: the muted failure doesn't turn the rollup red
+	if status, cmd := m.rollupHook("o/r", "1", m.prData, rollupSuccess, &settler{}); status != rollupSuccess || cmd != nil {
+		t.Errorf("rollup with a muted failure = %q", status)
+	}
+
+	// The next push brings it back
+	pushed := &PRData{HeadSHA: "def", Checks: m.prData.Checks}
+	if status, _ := m.rollupHook("o/r", "1", pushed, rollupSuccess, &settler{}); status != rollupFailure {
+		t.Errorf("rollup after a push = %q", status)
+	}
+	m.prData = pushed
+	if m.isMuted(pushed.Checks[0]) || len(m.failingChecks()) != 1 {
+		t.Error("still muted after a push")
+	}
+
+	// m again unmutes
+	m = m.toggleMute(pushed.Checks[0])
+	m = m.toggleMute(pushed.Checks[0])
+	if m.isMuted(pushed.Checks[0]) || m.flash != "Unmuted flaky" {
+		t.Errorf("unmute: %q", m.flash)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// listLen is the number of rows the cursor moves over: the PRs in the
+// picker and dashboard, the filtered checks when viewing a PR.
+func (m model) listLen() int {
+	if m.mode != modeViewing {
+		return len(m.prs)
+	}
+	return len(m.filteredChecks())
+}
+
+// moveSelection moves the cursor by delta rows. It stops at either end,
+// unless wrap is on and the cursor was already there, as in vim's
+// 'wrapscan'; a long jump that would overshoot still stops at the end.
+func (m model) moveSelection(delta int) model {
+	n := m.listLen()
+	if n == 0 {
+		return m
+	}
+	target := m.selected + delta
+	switch {
+	case m.wrap && delta < 0 && m.selected == 0:
+		target = n - 1
+	case m.wrap && delta > 0 && m.selected == n-1:
+		target = 0
+	}
+	m.selected = min(max(target, 0), n-1)
+	return m
+}
+
+// jumpTo selects row i (0-based), clamped to the list.
+func (m model) jumpTo(i int) model {
+	if n := m.listLen(); n > 0 {
+		m.selected = min(max(i, 0), n-1)
+	}
+	return m
+}
+
+// updateNavKey handles the vim-style movement keys shared by the picker,
+// the dashboard and the check list: j/k and the arrows, g/G and home/end,
+// ctrl+d/ctrl+u for half a page, pgup/pgdown for a whole one, n/N for the
+// next failing or running check, and a count in front (5j, 20G). It
+// reports whether msg was one of them. The picker's digits are
+// quick-select keys rather than counts.
+func (m model) updateNavKey(msg tea.KeyMsg) (model, bool) {
+	count := m.count
+	m.count = 0
+	steps := max(count, 1)
+	halfPage := max(m.tableRows()/2, 1)
+	switch msg.Type {
+	case tea.KeyUp:
+		return m.moveSelection(-steps), true
+	case tea.KeyDown:
+		return m.moveSelection(steps), true
+	case tea.KeyCtrlU:
+		return m.moveSelection(-halfPage * steps), true
+	case tea.KeyCtrlD:
+		return m.moveSelection(halfPage * steps), true
+	case tea.KeyPgUp:
+		return m.moveSelection(-m.tableRows() * steps), true
+	case tea.KeyPgDown:
+		return m.moveSelection(m.tableRows() * steps), true
+	case tea.KeyHome:
+		return m.jumpTo(0), true
+	case tea.KeyEnd:
+		return m.jumpTo(m.listLen() - 1), true
+	case tea.KeyRunes:
+		key := string(msg.Runes)
+		switch {
+		case m.mode == modeSelecting && len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+			// The picker's digits open its numbered PRs (quickselect.go)
+			return m, false
+		case len(key) == 1 && key[0] >= '1' && key[0] <= '9', key == "0" && count > 0:
+			m.count = count*10 + int(key[0]-'0')
+			return m, true
+		case key == "k":
+			return m.moveSelection(-steps), true
+		case key == "j":
+			return m.moveSelection(steps), true
+		case key == "g":
+			return m.jumpTo(0), true
+		case (key == "n" || key == "N") && m.mode == modeViewing:
+			dir := 1
+			if key == "N" {
+				dir = -1
+			}
+			for range steps {
+				m = m.nextAttention(dir)
+			}
+			return m, true
+		case key == "G":
+			if count > 0 {
+				return m.jumpTo(count - 1), true
+			}
+			return m.jumpTo(m.listLen() - 1), true
+		}
+	}
+	return m, false
+}
+
+// nextAttention selects the next failing or running check after the cursor
+// (dir 1) or before it (dir -1), wrapping around like vim's n. Green,
+// skipped, acknowledged and muted checks are passed over.
+func (m model) nextAttention(dir int) model {
+	checks := m.filteredChecks()
+	n := len(checks)
+	for step := 1; step <= n; step++ {
+		i := ((m.selected+dir*step)%n + n) % n
+		c := checks[i]
+		if !m.isMuted(c) && (c.Status == Running || c.Status == Fail && !m.isAcked(c)) {
+			m.selected = i
+			return m
+		}
+	}
+	m.flash = "No failing or running checks"
+	return m
+}
+
+// scrollbar draws a vertical scrollbar for rows lines of a total-line list
+// scrolled offset lines down: a dim track with a thumb sized and placed in
+// proportion.
+func scrollbar(rows, total, offset int) []string {
+	bar := make([]string, rows)
+	if rows == 0 || total <= rows {
+		return bar
+	}
+	thumb := max(rows*rows/total, 1)
+	top := min(offset*rows/total, rows-thumb)
+	if offset+rows >= total {
+		top = rows - thumb // the end of the list shows as the end of the bar
+	}
+	for i := range bar {
+		bar[i] = styleDim.Render("│")
+		if i >= top && i < top+thumb {
+			bar[i] = "┃"
+		}
+	}
+	return bar
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func navModel(n int) model {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 80, 20
+	m.store = &stateStore{}
+	m.prData = &PRData{}
+	for i := range n {
+		m.prData.Checks = append(m.prData.Checks, Check{Name: fmt.Sprintf("check %02d", i), Status: Pass})
+	}
+	return m
+}
+
+func TestNavKeys(t *testing.T) {
+	half := navModel(0).tableRows() / 2
+	ctrlD, ctrlU := tea.KeyMsg{Type: tea.KeyCtrlD}, tea.KeyMsg{Type: tea.KeyCtrlU}
+	tests := []struct {
+		name string
+		keys []tea.KeyMsg
+		want int
+	}{
+		{"j", typed("jj"), 2},
+		{"count", typed("12j"), 12},
+		{"count with zero", typed("10jk"), 9},
+		{"G", typed("G"), 149},
+		{"count G", typed("20G"), 19},
+		{"g", typed("Gg"), 0},
+		{"end", []tea.KeyMsg{{Type: tea.KeyEnd}}, 149},
+		{"ctrl+d", []tea.KeyMsg{ctrlD, ctrlD}, 2 * half},
+		{"ctrl+u", []tea.KeyMsg{ctrlD, ctrlU}, 0},
+		{"stops at the top", []tea.KeyMsg{runeKey('k'), {Type: tea.KeyUp}}, 0},
+		{"stops at the bottom", typed("999j"), 149},
+		{"count is reset", typed("5jj"), 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := press(t, navModel(150), tt.keys...)
+			if m.selected != tt.want {
+				t.Errorf("selected = %d, want %d", m.selected, tt.want)
+			}
+			if m.selected < m.scrollOff || m.selected >= m.scrollOff+m.tableRows() {
+				t.Errorf("selected %d outside the viewport at %d", m.selected, m.scrollOff)
+			}
+		})
+	}
+}
+
+func TestNavWrap(t *testing.T) {
+	m := navModel(5)
+	m.wrap = true
+	if m, _ = press(t, m, runeKey('k')); m.selected != 4 {
+		t.Errorf("k at the top = %d, want 4", m.selected)
+	}
+	if m, _ = press(t, m, keyJ); m.selected != 0 {
+		t.Errorf("j at the bottom = %d, want 0", m.selected)
+	}
+	// A long jump stops at the end rather than wrapping
+	if m, _ = press(t, m, typed("2j9j")...); m.selected != 4 {
+		t.Errorf("9j from 2 = %d, want 4", m.selected)
+	}
+}
+
+func TestNavPickerFirstAndGroupKeys(t *testing.T) {
+	m := newSelectModel(5 * time.Second)
+	m.width, m.height = 80, 20
+	m.prs = []PRSummary{{Repo: "o/a", Number: 1}, {Repo: "o/b", Number: 2}, {Repo: "o/c", Number: 3}}
+	m, _ = press(t, m, runeKey('G'))
+	if m.selected != 2 {
+		t.Errorf("G = %d, want 2", m.selected)
+	}
+	m, _ = press(t, m, runeKey('g'))
+	if m.selected != 0 || m.groupByRepo {
+		t.Errorf("g = %d (grouped %v), want 0", m.selected, m.groupByRepo)
+	}
+	m, _ = press(t, m, runeKey('R'))
+	if !m.groupByRepo {
+		t.Error("R should group the picker by repo")
+	}
+}
+
+func TestNavPickerScrolls(t *testing.T) {
+	m := newSelectModel(5 * time.Second)
+	m.width, m.height = 60, 13 // three PRs to a page
+	m.loading = false
+	for i := 1; i <= 20; i++ {
+		m.prs = append(m.prs, PRSummary{Repo: "o/r", Number: i, Title: "PR"})
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyPgDown}, tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.selected != 6 || m.scrollOff != 4 {
+		t.Errorf("pgdown twice: selected %d, scrollOff %d", m.selected, m.scrollOff)
+	}
+	out := m.View()
+	if !strings.Contains(out, "▸ 7 o/r #7") || strings.Contains(out, "o/r #4 ") || !strings.Contains(out, "┃") {
+		t.Errorf("View():\n%s", out)
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.selected != 3 || m.scrollOff != 3 {
+		t.Errorf("pgup: selected %d, scrollOff %d", m.selected, m.scrollOff)
+	}
+}
+
+func TestScrollbar(t *testing.T) {
+	tests := []struct {
+		rows, total, offset int
+		want                string
+	}{
+		{4, 4, 0, "    "},
+		{4, 16, 0, "┃│││"},
+		{4, 16, 6, "│┃││"},
+		{4, 16, 12, "│││┃"},
+		{4, 8, 3, "│┃┃│"},
+	}
+	for _, tt := range tests {
+		var got strings.Builder
+		for _, c := range scrollbar(tt.rows, tt.total, tt.offset) {
+			got.WriteString(cmp.Or(ansi.Strip(c), " "))
+		}
+		if got.String() != tt.want {
+			t.Errorf("scrollbar(%d, %d, %d) = %q, want %q", tt.rows, tt.total, tt.offset, got.String(), tt.want)
+		}
+	}
+}
+
+func TestNextAttention(t *testing.T) {
+	m := navModel(6)
+	m.prData.Checks[1].Status = Fail
+	m.prData.Checks[3].Status = Running
+	m.prData.Checks[4].Status = Fail
+	m.store = &stateStore{path: t.TempDir() + "/state.json"}
+	if _, err := m.store.toggleAck("o/r", "7", "check 04"); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		key  rune
+		want int
+	}{{'n', 1}, {'n', 3}, {'n', 1}, {'N', 3}, {'N', 1}}
+	for _, s := range steps {
+		m, _ = press(t, m, runeKey(s.key))
+		if m.selected != s.want {
+			t.Fatalf("%c: selected = %d, want %d", s.key, m.selected, s.want)
+		}
+	}
+	if m, _ = press(t, m, typed("2n")...); m.selected != 1 {
+		t.Errorf("2n from 1 = %d, want 1", m.selected)
+	}
+
+	m = navModel(3)
+	if m, _ = press(t, m, runeKey('n')); m.selected != 0 || m.flash != "No failing or running checks" {
+		t.Errorf("all green: selected %d, flash %q", m.selected, m.flash)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// notificationReasons are the selector's labels for the reasons GitHub
+// gives for a notification. Reasons not listed are shown as they are.
+var notificationReasons = map[string]string{
+	"review_requested": "review requested",
+	"mention":          "mentioned",
+	"team_mention":     "mentioned",
+	"ci_activity":      "CI activity",
+	"author":           "your PR",
+	"assign":           "assigned",
+	"comment":          "comment",
+	"subscribed":       "subscribed",
+	"manual":           "subscribed",
+	"state_change":     "state changed",
+}
+
+// fetchNotificationPRs lists the open PRs in the user's notification feed
+// (review requests, mentions, CI activity on subscribed PRs...), most
+// recently updated first, with why each one is there in Reason.
+func fetchNotificationPRs(acct *Account) ([]PRSummary, error) {
+	out, err := runGh(acct, "api", "notifications?per_page=50")
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []struct {
+		Reason    string `json:"reason"`
+		UpdatedAt string `json:"updated_at"`
+		Subject   struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+			Type  string `json:"type"`
+		} `json:"subject"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse notifications: %w", err)
+	}
+
+	var prs []PRSummary
+	seen := map[string]bool{}
+	for _, n := range raw {
+		if n.Subject.Type != "PullRequest" {
+			continue
+		}
+		host, number, ok := notificationPR(n.Subject.URL)
+		if !ok {
+			continue
+		}
+		repo := n.Repository.FullName
+		if host != "" {
+			repo = host + "/" + repo
+		}
+		key := prKey(repo, strconv.Itoa(number))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		reason := notificationReasons[n.Reason]
+		if reason == "" {
+			reason = strings.ReplaceAll(n.Reason, "_", " ")
+		}
+		prs = append(prs, PRSummary{
+			Repo:      repo,
+			Number:    number,
+			Title:     n.Subject.Title,
+			URL:       "https://" + cmp.Or(host, "github.com") + "/" + n.Repository.FullName + "/pull/" + strconv.Itoa(number),
+			UpdatedAt: n.UpdatedAt,
+			Reason:    reason,
+		})
+	}
+	if len(prs) == 0 {
+		return prs, nil
+	}
+
+	open, err := fetchOpenPRs(acct, prs)
+	if err != nil {
+		return nil, err
+	}
+	kept := prs[:0]
+	for _, pr := range prs {
+		if o, ok := open[prKey(pr.Repo, strconv.Itoa(pr.Number))]; ok {
+			pr.Draft, pr.CreatedAt = o.Draft, o.CreatedAt
+			kept = append(kept, pr)
+		}
+	}
+	sortPRs(kept, sortUpdated, false)
+	return kept, nil
+}
+
+// notificationPR parses a notification's subject URL, an API URL like
+// https://api.github.com/repos/o/r/pulls/123, into the PR number and, off
+// github.com, the host.
+func notificationPR(subject string) (host string, number int, ok bool) {
+	u, err := url.Parse(subject)
+	if err != nil {
+		return "", 0, false
+	}
+	_, num, found := strings.Cut(u.Path, "/pulls/")
+	if !found {
+		return "", 0, false
+	}
+	number, err = strconv.Atoi(strings.Trim(num, "/"))
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	if u.Host != "api.github.com" && u.Host != "github.com" {
+		host = u.Host
+	}
+	return host, number, true
+}
+
+// openPR is what fetchOpenPRs learns about an open PR that a notification
+// doesn't say.
+type openPR struct {
+	Draft     bool
+	CreatedAt string
+}
+
+// graphqlAliasRe finds the pr<N> alias in the path gh appends to each
+// GraphQL error, as in "Could not resolve to a Repository ... (pr3)".
+var graphqlAliasRe = regexp.MustCompile(`\(pr(\d+)[.)]`)
+
+// fetchOpenPRs looks up which of prs are still open in a single GraphQL
+// request, keyed by prKey. All PRs must live on the same host. GitHub fails
+// the whole request when one repo is gone or behind SAML, so the PRs it
+// names are left out and the rest asked for again.
+func fetchOpenPRs(acct *Account, prs []PRSummary) (map[string]openPR, error) {
+	for {
+		open, err := fetchOpenPRBatch(acct, prs)
+		if err == nil {
+			return open, nil
+		}
+		failed := map[int]bool{}
+		for _, m := range graphqlAliasRe.FindAllStringSubmatch(err.Error(), -1) {
+			if i, _ := strconv.Atoi(m[1]); i < len(prs) {
+				failed[i] = true
+			}
+		}
+		if len(failed) == 0 {
+			return nil, err
+		}
+		var rest []PRSummary
+		for i, pr := range prs {
+			if failed[i] {
+				logger.Debug("skipping inaccessible PR", "repo", pr.Repo, "number", pr.Number, "err", err)
+				continue
+			}
+			rest = append(rest, pr)
+		}
+		if len(rest) == 0 {
+			return map[string]openPR{}, nil
+		}
+		prs = rest
+	}
+}
+
+func fetchOpenPRBatch(acct *Account, prs []PRSummary) (map[string]openPR, error) {
+	var q strings.Builder
+	q.WriteString("query {\n")
+	for i, pr := range prs {
+		_, ownerRepo := splitRepoHost(pr.Repo)
+		owner, name, _ := strings.Cut(ownerRepo, "/")
+		fmt.Fprintf(&q, "  pr%d: repository(owner: %q, name: %q) { pullRequest(number: %d) { state isDraft createdAt } }\n",
+			i, owner, name, pr.Number)
+	}
+	q.WriteString("}")
+	out, err := ghAPI(acct, prs[0].Repo, "graphql", "-f", "query="+q.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data map[string]*struct {
+			PullRequest *struct {
+				State     string `json:"state"`
+				IsDraft   bool   `json:"isDraft"`
+				CreatedAt string `json:"createdAt"`
+			} `json:"pullRequest"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse PR states: %w", err)
+	}
+
+	open := map[string]openPR{}
+	for i, pr := range prs {
+		repo := resp.Data[fmt.Sprintf("pr%d", i)]
+		if repo != nil && repo.PullRequest != nil && repo.PullRequest.State == "OPEN" {
+			open[prKey(pr.Repo, strconv.Itoa(pr.Number))] = openPR{Draft: repo.PullRequest.IsDraft, CreatedAt: repo.PullRequest.CreatedAt}
+		}
+	}
+	return open, nil
+}
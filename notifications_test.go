@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchNotificationPRs(t *testing.T) {
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	execCommand = fakeExecByArgs(map[string]string{
+		"notifications?per_page": `[
+			{"reason":"review_requested","updated_at":"2024-01-01T00:00:00Z","subject":{"title":"Fix login","url":"https://api.github.com/repos/team/svc/pulls/3","type":"PullRequest"},"repository":{"full_name":"team/svc"}},
+			{"reason":"ci_activity","updated_at":"2024-03-01T00:00:00Z","subject":{"title":"Bump deps","url":"https://api.github.com/repos/team/lib/pulls/9","type":"PullRequest"},"repository":{"full_name":"team/lib"}},
+			{"reason":"mention","updated_at":"2024-02-01T00:00:00Z","subject":{"title":"A bug","url":"https://api.github.com/repos/team/svc/issues/4","type":"Issue"},"repository":{"full_name":"team/svc"}},
+			{"reason":"mention","updated_at":"2024-02-01T00:00:00Z","subject":{"title":"Merged","url":"https://api.github.com/repos/team/svc/pulls/5","type":"PullRequest"},"repository":{"full_name":"team/svc"}},
+			{"reason":"security_alert","updated_at":"2024-02-02T00:00:00Z","subject":{"title":"On GHE","url":"https://ghe.corp.com/api/v3/repos/t/s/pulls/7","type":"PullRequest"},"repository":{"full_name":"t/s"}}
+		]`,
+		"graphql": `{"data":{
+			"pr0":{"pullRequest":{"state":"OPEN","createdAt":"2023-12-01T00:00:00Z"}},
+			"pr1":{"pullRequest":{"state":"OPEN","isDraft":true}},
+			"pr2":{"pullRequest":{"state":"MERGED"}},
+			"pr3":{"pullRequest":{"state":"OPEN"}}
+		}}`,
+	})
+	prs, err := fetchNotificationPRs(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, pr := range prs {
+		got = append(got, fmt.Sprintf("%s#%d %s", pr.Repo, pr.Number, pr.Reason))
+	}
+	want := []string{
+		"team/lib#9 CI activity",
+		"ghe.corp.com/t/s#7 security alert",
+		"team/svc#3 review requested",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !prs[0].Draft || prs[2].CreatedAt != "2023-12-01T00:00:00Z" {
+		t.Errorf("PR details not filled in: %+v", prs)
+	}
+	if prs[1].URL != "https://ghe.corp.com/t/s/pull/7" {
+		t.Errorf("GHE URL = %q", prs[1].URL)
+	}
+}
+
+func TestFetchOpenPRsSkipsInaccessible(t *testing.T) {
+	var queries []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		query := strings.Join(args, " ")
+		queries = append(queries, query)
+		if strings.Contains(query, `"gone"`) {
+			return nil, newGhError("GraphQL: Could not resolve to a Repository with the name 'o/gone'. (pr1)")
+		}
+		return []byte(`{"data":{"pr0":{"pullRequest":{"state":"OPEN"}},"pr1":{"pullRequest":{"state":"OPEN"}}}}`), nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	open, err := fetchOpenPRs(nil, []PRSummary{{Repo: "o/a", Number: 1}, {Repo: "o/gone", Number: 2}, {Repo: "o/b", Number: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 2 || len(queries) != 2 {
+		t.Errorf("open = %v after %d queries; want o/a#1 and o/b#3 after 2", open, len(queries))
+	}
+	if _, ok := open[prKey("o/b", "3")]; !ok {
+		t.Errorf("o/b#3 missing from %v", open)
+	}
+
+	// An error that names no PR still fails
+	ghOverride = ghFunc(func([]string) ([]byte, error) { return nil, newGhError("GraphQL: API rate limit exceeded") })
+	if _, err := fetchOpenPRs(nil, []PRSummary{{Repo: "o/a", Number: 1}}); err == nil {
+		t.Error("expected the rate limit error")
+	}
+}
+
+func TestNotificationPR(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantHost string
+		wantNum  int
+		wantOK   bool
+	}{
+		{"https://api.github.com/repos/o/r/pulls/12", "", 12, true},
+		{"https://ghe.corp.com/api/v3/repos/o/r/pulls/3", "ghe.corp.com", 3, true},
+		{"https://api.github.com/repos/o/r/issues/12", "", 0, false},
+		{"https://api.github.com/repos/o/r/pulls/x", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, tt := range tests {
+		host, n, ok := notificationPR(tt.url)
+		if host != tt.wantHost || n != tt.wantNum || ok != tt.wantOK {
+			t.Errorf("notificationPR(%q) = %q, %d, %v; want %q, %d, %v", tt.url, host, n, ok, tt.wantHost, tt.wantNum, tt.wantOK)
+		}
+	}
+}
+
+func TestNotificationsSelector(t *testing.T) {
+	m := newSelectModel(5 * time.Second)
+	m.selectNotifications = true
+	if m.usesSearch() {
+		t.Error("the notification feed shouldn't need gh search")
+	}
+	m.width, m.height = 80, 20
+	m.loading = false
+	m.prs = []PRSummary{{Repo: "o/r", Number: 1, Title: "Fix", Reason: "review requested"}}
+	out := m.viewSelecting()
+	for _, want := range []string{"Open pull requests in your notifications", "o/r #1  review requested"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view missing %q:\n%s", want, out)
+		}
+	}
+}
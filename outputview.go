@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// outputRenderer turns one kind of check run output into the details
+// pane's lines. match recognizes the checks it's for when no [output] rule
+// names a renderer.
+type outputRenderer struct {
+	name   string
+	match  func(c Check, o *checkOutput) bool
+	render func(o *checkOutput, width int) []string
+}
+
+// outputRenderers are tried in order; markdown takes whatever the others
+// don't. Atlantis comes first because its output contains Terraform plans.
+var outputRenderers = []outputRenderer{
+	{"atlantis", isAtlantisOutput, renderAtlantis},
+	{"terraform", isTerraformOutput, renderTerraform},
+	{"markdown", func(Check, *checkOutput) bool { return true }, func(o *checkOutput, width int) []string {
+		return renderMarkdown(o.body(), width)
+	}},
+}
+
+func findOutputRenderer(name string) (outputRenderer, bool) {
+	for _, r := range outputRenderers {
+		if r.name == name {
+			return r, true
+		}
+	}
+	return outputRenderer{}, false
+}
+
+// outputRule has the checks whose name matches glob rendered by renderer,
+// from [output] renderers in the config.
+type outputRule struct {
+	glob     string
+	renderer outputRenderer
+}
+
+// parseOutputRules validates [output] renderers, which maps a renderer's
+// name to globs of the check names it's for. Rules are tried in renderer
+// name order.
+func parseOutputRules(o Output) ([]outputRule, error) {
+	names := make([]string, 0, len(o.Renderers))
+	for name := range o.Renderers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var rules []outputRule
+	for _, name := range names {
+		r, ok := findOutputRenderer(name)
+		if !ok {
+			return nil, fmt.Errorf("output.renderers: unknown renderer %q (want atlantis, terraform or markdown)", name)
+		}
+		for _, glob := range o.Renderers[name] {
+			if _, err := path.Match(glob, ""); err != nil || glob == "" {
+				return nil, fmt.Errorf("output.renderers.%s: bad pattern %q", name, glob)
+			}
+			rules = append(rules, outputRule{glob: strings.ToLower(glob), renderer: r})
+		}
+	}
+	return rules, nil
+}
+
+// outputRenderer picks how c's output o is rendered: the first [output]
+// rule matching its name, with or without the workflow, or else the first
+// renderer that recognizes it.
+func (m model) outputRenderer(c Check, o *checkOutput) outputRenderer {
+	for _, rule := range m.outputRules {
+		for _, name := range []string{c.Name, c.JobName} {
+			if ok, _ := path.Match(rule.glob, strings.ToLower(name)); ok {
+				return rule.renderer
+			}
+		}
+	}
+	for _, r := range outputRenderers {
+		if r.match(c, o) {
+			return r
+		}
+	}
+	return outputRenderers[len(outputRenderers)-1]
+}
+
+var (
+	tfPlanLine    = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy`)
+	tfNoChanges   = regexp.MustCompile(`No changes\.|Your infrastructure matches the configuration`)
+	tfActionsLine = "Terraform will perform the following actions"
+	// atlantisProject starts a project's section of a multi-project run:
+	// "### 1. dir: `infra` workspace: `default`"
+	atlantisProject = regexp.MustCompile("^#+\\s*\\d+\\.\\s*((?:dir|project):.*)$")
+	// atlantisRan is the first line of a single-project run
+	atlantisRan   = regexp.MustCompile("^Ran (?:Plan|Apply|Policy Check) for ((?:dir|project):.*)$")
+	atlantisField = regexp.MustCompile("(\\w+): `([^`]*)`")
+)
+
+func isTerraformOutput(c Check, o *checkOutput) bool {
+	if strings.Contains(o.App, "terraform") || strings.Contains(strings.ToLower(c.Name), "terraform") {
+		return true
+	}
+	body := o.body()
+	return strings.Contains(body, tfActionsLine) || tfPlanLine.MatchString(body)
+}
+
+func isAtlantisOutput(c Check, o *checkOutput) bool {
+	if strings.Contains(o.App, "atlantis") || strings.HasPrefix(strings.ToLower(c.JobName), "atlantis") {
+		return true
+	}
+	body := o.body()
+	return strings.Contains(body, "Ran Plan for") || strings.Contains(body, "Ran Apply for")
+}
+
+// renderTerraform leads with the plan's totals, then shows the plan with
+// creates in green, destroys in red and in-place updates in yellow.
+func renderTerraform(o *checkOutput, width int) []string {
+	lines := strings.Split(o.body(), "\n")
+	out := []string{planTotals(lines)}
+	return wrapLines(append(out, tfLines(lines)...), width)
+}
+
+// renderAtlantis summarizes an Atlantis run with a line per project, then
+// shows each project's plan as renderTerraform does. The how-to-apply
+// instructions Atlantis appends are left out.
+func renderAtlantis(o *checkOutput, width int) []string {
+	type project struct {
+		label string
+		lines []string
+	}
+	var projects []project
+	for _, l := range strings.Split(o.body(), "\n") {
+		if strings.TrimSpace(l) == "---" {
+			break
+		}
+		if m := atlantisProject.FindStringSubmatch(l); m != nil {
+			projects = append(projects, project{label: atlantisLabel(m[1])})
+			continue
+		}
+		if m := atlantisRan.FindStringSubmatch(l); m != nil && len(projects) == 0 {
+			projects = append(projects, project{label: atlantisLabel(m[1])})
+			continue
+		}
+		if len(projects) > 0 {
+			projects[len(projects)-1].lines = append(projects[len(projects)-1].lines, l)
+		}
+	}
+	if len(projects) == 0 {
+		return renderTerraform(o, width)
+	}
+	labelW := 0
+	for _, p := range projects {
+		labelW = max(labelW, ansi.StringWidth(p.label))
+	}
+	var out []string
+	for _, p := range projects {
+		out = append(out, p.label+strings.Repeat(" ", labelW-ansi.StringWidth(p.label))+"  "+planTotals(p.lines))
+	}
+	for _, p := range projects {
+		out = append(out, "", styleBold.Render(p.label))
+		lines := tfLines(p.lines)
+		for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+			lines = lines[1:]
+		}
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		out = append(out, lines...)
+	}
+	return wrapLines(out, width)
+}
+
+// atlantisLabel is "dir: `infra` workspace: `default`" as "infra (default)".
+func atlantisLabel(s string) string {
+	fields := map[string]string{}
+	for _, m := range atlantisField.FindAllStringSubmatch(s, -1) {
+		fields[m[1]] = m[2]
+	}
+	label := fields["project"]
+	if label == "" {
+		label = fields["dir"]
+	}
+	if label == "" {
+		return strings.TrimSpace(strings.ReplaceAll(s, "`", ""))
+	}
+	if ws := fields["workspace"]; ws != "" && ws != "default" {
+		label += " (" + ws + ")"
+	}
+	return label
+}
+
+// planTotals adds up the "Plan: N to add, ..." lines as "+1 to add  ~2 to
+// change  -3 to destroy", or says there are no changes or the plan failed.
+func planTotals(lines []string) string {
+	var add, change, destroy int
+	var plans, errs int
+	noChanges := false
+	for _, l := range lines {
+		if m := tfPlanLine.FindStringSubmatch(l); m != nil {
+			a, _ := strconv.Atoi(m[1])
+			c, _ := strconv.Atoi(m[2])
+			d, _ := strconv.Atoi(m[3])
+			add, change, destroy = add+a, change+c, destroy+d
+			plans++
+		}
+		if tfNoChanges.MatchString(l) {
+			noChanges = true
+		}
+		if strings.HasPrefix(strings.TrimLeft(l, " │╷"), "Error:") {
+			errs++
+		}
+	}
+	switch {
+	case errs > 0:
+		if errs == 1 {
+			return styleFail.Render("1 error")
+		}
+		return styleFail.Render(fmt.Sprintf("%d errors", errs))
+	case plans > 0:
+		return stylePass.Render(fmt.Sprintf("+%d to add", add)) + "  " +
+			styleRunning.Render(fmt.Sprintf("~%d to change", change)) + "  " +
+			styleFail.Render(fmt.Sprintf("-%d to destroy", destroy))
+	case noChanges:
+		return stylePass.Render("no changes")
+	}
+	return styleDim.Render("no plan found")
+}
+
+// tfLines colors a plan by the action on each line, dropping the Markdown
+// code fences and HTML <details> tags bots wrap it in. Outside code blocks,
+// Markdown headings are shown in bold.
+func tfLines(lines []string) []string {
+	var out []string
+	fenced := false
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		switch {
+		case strings.HasPrefix(t, "```"):
+			fenced = !fenced
+			continue
+		case !fenced && (strings.HasPrefix(t, "<details") || strings.HasPrefix(t, "</details") || strings.HasPrefix(t, "<summary")):
+			continue
+		case !fenced && strings.HasPrefix(l, "#"):
+			out = append(out, styleBold.Render(strings.TrimSpace(strings.TrimLeft(l, "#"))))
+			continue
+		}
+		out = append(out, tfLine(l))
+	}
+	return out
+}
+
+// tfLine colors one line of a plan. Atlantis's diff blocks mark in-place
+// updates with ! instead of Terraform's ~.
+func tfLine(l string) string {
+	t := strings.TrimLeft(l, " ")
+	switch {
+	case strings.HasPrefix(t, "-/+"), strings.HasPrefix(t, "+/-"):
+		return styleFail.Render(l)
+	case strings.HasPrefix(t, "+"):
+		return stylePass.Render(l)
+	case strings.HasPrefix(t, "-"):
+		return styleFail.Render(l)
+	case strings.HasPrefix(t, "~"), strings.HasPrefix(t, "!"):
+		return styleRunning.Render(l)
+	case strings.HasPrefix(t, "<="):
+		return styleDim.Render(l)
+	case strings.HasPrefix(t, "# "):
+		return styleBold.Render(l)
+	case tfPlanLine.MatchString(l), strings.HasPrefix(strings.TrimLeft(l, " │╷"), "Error:"):
+		return styleBold.Render(l)
+	}
+	return l
+}
+
+// wrapLines breaks lines wider than width, so a long resource address
+// isn't cut off at the pane's edge.
+func wrapLines(lines []string, width int) []string {
+	var out []string
+	for _, l := range lines {
+		out = append(out, strings.Split(ansi.Hardwrap(l, max(width, 20), true), "\n")...)
+	}
+	return out
+}
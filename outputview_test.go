@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const tfPlan = "Terraform will perform the following actions:\n\n" +
+	"  # aws_s3_bucket.logs will be created\n" +
+	"  + resource \"aws_s3_bucket\" \"logs\" {\n" +
+	"  ~ tags = {}\n" +
+	"  - acl  = \"private\"\n" +
+	"Plan: 1 to add, 1 to change, 0 to destroy."
+
+const atlantisPlan = "Ran Plan for 2 projects:\n\n" +
+	"1. dir: `infra/a` workspace: `default`\n" +
+	"1. dir: `infra/b` workspace: `prod`\n\n" +
+	"### 1. dir: `infra/a` workspace: `default`\n" +
+	"<details><summary>Show Output</summary>\n\n" +
+	"```diff\n" +
+	"+ resource \"aws_iam_role\" \"ci\" {\n" +
+	"Plan: 1 to add, 0 to change, 0 to destroy.\n" +
+	"```\n" +
+	"</details>\n\n" +
+	"### 2. dir: `infra/b` workspace: `prod`\n" +
+	"```\n" +
+	"No changes. Your infrastructure matches the configuration.\n" +
+	"```\n\n" +
+	"---\n" +
+	"* :arrow_forward: To **apply** all unapplied plans from this pull request, comment:\n" +
+	"    * `atlantis apply`\n"
+
+func TestOutputRendererChoice(t *testing.T) {
+	m := newModel("o/r", "1", 0)
+	tests := []struct {
+		name string
+		c    Check
+		o    checkOutput
+		want string
+	}{
+		{"terraform app", Check{Name: "plan"}, checkOutput{App: "terraform-cloud", Summary: "x"}, "terraform"},
+		{"terraform plan text", Check{Name: "infra"}, checkOutput{Text: tfPlan}, "terraform"},
+		{"atlantis name", Check{Name: "atlantis/plan", JobName: "atlantis/plan"}, checkOutput{Summary: "x"}, "atlantis"},
+		{"atlantis output", Check{Name: "plan"}, checkOutput{Text: atlantisPlan}, "atlantis"},
+		{"anything else", Check{Name: "coverage"}, checkOutput{Summary: "84%"}, "markdown"},
+	}
+	for _, tt := range tests {
+		if got := m.outputRenderer(tt.c, &tt.o).name; got != tt.want {
+			t.Errorf("%s: renderer %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// A config rule wins over recognizing the output
+	m.outputRules, _ = parseOutputRules(Output{Renderers: map[string][]string{"markdown": {"Infra *"}}})
+	if got := m.outputRenderer(Check{Name: "infra plan (CI)", JobName: "infra plan"}, &checkOutput{Text: tfPlan}).name; got != "markdown" {
+		t.Errorf("with a markdown rule: renderer %q", got)
+	}
+}
+
+func TestParseOutputRules(t *testing.T) {
+	for _, renderers := range []map[string][]string{
+		{"html": {"x"}},
+		{"terraform": {"[plan"}},
+		{"terraform": {""}},
+	} {
+		if _, err := parseOutputRules(Output{Renderers: renderers}); err == nil {
+			t.Errorf("parseOutputRules(%v) should fail", renderers)
+		}
+	}
+	rules, err := parseOutputRules(Output{Renderers: map[string][]string{"terraform": {"tf-*"}, "atlantis": {"atlantis/*"}}})
+	if err != nil || len(rules) != 2 || rules[0].renderer.name != "atlantis" {
+		t.Errorf("rules = %+v, %v; want atlantis then terraform", rules, err)
+	}
+}
+
+func TestRenderTerraform(t *testing.T) {
+	lines := renderTerraform(&checkOutput{Text: "```\n" + tfPlan + "\n```"}, 60)
+	if got := ansi.Strip(lines[0]); got != "+1 to add  ~1 to change  -0 to destroy" {
+		t.Errorf("totals = %q", got)
+	}
+	text := ansi.Strip(strings.Join(lines, "\n"))
+	if strings.Contains(text, "```") || !strings.Contains(text, `+ resource "aws_s3_bucket" "logs" {`) {
+		t.Errorf("plan:\n%s", text)
+	}
+
+	if got := planTotals([]string{"╷", "│ Error: Invalid reference", "╵"}); ansi.Strip(got) != "1 error" {
+		t.Errorf("failed plan totals = %q", got)
+	}
+}
+
+func TestRenderAtlantis(t *testing.T) {
+	lines := renderAtlantis(&checkOutput{Text: atlantisPlan}, 60)
+	text := ansi.Strip(strings.Join(lines, "\n"))
+	for _, want := range []string{
+		"infra/a         +1 to add  ~0 to change  -0 to destroy",
+		"infra/b (prod)  no changes",
+		`+ resource "aws_iam_role" "ci" {`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"atlantis apply", "<details>", "```"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("should leave out %q:\n%s", unwanted, text)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// overlayKind selects an alternate panel that replaces the check table in
+// viewing mode. The header and summary stay visible above it.
+type overlayKind int
+
+const (
+	overlayNone overlayKind = iota
+	overlayDeps
+	overlayThreads
+	overlayEvents
+	overlaySecurity
+	overlayTimeline
+	overlayAttempts
+	overlaySettings
+	overlayQueue
+	overlayJSON
+)
+
+type depGraphsMsg struct {
+	graphs []depGraph
+	err    error
+}
+
+func (m model) fetchDepsCmd() tea.Cmd {
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	var checks []Check
+	if m.prData != nil {
+		checks = m.prData.Checks
+	}
+	return func() tea.Msg {
+		graphs, err := fetchDepGraphs(acct, repo, checks)
+		return depGraphsMsg{graphs: graphs, err: err}
+	}
+}
+
+// toggleOverlay opens kind (closing any other overlay) or closes it if it is
+// already open, returning the command that loads the overlay's data.
+func (m model) toggleOverlay(kind overlayKind) (model, tea.Cmd) {
+	if m.overlay == kind {
+		m.overlay = overlayNone
+		return m, nil
+	}
+	m.overlay = kind
+	m.overlayOff = 0
+	return m, m.overlayCmd()
+}
+
+// overlayCmd returns the fetch that (re)loads the open overlay's data.
+func (m model) overlayCmd() tea.Cmd {
+	switch m.overlay {
+	case overlayDeps:
+		if m.prData == nil {
+			return nil
+		}
+		return m.fetchDepsCmd()
+	case overlayTimeline:
+		// The critical path follows needs: once the workflows are loaded;
+		// they only change with a push, so refreshes don't refetch them
+		if m.prData == nil || m.depGraphs != nil || m.depsErr != nil {
+			return nil
+		}
+		return m.fetchDepsCmd()
+	case overlayThreads:
+		return m.fetchThreadsCmd()
+	case overlaySecurity:
+		// Alerts only change with new commits, so refreshes don't refetch
+		if m.prData == nil || m.security != nil && m.security.sha == m.prData.HeadSHA {
+			return nil
+		}
+		return m.fetchSecurityCmd()
+	}
+	return nil
+}
+
+func (m model) fetchSecurityCmd() tea.Cmd {
+	acct := m.repoAccount(m.repo)
+	repo, prNumber := m.repo, m.prNumber
+	base, head := m.prData.BaseRefName, m.prData.HeadSHA
+	return func() tea.Msg {
+		return securityMsg{report: fetchSecurityReport(acct, repo, prNumber, base, head)}
+	}
+}
+
+// updateOverlayKey handles scrolling and closing while an overlay is open.
+// It reports false for keys the regular key handling should process.
+func (m model) updateOverlayKey(msg tea.KeyMsg) (model, bool) {
+	if m.overlay == overlaySettings {
+		return m.updateSettingsKey(msg)
+	}
+	last := len(m.overlayLines()) - 1
+	switch msg.String() {
+	case "esc":
+		m.overlay = overlayNone
+	case "up", "k":
+		if m.overlayOff > 0 {
+			m.overlayOff--
+		}
+	case "down", "j":
+		if m.overlayOff < last {
+			m.overlayOff++
+		}
+	case "pgup":
+		m.overlayOff = max(0, m.overlayOff-m.bodyRows())
+	case "pgdown":
+		m.overlayOff = max(0, min(last, m.overlayOff+m.bodyRows()))
+	case "home":
+		m.overlayOff = 0
+	case "end":
+		m.overlayOff = max(0, last)
+	case "tab":
+		if m.overlay != overlayJSON {
+			return m, false
+		}
+		m.jsonWhole = !m.jsonWhole
+		m.overlayOff = 0
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+func (m model) overlayTitle() string {
+	switch m.overlay {
+	case overlayDeps:
+		return "JOB DEPENDENCIES"
+	case overlayThreads:
+		return "UNRESOLVED REVIEW THREADS"
+	case overlayEvents:
+		return "EVENTS (newest first)"
+	case overlaySecurity:
+		return "SECURITY ALERTS INTRODUCED BY THIS PR"
+	case overlayTimeline:
+		return "TIMELINE (* critical path)"
+	case overlayAttempts:
+		return "RERUN CHECKS BY ATTEMPT"
+	case overlaySettings:
+		return "SETTINGS"
+	case overlayQueue:
+		return "MERGE QUEUE"
+	case overlayJSON:
+		return m.jsonTitle()
+	}
+	return ""
+}
+
+func (m model) overlayLines() []string {
+	switch m.overlay {
+	case overlayDeps:
+		switch {
+		case m.depsErr != nil:
+			return []string{styleFail.Render(fmt.Sprintf("Error: %s", m.depsErr))}
+		case m.depGraphs == nil:
+			return []string{"Loading workflow definitions..."}
+		case len(m.depGraphs) == 0:
+			return []string{"No GitHub Actions workflow runs found for this PR."}
+		}
+		var checks []Check
+		if m.prData != nil {
+			checks = m.prData.Checks
+		}
+		return renderDepGraphs(m.depGraphs, checks)
+	case overlayThreads:
+		switch {
+		case m.threadsErr != nil:
+			return []string{styleFail.Render(fmt.Sprintf("Error: %s", m.threadsErr))}
+		case m.threads == nil:
+			return []string{"Loading review threads..."}
+		case len(m.threads) == 0:
+			return []string{"No unresolved review threads."}
+		}
+		return renderReviewThreads(m.threads)
+	case overlayEvents:
+		if len(m.events) == 0 {
+			return []string{"No events yet."}
+		}
+		return renderEvents(m.events)
+	case overlaySecurity:
+		if m.security == nil {
+			return []string{"Loading code scanning and dependency alerts..."}
+		}
+		return renderSecurityReport(m.security)
+	case overlayTimeline:
+		if m.prData == nil {
+			return nil
+		}
+		return renderTimeline(m.prData.Checks, m.depGraphs, timeNow(), m.width)
+	case overlayAttempts:
+		return m.renderAttempts()
+	case overlaySettings:
+		return m.settingsLines()
+	case overlayQueue:
+		return m.renderMergeQueue()
+	case overlayJSON:
+		return m.jsonLines()
+	}
+	return nil
+}
+
+// viewOverlay renders the open overlay in place of the check table, padded
+// to the bottom of the screen with its own footer.
+func (m model) viewOverlay(maxRows int) string {
+	var b strings.Builder
+	b.WriteString(styleUnder.Render(truncate(m.overlayTitle(), m.width)))
+	b.WriteString("\n")
+
+	lines := m.overlayLines()
+	if m.overlayOff < len(lines) {
+		lines = lines[m.overlayOff:]
+	} else {
+		lines = nil
+	}
+	if len(lines) > maxRows {
+		lines = lines[:maxRows]
+	}
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteString("\n")
+	}
+
+	for i := m.headerLines() + 1 + len(lines); i < m.height-1; i++ {
+		b.WriteString("\n")
+	}
+	footer := "up/down: scroll | r: refresh | esc: close | q: quit"
+	switch m.overlay {
+	case overlaySettings:
+		footer = "up/down: select | left/right, enter: change | esc: close | q: quit"
+	case overlayJSON:
+		footer = "tab: check/PR | up/down, pgup/pgdown: scroll | J: selected check | esc: close | q: quit"
+	}
+	b.WriteString(styleDim.Render(truncate(footer, m.width)))
+	return b.String()
+}
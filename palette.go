@@ -0,0 +1,280 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteCommand is an entry in the `:` command palette. Commands that
+// don't apply to the current selection stay listed with the reason.
+type paletteCommand struct {
+	label    string
+	disabled string // reason the command is unavailable, empty if enabled
+	mutating bool   // changes the PR, its runs or the checkout; off with --read-only
+	run      func(m model) (model, tea.Cmd)
+}
+
+// actionMsg reports the outcome of a mutating gh action.
+type actionMsg struct {
+	text string
+	err  error
+}
+
+func (m model) selectedCheck() (Check, bool) {
+	checks := m.filteredChecks()
+	if m.selected < 0 || m.selected >= len(checks) {
+		return Check{}, false
+	}
+	return checks[m.selected], true
+}
+
+// paletteCommands lists the commands available in viewing mode.
+func (m model) paletteCommands() []paletteCommand {
+	check, ok := m.selectedCheck()
+	runID, jobID := actionsRunID(check.DetailsURL)
+	notActions := ""
+	switch {
+	case !ok:
+		notActions = "no check selected"
+	case runID == "":
+		notActions = "not a GitHub Actions check"
+	}
+	noJob := notActions
+	if noJob == "" && jobID == "" {
+		noJob = "check has no job ID"
+	}
+
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	rerun := func(label string, jobID string, failedOnly bool) func(model) (model, tea.Cmd) {
+		return func(m model) (model, tea.Cmd) {
+			return m, func() tea.Msg {
+				err := rerunWorkflow(acct, repo, runID, jobID, failedOnly)
+				return actionMsg{text: label, err: err}
+			}
+		}
+	}
+
+	prNumber := m.prNumber
+	noPR := ""
+	if m.prData == nil {
+		noPR = "PR not loaded"
+	}
+	noCheck := ""
+	if !ok {
+		noCheck = "no check selected"
+	}
+	update := func(label string, rebase bool) func(model) (model, tea.Cmd) {
+		return func(m model) (model, tea.Cmd) {
+			return m, func() tea.Msg {
+				err := updateBranch(acct, repo, prNumber, rebase)
+				return actionMsg{text: label, err: err}
+			}
+		}
+	}
+
+	return m.withReadOnly([]paletteCommand{
+		{
+			label:    "Update branch from base (merge)",
+			disabled: noPR,
+			mutating: true,
+			run:      update("Updated branch from base", false),
+		},
+		{
+			label:    "Update branch from base (rebase)",
+			disabled: noPR,
+			mutating: true,
+			run:      update("Rebased branch onto base", true),
+		},
+		m.localRebaseCommand(),
+		m.showIgnoredCommand(),
+		m.openEnvironmentCommand(),
+		{
+			label:    "Copy Markdown report to clipboard",
+			disabled: noPR,
+			run:      func(m model) (model, tea.Cmd) { return m, m.copyReportCmd() },
+		},
+		{
+			label:    "Write Markdown report to file",
+			disabled: noPR,
+			run:      func(m model) (model, tea.Cmd) { return m, m.writeReportCmd() },
+		},
+		{
+			label:    "Inspect raw JSON of the selected check",
+			disabled: noCheck,
+			run:      func(m model) (model, tea.Cmd) { return m.openJSON(false) },
+		},
+		{
+			label:    "Inspect raw JSON of the PR",
+			disabled: noPR,
+			run:      func(m model) (model, tea.Cmd) { return m.openJSON(true) },
+		},
+		{
+			label:    "Rerun selected job",
+			disabled: noJob,
+			mutating: true,
+			run:      rerun(fmt.Sprintf("Requested rerun of job %s", check.JobName), jobID, false),
+		},
+		{
+			label:    "Rerun failed jobs in this run",
+			disabled: notActions,
+			mutating: true,
+			run:      rerun(fmt.Sprintf("Requested rerun of failed jobs in run %s", runID), "", true),
+		},
+		{
+			label:    "Rerun entire workflow run",
+			disabled: notActions,
+			mutating: true,
+			run:      rerun(fmt.Sprintf("Requested rerun of run %s", runID), "", false),
+		},
+	})
+}
+
+// filteredPalette returns the commands whose label contains every word of
+// the palette query.
+func (m model) filteredPalette() []paletteCommand {
+	words := strings.Fields(strings.ToLower(m.paletteQuery))
+	var result []paletteCommand
+	for _, c := range m.paletteCommands() {
+		label := strings.ToLower(c.label)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(label, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (m model) openPalette() model {
+	m.paletteOpen = true
+	m.paletteQuery = ""
+	m.paletteSel = 0
+	m.gitRemote, m.gitBranch = "", ""
+	if m.prData != nil {
+		var hosts []string
+		for _, a := range m.accounts {
+			hosts = append(hosts, a.Host)
+		}
+		if m.gitRemote = localRemote(m.repo, hosts); m.gitRemote != "" {
+			m.gitBranch = currentBranch()
+		}
+	}
+	return m
+}
+
+// updatePaletteKey handles all keys while the palette is open.
+func (m model) updatePaletteKey(msg tea.KeyMsg) (model, tea.Cmd) {
+	cmds := m.filteredPalette()
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEsc:
+		m.paletteOpen = false
+	case tea.KeyUp:
+		if m.paletteSel > 0 {
+			m.paletteSel--
+		}
+	case tea.KeyDown:
+		if m.paletteSel < len(cmds)-1 {
+			m.paletteSel++
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.paletteQuery); len(r) > 0 {
+			m.paletteQuery = string(r[:len(r)-1])
+			m.paletteSel = 0
+		}
+	case tea.KeySpace:
+		m.paletteQuery += " "
+		m.paletteSel = 0
+	case tea.KeyRunes:
+		m.paletteQuery += string(msg.Runes)
+		m.paletteSel = 0
+	case tea.KeyEnter:
+		if m.paletteSel >= len(cmds) {
+			return m, nil
+		}
+		c := cmds[m.paletteSel]
+		if c.disabled != "" {
+			m.flash = c.label + ": " + c.disabled
+			return m, nil
+		}
+		m.paletteOpen = false
+		return c.run(m)
+	}
+	return m, nil
+}
+
+// viewPalette renders the palette in place of the check table.
+func (m model) viewPalette(maxRows int) string {
+	var b strings.Builder
+	b.WriteString(styleUnder.Render(truncate(":"+m.paletteQuery, m.width)))
+	b.WriteString("\n")
+
+	cmds := m.filteredPalette()
+	rows := 0
+	for i, c := range cmds {
+		if rows >= maxRows {
+			break
+		}
+		line := "  " + c.label
+		if c.disabled != "" {
+			line = styleDim.Render(line + " (" + c.disabled + ")")
+		}
+		if i == m.paletteSel {
+			line = styleSelected.Render("▸ ") + strings.TrimPrefix(line, "  ")
+		}
+		b.WriteString(truncate(line, m.width))
+		b.WriteString("\n")
+		rows++
+	}
+	if len(cmds) == 0 {
+		b.WriteString(styleDim.Render("  no matching commands"))
+		b.WriteString("\n")
+		rows++
+	}
+
+	for i := m.headerLines() + 1 + rows; i < m.height-1; i++ {
+		b.WriteString("\n")
+	}
+	b.WriteString(styleDim.Render(truncate("type to filter | up/down: select | enter: run | esc: cancel", m.width)))
+	return b.String()
+}
+
+// localRebaseCommand rebases the checked-out PR branch onto its base with
+// `git pull --rebase`, handing the terminal to git. It is only available
+// when the working directory is a clone of the repo on the PR's branch, as
+// detected when the palette was opened.
+func (m model) localRebaseCommand() paletteCommand {
+	c := paletteCommand{label: "Rebase local checkout onto base (git pull --rebase)", mutating: true}
+	switch {
+	case m.prData == nil:
+		c.disabled = "PR not loaded"
+		return c
+	case m.prData.BaseRefName == "":
+		c.disabled = "base branch unknown"
+		return c
+	case m.gitRemote == "":
+		c.disabled = "not inside a clone of " + m.repo
+		return c
+	case m.gitBranch != m.prData.HeadRefName:
+		c.disabled = "check out " + m.prData.HeadRefName + " first"
+		return c
+	}
+	remote, base := m.gitRemote, m.prData.BaseRefName
+	c.label = fmt.Sprintf("Rebase local checkout onto %s/%s (git pull --rebase)", remote, base)
+	c.run = func(m model) (model, tea.Cmd) {
+		cmd := execCommand("git", "pull", "--rebase", remote, base)
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return actionMsg{text: "Rebased onto " + remote + "/" + base + " (push to start a new run)", err: err}
+		})
+	}
+	return c
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func paletteTestModel() model {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width = 120
+	m.height = 30
+	m.prData = &PRData{Checks: []Check{
+		{Name: "test (CI)", JobName: "test", Status: Fail, DetailsURL: "https://github.com/o/r/actions/runs/11/job/22"},
+		{Name: "ci/jenkins", JobName: "ci/jenkins", Status: Pass, DetailsURL: "https://jenkins.example.com/job/1"},
+	}}
+	return m
+}
+
+func TestPaletteCommands(t *testing.T) {
+	reruns := func(m model) []paletteCommand {
+		m.paletteQuery = "rerun"
+		return m.filteredPalette()
+	}
+
+	t.Run("actions check enables all reruns", func(t *testing.T) {
+		m := paletteTestModel()
+		for _, c := range reruns(m) {
+			if c.disabled != "" {
+				t.Errorf("%q disabled: %s", c.label, c.disabled)
+			}
+		}
+	})
+
+	t.Run("non-actions check disables reruns", func(t *testing.T) {
+		m := paletteTestModel()
+		m.selected = 1
+		for _, c := range reruns(m) {
+			if !strings.Contains(c.disabled, "not a GitHub Actions check") {
+				t.Errorf("%q disabled = %q, want not-Actions reason", c.label, c.disabled)
+			}
+		}
+	})
+
+	t.Run("query filters by words", func(t *testing.T) {
+		m := paletteTestModel()
+		m.paletteQuery = "rerun failed"
+		cmds := m.filteredPalette()
+		if len(cmds) != 1 || cmds[0].label != "Rerun failed jobs in this run" {
+			t.Errorf("got %v, want only the failed-jobs command", cmds)
+		}
+	})
+}
+
+func TestPaletteKeys(t *testing.T) {
+	t.Run("colon opens and esc closes", func(t *testing.T) {
+		m, _ := press(t, paletteTestModel(), runeKey(':'))
+		if !m.paletteOpen {
+			t.Fatal("':' should open the palette")
+		}
+		if !strings.Contains(m.View(), "Rerun entire workflow run") {
+			t.Error("palette should list commands")
+		}
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if updated.(model).paletteOpen {
+			t.Error("esc should close the palette")
+		}
+	})
+
+	t.Run("q is typed into the query", func(t *testing.T) {
+		m, _ := press(t, paletteTestModel(), typed(":q")...)
+		if !m.paletteOpen || m.paletteQuery != "q" {
+			t.Errorf("paletteOpen = %v, query = %q; want open with query q", m.paletteOpen, m.paletteQuery)
+		}
+	})
+
+	t.Run("enter runs selected command", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{"run rerun --job 22 --repo o/r": ""})
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		m, cmd := press(t, paletteTestModel(), append(typed(":job"), tea.KeyMsg{Type: tea.KeyEnter})...)
+		if m.paletteOpen {
+			t.Error("palette should close after running a command")
+		}
+		if cmd == nil {
+			t.Fatal("expected a command")
+		}
+		msg, ok := cmd().(actionMsg)
+		if !ok {
+			t.Fatalf("cmd returned %T, want actionMsg", cmd())
+		}
+		if msg.err != nil {
+			t.Errorf("unexpected error: %v", msg.err)
+		}
+	})
+
+	t.Run("enter on disabled command keeps palette open", func(t *testing.T) {
+		m := paletteTestModel()
+		m.selected = 1
+		m, cmd := press(t, m, append(typed(":rerun"), tea.KeyMsg{Type: tea.KeyEnter})...)
+		if cmd != nil || !m.paletteOpen {
+			t.Error("disabled command should not run")
+		}
+		if !strings.Contains(m.flash, "not a GitHub Actions check") {
+			t.Errorf("flash = %q, should explain why", m.flash)
+		}
+	})
+}
+
+func TestActionMsg(t *testing.T) {
+	m := paletteTestModel()
+	updated, cmd := m.Update(actionMsg{text: "Requested rerun"})
+	m = updated.(model)
+	if m.flash != "Requested rerun" {
+		t.Errorf("flash = %q", m.flash)
+	}
+	if cmd == nil {
+		t.Error("successful action should trigger a refresh")
+	}
+	if !strings.Contains(m.View(), "Requested rerun") {
+		t.Error("flash should be rendered")
+	}
+
+	updated, cmd = m.Update(actionMsg{text: "Requested rerun", err: errors.New("HTTP 403")})
+	m = updated.(model)
+	if !strings.Contains(m.flash, "failed: No permission: HTTP 403") || cmd != nil {
+		t.Errorf("flash = %q, cmd = %v; want failure without refresh", m.flash, cmd)
+	}
+}
+
+func TestRerunWorkflowArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		jobID      string
+		failedOnly bool
+		wantArgs   string
+	}{
+		{"single job", "22", false, "run rerun --job 22 --repo o/r"},
+		{"failed jobs", "", true, "run rerun 11 --failed --repo o/r"},
+		{"whole run", "", false, "run rerun 11 --repo o/r"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCommand = fakeExecByArgs(map[string]string{tt.wantArgs: ""})
+			t.Cleanup(func() { execCommand = exec.Command })
+			if err := rerunWorkflow(nil, "o/r", "11", tt.jobID, tt.failedOnly); err != nil {
+				t.Errorf("rerunWorkflow: %v (want args %q)", err, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestLocalRebaseCommand(t *testing.T) {
+	base := func() model {
+		m := paletteTestModel()
+		m.prData.HeadRefName = "feature"
+		m.prData.BaseRefName = "main"
+		return m
+	}
+
+	t.Run("outside a clone", func(t *testing.T) {
+		c := base().localRebaseCommand()
+		if !strings.Contains(c.disabled, "not inside a clone") {
+			t.Errorf("disabled = %q", c.disabled)
+		}
+	})
+
+	t.Run("wrong branch checked out", func(t *testing.T) {
+		m := base()
+		m.gitRemote, m.gitBranch = "origin", "main"
+		c := m.localRebaseCommand()
+		if !strings.Contains(c.disabled, "check out feature") {
+			t.Errorf("disabled = %q", c.disabled)
+		}
+	})
+
+	t.Run("on the PR branch", func(t *testing.T) {
+		m := base()
+		m.gitRemote, m.gitBranch = "upstream", "feature"
+		c := m.localRebaseCommand()
+		if c.disabled != "" {
+			t.Fatalf("disabled = %q, want enabled", c.disabled)
+		}
+		if !strings.Contains(c.label, "upstream/main") {
+			t.Errorf("label = %q, should name remote/base", c.label)
+		}
+	})
+
+	t.Run("palette open detects the remote", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{
+			"remote -v": "origin\tgit@github.com:o/r.git (fetch)\n",
+			"rev-parse": "feature\n",
+		})
+		t.Cleanup(func() { execCommand = exec.Command })
+		m := base().openPalette()
+		if m.gitRemote != "origin" || m.gitBranch != "feature" {
+			t.Errorf("gitRemote, gitBranch = %q, %q; want origin, feature", m.gitRemote, m.gitBranch)
+		}
+	})
+}
+
+func TestUpdateBranchArgs(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{"pr update-branch 1 --repo o/r --rebase": ""})
+	t.Cleanup(func() { execCommand = exec.Command })
+	if err := updateBranch(nil, "o/r", "1", true); err != nil {
+		t.Errorf("updateBranch rebase: %v", err)
+	}
+	if err := updateBranch(nil, "o/r", "1", false); err == nil {
+		t.Error("merge update should not pass --rebase")
+	}
+}
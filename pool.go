@@ -0,0 +1,60 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fetchWorkers bounds how many dashboard fetches (gh processes) run at once.
+const fetchWorkers = 4
+
+// fetchJob is one dashboard PR to fetch.
+type fetchJob struct {
+	key    string
+	repo   string
+	number string
+	acct   *Account
+}
+
+// fetchPool runs dashboard fetches on a fixed set of workers. Each result is
+// delivered through next() as soon as its fetch finishes, so one slow PR
+// doesn't hold up the rest of the dashboard.
+type fetchPool struct {
+	jobs    chan fetchJob
+	results chan dashResult
+}
+
+type dashResultMsg dashResult
+
+func newFetchPool(workers int, fetch func(fetchJob) dashResult) *fetchPool {
+	p := &fetchPool{
+		jobs:    make(chan fetchJob, 256),
+		results: make(chan dashResult, workers),
+	}
+	for range workers {
+		go func() {
+			for j := range p.jobs {
+				p.results <- fetch(j)
+			}
+		}()
+	}
+	return p
+}
+
+// submitCmd queues jobs in order. It blocks (in the command's goroutine, not
+// the UI) if the queue is full.
+func (p *fetchPool) submitCmd(jobs []fetchJob) tea.Cmd {
+	return func() tea.Msg {
+		for _, j := range jobs {
+			p.jobs <- j
+		}
+		return nil
+	}
+}
+
+// next waits for the next finished fetch. The dashboard keeps exactly one
+// next() outstanding, re-issuing it after every dashResultMsg.
+func (p *fetchPool) next() tea.Cmd {
+	return func() tea.Msg {
+		return dashResultMsg(<-p.results)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchPoolBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	p := newFetchPool(2, func(j fetchJob) dashResult {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return dashResult{key: j.key}
+	})
+
+	jobs := []fetchJob{{key: "a"}, {key: "b"}, {key: "c"}, {key: "d"}, {key: "e"}}
+	go p.submitCmd(jobs)()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	seen := map[string]bool{}
+	for range jobs {
+		msg := p.next()().(dashResultMsg)
+		seen[msg.key] = true
+	}
+	if len(seen) != len(jobs) {
+		t.Errorf("got results for %v, want all %d jobs", seen, len(jobs))
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", got)
+	}
+}
+
+func TestFetchPoolDeliversResultsAsTheyFinish(t *testing.T) {
+	slow := make(chan struct{})
+	p := newFetchPool(2, func(j fetchJob) dashResult {
+		if j.key == "slow" {
+			<-slow
+		}
+		return dashResult{key: j.key}
+	})
+	defer close(slow)
+
+	p.submitCmd([]fetchJob{{key: "slow"}, {key: "fast"}})()
+	if msg := p.next()().(dashResultMsg); msg.key != "fast" {
+		t.Errorf("first result = %q, want fast (slow is still running)", msg.key)
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// actionsProvider is the GitHub App behind every Actions check run.
+const actionsProvider = "GitHub Actions"
+
+// contextProviders name the CI systems behind well-known status context
+// prefixes, matched case-insensitively.
+var contextProviders = []struct {
+	prefix, provider string
+}{
+	{"ci/circleci", "CircleCI"},
+	{"codecov/", "Codecov"},
+	{"continuous-integration/jenkins", "Jenkins"},
+	{"jenkins", "Jenkins"},
+	{"continuous-integration/travis-ci", "Travis CI"},
+	{"travis-ci", "Travis CI"},
+	{"continuous-integration/appveyor", "AppVeyor"},
+	{"buildkite/", "Buildkite"},
+	{"vercel", "Vercel"},
+	{"netlify/", "Netlify"},
+	{"sonarcloud", "SonarCloud"},
+}
+
+// urlProviders name the CI systems behind a check's details URL, by host.
+var urlProviders = []struct {
+	host, provider string
+}{
+	{"circleci.com", "CircleCI"},
+	{"codecov.io", "Codecov"},
+	{"travis-ci.com", "Travis CI"},
+	{"travis-ci.org", "Travis CI"},
+	{"buildkite.com", "Buildkite"},
+	{"dev.azure.com", "Azure Pipelines"},
+	{"ci.appveyor.com", "AppVeyor"},
+	{"vercel.com", "Vercel"},
+	{"netlify.com", "Netlify"},
+	{"sonarcloud.io", "SonarCloud"},
+}
+
+// checkProvider guesses which CI system reported a check from what gh pr
+// view says about it: Actions check runs name their workflow, status
+// contexts are prefixed by the system that posts them, and otherwise the
+// details URL's host usually gives it away. It is "" when none of these
+// do, in which case the check suite's app (see fetchCheckApps) may.
+func checkProvider(item ghCheckItem, detailsURL string) string {
+	if item.WorkflowName != "" {
+		return actionsProvider
+	}
+	if item.Typename == "StatusContext" {
+		context := strings.ToLower(item.Context)
+		for _, p := range contextProviders {
+			if strings.HasPrefix(context, p.prefix) {
+				return p.provider
+			}
+		}
+	}
+	if u, err := url.Parse(detailsURL); err == nil && u.Host != "" {
+		host := strings.ToLower(u.Hostname())
+		if strings.Contains(u.Path, "/actions/runs/") {
+			return actionsProvider
+		}
+		for _, p := range urlProviders {
+			if host == p.host || strings.HasSuffix(host, "."+p.host) {
+				return p.provider
+			}
+		}
+		if strings.Contains(host, "jenkins") {
+			return "Jenkins"
+		}
+	}
+	if prefix, _, ok := strings.Cut(item.Context, "/"); ok && prefix != "" {
+		// An unknown system's own prefix, e.g. "deploy" for deploy/staging
+		return prefix
+	}
+	return ""
+}
+
+// checkApps are the GitHub Apps that created the head commit's check
+// suites, by requiredKey of each check run.
+type checkApps struct {
+	repo string
+	sha  string
+	apps map[string]string
+	err  error
+}
+
+type checkAppsMsg struct {
+	apps *checkApps
+}
+
+const checkAppsQuery = `query($owner: String!, $name: String!, $oid: GitObjectID!) {
+  repository(owner: $owner, name: $name) {
+    object(oid: $oid) { ... on Commit { checkSuites(first: 50) { nodes {
+      app { name }
+      workflowRun { workflow { name } }
+      checkRuns(first: 100) { nodes { name } }
+    } } } }
+  }
+}`
+
+// fetchCheckApps asks which app created each of a commit's check runs.
+// gh pr view doesn't say, so this is a separate query, made once per head
+// commit and only when some check's provider can't be guessed.
+func fetchCheckApps(acct *Account, repo, sha string) *checkApps {
+	a := &checkApps{repo: repo, sha: sha}
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql", "-f", "query="+checkAppsQuery,
+		"-F", "owner="+owner, "-F", "name="+name, "-F", "oid="+sha)
+	if err != nil {
+		a.err = err
+		return a
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Object *struct {
+					CheckSuites struct {
+						Nodes []struct {
+							App *struct {
+								Name string `json:"name"`
+							} `json:"app"`
+							WorkflowRun *struct {
+								Workflow struct {
+									Name string `json:"name"`
+								} `json:"workflow"`
+							} `json:"workflowRun"`
+							CheckRuns struct {
+								Nodes []struct {
+									Name string `json:"name"`
+								} `json:"nodes"`
+							} `json:"checkRuns"`
+						} `json:"nodes"`
+					} `json:"checkSuites"`
+				} `json:"object"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		a.err = fmt.Errorf("failed to parse check suites: %w", err)
+		return a
+	}
+	a.apps = map[string]string{}
+	if resp.Data.Repository.Object == nil {
+		return a
+	}
+	for _, suite := range resp.Data.Repository.Object.CheckSuites.Nodes {
+		if suite.App == nil || suite.App.Name == "" {
+			continue
+		}
+		workflow := ""
+		if suite.WorkflowRun != nil {
+			workflow = suite.WorkflowRun.Workflow.Name
+		}
+		for _, run := range suite.CheckRuns.Nodes {
+			a.apps[requiredKey(run.Name, workflow)] = suite.App.Name
+		}
+	}
+	return a
+}
+
+// checkAppsCmd fetches the check suites' apps once per head commit, when
+// some check has no provider yet.
+func (m model) checkAppsCmd() (model, tea.Cmd) {
+	if m.prData == nil || m.prData.HeadSHA == "" || m.appsAsked == prKey(m.repo, m.prData.HeadSHA) {
+		return m, nil
+	}
+	if !slices.ContainsFunc(m.prData.Checks, func(c Check) bool { return c.Provider == "" }) {
+		return m, nil
+	}
+	repo, sha := m.repo, m.prData.HeadSHA
+	m.appsAsked = prKey(repo, sha)
+	acct := m.repoAccount(repo)
+	return m, func() tea.Msg {
+		return checkAppsMsg{apps: fetchCheckApps(acct, repo, sha)}
+	}
+}
+
+// withCheckApps fills in the providers of the checks the apps explain.
+// The PR data is copied rather than changed, since older snapshots of it
+// are kept elsewhere.
+func (m model) withCheckApps() model {
+	a := m.checkApps
+	if m.prData == nil || a == nil || a.repo != m.repo || a.sha != m.prData.HeadSHA || len(a.apps) == 0 {
+		return m
+	}
+	data := *m.prData
+	data.Checks = slices.Clone(data.Checks)
+	for i, c := range data.Checks {
+		if app := a.apps[requiredKey(c.JobName, c.Workflow)]; app != "" && c.Provider == "" {
+			data.Checks[i].Provider = app
+		}
+	}
+	m.prData = &data
+	return m
+}
+
+// providers are the distinct providers of the PR's checks, sorted, leaving
+// out the hidden ones.
+func (m model) providers() []string {
+	var names []string
+	if m.prData != nil {
+		for _, c := range m.prData.Checks {
+			if c.Provider != "" && !m.providerHidden(c) && !slices.Contains(names, c.Provider) {
+				names = append(names, c.Provider)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// cycleProvider narrows the check table to the next provider's checks, and
+// back to all of them after the last.
+func (m model) cycleProvider() model {
+	names := m.providers()
+	if len(names) < 2 && m.provider == "" {
+		m.flash = "No check says which provider it's from"
+		if len(names) == 1 {
+			m.flash = "All checks come from " + names[0]
+		}
+		return m
+	}
+	next := ""
+	if i := slices.Index(names, m.provider); m.provider == "" {
+		next = names[0]
+	} else if i+1 < len(names) {
+		next = names[i+1]
+	}
+	m.provider = next
+	m.selected, m.scrollOff = 0, 0
+	if next == "" {
+		m.flash = "Showing checks from every provider"
+	} else {
+		m.flash = fmt.Sprintf("Showing %s checks (P for the next provider)", next)
+	}
+	return m
+}
+
+// parseHideProviders reads [filter] hide_providers into a set of
+// lowercased names; nil when it isn't set.
+func parseHideProviders(f Filter) (map[string]bool, error) {
+	if len(f.HideProviders) == 0 {
+		return nil, nil
+	}
+	hidden := map[string]bool{}
+	for i, name := range f.HideProviders {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("filter.hide_providers[%d]: empty provider", i)
+		}
+		hidden[strings.ToLower(name)] = true
+	}
+	return hidden, nil
+}
+
+// providerHidden reports whether c is left out of the table because
+// [filter] hide_providers names its provider.
+func (m model) providerHidden(c Check) bool {
+	return !m.showProviders && c.Provider != "" && m.hiddenProviders[strings.ToLower(c.Provider)]
+}
+
+// providerHiddenCount is how many of the PR's checks hide_providers keeps
+// out of the table, skipped ones aside, which are counted with the rest
+// of the skipped.
+func (m model) providerHiddenCount() int {
+	n := 0
+	if m.prData != nil {
+		for _, c := range m.prData.Checks {
+			if m.providerHidden(c) && (c.Status != Skipped || !m.hideSkipped) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// toggleHiddenProviders shows the checks of the hidden providers (H), or
+// hides them again.
+func (m model) toggleHiddenProviders() model {
+	if len(m.hiddenProviders) == 0 {
+		m.flash = "Set hide_providers under [filter] in the config to the providers whose checks to hide"
+		return m
+	}
+	m.showProviders = !m.showProviders
+	m.selected, m.scrollOff = 0, 0
+	if m.showProviders {
+		m.flash = "Showing checks from hidden providers"
+	} else {
+		m.flash = "Hiding checks from " + strings.Join(slices.Sorted(maps.Keys(m.hiddenProviders)), ", ")
+	}
+	return m
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCheckProvider(t *testing.T) {
+	tests := []struct {
+		item ghCheckItem
+		url  string
+		want string
+	}{
+		{ghCheckItem{Typename: "CheckRun", Name: "build", WorkflowName: "CI"}, "https://github.com/o/r/actions/runs/1/job/2", "GitHub Actions"},
+		{ghCheckItem{Typename: "CheckRun", Name: "build"}, "https://github.com/o/r/actions/runs/1/job/2", "GitHub Actions"},
+		{ghCheckItem{Typename: "StatusContext", Context: "ci/circleci: test"}, "https://circleci.com/gh/o/r/12", "CircleCI"},
+		{ghCheckItem{Typename: "StatusContext", Context: "codecov/project"}, "https://app.codecov.io/gh/o/r", "Codecov"},
+		{ghCheckItem{Typename: "StatusContext", Context: "continuous-integration/jenkins/pr-merge"}, "https://ci.example.com/job/1", "Jenkins"},
+		{ghCheckItem{Typename: "StatusContext", Context: "build"}, "https://jenkins.example.com/job/1", "Jenkins"},
+		{ghCheckItem{Typename: "CheckRun", Name: "buildkite/pipeline"}, "https://buildkite.com/o/p/builds/3", "Buildkite"},
+		{ghCheckItem{Typename: "StatusContext", Context: "deploy/staging"}, "", "deploy"},
+		{ghCheckItem{Typename: "CheckRun", Name: "Vercel Preview Comments"}, "", ""},
+	}
+	for _, tt := range tests {
+		if got := checkProvider(tt.item, tt.url); got != tt.want {
+			t.Errorf("checkProvider(%+v, %q) = %q, want %q", tt.item, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCheckApps(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"oid=abc123 graphql": `{"data":{"repository":{"object":{"checkSuites":{"nodes":[
+			{"app":{"name":"GitHub Actions"},"workflowRun":{"workflow":{"name":"CI"}},"checkRuns":{"nodes":[{"name":"build"}]}},
+			{"app":{"name":"Vercel"},"workflowRun":null,"checkRuns":{"nodes":[{"name":"Vercel Preview Comments"}]}},
+			{"app":null,"workflowRun":null,"checkRuns":{"nodes":[{"name":"orphan"}]}}]}}}}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.prData = &PRData{HeadSHA: "abc123", Checks: []Check{
+		{Name: "build (CI)", JobName: "build", Workflow: "CI", Status: Pass, Provider: actionsProvider},
+		{Name: "Vercel Preview Comments", JobName: "Vercel Preview Comments", Status: Pass},
+		{Name: "codecov/project", JobName: "codecov/project", Status: Fail, Provider: "Codecov"},
+	}}
+	before := m.prData
+
+	m, cmd := m.checkAppsCmd()
+	if cmd == nil {
+		t.Fatal("no fetch for a check without a provider")
+	}
+	if _, again := m.checkAppsCmd(); again != nil {
+		t.Error("refetched the same commit's apps")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(model)
+	if got := m.prData.Checks[1].Provider; got != "Vercel" {
+		t.Errorf("Vercel check's provider = %q", got)
+	}
+	if before.Checks[1].Provider != "" {
+		t.Error("apps written into the fetched data")
+	}
+	if got := strings.Join(m.providers(), ","); got != "Codecov,GitHub Actions,Vercel" {
+		t.Errorf("providers = %s", got)
+	}
+
+	// A refresh of the same commit keeps the apps
+	updated, _ = m.Update(prDataMsg{data: &PRData{HeadSHA: "abc123", Checks: before.Checks}, key: prKey("o/r", "1")})
+	m = updated.(model)
+	if got := m.prData.Checks[1].Provider; got != "Vercel" {
+		t.Errorf("after a refresh: %q", got)
+	}
+
+	// Every provider guessed: nothing to ask
+	m2 := newModel("o/r", "1", 5*time.Second)
+	m2.prData = &PRData{HeadSHA: "def456", Checks: before.Checks[:1]}
+	if _, cmd := m2.checkAppsCmd(); cmd != nil {
+		t.Error("fetched apps with every provider known")
+	}
+}
+
+func TestProviderFilter(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.prData = &PRData{Checks: []Check{
+		{Name: "build (CI)", Status: Pass, Provider: actionsProvider},
+		{Name: "lint (CI)", Status: Pass, Provider: actionsProvider},
+		{Name: "ci/circleci: test", Status: Fail, Provider: "CircleCI"},
+	}}
+
+	m = m.cycleProvider()
+	if got := len(m.filteredChecks()); m.provider != "CircleCI" || got != 1 {
+		t.Fatalf("provider %q, %d checks", m.provider, got)
+	}
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "P: CircleCI | s: show skipped") || strings.Contains(out, "lint (CI)") {
+		t.Errorf("CircleCI only:\n%s", out)
+	}
+	if !strings.Contains(out, "Checks: 3 total") {
+		t.Errorf("summary should count every check:\n%s", out)
+	}
+	m = m.cycleProvider()
+	if m.provider != actionsProvider || len(m.filteredChecks()) != 2 {
+		t.Errorf("second provider %q", m.provider)
+	}
+	m = m.cycleProvider()
+	if m.provider != "" || len(m.filteredChecks()) != 3 || m.flash != "Showing checks from every provider" {
+		t.Errorf("back to all: %q, %q", m.provider, m.flash)
+	}
+
+	m.prData.Checks = m.prData.Checks[:2]
+	if m = m.cycleProvider(); m.provider != "" || m.flash != "All checks come from GitHub Actions" {
+		t.Errorf("one provider: %q, %q", m.provider, m.flash)
+	}
+}
+
+func TestProviderColumn(t *testing.T) {
+	cols, err := parseTable(Table{Columns: []string{"status", "provider", "name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols[1].header != "PROVIDER" || cols[1].render(Check{Provider: "CircleCI"}) != "CircleCI" {
+		t.Errorf("provider column = %+v", cols[1])
+	}
+	// A [[table.column]] of the same name replaces it
+	cols, err = parseTable(Table{Columns: []string{"provider"}, Custom: []TableColumn{{Name: "provider", Header: "CI", Template: "{{lower .Provider}}"}}})
+	if err != nil || cols[0].header != "CI" || cols[0].render(Check{Provider: "CircleCI"}) != "circleci" {
+		t.Errorf("overridden: %+v, %v", cols, err)
+	}
+}
+
+func TestHideProviders(t *testing.T) {
+	if _, err := parseHideProviders(Filter{HideProviders: []string{"Codecov", " "}}); err == nil {
+		t.Error("empty provider accepted")
+	}
+	hidden, err := parseHideProviders(Filter{HideProviders: []string{"codecov", "License"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 140, 20
+	m.hiddenProviders = hidden
+	m.prData = &PRData{Checks: []Check{
+		{Name: "build (CI)", Status: Fail, Provider: actionsProvider},
+		{Name: "codecov/project", Status: Pass, Provider: "Codecov"},
+		{Name: "license/cla", Status: Pass, Provider: "license"},
+		{Name: "deploy (CD)", Status: Skipped, Provider: actionsProvider},
+	}}
+	out := ansi.Strip(m.View())
+	if got := len(m.filteredChecks()); got != 1 || strings.Contains(out, "codecov/project") {
+		t.Errorf("%d checks shown:\n%s", got, out)
+	}
+	if !strings.Contains(out, "Checks: 4 total") || !strings.Contains(out, "(3 hidden)") || !strings.Contains(out, "H: show hidden providers") {
+		t.Errorf("summary and footer:\n%s", out)
+	}
+	if got := m.providers(); len(got) != 1 {
+		t.Errorf("P would offer %v", got)
+	}
+
+	m = m.toggleHiddenProviders()
+	if got := len(m.filteredChecks()); got != 3 || m.flash != "Showing checks from hidden providers" {
+		t.Errorf("shown again: %d checks, %q", got, m.flash)
+	}
+	m = m.toggleHiddenProviders()
+	if got := len(m.filteredChecks()); got != 1 || m.flash != "Hiding checks from codecov, license" {
+		t.Errorf("hidden again: %d checks, %q", got, m.flash)
+	}
+
+	m.hiddenProviders = nil
+	if m = m.toggleHiddenProviders(); !strings.Contains(m.flash, "hide_providers") {
+		t.Errorf("unconfigured: %q", m.flash)
+	}
+}
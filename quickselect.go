@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickSelectMax is how many of the picker's PRs are numbered for a single
+// keypress.
+const quickSelectMax = 9
+
+// branchPRMsg is the PR for the branch checked out in the working
+// directory, asked for with 0 in the picker.
+type branchPRMsg struct {
+	url string
+	err error
+}
+
+// quickSelect opens the picker's PR numbered digit, or with 0 the PR for
+// the current branch.
+func (m model) quickSelect(digit int) (model, tea.Cmd) {
+	if digit == 0 {
+		m.flash = "Looking up the current branch's PR..."
+		return m, func() tea.Msg {
+			url, err := currentBranchPR()
+			return branchPRMsg{url: url, err: err}
+		}
+	}
+	if digit > len(m.prs) {
+		m.flash = fmt.Sprintf("There's no PR %d", digit)
+		return m, nil
+	}
+	pr := m.prs[digit-1]
+	return m.viewPR(pr.Repo, strconv.Itoa(pr.Number))
+}
+
+// openBranchPR views the PR a branchPRMsg found, if the picker is still
+// open.
+func (m model) openBranchPR(msg branchPRMsg) (model, tea.Cmd) {
+	if m.mode != modeSelecting {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.flash = "No PR for the current branch"
+		logger.Debug("current branch PR failed", "err", msg.err)
+		return m, nil
+	}
+	var hosts []string
+	for _, a := range m.accounts {
+		hosts = append(hosts, a.Host)
+	}
+	repo, prNumber, ok := parsePRURL(msg.url, hosts...)
+	if !ok {
+		m.flash = "Can't open " + msg.url
+		return m, nil
+	}
+	m.flash = ""
+	return m.viewPR(repo, prNumber)
+}
+
+// quickSelectLabel is the number in front of a picker row, blank past
+// quickSelectMax.
+func quickSelectLabel(idx int) string {
+	if idx >= quickSelectMax {
+		return "  "
+	}
+	return styleDim.Render(strconv.Itoa(idx+1)) + " "
+}
+
+// autoSelect is whether the picker skips itself once its PRs load.
+type autoSelect int
+
+const (
+	autoSelectOff    autoSelect = iota
+	autoSelectSingle            // when exactly one PR is listed
+	autoSelectFirst             // --auto: whatever is first
+)
+
+// autoSelectPR is the PR the picker's list opens by itself, if any. Only
+// the first list counts: going back to the picker from the PR shows it.
+func (m model) autoSelectPR() (repo, prNumber string, ok bool) {
+	switch {
+	case len(m.prs) == 0:
+		return "", "", false
+	case m.autoSelect == autoSelectFirst, m.autoSelect == autoSelectSingle && len(m.prs) == 1:
+		return m.prs[0].Repo, strconv.Itoa(m.prs[0].Number), true
+	}
+	return "", "", false
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func quickSelectModel() model {
+	m := newSelectModel(5 * time.Second)
+	m.width, m.height = 100, 40
+	m.loading = false
+	for i := 1; i <= 11; i++ {
+		m.prs = append(m.prs, PRSummary{Repo: "o/r", Number: 100 + i, Title: "PR"})
+	}
+	return m
+}
+
+func TestQuickSelect(t *testing.T) {
+	m, _ := press(t, quickSelectModel(), runeKey('3'))
+	if m.mode != modeViewing || m.repo != "o/r" || m.prNumber != "103" {
+		t.Errorf("3 opened %s#%s in mode %v, want o/r#103", m.repo, m.prNumber, m.mode)
+	}
+
+	m = quickSelectModel()
+	m.prs = m.prs[:2]
+	m, _ = press(t, m, runeKey('7'))
+	if m.mode != modeSelecting || !strings.Contains(m.View(), "There's no PR 7") {
+		t.Errorf("7 with two PRs: mode %v, flash %q", m.mode, m.flash)
+	}
+
+	// Viewing mode keeps digits as counts
+	m = newModel("o/r", "1", 5*time.Second)
+	m.prData = &PRData{Checks: make([]Check, 10)}
+	m.hideSkipped = false
+	m, _ = press(t, m, typed("5j")...)
+	if m.selected != 5 {
+		t.Errorf("5j while viewing: selected %d, want 5", m.selected)
+	}
+}
+
+func TestQuickSelectNumbersRows(t *testing.T) {
+	view := quickSelectModel().View()
+	for _, want := range []string{"▸ 1 o/r #101", "  9 o/r #109", "    o/r #110"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestQuickSelectCurrentBranch(t *testing.T) {
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		return []byte(`{"url":"https://github.com/o/r/pull/42"}`), nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	m, cmd := press(t, quickSelectModel(), runeKey('0'))
+	if cmd == nil {
+		t.Fatal("0 should look up the current branch's PR")
+	}
+	msg := cmd()
+	next, _ := m.Update(msg)
+	m = next.(model)
+	if m.mode != modeViewing || m.repo != "o/r" || m.prNumber != "42" {
+		t.Errorf("0 opened %s#%s in mode %v, want o/r#42", m.repo, m.prNumber, m.mode)
+	}
+
+	m = quickSelectModel()
+	next, _ = m.Update(branchPRMsg{err: errors.New("no pull requests found for branch \"main\"")})
+	m = next.(model)
+	if m.mode != modeSelecting || m.flash != "No PR for the current branch" {
+		t.Errorf("no PR: mode %v, flash %q", m.mode, m.flash)
+	}
+
+	// The answer arriving after leaving the picker is dropped
+	m = quickSelectModel()
+	m.mode = modeDashboard
+	next, _ = m.Update(branchPRMsg{url: "https://github.com/o/r/pull/42"})
+	if next.(model).mode != modeDashboard {
+		t.Error("a late answer left the dashboard")
+	}
+}
+
+func TestAutoSelect(t *testing.T) {
+	resetRespCache(t)
+	list := func(m model, prs ...PRSummary) model {
+		t.Helper()
+		updated, _ := m.Update(prListMsg{prs: prs})
+		return updated.(model)
+	}
+	one := PRSummary{Repo: "o/r", Number: 1}
+	two := PRSummary{Repo: "o/r", Number: 2}
+
+	m := newSelectModel(5 * time.Second)
+	m.autoSelect = autoSelectSingle
+	m = list(m, one)
+	if m.mode != modeViewing || m.prNumber != "1" {
+		t.Fatalf("a single PR: mode %v, PR %q; want viewing #1", m.mode, m.prNumber)
+	}
+	// Going back shows the picker rather than opening the PR again
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = list(m, one)
+	if m.mode != modeSelecting {
+		t.Errorf("back in the picker: mode %v, want selecting", m.mode)
+	}
+
+	m = newSelectModel(5 * time.Second)
+	m.autoSelect = autoSelectSingle
+	if m = list(m, one, two); m.mode != modeSelecting {
+		t.Errorf("two PRs: mode %v, want selecting", m.mode)
+	}
+
+	m = newSelectModel(5 * time.Second)
+	m.autoSelect = autoSelectFirst
+	if m = list(m, one, two); m.mode != modeViewing || m.prNumber != "1" {
+		t.Errorf("--auto with two PRs: mode %v, PR %q; want viewing #1", m.mode, m.prNumber)
+	}
+
+	m = newSelectModel(5 * time.Second)
+	if m = list(m, one); m.mode != modeSelecting {
+		t.Errorf("auto-select off: mode %v, want selecting", m.mode)
+	}
+}
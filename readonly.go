@@ -0,0 +1,18 @@
+package main
+
+// readOnlyReason is why mutating commands are off with --read-only.
+const readOnlyReason = "read-only mode"
+
+// withReadOnly disables the palette's mutating commands under --read-only,
+// leaving them listed so it's clear why they don't run.
+func (m model) withReadOnly(cmds []paletteCommand) []paletteCommand {
+	if !m.readOnly {
+		return cmds
+	}
+	for i, c := range cmds {
+		if c.mutating {
+			cmds[i].disabled = readOnlyReason
+		}
+	}
+	return cmds
+}
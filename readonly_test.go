@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestReadOnly(t *testing.T) {
+	m := paletteTestModel()
+	m.readOnly = true
+
+	enabled := map[string]bool{}
+	for _, c := range m.paletteCommands() {
+		if c.disabled == "" {
+			enabled[c.label] = true
+		}
+		if c.mutating && c.disabled != readOnlyReason {
+			t.Errorf("%q disabled = %q", c.label, c.disabled)
+		}
+	}
+	if !enabled["Copy Markdown report to clipboard"] || enabled["Rerun selected job"] || enabled["Update branch from base (merge)"] {
+		t.Errorf("enabled commands: %v", enabled)
+	}
+
+	m.approvals = &pendingApprovals{repo: "o/r", runs: []approvalRun{{id: "1", name: "CI"}}}
+	if m, cmd := m.approveRuns(true); cmd != nil || !strings.Contains(m.flash, readOnlyReason) {
+		t.Errorf("approved in read-only mode: %q", m.flash)
+	}
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "READ-ONLY | Refresh:") {
+		t.Errorf("footer:\n%s", out)
+	}
+
+	s, _, _ := testSession(t)
+	s.readOnly = true
+	if err := s.rerun(rpcRerunParams{PR: "o/r#1", Check: "test (CI)"}); err == nil || !strings.Contains(err.Error(), readOnlyReason) {
+		t.Errorf("rerun over RPC: %v", err)
+	}
+}
+
+func TestReadOnlyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("read_only = true\n\n[polling]\ninterval = \"10s\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(path)
+	if err != nil || !cfg.ReadOnly {
+		t.Errorf("read_only = %v, %v", cfg.ReadOnly, err)
+	}
+}
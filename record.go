@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recordEntry is one gh invocation captured by --record, stored as a line of
+// JSON in the recording file.
+type recordEntry struct {
+	Time   time.Time `json:"time"`
+	Args   []string  `json:"args"`
+	Stdout string    `json:"stdout,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// recorder appends gh invocations to a JSONL file.
+type recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// replayer serves recorded gh output instead of running gh. Each distinct
+// argument list replays its entries in order and then repeats the last one.
+type replayer struct {
+	mu      sync.Mutex
+	entries map[string][]recordEntry
+	last    map[string]recordEntry
+}
+
+var recording *recorder
+
+func newRecorder(w io.Writer) *recorder {
+	return &recorder{enc: json.NewEncoder(w)}
+}
+
+func (r *recorder) add(args []string, out []byte, err error) {
+	// Redacted so the recording can be attached to a bug report
+	e := recordEntry{Time: time.Now().UTC(), Args: redactArgs(args), Stdout: redact(string(out))}
+	if err != nil {
+		e.Error = redact(err.Error())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if encErr := r.enc.Encode(e); encErr != nil {
+		logger.Debug("record failed", "err", encErr)
+	}
+}
+
+// startRecording creates (or truncates) path and records every gh invocation.
+func startRecording(path string) (io.Closer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	recording = newRecorder(f)
+	return f, nil
+}
+
+func loadReplay(r io.Reader) (*replayer, error) {
+	rp := &replayer{
+		entries: map[string][]recordEntry{},
+		last:    map[string]recordEntry{},
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 64<<20)
+	line := 0
+	for sc.Scan() {
+		line++
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e recordEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		key := replayKey(e.Args)
+		rp.entries[key] = append(rp.entries[key], e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return rp, nil
+}
+
+// startReplay loads the recording at path and serves gh calls from it.
+func startReplay(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open replay: %w", err)
+	}
+	defer f.Close()
+	rp, err := loadReplay(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse replay %s: %w", path, err)
+	}
+	ghOverride = rp
+	return nil
+}
+
+func replayKey(args []string) string {
+	return strings.Join(args, "\x00")
+}
+
+func (rp *replayer) run(args []string) ([]byte, error) {
+	key := replayKey(args)
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	e, ok := rp.last[key]
+	if queue := rp.entries[key]; len(queue) > 0 {
+		e, ok = queue[0], true
+		rp.entries[key] = queue[1:]
+		rp.last[key] = e
+	}
+	if !ok {
+		return nil, fmt.Errorf("replay: no recorded output for gh %s", strings.Join(args, " "))
+	}
+	if e.Error != "" {
+		return nil, errors.New(e.Error)
+	}
+	return []byte(e.Stdout), nil
+}
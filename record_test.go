@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec := newRecorder(&buf)
+	view := []string{"pr", "view", "1", "--repo", "o/r"}
+	rec.add(view, []byte(`{"title":"first"}`), nil)
+	rec.add(view, []byte(`{"title":"second"}`), nil)
+	rec.add([]string{"search", "prs"}, nil, errors.New("gh CLI error: rate limited"))
+
+	rp, err := loadReplay(&buf)
+	if err != nil {
+		t.Fatalf("loadReplay: %v", err)
+	}
+
+	for _, want := range []string{"first", "second", "second"} {
+		out, err := rp.run(view)
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if !strings.Contains(string(out), want) {
+			t.Errorf("run() = %s, want payload %q", out, want)
+		}
+	}
+
+	if _, err := rp.run([]string{"search", "prs"}); err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("recorded error should replay, got %v", err)
+	}
+
+	if _, err := rp.run([]string{"pr", "view", "2"}); err == nil {
+		t.Error("expected error for unrecorded args")
+	}
+}
+
+func TestLoadReplayInvalid(t *testing.T) {
+	_, err := loadReplay(strings.NewReader("{\"args\":[\"x\"]}\nnot json\n"))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error = %q, should name the bad line", err)
+	}
+}
+
+func TestRunGhUsesReplayAndRecorder(t *testing.T) {
+	t.Run("replay bypasses gh", func(t *testing.T) {
+		rp, err := loadReplay(strings.NewReader(`{"args":["pr","view","1"],"stdout":"replayed"}` + "\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ghOverride = rp
+		t.Cleanup(func() { ghOverride = nil })
+		execCommand = fakeExecCommand("live", "", 0)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		out, err := runGh(nil, "pr", "view", "1")
+		if err != nil {
+			t.Fatalf("runGh: %v", err)
+		}
+		if string(out) != "replayed" {
+			t.Errorf("runGh = %q, want replayed output", out)
+		}
+	})
+
+	t.Run("recorder captures live output", func(t *testing.T) {
+		var buf bytes.Buffer
+		recording = newRecorder(&buf)
+		t.Cleanup(func() { recording = nil })
+		execCommand = fakeExecCommand("live", "", 0)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		if _, err := runGh(nil, "pr", "view", "1"); err != nil {
+			t.Fatalf("runGh: %v", err)
+		}
+		if !strings.Contains(buf.String(), `"stdout":"live"`) {
+			t.Errorf("recording = %q, should contain live stdout", buf.String())
+		}
+	})
+}
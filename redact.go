@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secrets in debug logs, recordings and errors.
+const redacted = "[REDACTED]"
+
+// secretPatterns match credentials by their shape: GitHub's token formats,
+// Authorization headers, token environment variables and passwords in URLs.
+// repl keeps the prefix that says what kind of secret was there.
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`\b(gh[pousr]_)[A-Za-z0-9]{20,}`), "${1}" + redacted},
+	{regexp.MustCompile(`\b(github_pat_)[A-Za-z0-9_]{20,}`), "${1}" + redacted},
+	{regexp.MustCompile(`(?i)(authorization:\s*(?:bearer|token|basic)\s+)[^\s"',]+`), "${1}" + redacted},
+	{regexp.MustCompile(`(?i)\b((?:GH|GITHUB|GH_ENTERPRISE|GITHUB_ENTERPRISE)_TOKEN=)[^\s"',]+`), "${1}" + redacted},
+	{regexp.MustCompile(`(://[^/\s:@"]+:)[^/\s@"]+@`), "${1}" + redacted + "@"},
+}
+
+// knownSecrets are the tokens prtop has itself read, from the environment
+// or `gh auth token`, which are redacted whatever their shape.
+var knownSecrets = struct {
+	sync.Mutex
+	values []string
+}{}
+
+// addSecret has redact scrub s from now on. Short values are ignored, so
+// that an empty or placeholder token doesn't redact everything.
+func addSecret(s string) {
+	if len(s) < 8 {
+		return
+	}
+	knownSecrets.Lock()
+	defer knownSecrets.Unlock()
+	for _, v := range knownSecrets.values {
+		if v == s {
+			return
+		}
+	}
+	knownSecrets.values = append(knownSecrets.values, s)
+}
+
+// redact scrubs tokens and Authorization headers from s, so that debug
+// logs, recordings and errors can be shared in bug reports.
+func redact(s string) string {
+	knownSecrets.Lock()
+	for _, v := range knownSecrets.values {
+		s = strings.ReplaceAll(s, v, redacted)
+	}
+	knownSecrets.Unlock()
+	for _, p := range secretPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+// redactArgs is args with each one redacted.
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = redact(a)
+	}
+	return out
+}
+
+// redactHandler redacts the message and attributes of every log record
+// before passing it on.
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for i, a := range attrs {
+		attrs[i] = redactAttr(a)
+	}
+	return redactHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redact(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		out := make([]any, len(attrs))
+		for i, g := range attrs {
+			out[i] = redactAttr(g)
+		}
+		return slog.Group(a.Key, out...)
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case []string:
+			return slog.Any(a.Key, redactArgs(x))
+		case error:
+			return slog.String(a.Key, redact(x.Error()))
+		case nil:
+			return a
+		default:
+			s := fmt.Sprint(x)
+			if r := redact(s); r != s {
+				return slog.String(a.Key, r)
+			}
+		}
+	}
+	return a
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// releaseTrain is a `prtop release` manifest: PRs across repos that ship in
+// order. Each PR is a stage, done once it is merged.
+type releaseTrain struct {
+	Name string
+	PRs  []PRSummary
+}
+
+// releaseGateW is the width of each gate column ("✓ approved ").
+const releaseGateW = 11
+
+// loadReleaseManifest reads a YAML manifest such as
+//
+//	name: 2024.05 rollout
+//	train:
+//	  - pr: owner/api#12
+//	  - pr: https://github.com/owner/web/pull/34
+//	  - repo: owner/mobile
+//	    pr: 56
+func loadReleaseManifest(path string, hosts []string) (*releaseTrain, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Name  string `yaml:"name"`
+		Train []struct {
+			Repo string `yaml:"repo"`
+			PR   string `yaml:"pr"`
+		} `yaml:"train"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(manifest.Train) == 0 {
+		return nil, fmt.Errorf("%s: no PRs under train:", path)
+	}
+	train := &releaseTrain{Name: manifest.Name}
+	for i, stage := range manifest.Train {
+		ref := stage.PR
+		if stage.Repo != "" {
+			ref = stage.Repo + "#" + strings.TrimPrefix(stage.PR, "#")
+		}
+		repo, number, ok := parsePRRef(ref, hosts...)
+		if !ok {
+			return nil, fmt.Errorf("%s: stage %d: invalid PR %q; expected owner/repo#123, a PR URL, or repo: and pr:", path, i+1, ref)
+		}
+		n, _ := strconv.Atoi(number)
+		train.PRs = append(train.PRs, PRSummary{Repo: repo, Number: n})
+	}
+	if train.Name == "" {
+		train.Name = path
+	}
+	return train, nil
+}
+
+func runRelease(s *session, args []string) (int, error) {
+	if len(args) != 1 {
+		return exitFailed, errors.New("expected a release manifest: prtop release train.yaml")
+	}
+	train, err := loadReleaseManifest(args[0], s.hosts)
+	if err != nil {
+		return exitFailed, err
+	}
+	m := newDashboardModel("", s.interval)
+	m.stdinPRs = train.PRs
+	m.release = train
+	return runTUI(s, m)
+}
+
+// releaseGates are a stage's three gates: checks green (acknowledged
+// failures aside) and settled, approved, and merged. A merged PR has passed
+// them all.
+func (m model) releaseGates(pr PRSummary, row dashRow) (checks, review, merged CheckStatus) {
+	data := row.data
+	if data == nil {
+		return Skipped, Skipped, Skipped
+	}
+	if data.State == "MERGED" {
+		return Pass, Pass, Pass
+	}
+	switch status, _ := m.rollupFor(pr.Repo, strconv.Itoa(pr.Number), data); status {
+	case rollupSuccess:
+		checks = Pass
+		if !row.settle.settled(timeNow(), m.settleWindow) {
+			checks = Running
+		}
+	case rollupFailure:
+		checks = Fail
+	case rollupPending:
+		checks = Running
+	default:
+		checks = Neutral // no checks
+	}
+	switch data.ReviewDecision {
+	case "APPROVED":
+		review = Pass
+	case "CHANGES_REQUESTED":
+		review = Fail
+	case "REVIEW_REQUIRED":
+		review = Running
+	default:
+		review = Neutral // the repo doesn't require reviews
+	}
+	merged = Running
+	if data.State == "CLOSED" {
+		merged = Fail
+	}
+	return checks, review, merged
+}
+
+// releaseStage is the index of the train's first unmerged PR, or len(prs)
+// when every stage is merged.
+func (m model) releaseStage() int {
+	for i, pr := range m.prs {
+		row := m.dashRows[summaryKey(pr)]
+		if row.data == nil || row.data.State != "MERGED" {
+			return i
+		}
+	}
+	return len(m.prs)
+}
+
+// releaseGate renders one gate cell: the gate's glyph and a word for where
+// it stands.
+func (m model) releaseGate(s CheckStatus, words [Skipped + 1]string) string {
+	text := fmt.Sprintf("%s %-*s", m.glyphs.glyph(s), releaseGateW-2, words[s])
+	if s == Skipped || s == Neutral {
+		return styleDim.Render(text)
+	}
+	return statusStyle(s).Render(text)
+}
+
+var (
+	releaseChecksWords = [Skipped + 1]string{Pass: "green", Fail: "failing", Running: "running", Neutral: "no checks", Skipped: "…"}
+	releaseReviewWords = [Skipped + 1]string{Pass: "approved", Fail: "changes", Running: "pending", Neutral: "optional", Skipped: "…"}
+	releaseMergedWords = [Skipped + 1]string{Pass: "merged", Fail: "closed", Running: "open", Skipped: "…"}
+)
+
+// viewRelease draws the dashboard as a release train: one row per stage in
+// manifest order, with the first unmerged stage highlighted.
+func (m model) viewRelease() string {
+	var b strings.Builder
+	maxWidth := m.width
+
+	b.WriteString(styleHeader.Render("  prtop release: " + m.release.Name))
+	b.WriteString("\n")
+	stage := m.releaseStage()
+	subtitle := fmt.Sprintf("  All %d stages merged", len(m.prs))
+	if stage < len(m.prs) {
+		pr := m.prs[stage]
+		subtitle = fmt.Sprintf("  Stage %d of %d: %s #%d", stage+1, len(m.prs), pr.Repo, pr.Number)
+	}
+	b.WriteString(styleDim.Render(truncate(subtitle, maxWidth)))
+	b.WriteString("\n")
+	if m.flash != "" {
+		b.WriteString(styleRunning.Render(truncate(m.flash, maxWidth)))
+	}
+	b.WriteString("\n")
+
+	refW := 0
+	for _, pr := range m.prs {
+		refW = max(refW, len(fmt.Sprintf("%s #%d", pr.Repo, pr.Number)))
+	}
+	hdr := fmt.Sprintf("       %-*s  %-*s %-*s %-*s TITLE", refW, "PR",
+		releaseGateW, "CHECKS", releaseGateW, "REVIEW", releaseGateW, "MERGED")
+	b.WriteString(styleUnder.Render(truncate(hdr, maxWidth)))
+	b.WriteString("\n")
+
+	maxRows := max(m.height-6, 1)
+	rows := 0
+	for idx := m.scrollOff; idx < len(m.prs) && idx < m.scrollOff+maxRows; idx++ {
+		rows++
+		pr := m.prs[idx]
+		row := m.dashRows[summaryKey(pr)]
+		marker := m.rowMarker(idx, pr)
+		num := fmt.Sprintf("%2d ", idx+1)
+		switch {
+		case idx < stage:
+			num = stylePass.Render(num + m.glyphs.glyph(Pass))
+		case idx == stage:
+			num = styleBold.Render(num + "▶")
+		default:
+			num = styleDim.Render(num + " ")
+		}
+		ref := fmt.Sprintf("%s #%d", pr.Repo, pr.Number)
+		ref += strings.Repeat(" ", refW-len(ref))
+		checks, review, merged := m.releaseGates(pr, row)
+		line := marker + num + " " + styleRepo.Render(ref) + "  " +
+			m.releaseGate(checks, releaseChecksWords) + " " +
+			m.releaseGate(review, releaseReviewWords) + " " +
+			m.releaseGate(merged, releaseMergedWords) + " "
+		titleW := max(maxWidth-7-refW-2-3*(releaseGateW+1), 1)
+		title := ""
+		switch {
+		case row.data != nil:
+			title = styleTitle.Render(truncate(row.data.Title, titleW))
+		case row.err != nil:
+			title = styleFail.Render(truncate("error: "+row.err.Error(), titleW))
+		}
+		if idx == m.selected {
+			b.WriteString(styleSelectedBg.Render(line + title))
+		} else {
+			b.WriteString(line + title)
+		}
+		b.WriteString("\n")
+	}
+
+	for i := 4 + rows; i < m.height-1; i++ {
+		b.WriteString("\n")
+	}
+	footer := "up/down: select | enter: view PR | space: mark | r: refresh all | q: quit"
+	if bulk := m.bulkFooter(); bulk != "" {
+		footer = bulk
+	}
+	b.WriteString(styleDim.Render(truncate(footer, maxWidth)))
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeManifest(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "train.yaml")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadReleaseManifest(t *testing.T) {
+	path := writeManifest(t, `name: May rollout
+train:
+  - pr: o/api#12
+  - pr: https://github.com/o/web/pull/34
+  - repo: o/mobile
+    pr: 56
+`)
+	train, err := loadReleaseManifest(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PRSummary{{Repo: "o/api", Number: 12}, {Repo: "o/web", Number: 34}, {Repo: "o/mobile", Number: 56}}
+	if train.Name != "May rollout" || len(train.PRs) != len(want) {
+		t.Fatalf("train = %+v", train)
+	}
+	for i, pr := range want {
+		if train.PRs[i] != pr {
+			t.Errorf("stage %d = %+v, want %+v", i+1, train.PRs[i], pr)
+		}
+	}
+
+	for name, text := range map[string]string{
+		"empty":  "name: x\n",
+		"bad PR": "train:\n  - pr: nope\n",
+		"yaml":   "train: [\n",
+	} {
+		if _, err := loadReleaseManifest(writeManifest(t, text), nil); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestViewRelease(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.store = &stateStore{}
+	m.width, m.height = 120, 12
+	m.release = &releaseTrain{Name: "May rollout"}
+	m.prs = []PRSummary{{Repo: "o/api", Number: 12}, {Repo: "o/web", Number: 34}, {Repo: "o/app", Number: 56}}
+	m.dashRows[prKey("o/api", "12")] = dashRow{data: &PRData{Title: "API", State: "MERGED"}}
+	m.dashRows[prKey("o/web", "34")] = dashRow{data: &PRData{Title: "Web", State: "OPEN", ReviewDecision: "APPROVED",
+		Checks: []Check{{Name: "build", Status: Running}}}}
+
+	out := m.View()
+	for _, want := range []string{
+		"prtop release: May rollout",
+		"Stage 2 of 3: o/web #34",
+		" 1 ✓ o/api #12  ✓ green     ✓ approved  ✓ merged    API",
+		" 2 ▶ o/web #34  ● running   ✓ approved  ● open      Web",
+		" 3   o/app #56  ⊘ …         ⊘ …         ⊘ …",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() missing %q:\n%s", want, out)
+		}
+	}
+
+	m.dashRows[prKey("o/web", "34")] = dashRow{data: &PRData{State: "MERGED"}}
+	m.dashRows[prKey("o/app", "56")] = dashRow{data: &PRData{State: "MERGED"}}
+	if out := m.View(); !strings.Contains(out, "All 3 stages merged") {
+		t.Errorf("finished train:\n%s", out)
+	}
+}
+
+func TestReleaseGates(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.store = &stateStore{}
+	pr := PRSummary{Repo: "o/r", Number: 1}
+	checks, review, merged := m.releaseGates(pr, dashRow{data: &PRData{State: "CLOSED", ReviewDecision: "CHANGES_REQUESTED",
+		Checks: []Check{{Name: "build", Status: Fail}}}})
+	if checks != Fail || review != Fail || merged != Fail {
+		t.Errorf("closed PR gates = %v, %v, %v", checks, review, merged)
+	}
+	checks, review, merged = m.releaseGates(pr, dashRow{data: &PRData{State: "OPEN"}})
+	if checks != Neutral || review != Neutral || merged != Running {
+		t.Errorf("open PR without checks or required reviews = %v, %v, %v", checks, review, merged)
+	}
+
+	grading, _ = parseGrade(Grade{Ignore: []string{"nightly"}})
+	t.Cleanup(func() { grading = gradePolicy{} })
+	checks, _, _ = m.releaseGates(pr, dashRow{data: &PRData{State: "OPEN",
+		Checks: []Check{{Name: "build", Status: Pass}, {Name: "nightly", Status: Fail}}}})
+	if checks != Pass {
+		t.Errorf("checks gate with an ignored failure = %v, want pass", checks)
+	}
+}
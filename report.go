@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reportMsg reports where the Markdown report went.
+type reportMsg struct {
+	text string
+	err  error
+}
+
+// clipboardCommands are tried in order to copy text; the first one found
+// on PATH is used.
+var clipboardCommands = func() [][]string {
+	if runtime.GOOS == "darwin" {
+		return [][]string{{"pbcopy"}}
+	}
+	return [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+}()
+
+// markdownReport summarizes the PR's checks for pasting into chat: a link to
+// the PR, the counts and a table of the failing checks.
+func (m model) markdownReport() string {
+	if m.prData == nil {
+		return ""
+	}
+	var b strings.Builder
+	title := fmt.Sprintf("%s#%s", m.repo, m.prNumber)
+	if m.prData.URL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, m.prData.URL)
+	}
+	if m.prData.Title != "" {
+		title += " " + m.prData.Title
+	}
+	fmt.Fprintf(&b, "**%s**\n\n", title)
+	fmt.Fprintf(&b, "%s\n", countsLine(m.prData.Checks))
+
+	var failing []Check
+	for _, c := range m.prData.Checks {
+		if c.Status == Fail {
+			failing = append(failing, c)
+		}
+	}
+	if len(failing) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n| Failing check | Duration |\n|---|---|\n")
+	for _, c := range failing {
+		name := markdownEscape(c.Name)
+		if c.DetailsURL != "" {
+			name = fmt.Sprintf("[%s](%s)", name, c.DetailsURL)
+		}
+		if m.isAcked(c) {
+			name += " (acknowledged)"
+		}
+		dur := c.Duration
+		if dur == "" {
+			dur = "-"
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", name, dur)
+	}
+	return b.String()
+}
+
+// markdownEscape keeps a check name from breaking the table or links.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "[", `\[`, "]", `\]`).Replace(s)
+}
+
+// copyReportCmd copies the report to the clipboard, or writes it to a file
+// in the working directory when no clipboard command is available.
+func (m model) copyReportCmd() tea.Cmd {
+	report := m.markdownReport()
+	if report == "" {
+		return nil
+	}
+	path := reportPath(m.repo, m.prNumber)
+	return func() tea.Msg {
+		if err := copyToClipboard(report); err == nil {
+			return reportMsg{text: "Copied Markdown report to the clipboard"}
+		}
+		return writeReport(path, report)
+	}
+}
+
+// writeReportCmd writes the report to a file in the working directory.
+func (m model) writeReportCmd() tea.Cmd {
+	report := m.markdownReport()
+	if report == "" {
+		return nil
+	}
+	path := reportPath(m.repo, m.prNumber)
+	return func() tea.Msg { return writeReport(path, report) }
+}
+
+func writeReport(path, report string) reportMsg {
+	if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+		return reportMsg{text: "Writing the report", err: err}
+	}
+	return reportMsg{text: "Wrote Markdown report to " + path}
+}
+
+// reportPath is the report's file name, e.g. prtop-owner-repo-123.md.
+func reportPath(repo, prNumber string) string {
+	_, ownerRepo := splitRepoHost(repo)
+	return fmt.Sprintf("prtop-%s-%s.md", strings.ReplaceAll(ownerRepo, "/", "-"), prNumber)
+}
+
+func copyToClipboard(text string) error {
+	for _, args := range clipboardCommands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := execCommand(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return fmt.Errorf("no clipboard command found")
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reportModel() model {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.store = &stateStore{}
+	m.prData = &PRData{Title: "Fix it", URL: "https://github.com/o/r/pull/7", Checks: []Check{
+		{Name: "build (CI)", Status: Fail, Duration: "2m10s", DetailsURL: "https://ci/build"},
+		{Name: "a|b", Status: Fail},
+		{Name: "test", Status: Pass, Duration: "30s"},
+	}}
+	return m
+}
+
+func TestMarkdownReport(t *testing.T) {
+	want := "**[o/r#7](https://github.com/o/r/pull/7) Fix it**\n\n" +
+		"1 passed, 2 failed, 0 running, 0 skipped\n\n" +
+		"| Failing check | Duration |\n|---|---|\n" +
+		"| [build (CI)](https://ci/build) | 2m10s |\n" +
+		"| a\\|b | - |\n"
+	if got := reportModel().markdownReport(); got != want {
+		t.Errorf("report =\n%s\nwant\n%s", got, want)
+	}
+
+	m := reportModel()
+	m.prData.Checks = m.prData.Checks[2:]
+	if got := m.markdownReport(); strings.Contains(got, "| Failing check") {
+		t.Errorf("table without failures:\n%s", got)
+	}
+	m.prData = nil
+	if m.markdownReport() != "" || m.copyReportCmd() != nil {
+		t.Error("report without a PR")
+	}
+}
+
+func TestCopyReportFallsBackToFile(t *testing.T) {
+	saved := clipboardCommands
+	clipboardCommands = [][]string{{"prtop-no-such-clipboard"}}
+	t.Cleanup(func() { clipboardCommands = saved })
+	t.Chdir(t.TempDir())
+
+	msg := reportModel().copyReportCmd()().(reportMsg)
+	if msg.err != nil || msg.text != "Wrote Markdown report to prtop-o-r-7.md" {
+		t.Fatalf("msg = %+v", msg)
+	}
+	data, err := os.ReadFile(filepath.Join(".", "prtop-o-r-7.md"))
+	if err != nil || !strings.HasPrefix(string(data), "**[o/r#7]") {
+		t.Errorf("file = %q, %v", data, err)
+	}
+}
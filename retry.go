@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// retrier is how runGh retries a read that failed for a transient reason:
+// up to attempts tries in all, waiting about backoff before the first
+// retry and twice as long before each one after it.
+type retrier struct {
+	attempts int
+	backoff  time.Duration
+}
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = time.Second
+	maxAttempts     = 10
+	maxBackoff      = 30 * time.Second // also caps each doubled wait
+)
+
+// retryPolicy is set from [polling] attempts and backoff by run. Until
+// then gh is tried once, which keeps tests that fake failures fast.
+var retryPolicy = retrier{attempts: 1}
+
+// retrySleep waits between attempts; tests replace it.
+var retrySleep = time.Sleep
+
+// parseRetry reads [polling] attempts and backoff, defaulting unset ones.
+// attempts = 1 turns retries off.
+func parseRetry(p Polling) (retrier, error) {
+	r := retrier{attempts: defaultAttempts, backoff: defaultBackoff}
+	if p.Attempts != 0 {
+		if p.Attempts < 1 || p.Attempts > maxAttempts {
+			return retrier{}, fmt.Errorf("polling.attempts: %d is not between 1 and %d", p.Attempts, maxAttempts)
+		}
+		r.attempts = p.Attempts
+	}
+	if p.Backoff != 0 {
+		if p.Backoff < 0 || p.Backoff > maxBackoff {
+			return retrier{}, fmt.Errorf("polling.backoff: %s is not between 0 and %s", p.Backoff, maxBackoff)
+		}
+		r.backoff = p.Backoff
+	}
+	return r, nil
+}
+
+// delay is the wait before retry n (from 1): the doubled backoff with
+// jitter, so PRs that failed together don't all retry at the same moment.
+func (r retrier) delay(n int) time.Duration {
+	d := r.backoff
+	for i := 1; i < n && d < maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable reports whether gh args failing with err should be tried
+// again: network errors and GitHub 5xx, not a missing PR, credentials,
+// permissions or rate limits, which asking again won't fix. Only reads are
+// retried, since a rerun or branch update that failed on the way back may
+// already have happened. A gh that hung until ghTimeout isn't either; the
+// wait already cost enough.
+func retryable(args []string, err error) bool {
+	if err == nil || !readOnlyGh(args) || strings.Contains(err.Error(), "timed out after") {
+		return false
+	}
+	kind := ghErrKind(err)
+	return kind == ghErrNetwork || kind == ghErrServer
+}
+
+// readOnlyGh reports whether a gh invocation only reads.
+func readOnlyGh(args []string) bool {
+	if len(args) < 2 {
+		return false
+	}
+	switch args[0] {
+	case "search":
+		return true
+	case "pr":
+		return args[1] == "view" || args[1] == "list" || args[1] == "checks"
+	case "run":
+		return args[1] == "view" || args[1] == "list"
+	case "api":
+		for i, a := range args {
+			if (a == "-X" || a == "--method") && i+1 < len(args) && !strings.EqualFold(args[i+1], "GET") {
+				return false
+			}
+			if strings.HasPrefix(a, "query=") && strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(a, "query=")), "mutation") {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// lastRetry is the most recent retry, shown on the status line while it
+// is fresh so a slow refresh explains itself.
+var lastRetry = struct {
+	sync.Mutex
+	note string
+	at   time.Time
+}{}
+
+// retryNoteTTL is how long a retry stays on the status line.
+const retryNoteTTL = 10 * time.Second
+
+func noteRetry(note string) {
+	lastRetry.Lock()
+	defer lastRetry.Unlock()
+	lastRetry.note, lastRetry.at = note, time.Now()
+}
+
+// retryBanner is the latest retry's note, or "" once it is older than
+// retryNoteTTL.
+func retryBanner(now time.Time) string {
+	lastRetry.Lock()
+	defer lastRetry.Unlock()
+	if lastRetry.note == "" || now.Sub(lastRetry.at) > retryNoteTTL {
+		return ""
+	}
+	return lastRetry.note
+}
+
+// runWithRetry calls run until it succeeds, fails for good, or runs out
+// of attempts under retryPolicy.
+func runWithRetry(args []string, run func() ([]byte, error)) ([]byte, error) {
+	out, err := run()
+	r := retryPolicy
+	for n := 1; n < r.attempts && retryable(args, err); n++ {
+		d := r.delay(n)
+		logger.Debug("retrying gh", "args", args, "retry", n, "of", r.attempts-1, "in", d, "err", err)
+		noteRetry(fmt.Sprintf("gh failed, retrying (%d of %d): %s", n, r.attempts-1, strings.TrimPrefix(err.Error(), "gh CLI error: ")))
+		retrySleep(d)
+		out, err = run()
+	}
+	return out, err
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetry(t *testing.T) {
+	if r, err := parseRetry(Polling{}); err != nil || r != (retrier{attempts: defaultAttempts, backoff: defaultBackoff}) {
+		t.Errorf("defaults = %+v, %v", r, err)
+	}
+	if r, err := parseRetry(Polling{Attempts: 1, Backoff: 200 * time.Millisecond}); err != nil || r.attempts != 1 || r.backoff != 200*time.Millisecond {
+		t.Errorf("set = %+v, %v", r, err)
+	}
+	for _, p := range []Polling{{Attempts: -1}, {Attempts: 11}, {Backoff: -time.Second}, {Backoff: time.Hour}} {
+		if _, err := parseRetry(p); err == nil {
+			t.Errorf("parseRetry(%+v): no error", p)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	r := retrier{attempts: 5, backoff: time.Second}
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: maxBackoff} {
+		for range 20 {
+			if d := r.delay(n); d < want/2 || d > want {
+				t.Fatalf("delay(%d) = %s, want %s to %s", n, d, want/2, want)
+			}
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	view := []string{"pr", "view", "1", "--repo", "o/r"}
+	tests := []struct {
+		args []string
+		err  string
+		want bool
+	}{
+		{view, "gh CLI error: HTTP 502: Bad Gateway (https://api.github.com/graphql)", true},
+		{view, "gh CLI error: Post \"https://api.github.com/graphql\": dial tcp: lookup api.github.com: no such host", true},
+		{view, "gh CLI error: GraphQL: Could not resolve to a PullRequest with the number of 1.", false},
+		{view, "gh CLI error: HTTP 401: Bad credentials", false},
+		{view, "gh CLI error: API rate limit exceeded (HTTP 403)", false},
+		{view, "gh CLI error: no pull requests found", false},
+		{[]string{"api", "graphql", "-f", "query=query { viewer { login } }"}, "HTTP 503", true},
+		{[]string{"api", "graphql", "-f", "query=mutation { x }"}, "HTTP 503", false},
+		{[]string{"api", "-X", "POST", "repos/o/r/actions/runs/1/rerun"}, "HTTP 503", false},
+		{[]string{"run", "rerun", "1", "--repo", "o/r"}, "HTTP 503", false},
+		{[]string{"search", "prs", "--author=@me"}, "connection reset by peer", true},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.args, errors.New(tt.err)); got != tt.want {
+			t.Errorf("retryable(%q, %q) = %v, want %v", tt.args, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRunGhRetries(t *testing.T) {
+	calls := 0
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		calls++
+		if calls < 3 {
+			return fakeExecCommand("", "HTTP 502: Bad Gateway", 1)(command, args...)
+		}
+		return fakeExecCommand("ok", "", 0)(command, args...)
+	}
+	var waits []time.Duration
+	retrySleep = func(d time.Duration) { waits = append(waits, d) }
+	retryPolicy = retrier{attempts: 3, backoff: time.Second}
+	t.Cleanup(func() {
+		execCommand = exec.Command
+		retrySleep = time.Sleep
+		retryPolicy = retrier{attempts: 1}
+		noteRetry("")
+	})
+
+	out, err := runGh(nil, "pr", "view", "1", "--repo", "o/r")
+	if err != nil || string(out) != "ok" || calls != 3 || len(waits) != 2 {
+		t.Fatalf("runGh = %q, %v after %d calls, waits %v", out, err, calls, waits)
+	}
+	if got := retryBanner(time.Now()); got != "gh failed, retrying (2 of 2): HTTP 502: Bad Gateway" {
+		t.Errorf("banner = %q", got)
+	}
+	if retryBanner(time.Now().Add(retryNoteTTL+time.Second)) != "" {
+		t.Error("banner outlived retryNoteTTL")
+	}
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 100, 20
+	m.prData = &PRData{}
+	if !strings.Contains(m.View(), "gh failed, retrying (2 of 2)") {
+		t.Errorf("status line:\n%s", m.View())
+	}
+
+	// Out of attempts: the last error is returned
+	calls = -10
+	if _, err := runGh(nil, "pr", "view", "1", "--repo", "o/r"); err == nil || calls != -7 {
+		t.Errorf("gave up with %v after %d calls", err, calls+10)
+	}
+	// Writes aren't retried
+	calls = -10
+	if _, err := runGh(nil, "run", "rerun", "1", "--repo", "o/r"); err == nil || calls != -9 {
+		t.Errorf("rerun retried: %d calls", calls+10)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// reviewThread is an unresolved review conversation on a PR.
+type reviewThread struct {
+	Path    string
+	Line    int
+	Author  string
+	Snippet string // first line of the thread's first comment
+}
+
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved
+          path
+          line
+          originalLine
+          comments(first: 1) { nodes { body author { login } } }
+        }
+      }
+    }
+  }
+}`
+
+// fetchReviewThreads returns the PR's unresolved review threads.
+func fetchReviewThreads(acct *Account, repo, prNumber string) ([]reviewThread, error) {
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql",
+		"-f", "query="+reviewThreadsQuery,
+		"-F", "owner="+owner,
+		"-F", "name="+name,
+		"-F", "number="+prNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							IsResolved   bool   `json:"isResolved"`
+							Path         string `json:"path"`
+							Line         int    `json:"line"`
+							OriginalLine int    `json:"originalLine"`
+							Comments     struct {
+								Nodes []struct {
+									Body   string `json:"body"`
+									Author struct {
+										Login string `json:"login"`
+									} `json:"author"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse review threads: %w", err)
+	}
+
+	threads := []reviewThread{}
+	for _, n := range resp.Data.Repository.PullRequest.ReviewThreads.Nodes {
+		if n.IsResolved {
+			continue
+		}
+		t := reviewThread{Path: n.Path, Line: n.Line}
+		if t.Line == 0 {
+			t.Line = n.OriginalLine
+		}
+		if len(n.Comments.Nodes) > 0 {
+			c := n.Comments.Nodes[0]
+			t.Author = c.Author.Login
+			t.Snippet, _, _ = strings.Cut(strings.TrimSpace(c.Body), "\n")
+		}
+		threads = append(threads, t)
+	}
+	return threads, nil
+}
+
+// renderReviewThreads lists unresolved threads as "path:line  @author: text".
+func renderReviewThreads(threads []reviewThread) []string {
+	lines := make([]string, 0, len(threads))
+	for _, t := range threads {
+		loc := t.Path
+		if t.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", t.Path, t.Line)
+		}
+		line := styleRepo.Render(loc)
+		if t.Author != "" {
+			line += "  " + stylePRNumber.Render("@"+t.Author) + ":"
+		}
+		lines = append(lines, line+" "+t.Snippet)
+	}
+	return lines
+}
+
+// reviewSummary formats the review decision and unresolved thread count for
+// the header, e.g. "Review: APPROVED (2 unresolved)".
+func reviewSummary(decision string, threads []reviewThread) string {
+	var s string
+	if decision != "" {
+		s = "Review: " + decision
+	}
+	if threads == nil {
+		return s
+	}
+	if n := len(threads); n > 0 {
+		if s == "" {
+			s = "Review:"
+		}
+		s += fmt.Sprintf(" (%d unresolved)", n)
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFetchReviewThreads(t *testing.T) {
+	t.Run("keeps only unresolved threads", func(t *testing.T) {
+		json := `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[
+			{"isResolved":false,"path":"main.go","line":42,"comments":{"nodes":[{"body":"Can this be nil?\nmore","author":{"login":"alice"}}]}},
+			{"isResolved":true,"path":"ui.go","line":7,"comments":{"nodes":[{"body":"done","author":{"login":"bob"}}]}},
+			{"isResolved":false,"path":"gh.go","line":0,"originalLine":9,"comments":{"nodes":[]}}
+		]}}}}}`
+		execCommand = fakeExecByArgs(map[string]string{"reviewThreads": json})
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		threads, err := fetchReviewThreads(nil, "o/r", "1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(threads) != 2 {
+			t.Fatalf("got %d threads, want 2", len(threads))
+		}
+		if threads[0].Snippet != "Can this be nil?" || threads[0].Author != "alice" {
+			t.Errorf("threads[0] = %+v, want first line of alice's comment", threads[0])
+		}
+		if threads[1].Line != 9 {
+			t.Errorf("threads[1].Line = %d, want originalLine 9 for outdated thread", threads[1].Line)
+		}
+	})
+
+	t.Run("no threads is empty, not nil", func(t *testing.T) {
+		execCommand = fakeExecCommand(`{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[]}}}}}`, "", 0)
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		threads, err := fetchReviewThreads(nil, "o/r", "1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if threads == nil || len(threads) != 0 {
+			t.Errorf("threads = %#v, want empty non-nil slice", threads)
+		}
+	})
+}
+
+func TestReviewSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision string
+		threads  []reviewThread
+		want     string
+	}{
+		{"nothing known", "", nil, ""},
+		{"decision only", "APPROVED", nil, "Review: APPROVED"},
+		{"all resolved", "APPROVED", []reviewThread{}, "Review: APPROVED"},
+		{"unresolved", "APPROVED", make([]reviewThread, 4), "Review: APPROVED (4 unresolved)"},
+		{"unresolved without decision", "", make([]reviewThread, 1), "Review: (1 unresolved)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reviewSummary(tt.decision, tt.threads); got != tt.want {
+				t.Errorf("reviewSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewThreadsOverlay(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width = 120
+	m.height = 30
+	m.prData = &PRData{HeadRefName: "feat", ReviewDecision: "APPROVED"}
+
+	updated, _ := m.Update(reviewThreadsMsg{threads: []reviewThread{
+		{Path: "main.go", Line: 42, Author: "alice", Snippet: "Can this be nil?"},
+	}})
+	m = updated.(model)
+	if !strings.Contains(m.View(), "Review: APPROVED (1 unresolved)") {
+		t.Error("header should show unresolved count next to review decision")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	m = updated.(model)
+	if m.overlay != overlayThreads || cmd == nil {
+		t.Fatalf("u should open the threads overlay and refetch (overlay=%v)", m.overlay)
+	}
+	out := m.View()
+	if !strings.Contains(out, "main.go:42") || !strings.Contains(out, "Can this be nil?") {
+		t.Errorf("overlay should list the thread, got %q", out)
+	}
+}
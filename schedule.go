@@ -0,0 +1,133 @@
+package main
+
+import (
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+const (
+	// defaultBackgroundInterval is how often dashboard PRs other than the
+	// selected one are polled when the config doesn't say otherwise.
+	defaultBackgroundInterval = 60 * time.Second
+	// pollJitter spreads each poll by up to ±10% of its interval.
+	pollJitter = 0.1
+	// initialSpread staggers the first poll of newly added PRs.
+	initialSpread = time.Second
+)
+
+// pollScheduler decides when each dashboard PR is fetched next. The active
+// PR is polled every fast interval and the rest every slow interval, unless
+// the config overrides a PR's interval. Every poll is jittered so that PRs
+// drift apart instead of spawning gh all at once on the same tick.
+type pollScheduler struct {
+	fast      time.Duration
+	slow      time.Duration
+	overrides map[string]time.Duration // keyed by prKey
+	next      map[string]time.Time
+	inflight  map[string]bool
+	rand      func() float64
+}
+
+func newPollScheduler(fast, slow time.Duration, overrides map[string]time.Duration) *pollScheduler {
+	if slow < fast {
+		slow = fast
+	}
+	return &pollScheduler{
+		fast:      fast,
+		slow:      slow,
+		overrides: overrides,
+		next:      map[string]time.Time{},
+		inflight:  map[string]bool{},
+		rand:      rand.Float64,
+	}
+}
+
+// setFast changes the active PR's interval, raising slow to match if needed.
+func (s *pollScheduler) setFast(d time.Duration) {
+	s.fast = d
+	if s.slow < d {
+		s.slow = d
+	}
+}
+
+// interval returns the polling interval for key.
+func (s *pollScheduler) interval(key string, active bool) time.Duration {
+	if d, ok := s.overrides[key]; ok && d > 0 {
+		return d
+	}
+	if active {
+		return s.fast
+	}
+	return s.slow
+}
+
+func (s *pollScheduler) jittered(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + pollJitter*(2*s.rand()-1)))
+}
+
+// sync makes the scheduled set match keys. New keys are due within
+// initialSpread of now; keys no longer listed are forgotten.
+func (s *pollScheduler) sync(keys []string, now time.Time) {
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		seen[k] = true
+		if _, ok := s.next[k]; !ok {
+			s.next[k] = now.Add(time.Duration(s.rand() * float64(initialSpread)))
+		}
+	}
+	for k := range s.next {
+		if !seen[k] {
+			delete(s.next, k)
+			delete(s.inflight, k)
+		}
+	}
+}
+
+// due returns the keys whose poll time has come and marks them in flight,
+// active first. The active PR is also due when its next poll was scheduled
+// at the slow rate and is further away than its own interval.
+func (s *pollScheduler) due(now time.Time, active string) []string {
+	var keys []string
+	for k, at := range s.next {
+		if s.inflight[k] {
+			continue
+		}
+		if !at.After(now) || (k == active && at.Sub(now) > s.interval(k, true)) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == active) != (keys[j] == active) {
+			return keys[i] == active
+		}
+		return s.next[keys[i]].Before(s.next[keys[j]])
+	})
+	for _, k := range keys {
+		s.inflight[k] = true
+	}
+	return keys
+}
+
+// done records that key was fetched at now and schedules its next poll.
+func (s *pollScheduler) done(key string, now time.Time, active bool) {
+	delete(s.inflight, key)
+	if _, ok := s.next[key]; !ok {
+		return
+	}
+	s.next[key] = now.Add(s.jittered(s.interval(key, active)))
+}
+
+// poke makes key due immediately.
+func (s *pollScheduler) poke(key string, now time.Time) {
+	if _, ok := s.next[key]; ok {
+		s.next[key] = now
+	}
+}
+
+// pokeAll makes every key due immediately.
+func (s *pollScheduler) pokeAll(now time.Time) {
+	for k := range s.next {
+		s.next[k] = now
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPollScheduler(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := newPollScheduler(5*time.Second, time.Minute, map[string]time.Duration{"o/r#3": 20 * time.Second})
+	s.rand = func() float64 { return 0.5 } // no jitter, half of initialSpread
+
+	s.sync([]string{"o/r#1", "o/r#2", "o/r#3"}, now)
+	if got := s.due(now, "o/r#1"); len(got) != 0 {
+		t.Errorf("new PRs should be staggered, got %v due immediately", got)
+	}
+	due := s.due(now.Add(initialSpread), "o/r#2")
+	if len(due) != 3 || due[0] != "o/r#2" {
+		t.Fatalf("due = %v, want all three with active first", due)
+	}
+	if got := s.due(now.Add(initialSpread), "o/r#2"); len(got) != 0 {
+		t.Errorf("in-flight PRs should not be due again, got %v", got)
+	}
+
+	at := now.Add(2 * time.Second)
+	s.done("o/r#1", at, false)
+	s.done("o/r#2", at, true)
+	s.done("o/r#3", at, false)
+	tests := []struct {
+		key  string
+		want time.Duration
+	}{
+		{"o/r#1", time.Minute},
+		{"o/r#2", 5 * time.Second},
+		{"o/r#3", 20 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := s.next[tt.key].Sub(at); got != tt.want {
+			t.Errorf("%s next poll in %s, want %s", tt.key, got, tt.want)
+		}
+	}
+
+	// Moving the cursor to a slow PR makes it due right away.
+	if got := s.due(at.Add(time.Second), "o/r#1"); !slices.Equal(got, []string{"o/r#1"}) {
+		t.Errorf("newly active PR: due = %v, want [o/r#1]", got)
+	}
+
+	s.sync([]string{"o/r#2"}, at)
+	if _, ok := s.next["o/r#1"]; ok {
+		t.Error("sync should drop PRs no longer listed")
+	}
+}
+
+func TestPollSchedulerJitter(t *testing.T) {
+	s := newPollScheduler(10*time.Second, time.Minute, nil)
+	for _, r := range []float64{0, 1} {
+		s.rand = func() float64 { return r }
+		got := s.jittered(10 * time.Second)
+		if got < 9*time.Second || got > 11*time.Second {
+			t.Errorf("jittered(10s) with rand %v = %s, want within ±10%%", r, got)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// securityAlert is a code scanning alert or a vulnerable dependency that
+// the PR introduces.
+type securityAlert struct {
+	Severity string // critical, high, medium, low, or a rule level (error, warning, note)
+	Title    string
+	Where    string // path:line, or package@version (ecosystem)
+}
+
+// securityReport is what the security overlay shows. Each source fails
+// independently: code scanning and dependency review need GitHub Advanced
+// Security or a public repo, so either may be unavailable.
+type securityReport struct {
+	sha     string // head commit the report was built for
+	code    []securityAlert
+	codeErr error
+	deps    []securityAlert
+	depsErr error
+}
+
+type securityMsg struct {
+	report *securityReport
+}
+
+// severityRank orders severities most severe first.
+func severityRank(s string) int {
+	switch s {
+	case "critical":
+		return 0
+	case "high", "error":
+		return 1
+	case "medium", "warning":
+		return 2
+	case "low", "note":
+		return 3
+	}
+	return 4
+}
+
+// normalizeSeverity lower-cases a severity and maps Dependabot's "moderate"
+// to "medium" so both sources count alike.
+func normalizeSeverity(s string) string {
+	s = strings.ToLower(s)
+	if s == "moderate" {
+		return "medium"
+	}
+	return s
+}
+
+func sortAlerts(alerts []securityAlert) {
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return severityRank(alerts[i].Severity) < severityRank(alerts[j].Severity)
+	})
+}
+
+type codeScanningAlert struct {
+	Number int `json:"number"`
+	Rule   struct {
+		ID                    string `json:"id"`
+		Severity              string `json:"severity"`
+		SecuritySeverityLevel string `json:"security_severity_level"`
+		Description           string `json:"description"`
+	} `json:"rule"`
+	MostRecentInstance struct {
+		Location struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+		} `json:"location"`
+	} `json:"most_recent_instance"`
+}
+
+func fetchCodeScanningAlerts(acct *Account, repo, ref string) ([]codeScanningAlert, error) {
+	q := url.Values{"ref": {ref}, "state": {"open"}, "per_page": {"100"}}
+	out, err := ghAPI(acct, repo, "repos/{repo}/code-scanning/alerts?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	var alerts []codeScanningAlert
+	if err := json.Unmarshal(out, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to parse code scanning alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// fetchNewCodeScanningAlerts returns the open alerts on the PR's merge ref
+// that the base branch doesn't have.
+func fetchNewCodeScanningAlerts(acct *Account, repo, prNumber, base string) ([]securityAlert, error) {
+	onPR, err := fetchCodeScanningAlerts(acct, repo, "refs/pull/"+prNumber+"/merge")
+	if err != nil {
+		return nil, err
+	}
+	onBase, err := fetchCodeScanningAlerts(acct, repo, "refs/heads/"+base)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[int]bool{}
+	for _, a := range onBase {
+		existing[a.Number] = true
+	}
+	alerts := []securityAlert{}
+	for _, a := range onPR {
+		if existing[a.Number] {
+			continue
+		}
+		severity := a.Rule.SecuritySeverityLevel
+		if severity == "" {
+			severity = a.Rule.Severity
+		}
+		where := a.MostRecentInstance.Location.Path
+		if line := a.MostRecentInstance.Location.StartLine; line > 0 {
+			where = fmt.Sprintf("%s:%d", where, line)
+		}
+		title := a.Rule.Description
+		if title == "" {
+			title = a.Rule.ID
+		}
+		alerts = append(alerts, securityAlert{Severity: normalizeSeverity(severity), Title: title, Where: where})
+	}
+	sortAlerts(alerts)
+	return alerts, nil
+}
+
+// fetchVulnerableDependencies uses the dependency review API to find
+// dependencies the PR adds that have known vulnerabilities.
+func fetchVulnerableDependencies(acct *Account, repo, base, head string) ([]securityAlert, error) {
+	out, err := ghAPI(acct, repo, "repos/{repo}/dependency-graph/compare/"+base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+	var changes []struct {
+		ChangeType      string `json:"change_type"`
+		Ecosystem       string `json:"ecosystem"`
+		Name            string `json:"name"`
+		Version         string `json:"version"`
+		Vulnerabilities []struct {
+			Severity string `json:"severity"`
+			GHSA     string `json:"advisory_ghsa_id"`
+			Summary  string `json:"advisory_summary"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(out, &changes); err != nil {
+		return nil, fmt.Errorf("failed to parse dependency review: %w", err)
+	}
+	alerts := []securityAlert{}
+	for _, c := range changes {
+		if c.ChangeType != "added" {
+			continue
+		}
+		for _, v := range c.Vulnerabilities {
+			alerts = append(alerts, securityAlert{
+				Severity: normalizeSeverity(v.Severity),
+				Title:    v.GHSA + " " + v.Summary,
+				Where:    fmt.Sprintf("%s@%s (%s)", c.Name, c.Version, c.Ecosystem),
+			})
+		}
+	}
+	sortAlerts(alerts)
+	return alerts, nil
+}
+
+// fetchSecurityReport gathers both sources for the PR's head commit.
+func fetchSecurityReport(acct *Account, repo, prNumber, base, head string) *securityReport {
+	r := &securityReport{sha: head}
+	r.code, r.codeErr = fetchNewCodeScanningAlerts(acct, repo, prNumber, base)
+	r.deps, r.depsErr = fetchVulnerableDependencies(acct, repo, base, head)
+	return r
+}
+
+// severityCounts summarizes alerts as "1 critical, 2 high".
+func severityCounts(alerts []securityAlert) string {
+	counts := map[string]int{}
+	var order []string
+	for _, a := range alerts {
+		if counts[a.Severity] == 0 {
+			order = append(order, a.Severity)
+		}
+		counts[a.Severity]++
+	}
+	parts := make([]string, 0, len(order))
+	for _, s := range order {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func severityStyle(severity string) string {
+	text := fmt.Sprintf("%-9s", strings.ToUpper(severity))
+	switch severityRank(severity) {
+	case 0, 1:
+		return styleFail.Render(text)
+	case 2:
+		return styleRunning.Render(text)
+	}
+	return styleDim.Render(text)
+}
+
+// renderSecurityReport lists the new alerts under a severity summary.
+func renderSecurityReport(r *securityReport) []string {
+	all := append(append([]securityAlert{}, r.code...), r.deps...)
+	sortAlerts(all)
+	var lines []string
+	switch {
+	case len(all) > 0:
+		lines = append(lines, styleFail.Render("New alerts: "+severityCounts(all)))
+	case r.codeErr == nil && r.depsErr == nil:
+		lines = append(lines, stylePass.Render("No new alerts."))
+	}
+	section := func(title string, alerts []securityAlert, err error) {
+		lines = append(lines, "", styleBold.Render(title))
+		switch {
+		case err != nil:
+			lines = append(lines, styleDim.Render(fmt.Sprintf("  unavailable: %s", err)))
+		case len(alerts) == 0:
+			lines = append(lines, styleDim.Render("  none"))
+		}
+		for _, a := range alerts {
+			lines = append(lines, "  "+severityStyle(a.Severity)+" "+styleRepo.Render(a.Where)+"  "+a.Title)
+		}
+	}
+	section("Code scanning", r.code, r.codeErr)
+	section("Vulnerable dependencies added", r.deps, r.depsErr)
+	return lines
+}
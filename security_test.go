@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	prAlertsJSON = `[
+  {"number": 3, "rule": {"id": "go/sql-injection", "severity": "error", "security_severity_level": "high", "description": "Database query built from user-controlled sources"},
+   "most_recent_instance": {"location": {"path": "db/query.go", "start_line": 42}}},
+  {"number": 1, "rule": {"id": "go/unused", "severity": "note"}, "most_recent_instance": {"location": {"path": "old.go"}}}
+]`
+	baseAlertsJSON = `[{"number": 1, "rule": {"id": "go/unused", "severity": "note"}}]`
+	depReviewJSON  = `[
+  {"change_type": "added", "ecosystem": "npm", "name": "lodash", "version": "4.17.15",
+   "vulnerabilities": [{"severity": "critical", "advisory_ghsa_id": "GHSA-p6mc-m468-83gw", "advisory_summary": "Prototype Pollution"}]},
+  {"change_type": "removed", "ecosystem": "npm", "name": "minimist", "version": "0.0.8",
+   "vulnerabilities": [{"severity": "moderate", "advisory_ghsa_id": "GHSA-x", "advisory_summary": "old"}]}
+]`
+)
+
+// ---------------------------------------------------------------------------
+// fetchSecurityReport
+// ---------------------------------------------------------------------------
+
+func TestFetchSecurityReport(t *testing.T) {
+	t.Run("new alerts only", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{
+			"ref=refs%2Fpull%2F7%2Fmerge":         prAlertsJSON,
+			"ref=refs%2Fheads%2Fmain":             baseAlertsJSON,
+			"dependency-graph/compare/main...abc": depReviewJSON,
+		})
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		r := fetchSecurityReport(nil, "o/r", "7", "main", "abc")
+		if r.codeErr != nil || r.depsErr != nil {
+			t.Fatalf("errors: %v, %v", r.codeErr, r.depsErr)
+		}
+		if len(r.code) != 1 || r.code[0] != (securityAlert{Severity: "high",
+			Title: "Database query built from user-controlled sources", Where: "db/query.go:42"}) {
+			t.Errorf("code = %+v", r.code)
+		}
+		if len(r.deps) != 1 || r.deps[0] != (securityAlert{Severity: "critical",
+			Title: "GHSA-p6mc-m468-83gw Prototype Pollution", Where: "lodash@4.17.15 (npm)"}) {
+			t.Errorf("deps = %+v", r.deps)
+		}
+
+		lines := renderSecurityReport(r)
+		if lines[0] != "New alerts: 1 critical, 1 high" {
+			t.Errorf("summary = %q", lines[0])
+		}
+	})
+
+	t.Run("sources fail independently", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{"dependency-graph/compare": "[]"})
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		r := fetchSecurityReport(nil, "o/r", "7", "main", "abc")
+		if r.codeErr == nil || r.depsErr != nil {
+			t.Fatalf("errors: %v, %v", r.codeErr, r.depsErr)
+		}
+		got := strings.Join(renderSecurityReport(r), "\n")
+		if !strings.Contains(got, "unavailable:") || strings.Contains(got, "No new alerts") {
+			t.Errorf("report:\n%s", got)
+		}
+	})
+}
+
+func TestSeverityCounts(t *testing.T) {
+	alerts := []securityAlert{{Severity: "low"}, {Severity: "critical"}, {Severity: "medium"}, {Severity: "critical"}}
+	sortAlerts(alerts)
+	if got := severityCounts(alerts); got != "2 critical, 1 medium, 1 low" {
+		t.Errorf("severityCounts() = %q", got)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Security overlay
+// ---------------------------------------------------------------------------
+
+func TestSecurityOverlay(t *testing.T) {
+	m := newModel("o/r", "7", 5*time.Second)
+	m.width, m.height = 100, 20
+	m.prData = &PRData{Title: "t", BaseRefName: "main", HeadSHA: "abc"}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	m = updated.(model)
+	if m.overlay != overlaySecurity || cmd == nil {
+		t.Fatalf("overlay = %v, cmd = %v", m.overlay, cmd)
+	}
+	if !strings.Contains(m.View(), "Loading code scanning") {
+		t.Error("missing loading message")
+	}
+
+	updated, _ = m.Update(securityMsg{report: &securityReport{sha: "abc"}})
+	m = updated.(model)
+	if !strings.Contains(m.View(), "No new alerts.") {
+		t.Errorf("view:\n%s", m.View())
+	}
+	if m.overlayCmd() != nil {
+		t.Error("refresh refetches alerts for the same commit")
+	}
+
+	updated, _ = m.Update(securityMsg{report: &securityReport{sha: "old", code: []securityAlert{{Severity: "high"}}}})
+	if updated.(model).security.sha != "abc" {
+		t.Error("report for another commit replaced the current one")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The details a picker row can show after its CI summary, chosen with
+// [selector] columns.
+const (
+	selectDraft  = "draft"
+	selectAuthor = "author"
+	selectReview = "review"
+	selectBase   = "base"
+)
+
+var defaultSelectColumns = []string{selectDraft, selectAuthor, selectReview, selectBase}
+
+// parseSelectColumns checks [selector] columns. Leaving it out shows every
+// detail; an empty list shows none.
+func parseSelectColumns(names []string) ([]string, error) {
+	if names == nil {
+		return defaultSelectColumns, nil
+	}
+	seen := map[string]bool{}
+	for _, n := range names {
+		if !slices.Contains(defaultSelectColumns, n) {
+			return nil, fmt.Errorf("selector.columns: unknown column %q (want %s)", n, strings.Join(defaultSelectColumns, ", "))
+		}
+		if seen[n] {
+			return nil, fmt.Errorf("selector.columns: %q listed twice", n)
+		}
+		seen[n] = true
+	}
+	return names, nil
+}
+
+// selectRowDetails are the configured details of a picker row, rendered,
+// in order. Details the PR doesn't have (no review required, author not
+// fetched yet) are left out.
+func (m model) selectRowDetails(pr PRSummary) []string {
+	var details []string
+	for _, col := range m.selectColumns {
+		var d string
+		switch col {
+		case selectDraft:
+			if pr.Draft {
+				d = styleDim.Render("draft")
+			}
+		case selectAuthor:
+			if pr.Author != "" {
+				d = styleDim.Render("@" + strings.TrimPrefix(pr.Author, "app/"))
+			}
+		case selectReview:
+			d = reviewBadge(pr.ReviewDecision)
+		case selectBase:
+			if pr.BaseRef != "" {
+				d = styleDim.Render("→ " + pr.BaseRef)
+			}
+		}
+		if d != "" {
+			details = append(details, d)
+		}
+	}
+	return details
+}
+
+// reviewBadge is a picker row's review decision.
+func reviewBadge(decision string) string {
+	switch decision {
+	case "APPROVED":
+		return stylePass.Render("approved")
+	case "CHANGES_REQUESTED":
+		return styleFail.Render("changes requested")
+	case "REVIEW_REQUIRED":
+		return styleRunning.Render("review required")
+	}
+	return ""
+}
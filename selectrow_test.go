@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseSelectColumns(t *testing.T) {
+	if got, err := parseSelectColumns(nil); err != nil || len(got) != len(defaultSelectColumns) {
+		t.Errorf("default = %v, %v; want every column", got, err)
+	}
+	if got, err := parseSelectColumns([]string{}); err != nil || len(got) != 0 {
+		t.Errorf("empty = %v, %v; want none", got, err)
+	}
+	if got, err := parseSelectColumns([]string{"review", "author"}); err != nil || strings.Join(got, ",") != "review,author" {
+		t.Errorf("got %v, %v", got, err)
+	}
+	for _, bad := range [][]string{{"labels"}, {"base", "base"}} {
+		if _, err := parseSelectColumns(bad); err == nil {
+			t.Errorf("%v: want an error", bad)
+		}
+	}
+}
+
+func TestSelectRowDetails(t *testing.T) {
+	m := newSelectModel(5 * time.Second)
+	m.width, m.height = 140, 20
+	m.loading = false
+	m.prs = []PRSummary{
+		{Repo: "o/r", Number: 1, Title: "Fix", Draft: true, Author: "alice", ReviewDecision: "CHANGES_REQUESTED", BaseRef: "release-2.0"},
+		{Repo: "o/r", Number: 2, Title: "Bump", Author: "app/dependabot", ReviewDecision: "APPROVED", BaseRef: "main"},
+	}
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"o/r #1  draft  @alice  changes requested  → release-2.0", "o/r #2  @dependabot  approved  → main"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+
+	m.selectColumns = []string{selectReview}
+	view = ansi.Strip(m.View())
+	if strings.Contains(view, "@alice") || strings.Contains(view, "draft") || !strings.Contains(view, "o/r #1  changes requested") {
+		t.Errorf("columns = review only:\n%s", view)
+	}
+}
+
+func TestApplyCIStatesFillsDetails(t *testing.T) {
+	m := newSelectModel(5 * time.Second)
+	m.prs = []PRSummary{{Repo: "o/r", Number: 1, Author: "kept"}, {Repo: "o/r", Number: 2}}
+	m = m.applyCIStates(map[string]prCI{
+		"o/r#1": {ReviewDecision: "REVIEW_REQUIRED", BaseRef: "main"},
+		"o/r#2": {Author: "bob"},
+	})
+	if pr := m.prs[0]; pr.Author != "kept" || pr.ReviewDecision != "REVIEW_REQUIRED" || pr.BaseRef != "main" {
+		t.Errorf("prs[0] = %+v", pr)
+	}
+	if m.prs[1].Author != "bob" {
+		t.Errorf("prs[1].Author = %q, want bob", m.prs[1].Author)
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// prtopRepo is where prtop's own releases are published.
+const prtopRepo = "eadamsatx/prtop"
+
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func fetchLatestRelease(acct *Account) (*release, error) {
+	out, err := ghAPI(acct, prtopRepo, "repos/{repo}/releases/latest")
+	if err != nil {
+		return nil, err
+	}
+	var r release
+	if err := json.Unmarshal(out, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &r, nil
+}
+
+func downloadAsset(acct *Account, a releaseAsset) ([]byte, error) {
+	return ghAPI(acct, prtopRepo, fmt.Sprintf("repos/{repo}/releases/assets/%d", a.ID),
+		"-H", "Accept: application/octet-stream")
+}
+
+// archAliases are the other names release archives use for a GOARCH.
+// x86_64 is rewritten to amd64 before names are split on underscores.
+var archAliases = map[string][]string{
+	"386":   {"386", "i386"},
+	"arm64": {"arm64", "aarch64"},
+}
+
+// archiveFor finds the release archive for goos/goarch among goreleaser's
+// default names, prtop_1.2.0_linux_amd64.tar.gz and the like.
+func (r *release) archiveFor(goos, goarch string) (releaseAsset, bool) {
+	arches := archAliases[goarch]
+	if arches == nil {
+		arches = []string{goarch}
+	}
+	for _, a := range r.Assets {
+		name := strings.ToLower(a.Name)
+		base := strings.TrimSuffix(strings.TrimSuffix(name, ".tar.gz"), ".zip")
+		if base == name {
+			continue
+		}
+		base = strings.ReplaceAll(base, "x86_64", "amd64")
+		fields := strings.FieldsFunc(base, func(r rune) bool { return r == '_' || r == '-' })
+		hasOS, hasArch := false, false
+		for _, f := range fields {
+			hasOS = hasOS || f == goos
+			for _, arch := range arches {
+				hasArch = hasArch || f == arch
+			}
+		}
+		if hasOS && hasArch {
+			return a, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// checksumsAsset finds goreleaser's checksums.txt.
+func (r *release) checksumsAsset() (releaseAsset, bool) {
+	for _, a := range r.Assets {
+		if strings.HasSuffix(strings.ToLower(a.Name), "checksums.txt") {
+			return a, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// parseChecksums reads sha256sum output ("HASH  NAME" per line) into a map
+// from file name to hash.
+func parseChecksums(data []byte) map[string]string {
+	sums := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 {
+			sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	return sums
+}
+
+// extractBinary pulls the prtop executable out of a .tar.gz or .zip
+// release archive.
+func extractBinary(name string, data []byte) ([]byte, error) {
+	isBinary := func(p string) bool {
+		base := path.Base(p)
+		return base == "prtop" || base == "prtop.exe"
+	}
+	if strings.HasSuffix(strings.ToLower(name), ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, f := range zr.File {
+			if !isBinary(f.Name) {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s has no prtop executable", name)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s has no prtop executable", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if h.Typeflag == tar.TypeReg && isBinary(h.Name) {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// parseSemver parses "v1.2.3" (with or without the v, ignoring any
+// -prerelease or +build suffix).
+func parseSemver(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// newerVersion reports whether latest is a newer release than current. ok
+// is false when either isn't a release version (e.g. a dev build).
+func newerVersion(current, latest string) (newer, ok bool) {
+	c, ok1 := parseSemver(current)
+	l, ok2 := parseSemver(latest)
+	if !ok1 || !ok2 {
+		return false, false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i], true
+		}
+	}
+	return false, true
+}
+
+// managedInstall names the package manager that owns exe, if any; those
+// installs must be updated through it.
+func managedInstall(exe string) string {
+	switch {
+	case strings.Contains(exe, "/Cellar/"), strings.Contains(exe, "/homebrew/"), strings.Contains(exe, "/linuxbrew/"):
+		return "brew upgrade prtop"
+	case strings.HasPrefix(exe, "/usr/bin/"):
+		return "your system package manager"
+	}
+	return ""
+}
+
+// replaceExecutable swaps data in for the executable at exe. The new binary
+// is written next to it and renamed over it, so a failure leaves the old one
+// in place. Windows can't replace a running executable, so there it is
+// moved aside to exe.old first, and moved back if the new one can't take
+// its place.
+func replaceExecutable(exe string, data []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".prtop-update-*")
+	if err != nil {
+		return fmt.Errorf("can't write to %s: %w", filepath.Dir(exe), err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			if rerr := os.Rename(old, exe); rerr != nil {
+				return fmt.Errorf("%w; the old prtop is left at %s", err, old)
+			}
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmp.Name(), exe)
+}
+
+// selfUpdate brings the prtop at exe (version current) up to the latest
+// release, or with checkOnly just reports whether there is one.
+func selfUpdate(acct *Account, exe, current string, checkOnly bool, out io.Writer) error {
+	r, err := fetchLatestRelease(acct)
+	if err != nil {
+		return fmt.Errorf("can't look up the latest release: %w", err)
+	}
+	newer, ok := newerVersion(current, r.TagName)
+	switch {
+	case !ok && checkOnly:
+		fmt.Fprintf(out, "The latest release is %s (this is prtop %s)\n", r.TagName, current)
+		return nil
+	case !ok:
+		return fmt.Errorf("prtop %s is not a release build, so it won't be replaced; the latest release is %s", current, r.TagName)
+	case !newer:
+		fmt.Fprintf(out, "prtop %s is up to date\n", current)
+		return nil
+	case checkOnly:
+		fmt.Fprintf(out, "prtop %s is available (you have %s); run 'prtop self-update' to install it\n", r.TagName, current)
+		return nil
+	}
+	if how := managedInstall(exe); how != "" {
+		return fmt.Errorf("%s is managed by a package manager; update it with %s", exe, how)
+	}
+
+	archive, ok := r.archiveFor(runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return fmt.Errorf("release %s has no build for %s/%s", r.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sumsAsset, ok := r.checksumsAsset()
+	if !ok {
+		return fmt.Errorf("release %s has no checksums file", r.TagName)
+	}
+	sums, err := downloadAsset(acct, sumsAsset)
+	if err != nil {
+		return fmt.Errorf("can't download %s: %w", sumsAsset.Name, err)
+	}
+	want, ok := parseChecksums(sums)[archive.Name]
+	if !ok {
+		return fmt.Errorf("%s has no checksum for %s", sumsAsset.Name, archive.Name)
+	}
+	fmt.Fprintf(out, "Downloading %s...\n", archive.Name)
+	data, err := downloadAsset(acct, archive)
+	if err != nil {
+		return fmt.Errorf("can't download %s: %w", archive.Name, err)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != want {
+		return fmt.Errorf("checksum mismatch for %s; not updating", archive.Name)
+	}
+	bin, err := extractBinary(archive.Name, data)
+	if err != nil {
+		return err
+	}
+	if err := replaceExecutable(exe, bin); err != nil {
+		return fmt.Errorf("can't replace %s: %w", exe, err)
+	}
+	fmt.Fprintf(out, "Updated prtop %s -> %s\n", current, r.TagName)
+	return nil
+}
+
+func runSelfUpdate(s *session, args []string) (int, error) {
+	if len(args) > 0 {
+		return exitFailed, errors.New("self-update takes no arguments")
+	}
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		return exitFailed, fmt.Errorf("can't find the prtop executable: %w", err)
+	}
+	current, _, _ := buildInfo()
+	if err := selfUpdate(s.acct(prtopRepo), exe, current, s.opts.check, s.stdout); err != nil {
+		return exitFailed, err
+	}
+	return exitOK, nil
+}
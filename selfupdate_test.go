@@ -0,0 +1,264 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// ghFunc answers gh invocations with a function, for responses that can't
+// travel through fakeExecByArgs (binary release assets).
+type ghFunc func(args []string) ([]byte, error)
+
+func (f ghFunc) run(args []string) ([]byte, error) { return f(args) }
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// ---------------------------------------------------------------------------
+// Release assets
+// ---------------------------------------------------------------------------
+
+func TestArchiveFor(t *testing.T) {
+	r := &release{Assets: []releaseAsset{
+		{ID: 1, Name: "checksums.txt"},
+		{ID: 2, Name: "prtop_1.2.0_Darwin_x86_64.tar.gz"},
+		{ID: 3, Name: "prtop_1.2.0_linux_arm64.tar.gz"},
+		{ID: 4, Name: "prtop_1.2.0_linux_amd64.tar.gz.sbom.json"},
+		{ID: 5, Name: "prtop_1.2.0_linux_amd64.tar.gz"},
+		{ID: 6, Name: "prtop-1.2.0-windows-amd64.zip"},
+	}}
+	tests := []struct {
+		goos, goarch string
+		want         int64
+	}{
+		{"linux", "amd64", 5},
+		{"linux", "arm64", 3},
+		{"darwin", "amd64", 2},
+		{"windows", "amd64", 6},
+		{"darwin", "arm64", 0},
+		{"freebsd", "amd64", 0},
+	}
+	for _, tt := range tests {
+		a, ok := r.archiveFor(tt.goos, tt.goarch)
+		if a.ID != tt.want || ok != (tt.want != 0) {
+			t.Errorf("archiveFor(%s, %s) = %d, %v; want %d", tt.goos, tt.goarch, a.ID, ok, tt.want)
+		}
+	}
+	if a, ok := r.checksumsAsset(); !ok || a.ID != 1 {
+		t.Errorf("checksumsAsset = %v, %v", a, ok)
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sums := parseChecksums([]byte("ABC123  prtop_linux_amd64.tar.gz\ndef456 *prtop_windows_amd64.zip\n\nnonsense\n"))
+	if len(sums) != 2 || sums["prtop_linux_amd64.tar.gz"] != "abc123" || sums["prtop_windows_amd64.zip"] != "def456" {
+		t.Errorf("parseChecksums = %v", sums)
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	files := map[string]string{"README.md": "docs", "prtop_1.2.0/prtop": "new binary"}
+	for _, tt := range []struct {
+		name string
+		data []byte
+	}{
+		{"prtop.tar.gz", tarGz(t, files)},
+		{"prtop.zip", zipOf(t, files)},
+	} {
+		got, err := extractBinary(tt.name, tt.data)
+		if err != nil || string(got) != "new binary" {
+			t.Errorf("%s: extractBinary = %q, %v", tt.name, got, err)
+		}
+	}
+
+	if _, err := extractBinary("x.tar.gz", tarGz(t, map[string]string{"README.md": "docs"})); err == nil ||
+		!strings.Contains(err.Error(), "no prtop executable") {
+		t.Errorf("missing binary: err = %v", err)
+	}
+	if _, err := extractBinary("x.tar.gz", []byte("not gzip")); err == nil {
+		t.Error("corrupt archive: want an error")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Versions
+// ---------------------------------------------------------------------------
+
+func TestNewerVersion(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		newer, ok       bool
+	}{
+		{"v1.2.0", "v1.3.0", true, true},
+		{"v1.2.0", "v1.2.0", false, true},
+		{"1.10.0", "v1.9.9", false, true},
+		{"v1.2.3", "v2.0.0", true, true},
+		{"v1.2.0-rc.1", "v1.2.1", true, true},
+		{"dev", "v1.2.0", false, false},
+		{"v1.2", "v1.3.0", false, false},
+		{"v1.2.0", "latest", false, false},
+	}
+	for _, tt := range tests {
+		newer, ok := newerVersion(tt.current, tt.latest)
+		if newer != tt.newer || ok != tt.ok {
+			t.Errorf("newerVersion(%q, %q) = %v, %v; want %v, %v", tt.current, tt.latest, newer, ok, tt.newer, tt.ok)
+		}
+	}
+}
+
+func TestManagedInstall(t *testing.T) {
+	for exe, want := range map[string]string{
+		"/opt/homebrew/bin/prtop":                 "brew upgrade prtop",
+		"/usr/local/Cellar/prtop/1.2.0/bin/prtop": "brew upgrade prtop",
+		"/home/linuxbrew/.linuxbrew/bin/prtop":    "brew upgrade prtop",
+		"/usr/bin/prtop":                          "your system package manager",
+		"/usr/local/bin/prtop":                    "",
+		"/home/me/go/bin/prtop":                   "",
+	} {
+		if got := managedInstall(exe); got != want {
+			t.Errorf("managedInstall(%q) = %q, want %q", exe, got, want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// selfUpdate
+// ---------------------------------------------------------------------------
+
+// fakeRelease serves a v1.3.0 release with an archive for this platform
+// holding body, and a checksums file listing sum for it ("" for the real
+// sum). It records the assets downloaded.
+func fakeRelease(t *testing.T, body, sum string) *[]string {
+	t.Helper()
+	name := fmt.Sprintf("prtop_1.3.0_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	archive := tarGz(t, map[string]string{"prtop": body})
+	if sum == "" {
+		s := sha256.Sum256(archive)
+		sum = hex.EncodeToString(s[:])
+	}
+	rel, _ := json.Marshal(release{TagName: "v1.3.0", Assets: []releaseAsset{
+		{ID: 10, Name: "checksums.txt"},
+		{ID: 11, Name: name},
+		{ID: 12, Name: "prtop_1.3.0_plan9_mips.tar.gz"},
+	}})
+
+	var downloaded []string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		endpoint := args[len(args)-1]
+		switch endpoint {
+		case "repos/eadamsatx/prtop/releases/latest":
+			return rel, nil
+		case "repos/eadamsatx/prtop/releases/assets/10":
+			downloaded = append(downloaded, "checksums.txt")
+			return []byte(sum + "  " + name + "\n"), nil
+		case "repos/eadamsatx/prtop/releases/assets/11":
+			downloaded = append(downloaded, name)
+			return archive, nil
+		}
+		return nil, fmt.Errorf("unexpected gh %v", args)
+	})
+	t.Cleanup(func() { ghOverride = nil })
+	return &downloaded
+}
+
+func tempExecutable(t *testing.T) string {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "prtop")
+	if err := os.WriteFile(exe, []byte("old binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return exe
+}
+
+func TestSelfUpdate(t *testing.T) {
+	t.Run("updates", func(t *testing.T) {
+		downloaded := fakeRelease(t, "new binary", "")
+		exe := tempExecutable(t)
+		var out bytes.Buffer
+		if err := selfUpdate(nil, exe, "v1.2.0", false, &out); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(exe); string(got) != "new binary" {
+			t.Errorf("executable = %q", got)
+		}
+		if info, _ := os.Stat(exe); info.Mode().Perm()&0o111 == 0 {
+			t.Errorf("mode = %v, want executable", info.Mode())
+		}
+		if !strings.HasSuffix(out.String(), "Updated prtop v1.2.0 -> v1.3.0\n") {
+			t.Errorf("output = %q", out.String())
+		}
+		if len(*downloaded) != 2 {
+			t.Errorf("downloaded %v", *downloaded)
+		}
+		if entries, _ := os.ReadDir(filepath.Dir(exe)); len(entries) != 1 {
+			t.Errorf("left %d files behind", len(entries)-1)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		current   string
+		checkOnly bool
+		sum       string
+		exe       string
+		wantOut   string
+		wantErr   string
+	}{
+		{name: "up to date", current: "v1.3.0", wantOut: "prtop v1.3.0 is up to date\n"},
+		{name: "check", current: "v1.2.0", checkOnly: true,
+			wantOut: "prtop v1.3.0 is available (you have v1.2.0); run 'prtop self-update' to install it\n"},
+		{name: "check dev build", current: "dev", checkOnly: true, wantOut: "The latest release is v1.3.0 (this is prtop dev)\n"},
+		{name: "dev build", current: "dev", wantErr: "prtop dev is not a release build"},
+		{name: "homebrew", current: "v1.2.0", exe: "/opt/homebrew/bin/prtop", wantErr: "update it with brew upgrade prtop"},
+		{name: "checksum mismatch", current: "v1.2.0", sum: strings.Repeat("0", 64), wantErr: "checksum mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloaded := fakeRelease(t, "new binary", tt.sum)
+			exe := tempExecutable(t)
+			if tt.exe != "" {
+				exe = tt.exe
+			}
+			var out bytes.Buffer
+			err := selfUpdate(nil, exe, tt.current, tt.checkOnly, &out)
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+			if tt.wantOut != "" && out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if got, err := os.ReadFile(exe); err == nil && string(got) != "old binary" {
+				t.Errorf("executable replaced: %q", got)
+			}
+			if tt.sum == "" && len(*downloaded) != 0 {
+				t.Errorf("downloaded %v", *downloaded)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000 // gh failed
+)
+
+// rpcMaxLine bounds one request; normalize params can hold a whole PR.
+const rpcMaxLine = 16 << 20
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// rpcPRParams names a PR as the CLI takes it: a URL or owner/repo#123.
+type rpcPRParams struct {
+	PR string `json:"pr"`
+}
+
+type rpcRerunParams struct {
+	PR    string `json:"pr"`
+	Check string `json:"check"`
+	// Scope is "job" (the default), "failed" for the run's failed jobs or
+	// "run" for the whole workflow run, as in the command palette.
+	Scope string `json:"scope"`
+}
+
+// normalizeResult is what normalize returns: the checks of a `gh pr view
+// --json statusCheckRollup` payload as prtop sees them.
+type normalizeResult struct {
+	Status string        `json:"status"`
+	Checks []exportCheck `json:"checks"`
+}
+
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return &rpcError{rpcInvalidParams, "missing params"}
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpcError{rpcInvalidParams, fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+// rpcCall runs one method.
+func (s *session) rpcCall(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "version":
+		v, c, d := buildInfo()
+		return map[string]string{"version": v, "commit": c, "date": d}, nil
+
+	case "checks":
+		var p rpcPRParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		repo, prNumber, data, status, err := s.fetchChecks([]string{p.PR})
+		if err != nil {
+			return nil, err
+		}
+		return newExportPR(repo, prNumber, data, status), nil
+
+	case "normalize":
+		if len(params) == 0 {
+			return nil, &rpcError{rpcInvalidParams, "missing params"}
+		}
+		data, err := parsePRView(params)
+		if err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		status := rollupStatus(data.Checks, func(Check) bool { return false })
+		return normalizeResult{status, exportChecks(data.Checks)}, nil
+
+	case "rerun":
+		var p rpcRerunParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return struct{}{}, s.rerun(p)
+	}
+	return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("unknown method %q", method)}
+}
+
+// rerun reruns the Actions job behind a check, its run's failed jobs, or
+// the whole run.
+func (s *session) rerun(p rpcRerunParams) error {
+	if s.readOnly {
+		return errors.New("reruns are off in " + readOnlyReason)
+	}
+	repo, prNumber, err := s.prArgs([]string{p.PR})
+	if err != nil {
+		return &rpcError{rpcInvalidParams, err.Error()}
+	}
+	acct := s.acct(repo)
+	data, err := fetchPRData(acct, repo, prNumber)
+	if err != nil {
+		return err
+	}
+	var check *Check
+	for i := range data.Checks {
+		if data.Checks[i].Name == p.Check {
+			check = &data.Checks[i]
+		}
+	}
+	if check == nil {
+		return &rpcError{rpcInvalidParams, fmt.Sprintf("%s#%s has no check named %q", repo, prNumber, p.Check)}
+	}
+	runID, jobID := actionsRunID(check.DetailsURL)
+	if runID == "" {
+		return &rpcError{rpcInvalidParams, fmt.Sprintf("%s is not a GitHub Actions check", p.Check)}
+	}
+	switch p.Scope {
+	case "", "job":
+		if jobID == "" {
+			return &rpcError{rpcInvalidParams, fmt.Sprintf("%s has no job ID", p.Check)}
+		}
+		return rerunWorkflow(acct, repo, runID, jobID, false)
+	case "failed":
+		return rerunWorkflow(acct, repo, runID, "", true)
+	case "run":
+		return rerunWorkflow(acct, repo, runID, "", false)
+	}
+	return &rpcError{rpcInvalidParams, fmt.Sprintf("unknown scope %q (want job, failed or run)", p.Scope)}
+}
+
+// rpcHandler runs one JSON-RPC method. An *rpcError sets the error code;
+// any other error is reported as rpcServerError.
+type rpcHandler func(method string, params json.RawMessage) (any, error)
+
+// serveRPC answers newline-delimited JSON-RPC 2.0 requests from r on w
+// until r ends. Requests run concurrently, so a slow fetch doesn't hold up
+// the others; answers carry the request's id.
+func serveRPC(r io.Reader, w io.Writer, handle rpcHandler) error {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	reply := func(resp rpcResponse) {
+		resp.JSONRPC = "2.0"
+		if resp.ID == nil {
+			resp.ID = json.RawMessage("null")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(resp)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, rpcMaxLine)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			reply(rpcResponse{Error: &rpcError{rpcParseError, fmt.Sprintf("parse error: %v", err)}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			reply(rpcResponse{ID: req.ID, Error: &rpcError{rpcInvalidRequest, "invalid request"}})
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := handle(req.Method, req.Params)
+			if req.ID == nil {
+				return // a notification
+			}
+			resp := rpcResponse{ID: req.ID, Result: result}
+			if err != nil {
+				var rerr *rpcError
+				if !errors.As(err, &rerr) {
+					rerr = &rpcError{rpcServerError, err.Error()}
+				}
+				resp.Result, resp.Error = nil, rerr
+			}
+			reply(resp)
+		}()
+	}
+	return sc.Err()
+}
+
+func runServe(s *session, args []string) (int, error) {
+	if !s.opts.jsonRPC {
+		return exitFailed, errors.New("serve needs a protocol; only --json-rpc is supported")
+	}
+	if len(args) > 0 {
+		return exitFailed, errors.New("serve takes no arguments")
+	}
+	if err := serveRPC(s.stdin, s.stdout, s.rpcCall); err != nil {
+		return exitFailed, err
+	}
+	return exitOK, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rpcExchange serves requests (one per line) and returns the answers by id.
+func rpcExchange(t *testing.T, handle rpcHandler, requests ...string) map[string]rpcResponse {
+	t.Helper()
+	var out bytes.Buffer
+	if err := serveRPC(strings.NewReader(strings.Join(requests, "\n")+"\n"), &out, handle); err != nil {
+		t.Fatal(err)
+	}
+	answers := map[string]rpcResponse{}
+	sc := bufio.NewScanner(&out)
+	for sc.Scan() {
+		var resp struct {
+			rpcResponse
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid answer %q: %v", sc.Text(), err)
+		}
+		if resp.JSONRPC != "2.0" {
+			t.Errorf("answer %s has jsonrpc %q", sc.Text(), resp.JSONRPC)
+		}
+		resp.rpcResponse.Result = resp.Result
+		answers[string(resp.ID)] = resp.rpcResponse
+	}
+	return answers
+}
+
+func TestServeRPC(t *testing.T) {
+	var mu sync.Mutex
+	var reruns [][]string
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		switch {
+		case args[0] == "pr" && args[2] == "7":
+			return []byte(streamPR("abc",
+				`{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"FAILURE","detailsUrl":"https://github.com/o/r/actions/runs/11/job/22"}`,
+				`{"__typename":"StatusContext","context":"ci/legacy","state":"SUCCESS","targetUrl":"https://ci.example.com/1"}`)), nil
+		case args[0] == "pr":
+			return nil, errors.New("GraphQL: Could not resolve to a PullRequest")
+		case args[0] == "run" && args[1] == "rerun":
+			mu.Lock()
+			reruns = append(reruns, args)
+			mu.Unlock()
+			return nil, nil
+		}
+		t.Errorf("unexpected gh %v", args)
+		return nil, nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+
+	s, _, _ := testSession(t)
+	answers := rpcExchange(t, s.rpcCall,
+		`{"jsonrpc":"2.0","id":1,"method":"checks","params":{"pr":"o/r#7"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"normalize","params":{"statusCheckRollup":[{"__typename":"CheckRun","name":"lint","status":"IN_PROGRESS"}]}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"rerun","params":{"pr":"https://github.com/o/r/pull/7","check":"build"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"rerun","params":{"pr":"o/r#7","check":"build","scope":"failed"}}`,
+		`{"jsonrpc":"2.0","id":"v","method":"version"}`,
+		`{"jsonrpc":"2.0","method":"checks","params":{"pr":"o/r#7"}}`,
+		``,
+		`{"jsonrpc":"2.0","id":5,"method":"rerun","params":{"pr":"o/r#7","check":"ci/legacy"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"rerun","params":{"pr":"o/r#7","check":"nope"}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"checks","params":{"pr":"o/r#8"}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"checks"}`,
+		`{"jsonrpc":"2.0","id":9,"method":"frobnicate"}`,
+		`{"id":10,"method":"checks"}`,
+		`{not json`,
+	)
+	if len(answers) != 12 {
+		t.Errorf("got %d answers, want 12 (none for the notification)", len(answers))
+	}
+
+	var pr exportPR
+	if err := json.Unmarshal(answers["1"].Result.(json.RawMessage), &pr); err != nil ||
+		pr.Repo != "o/r" || pr.Number != 7 || pr.Status != rollupFailure || len(pr.Checks) != 2 {
+		t.Errorf("checks = %+v, %v", pr, err)
+	}
+	var norm normalizeResult
+	if err := json.Unmarshal(answers["2"].Result.(json.RawMessage), &norm); err != nil ||
+		norm.Status != rollupPending || len(norm.Checks) != 1 || norm.Checks[0].Status != "running" {
+		t.Errorf("normalize = %+v, %v", norm, err)
+	}
+	for _, id := range []string{"3", "4"} {
+		if a := answers[id]; a.Error != nil {
+			t.Errorf("rerun %s: %+v", id, a.Error)
+		}
+	}
+	got := map[string]bool{}
+	for _, args := range reruns {
+		got[strings.Join(args, " ")] = true
+	}
+	if !got["run rerun --job 22 --repo o/r"] || !got["run rerun 11 --failed --repo o/r"] {
+		t.Errorf("reruns = %v", reruns)
+	}
+	if r, _ := answers[`"v"`].Result.(json.RawMessage); !strings.Contains(string(r), `"version"`) {
+		t.Errorf("version = %s", r)
+	}
+
+	errs := []struct {
+		id   string
+		code int
+		msg  string
+	}{
+		{"5", rpcInvalidParams, "ci/legacy is not a GitHub Actions check"},
+		{"6", rpcInvalidParams, `o/r#7 has no check named "nope"`},
+		{"7", rpcServerError, "Could not resolve"},
+		{"8", rpcInvalidParams, "missing params"},
+		{"9", rpcMethodNotFound, `unknown method "frobnicate"`},
+		{"10", rpcInvalidRequest, "invalid request"},
+		{"null", rpcParseError, "parse error"},
+	}
+	for _, e := range errs {
+		a := answers[e.id]
+		if a.Error == nil || a.Error.Code != e.code || !strings.Contains(a.Error.Message, e.msg) {
+			t.Errorf("answer %s = %+v, want code %d %q", e.id, a.Error, e.code, e.msg)
+		}
+		if r, _ := a.Result.(json.RawMessage); len(r) > 0 {
+			t.Errorf("answer %s has a result beside its error", e.id)
+		}
+	}
+}
+
+func TestRunServe(t *testing.T) {
+	s, _, _ := testSession(t)
+	if code, err := runServe(s, nil); code != exitFailed || err == nil || !strings.Contains(err.Error(), "--json-rpc") {
+		t.Errorf("runServe without --json-rpc = %d, %v", code, err)
+	}
+
+	s, stdout, _ := testSession(t)
+	s.opts.jsonRPC = true
+	s.stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"version"}` + "\n")
+	if code, err := runServe(s, nil); code != exitOK || err != nil {
+		t.Fatalf("runServe = %d, %v", code, err)
+	}
+	if !strings.HasPrefix(stdout.String(), `{"jsonrpc":"2.0","id":1,"result":{`) {
+		t.Errorf("stdout = %q", stdout)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setting is one row of the settings pane (,). change steps the value
+// forward (dir 1) or back (dir -1) and returns the config key that keeps
+// it, so the next session starts the same way, or no key when the change
+// is kept somewhere else.
+type setting struct {
+	label  string
+	value  func(m model) string
+	change func(m model, dir int) (model, configKey)
+}
+
+// configKey is a key in the config file and its new TOML value.
+type configKey struct {
+	table, key, value string
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// cycle steps i by dir through n choices, wrapping at either end.
+func cycle(i, n, dir int) int {
+	return ((i+dir)%n + n) % n
+}
+
+var settings = []setting{
+	{
+		label: "Refresh interval",
+		value: func(m model) string { return formatInterval(m.interval) },
+		change: func(m model, dir int) (model, configKey) {
+			m.interval = stepInterval(m.interval, dir > 0)
+			if m.sched != nil {
+				m.sched.setFast(m.interval)
+			}
+			return m, configKey{"polling", "interval", strconv.Quote(formatInterval(m.interval))}
+		},
+	},
+	{
+		label: "Show skipped checks",
+		value: func(m model) string { return onOff(!m.hideSkipped) },
+		change: func(m model, _ int) (model, configKey) {
+			m.hideSkipped = !m.hideSkipped
+			m.selected = 0
+			m.scrollOff = 0
+			return m, configKey{"display", "show_skipped", strconv.FormatBool(!m.hideSkipped)}
+		},
+	},
+	{
+		label: "Glyphs",
+		value: func(m model) string { return m.glyphs.String() },
+		change: func(m model, dir int) (model, configKey) {
+			m.glyphs = glyphSet(cycle(int(m.glyphs), len(glyphSetNames), dir))
+			return m, configKey{"display", "glyphs", strconv.Quote(m.glyphs.String())}
+		},
+	},
+	{
+		label: "Failure banner",
+		value: func(m model) string { return onOff(m.attention) },
+		change: func(m model, _ int) (model, configKey) {
+			m.attention = !m.attention
+			return m, configKey{"display", "attention", strconv.FormatBool(m.attention)}
+		},
+	},
+	{
+		label: "PR sort",
+		value: func(m model) string { return m.prSort.String() },
+		change: func(m model, dir int) (model, configKey) {
+			m.prSort = prSort(cycle(int(m.prSort), len(prSortNames), dir))
+			// The cursor is on a check, so the list is resorted without
+			// resortPRs moving it
+			sortPRs(m.prs, m.prSort, m.groupByRepo)
+			return m, configKey{"selector", "sort", strconv.Quote(m.prSort.String())}
+		},
+	},
+	{
+		label: "Group PRs by repo",
+		value: func(m model) string { return onOff(m.groupByRepo) },
+		change: func(m model, _ int) (model, configKey) {
+			m.groupByRepo = !m.groupByRepo
+			sortPRs(m.prs, m.prSort, m.groupByRepo)
+			return m, configKey{"selector", "group", strconv.FormatBool(m.groupByRepo)}
+		},
+	},
+	{
+		label: "Density",
+		value: func(m model) string { return m.density.String() },
+		change: func(m model, dir int) (model, configKey) {
+			m.density = density(cycle(int(m.density), len(densityNames), dir))
+			return m, configKey{"display", "density", strconv.Quote(m.density.String())}
+		},
+	},
+	{
+		label: "Wrap-around",
+		value: func(m model) string { return onOff(m.wrap) },
+		change: func(m model, _ int) (model, configKey) {
+			m.wrap = !m.wrap
+			return m, configKey{"display", "wrap", strconv.FormatBool(m.wrap)}
+		},
+	},
+	{
+		label: "Celebration",
+		value: func(m model) string { return m.celebration.String() },
+		change: func(m model, dir int) (model, configKey) {
+			m.celebration = celebration(cycle(int(m.celebration), len(celebrationNames), dir))
+			return m, configKey{"display", "celebrate", strconv.Quote(m.celebration.String())}
+		},
+	},
+}
+
+// settingRows are the settings pane's rows: settings, then one for each
+// check ignored in the viewed repo (I), which un-ignores it.
+func (m model) settingRows() []setting {
+	rows := slices.Clip(settings)
+	ignored := slices.Sorted(maps.Keys(m.store.ignored(m.repo)))
+	for _, name := range ignored {
+		rows = append(rows, setting{
+			label: "Ignored: " + name,
+			value: func(model) string { return "un-ignore" },
+			change: func(m model, _ int) (model, configKey) {
+				return m.toggleIgnore(Check{Name: name}), configKey{}
+			},
+		})
+	}
+	return rows
+}
+
+// changeSetting steps the selected setting and saves it to the config
+// file. The change applies either way; a failed save only says so.
+func (m model) changeSetting(dir int) model {
+	s := m.settingRows()[m.settingsSel]
+	m, key := s.change(m, dir)
+	if key == (configKey{}) {
+		// Un-ignoring drops the row
+		m.settingsSel = min(m.settingsSel, len(m.settingRows())-1)
+		return m
+	}
+	m.flash = s.label + ": " + s.value(m)
+	if err := saveConfigValue(m.configPath, key.table, key.key, key.value); err != nil {
+		m.flash += fmt.Sprintf(" (not saved: %s)", err)
+	}
+	return m
+}
+
+// settingsLines renders the settings pane, one setting per line.
+func (m model) settingsLines() []string {
+	rows := m.settingRows()
+	labelW := 0
+	for _, s := range rows {
+		labelW = max(labelW, len(s.label))
+	}
+	var lines []string
+	for i, s := range rows {
+		marker := "  "
+		if i == m.settingsSel {
+			marker = styleSelected.Render("▸ ")
+		}
+		lines = append(lines, fmt.Sprintf("%s%-*s  %s", marker, labelW, s.label, styleBold.Render(s.value(m))))
+	}
+	lines = append(lines, "")
+	if m.configPath == "" {
+		lines = append(lines, styleDim.Render("No config file: changes last until prtop exits"))
+	} else {
+		lines = append(lines, styleDim.Render("Changes are saved to "+m.configPath))
+	}
+	return lines
+}
+
+// updateSettingsKey handles keys while the settings pane is open: up/down
+// pick a setting, left/right (or enter and space, forward) change it.
+func (m model) updateSettingsKey(msg tea.KeyMsg) (model, bool) {
+	switch msg.String() {
+	case "esc", ",":
+		m.overlay = overlayNone
+	case "up", "k":
+		m.settingsSel = max(m.settingsSel-1, 0)
+	case "down", "j":
+		m.settingsSel = min(m.settingsSel+1, len(m.settingRows())-1)
+	case "right", "l", "enter", " ":
+		m = m.changeSetting(1)
+	case "left", "h":
+		m = m.changeSetting(-1)
+	default:
+		return m, false
+	}
+	return m, true
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSaveConfigValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	in := "# mine\n[display]\ndensity = \"comfy\" # roomy\n\n[[table.column]]\nname = \"x\"\n"
+	if err := os.WriteFile(path, []byte(in), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range []configKey{
+		{"display", "density", `"compact"`},
+		{"display", "wrap", "true"},
+		{"selector", "sort", `"ci"`},
+	} {
+		if err := saveConfigValue(path, kv.table, kv.key, kv.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, _ := os.ReadFile(path)
+	want := "# mine\n[display]\nwrap = true\ndensity = \"compact\"\n\n[[table.column]]\nname = \"x\"\n\n[selector]\nsort = \"ci\"\n"
+	if string(got) != want {
+		t.Errorf("config =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSettingsPane(t *testing.T) {
+	m := splitTestModel()
+	m.configPath = filepath.Join(t.TempDir(), "config.toml")
+	m.prs = []PRSummary{{Repo: "o/b", Number: 2}, {Repo: "o/a", Number: 1}}
+
+	m, _ = press(t, m, runeKey(','))
+	if m.overlay != overlaySettings {
+		t.Fatalf("overlay = %v after ,", m.overlay)
+	}
+	if out := m.View(); !strings.Contains(out, "SETTINGS") || !strings.Contains(out, "Refresh interval     5s") {
+		t.Errorf("View():\n%s", out)
+	}
+
+	// Interval up, skipped checks shown, glyphs back to none, density
+	// forward, wrap on
+	m, _ = press(t, m,
+		tea.KeyMsg{Type: tea.KeyRight},
+		tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter},
+		tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyLeft},
+		runeKey('j'), runeKey('j'), runeKey('j'), runeKey('j'), runeKey('l'),
+		runeKey('j'), runeKey('l'))
+	if m.interval != 10*time.Second || m.hideSkipped || m.glyphs != glyphsNone ||
+		m.density != densityCompact || !m.wrap || m.flash != "Wrap-around: on" {
+		t.Errorf("model: interval %v, hideSkipped %v, glyphs %v, density %v, wrap %v, flash %q",
+			m.interval, m.hideSkipped, m.glyphs, m.density, m.wrap, m.flash)
+	}
+	cfg, err := loadConfig(m.configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Polling.Interval != 10*time.Second || !cfg.Display.ShowSkipped || cfg.Display.Glyphs != "none" ||
+		cfg.Display.Density != "compact" || !cfg.Display.Wrap {
+		t.Errorf("saved config = %+v, %+v", cfg.Polling, cfg.Display)
+	}
+
+	// The cursor stays put at the bottom; , closes the pane
+	m, _ = press(t, m, runeKey('j'), runeKey('j'), runeKey(','))
+	if m.settingsSel != len(settings)-1 || m.overlay != overlayNone {
+		t.Errorf("settingsSel = %d, overlay = %v", m.settingsSel, m.overlay)
+	}
+
+	m.configPath = ""
+	m, _ = press(t, m, runeKey(','), runeKey('l'))
+	if !strings.Contains(m.flash, "not saved") || m.celebration != celebrateBanner {
+		t.Errorf("without a config file: celebration %v, flash %q", m.celebration, m.flash)
+	}
+}
+
+func TestSettingsUnignore(t *testing.T) {
+	m := splitTestModel()
+	m.store = &stateStore{path: filepath.Join(t.TempDir(), "state.json")}
+	for _, name := range []string{"lint", "ext"} {
+		if _, err := m.store.toggleIgnore(m.repo, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, _ = press(t, m, runeKey(','))
+	if out := m.View(); !strings.Contains(out, "Ignored: ext") || !strings.Contains(out, "Ignored: lint") {
+		t.Fatalf("ignored checks not listed:\n%s", out)
+	}
+	m.settingsSel = len(settings) // Ignored: ext
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.ignoredCount() != 1 || m.flash != "No longer ignoring ext" || m.settingsSel != len(settings) {
+		t.Errorf("un-ignore: %d ignored, flash %q, settingsSel %d", m.ignoredCount(), m.flash, m.settingsSel)
+	}
+	m, _ = press(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.ignoredCount() != 0 || m.settingsSel != len(settings)-1 || strings.Contains(m.View(), "Ignored:") {
+		t.Errorf("last un-ignore: %d ignored, settingsSel %d", m.ignoredCount(), m.settingsSel)
+	}
+}
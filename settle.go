@@ -0,0 +1,43 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// settler decides when a PR's checks have settled: none is running and no
+// new one has appeared for a while. Pipelines that start checks in waves
+// pass every check of one wave before the next appears, so "all passed"
+// isn't trusted until then.
+type settler struct {
+	names string    // the check names last seen, sorted
+	quiet time.Time // when the checks were last seen to change or run; zero while some run
+}
+
+// observe records a fetch of checks at now and reports whether they have
+// settled for window. A window of 0 turns settling off.
+func (s *settler) observe(checks []Check, now time.Time, window time.Duration) bool {
+	names := make([]string, len(checks))
+	running := false
+	for i, c := range checks {
+		names[i] = c.Name
+		running = running || c.Status == Running
+	}
+	slices.Sort(names)
+	key := strings.Join(names, "\n")
+	switch {
+	case running:
+		s.quiet = time.Time{}
+	case key != s.names || s.quiet.IsZero():
+		s.quiet = now
+	}
+	s.names = key
+	return s.settled(now, window)
+}
+
+// settled reports whether the checks last observed have been quiet for
+// window.
+func (s *settler) settled(now time.Time, window time.Duration) bool {
+	return window <= 0 || !s.quiet.IsZero() && now.Sub(s.quiet) >= window
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSettler(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+	wave1 := []Check{{Name: "build", Status: Pass}, {Name: "lint", Status: Pass}}
+	wave2 := append(wave1[:2:2], Check{Name: "deploy", Status: Running})
+
+	var s settler
+	if s.observe(wave1, at(0), 30*time.Second) {
+		t.Error("settled as soon as the checks passed")
+	}
+	if s.observe(wave1, at(20), 30*time.Second) {
+		t.Error("settled before the window")
+	}
+	if s.observe(wave2, at(25), 30*time.Second) {
+		t.Error("settled with a check running")
+	}
+	wave2[2].Status = Pass
+	if s.observe(wave2, at(40), 30*time.Second) || !s.observe(wave2, at(70), 30*time.Second) {
+		t.Error("window not counted from the last running check")
+	}
+	// A check that appears already finished restarts the window
+	wave3 := append(wave2[:3:3], Check{Name: "notify", Status: Skipped})
+	if s.observe(wave3, at(75), 30*time.Second) {
+		t.Error("settled right after a new check appeared")
+	}
+	if !(&settler{}).observe(wave1, at(0), 0) {
+		t.Error("settling off still waited")
+	}
+}
+
+func TestRollupHookSettle(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return clock }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.store = &stateStore{}
+	m.onChange = "true"
+	m.settleWindow = time.Minute
+	var s settler
+	running := &PRData{Checks: []Check{{Name: "build", Status: Running}}}
+	passed := &PRData{Checks: []Check{{Name: "build", Status: Pass}}}
+
+	status, _ := m.rollupHook("o/r", "1", running, "", &s)
+	status, cmd := m.rollupHook("o/r", "1", passed, status, &s)
+	if status != rollupPending || cmd != nil {
+		t.Errorf("unsettled success = %q, hook %v", status, cmd != nil)
+	}
+	clock = clock.Add(time.Minute)
+	if status, cmd = m.rollupHook("o/r", "1", passed, status, &s); status != rollupSuccess || cmd == nil {
+		t.Errorf("settled success = %q, hook %v", status, cmd != nil)
+	}
+}
+
+func TestRunWaitSettle(t *testing.T) {
+	fetches := 0
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		fetches++
+		if fetches < 3 {
+			return []byte(`{"statusCheckRollup":[{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"}]}`), nil
+		}
+		// The second wave starts, and fails
+		return []byte(`{"statusCheckRollup":[{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"SUCCESS"},
+			{"__typename":"CheckRun","name":"e2e","status":"COMPLETED","conclusion":"FAILURE"}]}`), nil
+	})
+	t.Cleanup(func() { ghOverride = nil; execCommand = exec.Command })
+
+	s, stdout, stderr := testSession(t)
+	s.settle = time.Minute
+	if code, err := runWait(s, []string{"o/r#7"}); code != exitFailed || err != nil {
+		t.Errorf("runWait = %d, %v; want 1", code, err)
+	}
+	if fetches != 3 || !strings.Contains(stdout.String(), "1 passed, 1 failed") {
+		t.Errorf("%d fetches, stdout:\n%s", fetches, stdout)
+	}
+	if got := stderr.String(); got != "1 checks passed; waiting 1m0s for more to appear\n" {
+		t.Errorf("progress = %q", got)
+	}
+}
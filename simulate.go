@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// scenario is a --simulate script: one PR whose checks step through a
+// sequence of states, one state per fetch, so the UI, wait's exit codes and
+// --on-change can be exercised without a real PR. For example:
+//
+//	repo: acme/widgets
+//	pr: 128
+//	states:
+//	  - name: pending
+//	    checks:
+//	      - {name: lint, status: queued}
+//	      - {name: test, status: queued}
+//	  - name: running
+//	    repeat: 3
+//	    checks:
+//	      - {name: lint, status: running}
+//	      - {name: test, status: running}
+//	  - name: done
+//	    checks:
+//	      - {name: lint, status: pass}
+//	      - {name: test, status: fail}
+type scenario struct {
+	Repo   string          `yaml:"repo"`
+	PR     int             `yaml:"pr"`
+	Title  string          `yaml:"title"`
+	Branch string          `yaml:"branch"`
+	States []scenarioState `yaml:"states"`
+}
+
+// scenarioState is what the PR's checks look like for Repeat fetches
+// (default 1). The last state holds once the script runs out.
+type scenarioState struct {
+	Name   string          `yaml:"name"`
+	Repeat int             `yaml:"repeat"`
+	SHA    string          `yaml:"sha"`
+	Checks []scenarioCheck `yaml:"checks"`
+}
+
+type scenarioCheck struct {
+	Name     string `yaml:"name"`
+	Workflow string `yaml:"workflow"`
+	Status   string `yaml:"status"`
+}
+
+// scenarioStatuses maps a scenario check status to gh's status and
+// conclusion.
+var scenarioStatuses = map[string][2]string{
+	"queued":    {"QUEUED", ""},
+	"running":   {"IN_PROGRESS", ""},
+	"pass":      {"COMPLETED", "SUCCESS"},
+	"fail":      {"COMPLETED", "FAILURE"},
+	"cancelled": {"COMPLETED", "CANCELLED"},
+	"skipped":   {"COMPLETED", "SKIPPED"},
+	"neutral":   {"COMPLETED", "NEUTRAL"},
+}
+
+// loadScenario reads and checks a scenario file.
+func loadScenario(path string) (*scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := parseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sc, nil
+}
+
+func parseScenario(data []byte) (*scenario, error) {
+	var sc scenario
+	if err := yaml.Unmarshal(data, &sc); err != nil {
+		return nil, err
+	}
+	if sc.Repo == "" {
+		sc.Repo = "prtop-sim/app"
+	}
+	if _, ok := parseRepo(sc.Repo); !ok {
+		return nil, fmt.Errorf("repo: %q is not owner/repo", sc.Repo)
+	}
+	if sc.PR == 0 {
+		sc.PR = 1
+	}
+	if sc.PR < 0 {
+		return nil, fmt.Errorf("pr: %d is not a PR number", sc.PR)
+	}
+	if sc.Title == "" {
+		sc.Title = "Simulated PR"
+	}
+	if sc.Branch == "" {
+		sc.Branch = "simulate"
+	}
+	if len(sc.States) == 0 {
+		return nil, fmt.Errorf("no states")
+	}
+	for i := range sc.States {
+		st := &sc.States[i]
+		if st.Repeat < 0 {
+			return nil, fmt.Errorf("states[%d]: repeat %d is negative", i, st.Repeat)
+		}
+		if st.Repeat == 0 {
+			st.Repeat = 1
+		}
+		if st.Name == "" {
+			st.Name = strconv.Itoa(i + 1)
+		}
+		for j, c := range st.Checks {
+			if c.Name == "" {
+				return nil, fmt.Errorf("states[%d].checks[%d]: no name", i, j)
+			}
+			if _, ok := scenarioStatuses[c.Status]; !ok {
+				return nil, fmt.Errorf("states[%d].checks[%d]: unknown status %q; expected queued, running, pass, fail, cancelled, skipped or neutral", i, j, c.Status)
+			}
+		}
+	}
+	return &sc, nil
+}
+
+// simulateSource serves a scenario for --simulate. Each `pr view` is one
+// step through the script. Checks get their start and finish times from
+// the step they first ran or finished in, so durations grow as they would
+// on a real PR.
+type simulateSource struct {
+	sc  *scenario
+	now func() time.Time
+
+	mu        sync.Mutex
+	fetches   int
+	started   map[string]time.Time
+	completed map[string]time.Time
+}
+
+func newSimulateSource(sc *scenario) *simulateSource {
+	return &simulateSource{sc: sc, now: time.Now, started: map[string]time.Time{}, completed: map[string]time.Time{}}
+}
+
+func (s *simulateSource) url() string {
+	return fmt.Sprintf("https://github.com/%s/pull/%d", s.sc.Repo, s.sc.PR)
+}
+
+func (s *simulateSource) run(args []string) ([]byte, error) {
+	if len(args) >= 2 && args[0] == "search" && args[1] == "prs" {
+		return s.listPRs(true)
+	}
+	if len(args) >= 2 && args[0] == "pr" && args[1] == "list" {
+		return s.listPRs(false)
+	}
+	if len(args) >= 3 && args[0] == "pr" && args[1] == "view" {
+		return s.viewPR(args[2])
+	}
+	return nil, fmt.Errorf("simulate mode does not support gh %s", strings.Join(args, " "))
+}
+
+func (s *simulateSource) listPRs(search bool) ([]byte, error) {
+	item := map[string]any{
+		"number":    s.sc.PR,
+		"title":     s.sc.Title,
+		"url":       s.url(),
+		"updatedAt": s.now().UTC().Format(time.RFC3339),
+	}
+	if search {
+		item["repository"] = map[string]string{"nameWithOwner": s.sc.Repo}
+	}
+	return json.Marshal([]any{item})
+}
+
+// state is the scenario state for fetch n (from 0).
+func (s *simulateSource) state(n int) scenarioState {
+	for _, st := range s.sc.States {
+		if n < st.Repeat {
+			return st
+		}
+		n -= st.Repeat
+	}
+	return s.sc.States[len(s.sc.States)-1]
+}
+
+func (s *simulateSource) viewPR(number string) ([]byte, error) {
+	if number != strconv.Itoa(s.sc.PR) {
+		return nil, newGhError("GraphQL: Could not resolve to a PullRequest with the number of " + number + ". (repository.pullRequest)")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.state(s.fetches)
+	s.fetches++
+	now := s.now().UTC()
+
+	resp := ghPRResponse{
+		Title:       s.sc.Title,
+		HeadRefName: s.sc.Branch,
+		HeadRefOid:  st.SHA,
+		URL:         s.url(),
+	}
+	for _, c := range st.Checks {
+		status := scenarioStatuses[c.Status]
+		item := ghCheckItem{
+			Typename:     "CheckRun",
+			Name:         c.Name,
+			WorkflowName: c.Workflow,
+			Status:       looseString(status[0]),
+			Conclusion:   looseString(status[1]),
+			DetailsURL:   s.url() + "/checks",
+		}
+		key := c.Workflow + "/" + c.Name
+		switch c.Status {
+		case "queued":
+			// A check queued again, as after a rerun, starts over
+			delete(s.started, key)
+			delete(s.completed, key)
+		case "running":
+			delete(s.completed, key)
+			if _, ok := s.started[key]; !ok {
+				s.started[key] = now
+			}
+			item.StartedAt = s.started[key].Format(time.RFC3339)
+		case "skipped":
+		default:
+			if _, ok := s.started[key]; !ok {
+				s.started[key] = now
+			}
+			if _, ok := s.completed[key]; !ok {
+				s.completed[key] = now
+			}
+			item.StartedAt = s.started[key].Format(time.RFC3339)
+			item.CompletedAt = s.completed[key].Format(time.RFC3339)
+		}
+		resp.StatusCheckRollup = append(resp.StatusCheckRollup, item)
+	}
+	return json.Marshal(resp)
+}
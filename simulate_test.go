@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+const rolloutScenario = "testdata/simulate/rollout.yaml"
+
+func TestParseScenario(t *testing.T) {
+	sc, err := parseScenario([]byte("states:\n  - checks:\n      - {name: lint, status: pass}\n    repeat: 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Repo != "prtop-sim/app" || sc.PR != 1 || sc.States[0].Repeat != 2 || sc.States[0].Name != "1" {
+		t.Errorf("defaults: %+v", sc)
+	}
+
+	for yml, want := range map[string]string{
+		"repo: acme/widgets\n":                           "no states",
+		"repo: nope\nstates: [{}]\n":                     `repo: "nope" is not owner/repo`,
+		"states:\n  - repeat: -1\n":                      "states[0]: repeat -1 is negative",
+		"states:\n  - checks: [{status: pass}]\n":        "states[0].checks[0]: no name",
+		"states:\n  - {}\n  - checks: [{name: a}]\n":     `states[1].checks[0]: unknown status ""`,
+		"states:\n  - checks: [{name: a, status: ok}]\n": `unknown status "ok"`,
+		"states: [": "yaml:",
+	} {
+		if _, err := parseScenario([]byte(yml)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseScenario(%q) = %v, want %q", yml, err, want)
+		}
+	}
+}
+
+func TestSimulateSourceSteps(t *testing.T) {
+	sc, err := loadScenario(rolloutScenario)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	src := newSimulateSource(sc)
+	src.now = func() time.Time { return now }
+	ghOverride = src
+	t.Cleanup(func() { ghOverride = nil })
+	execCommand = fakeExecCommand("", "gh should not run in simulate mode", 1)
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	step := func() *PRData {
+		t.Helper()
+		data, err := fetchPRData(nil, "acme/widgets", "128")
+		if err != nil {
+			t.Fatalf("fetchPRData: %v", err)
+		}
+		now = now.Add(time.Minute)
+		return data
+	}
+	statuses := func(data *PRData) string {
+		s := map[string]string{}
+		for _, c := range data.Checks {
+			s[c.Name] = c.Status.String()
+		}
+		return s["lint (CI)"] + "," + s["unit-tests (CI)"]
+	}
+
+	want := []string{"RUNNING,RUNNING", "RUNNING,RUNNING", "PASS,RUNNING", "PASS,FAIL", "PASS,FAIL"}
+	var last *PRData
+	for i, w := range want {
+		last = step()
+		if got := statuses(last); got != w {
+			t.Errorf("fetch %d: %s, want %s", i+1, got, w)
+		}
+	}
+	if last.Title != sc.Title || last.HeadRefName != "retry-uploads" {
+		t.Errorf("PR = %q on %q", last.Title, last.HeadRefName)
+	}
+	// lint ran from the second fetch to the third, unit-tests to the fourth
+	for _, c := range last.Checks {
+		want := map[string]time.Duration{"lint (CI)": time.Minute, "unit-tests (CI)": 2 * time.Minute}[c.Name]
+		if d := c.CompletedAt.Sub(c.StartedAt); d != want {
+			t.Errorf("%s took %s, want %s", c.Name, d, want)
+		}
+	}
+
+	if _, err := fetchPRData(nil, "acme/widgets", "9"); ghErrKind(err) != ghErrNotFound {
+		t.Errorf("other PR: %v", err)
+	}
+	prs, err := fetchRecentPRs(nil)
+	if err != nil || len(prs) != 1 || prs[0].Repo != "acme/widgets" || prs[0].Number != 128 {
+		t.Errorf("fetchRecentPRs = %+v, %v", prs, err)
+	}
+	if _, err := runGh(nil, "run", "rerun", "1"); err == nil {
+		t.Error("unsupported commands should return an error")
+	}
+}
+
+func TestSimulateCLI(t *testing.T) {
+	code, _, stderr := runCLI(t, "--simulate", "nope.yaml", "status", "acme/widgets#128")
+	if code != exitFailed || !strings.Contains(stderr, "--simulate: open nope.yaml") {
+		t.Errorf("missing file: %d %q", code, stderr)
+	}
+	code, _, stderr = runCLI(t, "--simulate", rolloutScenario, "--demo", "status", "acme/widgets#128")
+	if code != exitFailed || !strings.Contains(stderr, "cannot be used with --demo") {
+		t.Errorf("with --demo: %d %q", code, stderr)
+	}
+
+	// The first fetch is the pending state
+	if code, _, _ := runCLI(t, "--simulate", rolloutScenario, "status", "acme/widgets#128"); code != exitPending {
+		t.Errorf("status exit %d, want %d", code, exitPending)
+	}
+	done := filepath.Join(t.TempDir(), "done.yaml")
+	if err := os.WriteFile(done, []byte("repo: acme/widgets\npr: 128\nstates:\n  - checks: [{name: lint, status: fail}]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, _ := runCLI(t, "--simulate", done, "wait", "acme/widgets#128")
+	if code != exitFailed || !strings.Contains(stdout, "lint") {
+		t.Errorf("wait exit %d:\n%s", code, stdout)
+	}
+}
+
+// TestSimulateProgram walks the TUI through the rollout scenario, one
+// refresh per state.
+func TestSimulateProgram(t *testing.T) {
+	resetRespCache(t)
+	sc, err := loadScenario(rolloutScenario)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := snapshotModel(t, "", 100, 20)
+	src := newSimulateSource(sc)
+	src.now = timeNow
+	ghOverride = src
+	t.Cleanup(func() { ghOverride = nil })
+
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(100, 20))
+	for i, want := range []string{"RUNNING  -", "RUNNING  0s", "✓ PASS", "✗ FAIL"} {
+		if i > 0 {
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+		}
+		teatest.WaitFor(t, tm.Output(), func(b []byte) bool {
+			return strings.Contains(string(b), want)
+		}, teatest.WithDuration(5*time.Second))
+	}
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if got := rollupStatus(final.prData.Checks, func(Check) bool { return false }); got != rollupFailure {
+		t.Errorf("final rollup %q", got)
+	}
+}
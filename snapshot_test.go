@@ -0,0 +1,156 @@
+package main
+
+// Snapshot tests render whole screens and compare them with golden files in
+// testdata/, so layout changes show up as a diff rather than slipping past
+// substring checks. After an intended change, regenerate the files with
+//
+//	go test -run 'TestSnapshots|TestSnapshotProgram' -update
+//
+// and review the diff. To add a case, add a row to TestSnapshots; the PR
+// data comes from the `gh pr view --json` payloads in testdata/prview/.
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// snapshotTime is the pinned clock: a few minutes after the fixtures'
+// checks ran.
+var snapshotTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// pinClock fixes the UI clock at snapshotTime for the test.
+func pinClock(t *testing.T) {
+	t.Helper()
+	timeNow = func() time.Time { return snapshotTime }
+	t.Cleanup(func() { timeNow = time.Now })
+}
+
+// prViewFixture is testdata/prview/NAME.json, a `gh pr view --json` payload.
+func prViewFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	out, err := os.ReadFile(filepath.Join("testdata", "prview", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// fixturePR parses a prViewFixture the way a live fetch does.
+func fixturePR(t *testing.T, name string) *PRData {
+	t.Helper()
+	data, err := parsePRView(prViewFixture(t, name))
+	if err != nil {
+		t.Fatalf("fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// snapshotModel is a model viewing acme/widgets#128 with a fixture's data
+// in a width x height terminal, on the pinned clock.
+func snapshotModel(t *testing.T, fixture string, width, height int) model {
+	t.Helper()
+	pinClock(t)
+	m := newModel("acme/widgets", "128", 5*time.Second)
+	m.width, m.height = width, height
+	if fixture != "" {
+		m.prData = fixturePR(t, fixture)
+	}
+	return m
+}
+
+// requireSnapshot compares a rendered screen, without colors, with the
+// test's golden file.
+func requireSnapshot(t *testing.T, view string) {
+	t.Helper()
+	golden.RequireEqual(t, []byte(ansi.Strip(view)+"\n"))
+}
+
+func TestSnapshots(t *testing.T) {
+	tests := []struct {
+		name          string
+		fixture       string
+		width, height int
+		setup         func(m model) model
+	}{
+		{name: "normal", fixture: "mixed", width: 100, height: 20},
+		{name: "green", fixture: "green", width: 100, height: 16},
+		{name: "conflicts", fixture: "conflicts", width: 100, height: 12},
+		{name: "narrow", fixture: "mixed", width: 50, height: 20},
+		{name: "scrolling", fixture: "mixed", width: 100, height: 10},
+		{name: "too_small", fixture: "mixed", width: 30, height: 8},
+		{name: "loading", width: 80, height: 10},
+		{name: "compact", fixture: "mixed", width: 100, height: 12, setup: func(m model) model {
+			m.density = densityCompact
+			return m
+		}},
+		{name: "comfy", fixture: "mixed", width: 100, height: 24, setup: func(m model) model {
+			m.density = densityComfy
+			return m
+		}},
+		{name: "show_skipped", fixture: "mixed", width: 100, height: 20, setup: func(m model) model {
+			m.hideSkipped = false
+			return m
+		}},
+		{name: "split", fixture: "mixed", width: 120, height: 20, setup: func(m model) model {
+			m.split = true
+			return m
+		}},
+		{name: "timeline", fixture: "mixed", width: 100, height: 20, setup: func(m model) model {
+			m.overlay = overlayTimeline
+			return m
+		}},
+		{name: "mini", fixture: "mixed", width: 60, height: 3, setup: func(m model) model {
+			m.mini = true
+			return m
+		}},
+		{name: "error", width: 100, height: 10, setup: func(m model) model {
+			m.err = newGhError("GraphQL: Could not resolve to a PullRequest with the number of 128. (repository.pullRequest)")
+			return m
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := snapshotModel(t, tt.fixture, tt.width, tt.height)
+			if tt.setup != nil {
+				m = tt.setup(m)
+			}
+			requireSnapshot(t, m.View())
+		})
+	}
+}
+
+// TestSnapshotProgram runs the real program against faked gh: the first
+// fetch, a key press, and the screen it leaves behind.
+func TestSnapshotProgram(t *testing.T) {
+	resetRespCache(t)
+	execCommand = fakeExecByArgs(map[string]string{
+		"pr view": string(prViewFixture(t, "mixed")),
+		"graphql": `{"data":{}}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+	m := snapshotModel(t, "", 100, 20)
+
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(100, 20))
+	teatest.WaitFor(t, tm.Output(), func(b []byte) bool {
+		return strings.Contains(string(b), "unit-tests (CI)")
+	}, teatest.WithDuration(5*time.Second))
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.selected != 1 || final.density != densityCompact {
+		t.Errorf("selected %d, density %v", final.selected, final.density)
+	}
+	requireSnapshot(t, final.View())
+}
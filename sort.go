@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// prSort is the selector's ordering.
+type prSort int
+
+const (
+	sortUpdated prSort = iota // most recently updated first (default)
+	sortCreated               // newest first
+	sortCI                    // failing, then running, then passing
+	sortRepo                  // by repository name
+)
+
+var prSortNames = []string{"updated", "created", "ci", "repo"}
+
+func (s prSort) String() string {
+	return prSortNames[s]
+}
+
+// parsePRSort parses a sort name from config. An empty name is sortUpdated.
+func parsePRSort(name string) (prSort, error) {
+	if name == "" {
+		return sortUpdated, nil
+	}
+	for i, n := range prSortNames {
+		if strings.EqualFold(name, n) {
+			return prSort(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown selector sort %q (want one of %s)", name, strings.Join(prSortNames, ", "))
+}
+
+// ciRank orders PRs by how much attention their CI needs.
+func ciRank(pr PRSummary) int {
+	switch pr.CIState {
+	case "FAILURE", "ERROR":
+		return 0
+	case "PENDING", "EXPECTED":
+		return 1
+	case "SUCCESS":
+		return 2
+	}
+	return 3
+}
+
+// sortPRs orders prs in place. With group set, PRs are first ordered by
+// repository so each repo's PRs are contiguous, then by the chosen order.
+func sortPRs(prs []PRSummary, by prSort, group bool) {
+	less := func(a, b PRSummary) bool {
+		switch by {
+		case sortCreated:
+			return a.CreatedAt > b.CreatedAt
+		case sortCI:
+			if ra, rb := ciRank(a), ciRank(b); ra != rb {
+				return ra < rb
+			}
+		case sortRepo:
+			if a.Repo != b.Repo {
+				return a.Repo < b.Repo
+			}
+		}
+		return a.UpdatedAt > b.UpdatedAt
+	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		if group && prs[i].Repo != prs[j].Repo {
+			return prs[i].Repo < prs[j].Repo
+		}
+		return less(prs[i], prs[j])
+	})
+}
+
+// resortPRs reorders m.prs, keeping the cursor on the same PR.
+func (m model) resortPRs() model {
+	current := m.activeKey()
+	sortPRs(m.prs, m.prSort, m.groupByRepo)
+	for i, pr := range m.prs {
+		if summaryKey(pr) == current {
+			m.selected = i
+		}
+	}
+	return m
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSortPRs(t *testing.T) {
+	base := []PRSummary{
+		{Repo: "b/svc", Number: 1, UpdatedAt: "2024-01-03", CreatedAt: "2023-12-01", CIState: "SUCCESS"},
+		{Repo: "a/lib", Number: 2, UpdatedAt: "2024-01-01", CreatedAt: "2024-01-01", CIState: "FAILURE"},
+		{Repo: "b/svc", Number: 3, UpdatedAt: "2024-01-02", CreatedAt: "2023-11-01", CIState: "PENDING"},
+		{Repo: "a/lib", Number: 4, UpdatedAt: "2024-01-04", CreatedAt: "2023-10-01"},
+	}
+	tests := []struct {
+		by    prSort
+		group bool
+		want  []int
+	}{
+		{sortUpdated, false, []int{4, 1, 3, 2}},
+		{sortCreated, false, []int{2, 1, 3, 4}},
+		{sortCI, false, []int{2, 3, 1, 4}},
+		{sortRepo, false, []int{4, 2, 1, 3}},
+		{sortUpdated, true, []int{4, 2, 1, 3}},
+		{sortCI, true, []int{2, 4, 3, 1}},
+	}
+	for _, tt := range tests {
+		prs := append([]PRSummary(nil), base...)
+		sortPRs(prs, tt.by, tt.group)
+		var got []int
+		for _, pr := range prs {
+			got = append(got, pr.Number)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("sortPRs(%s, group=%v) = %v, want %v", tt.by, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestParsePRSort(t *testing.T) {
+	for name, want := range map[string]prSort{"": sortUpdated, "created": sortCreated, "CI": sortCI, "repo": sortRepo} {
+		got, err := parsePRSort(name)
+		if err != nil || got != want {
+			t.Errorf("parsePRSort(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := parsePRSort("stars"); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+}
+
+func TestSelectorSortAndGroupKeys(t *testing.T) {
+	m := newSelectModel(5 * time.Second)
+	m.width = 100
+	m.height = 40
+	updated, _ := m.Update(prListMsg{prs: []PRSummary{
+		{Repo: "b/svc", Number: 1, UpdatedAt: "2024-01-03", CreatedAt: "2023-01-01"},
+		{Repo: "a/lib", Number: 2, UpdatedAt: "2024-01-01", CreatedAt: "2024-01-01"},
+	}})
+	m = updated.(model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m = updated.(model)
+	if m.prSort != sortCreated || m.prs[0].Number != 2 {
+		t.Fatalf("o should sort by created, got %s with first PR #%d", m.prSort, m.prs[0].Number)
+	}
+	if m.prs[m.selected].Number != 2 {
+		t.Errorf("cursor should stay on PR #2, now on #%d", m.prs[m.selected].Number)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	m = updated.(model)
+	out := m.View()
+	if !strings.Contains(out, "── a/lib") || !strings.Contains(out, "── b/svc") {
+		t.Errorf("grouped view should have repo headers, got %q", out)
+	}
+	if !strings.Contains(out, "o: sort (created)") {
+		t.Errorf("footer should name the current sort, got %q", out)
+	}
+}
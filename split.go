@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// paneKind selects what the right-hand pane of the split view shows. The
+// check table is always on the left and the pane follows its selection.
+type paneKind int
+
+const (
+	paneLogs paneKind = iota
+	paneDetails
+	paneEvents
+)
+
+var paneNames = []string{"logs", "details", "events"}
+
+func (p paneKind) String() string {
+	return paneNames[p]
+}
+
+// Split widths are a percentage of the screen given to the check table.
+const (
+	splitDefault = 50
+	splitMin     = 20
+	splitMax     = 80
+	splitStep    = 5
+)
+
+type logsMsg struct {
+	key    string // logKey of the check the log belongs to
+	lines  []string
+	report *testReport // nil when no test results were found
+	err    error
+}
+
+// fetchJobLog downloads an Actions job's log, dropping the timestamp GitHub
+// prefixes to every line.
+func fetchJobLog(acct *Account, repo, jobID string) ([]string, error) {
+	out, err := ghAPI(acct, repo, "repos/{repo}/actions/jobs/"+jobID+"/logs")
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ReplaceAll(strings.TrimRight(string(out), "\n"), "\r", "")
+	lines := []string{}
+	if text == "" {
+		return lines, nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if ts, rest, ok := strings.Cut(line, " "); ok {
+			if _, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				line = rest
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// logKey identifies a check's log. It includes the status so a job's log is
+// fetched again once it finishes or is re-run.
+func logKey(c Check) string {
+	return c.DetailsURL + " " + c.Status.String()
+}
+
+// ensureLogs starts fetching what the pane shows of the selected check: its
+// log and test results, and in the details pane its check run output.
+func (m model) ensureLogs() (model, tea.Cmd) {
+	m, outputCmd := m.ensureOutput()
+	m, logCmd := m.ensureJobLog()
+	return m, tea.Batch(logCmd, outputCmd)
+}
+
+// ensureJobLog starts fetching the selected check's log (and test results)
+// when the logs or details pane is showing and the log isn't the one
+// already loaded. Only finished Actions jobs have a log to fetch.
+func (m model) ensureJobLog() (model, tea.Cmd) {
+	if !m.split || m.pane == paneEvents || m.mode != modeViewing {
+		return m, nil
+	}
+	c, ok := m.selectedCheck()
+	if !ok || logKey(c) == m.logKey {
+		return m, nil
+	}
+	key := logKey(c)
+	m.logKey = key
+	m.logLines, m.logReport, m.logErr = nil, nil, nil
+	m.paneOff = 0
+	runID, jobID := actionsRunID(c.DetailsURL)
+	if jobID == "" || !c.Completed {
+		return m, nil
+	}
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	failed := c.Status == Fail
+	return m, func() tea.Msg {
+		lines, err := fetchJobLog(acct, repo, jobID)
+		if err != nil {
+			return logsMsg{key: key, err: err}
+		}
+		report := parseGoTestLog(lines)
+		if report == nil && failed && runID != "" {
+			// Not Go; a failed job may have uploaded JUnit XML instead
+			if report, err = fetchJUnitReport(acct, repo, runID); err != nil {
+				logger.Debug("JUnit artifacts unavailable", "run", runID, "err", err)
+			}
+		}
+		return logsMsg{key: key, lines: lines, report: report}
+	}
+}
+
+// scrollPaneToEnd scrolls the pane so its last line is at the bottom, which
+// is where a job log's failure usually is.
+func (m model) scrollPaneToEnd() model {
+	m.paneOff = max(0, len(m.paneLines())-m.bodyRows())
+	return m
+}
+
+// updateSplitKey handles the split view's keys: a ctrl+w prefix (then w, h
+// or l to move focus, < or > to resize, = to reset), tab to change the
+// pane's content, and scrolling while the pane has focus. It reports false
+// for keys the regular key handling should process.
+func (m model) updateSplitKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	key := msg.String()
+	if m.winPrefix {
+		m.winPrefix = false
+		switch key {
+		case "w", "ctrl+w":
+			m.paneFocus = !m.paneFocus
+		case "h", "left":
+			m.paneFocus = false
+		case "l", "right":
+			m.paneFocus = true
+		case "<":
+			m.splitPct = max(splitMin, m.splitPct-splitStep)
+		case ">":
+			m.splitPct = min(splitMax, m.splitPct+splitStep)
+		case "=":
+			m.splitPct = splitDefault
+		}
+		return m, nil, true
+	}
+	switch key {
+	case "ctrl+w":
+		m.winPrefix = true
+	case "tab":
+		m.pane = (m.pane + 1) % paneKind(len(paneNames))
+		m.paneOff = 0
+		var cmd tea.Cmd
+		m, cmd = m.ensureLogs()
+		if m.pane == paneLogs {
+			m = m.scrollPaneToEnd()
+		}
+		return m, cmd, true
+	case "up", "k":
+		if !m.paneFocus {
+			return m, nil, false
+		}
+		if m.paneOff > 0 {
+			m.paneOff--
+		}
+	case "down", "j":
+		if !m.paneFocus {
+			return m, nil, false
+		}
+		if m.paneOff < len(m.paneLines())-1 {
+			m.paneOff++
+		}
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+func (m model) paneTitle() string {
+	switch m.pane {
+	case paneLogs:
+		if c, ok := m.selectedCheck(); ok {
+			return "LOG: " + c.Name
+		}
+		return "LOG"
+	case paneDetails:
+		return "DETAILS"
+	case paneEvents:
+		return "EVENTS (newest first)"
+	}
+	return ""
+}
+
+func (m model) paneLines() []string {
+	if m.pane == paneEvents {
+		if len(m.events) == 0 {
+			return []string{"No events yet."}
+		}
+		return renderEvents(m.events)
+	}
+	c, ok := m.selectedCheck()
+	if !ok {
+		return []string{"No check selected."}
+	}
+	if m.pane == paneDetails {
+		return m.checkDetails(c)
+	}
+	_, jobID := actionsRunID(c.DetailsURL)
+	switch {
+	case jobID == "":
+		return []string{"Logs are only available for GitHub Actions jobs.", c.DetailsURL}
+	case !c.Completed:
+		return []string{"The log is available once the job finishes."}
+	case logKey(c) != m.logKey || m.logLines == nil && m.logErr == nil:
+		return []string{"Loading log..."}
+	case m.logErr != nil:
+		return []string{styleFail.Render(fmt.Sprintf("Error: %s", m.logErr))}
+	case len(m.logLines) == 0:
+		return []string{"The log is empty."}
+	}
+	return m.logLines
+}
+
+func (m model) checkDetails(c Check) []string {
+	status := statusStyle(c.Status).Render(c.Status.String())
+	if m.isAcked(c) {
+		status += " (acknowledged)"
+	}
+	if m.isMuted(c) {
+		status += " (muted until the next push)"
+	}
+	lines := []string{
+		"Name:      " + c.Name,
+		"Status:    " + status,
+	}
+	if cov, ok := parseCoverage(c); ok {
+		lines = append(lines, "Coverage:  "+cov.String())
+	}
+	if c.Description != "" {
+		lines = append(lines, "Message:   "+c.Description)
+	}
+	if c.Provider != "" {
+		lines = append(lines, "Provider:  "+c.Provider)
+	}
+	if c.Workflow != "" {
+		lines = append(lines, "Workflow:  "+c.Workflow, "Job:       "+c.JobName)
+	}
+	if c.Duration != "" {
+		lines = append(lines, "Duration:  "+c.Duration)
+	}
+	if !c.StartedAt.IsZero() {
+		lines = append(lines, "Started:   "+c.StartedAt.Local().Format("15:04:05")+
+			" ("+relativeTime(c.StartedAt.Format(time.RFC3339))+")")
+	}
+	if c.DetailsURL != "" {
+		lines = append(lines, "URL:       "+c.DetailsURL)
+	}
+	if dep, ok := m.checkDeployment(c); ok {
+		lines = append(lines, "Deploy:    "+dep.environment+", "+dep.label())
+		if dep.url != "" {
+			lines = append(lines, "Env URL:   "+dep.url)
+		}
+	}
+	if _, jobID := actionsRunID(c.DetailsURL); logKey(c) == m.logKey {
+		switch {
+		case m.logReport != nil:
+			lines = append(append(lines, ""), renderTestReport(m.logReport)...)
+		case m.logLines == nil && m.logErr == nil && c.Completed && jobID != "":
+			lines = append(lines, "", styleDim.Render("Looking for test results..."))
+		}
+	}
+	return append(lines, m.outputLines(c)...)
+}
+
+// splitWidths divides width between the check table and the pane, leaving
+// one column for the divider.
+func (m model) splitWidths(width int) (left, right int) {
+	left = (width - 1) * m.splitPct / 100
+	return left, max(0, width-1-left)
+}
+
+// viewSplit lays the check table (a header line plus rows) out beside the
+// pane, rows lines tall below their headers. The focused side's header is
+// highlighted.
+func (m model) viewSplit(rows int) []string {
+	leftW, rightW := m.splitWidths(m.width)
+	table := m.viewCheckTable(leftW, m.tableRows())
+
+	header := styleUnder
+	if !m.paneFocus {
+		header = header.Reverse(true)
+	}
+	table[0] = header.Render(ansi.Strip(table[0]))
+
+	pane := m.paneLines()
+	if m.paneOff < len(pane) {
+		pane = pane[m.paneOff:]
+	} else {
+		pane = nil
+	}
+	if len(pane) > rows {
+		pane = pane[:rows]
+	}
+	header = styleUnder
+	if m.paneFocus {
+		header = header.Reverse(true)
+	}
+	pane = append([]string{header.Render(truncate(m.paneTitle(), rightW))}, pane...)
+
+	return joinColumns(table, pane, leftW, rightW, rows+1)
+}
+
+// joinColumns places left and right side by side, height lines tall, each
+// line cut or padded to its column width.
+func joinColumns(left, right []string, leftW, rightW, height int) []string {
+	divider := styleDim.Render("│")
+	lines := make([]string, height)
+	for i := range lines {
+		var l, r string
+		if i < len(left) {
+			l = ansi.Truncate(left[i], leftW, "")
+		}
+		if i < len(right) {
+			r = ansi.Truncate(right[i], rightW, "")
+		}
+		l += strings.Repeat(" ", max(0, leftW-lipgloss.Width(l)))
+		lines[i] = l + divider + r
+	}
+	return lines
+}
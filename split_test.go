@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func splitTestModel() model {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 80, 20
+	m.prData = &PRData{Title: "Fix it", Checks: []Check{
+		{Name: "build", Status: Fail, Completed: true,
+			DetailsURL: "https://github.com/o/r/actions/runs/1/job/11"},
+		{Name: "lint", Status: Running,
+			DetailsURL: "https://github.com/o/r/actions/runs/1/job/12"},
+		{Name: "ext", Status: Pass, Completed: true, DetailsURL: "https://ci.example.com/7"},
+	}}
+	return m
+}
+
+var (
+	keyV     = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}}
+	keyJ     = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	keyTab   = tea.KeyMsg{Type: tea.KeyTab}
+	keyCtrlW = tea.KeyMsg{Type: tea.KeyCtrlW}
+)
+
+// ---------------------------------------------------------------------------
+// fetchJobLog
+// ---------------------------------------------------------------------------
+
+func TestFetchJobLog(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"repos/o/r/actions/jobs/11/logs": "2024-05-01T10:00:00.1234567Z ##[group]Run make\r\n" +
+			"2024-05-01T10:00:01.0000000Z FAIL: TestX\nno timestamp here\n",
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	lines, err := fetchJobLog(nil, "o/r", "11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"##[group]Run make", "FAIL: TestX", "no timestamp here"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Split view keys and layout
+// ---------------------------------------------------------------------------
+
+func TestSplitView(t *testing.T) {
+	t.Run("v opens split and fetches the selected job log", func(t *testing.T) {
+		execCommand = fakeExecByArgs(map[string]string{"actions/jobs/11/logs": "line 1\nline 2\n"})
+		t.Cleanup(func() { execCommand = exec.Command })
+
+		m, cmd := press(t, splitTestModel(), keyV)
+		if !m.split || m.pane != paneLogs {
+			t.Fatalf("split = %v, pane = %v", m.split, m.pane)
+		}
+		if cmd == nil {
+			t.Fatal("no log fetch")
+		}
+		updated, _ := m.Update(cmd())
+		m = updated.(model)
+		if !slices.Equal(m.paneLines(), []string{"line 1", "line 2"}) {
+			t.Errorf("paneLines = %q", m.paneLines())
+		}
+	})
+
+	t.Run("log follows selection without fetching unfinished or external checks", func(t *testing.T) {
+		m, _ := press(t, splitTestModel(), keyV)
+		m, cmd := press(t, m, keyJ)
+		if cmd != nil {
+			t.Error("fetched the log of a running job")
+		}
+		if got := m.paneLines()[0]; !strings.Contains(got, "once the job finishes") {
+			t.Errorf("running job pane = %q", got)
+		}
+		m, _ = press(t, m, keyJ)
+		if got := m.paneLines()[0]; !strings.Contains(got, "only available for GitHub Actions") {
+			t.Errorf("external check pane = %q", got)
+		}
+	})
+
+	t.Run("stale log response is ignored", func(t *testing.T) {
+		m, _ := press(t, splitTestModel(), keyV)
+		updated, _ := m.Update(logsMsg{key: "other", lines: []string{"x"}})
+		if um := updated.(model); um.logLines != nil {
+			t.Errorf("logLines = %q, want nil", um.logLines)
+		}
+	})
+
+	t.Run("tab cycles pane content", func(t *testing.T) {
+		m, _ := press(t, splitTestModel(), keyV, keyTab)
+		if m.pane != paneDetails {
+			t.Fatalf("pane = %v, want details", m.pane)
+		}
+		if got := m.paneLines()[0]; got != "Name:      build" {
+			t.Errorf("details[0] = %q", got)
+		}
+		m, _ = press(t, m, keyTab)
+		if m.pane != paneEvents {
+			t.Errorf("pane = %v, want events", m.pane)
+		}
+		m, _ = press(t, m, keyTab)
+		if m.pane != paneLogs {
+			t.Errorf("pane = %v, want logs", m.pane)
+		}
+	})
+
+	t.Run("ctrl+w moves focus and up/down scroll the focused side", func(t *testing.T) {
+		m, _ := press(t, splitTestModel(), keyV, keyTab, keyCtrlW, runeKey('w'))
+		if !m.paneFocus || m.winPrefix {
+			t.Fatalf("paneFocus = %v, winPrefix = %v", m.paneFocus, m.winPrefix)
+		}
+		m, _ = press(t, m, keyJ)
+		if m.paneOff != 1 || m.selected != 0 {
+			t.Errorf("paneOff = %d, selected = %d; want 1, 0", m.paneOff, m.selected)
+		}
+		m, _ = press(t, m, keyCtrlW, runeKey('h'), keyJ)
+		if m.paneFocus || m.selected != 1 {
+			t.Errorf("paneFocus = %v, selected = %d; want false, 1", m.paneFocus, m.selected)
+		}
+	})
+
+	t.Run("ctrl+w resizes within bounds", func(t *testing.T) {
+		m, _ := press(t, splitTestModel(), keyV, keyCtrlW, runeKey('>'))
+		if m.splitPct != splitDefault+splitStep {
+			t.Errorf("splitPct = %d", m.splitPct)
+		}
+		for range 20 {
+			m, _ = press(t, m, keyCtrlW, runeKey('<'))
+		}
+		if m.splitPct != splitMin {
+			t.Errorf("splitPct = %d, want %d", m.splitPct, splitMin)
+		}
+		m, _ = press(t, m, keyCtrlW, runeKey('='))
+		if m.splitPct != splitDefault {
+			t.Errorf("splitPct = %d, want %d", m.splitPct, splitDefault)
+		}
+	})
+
+	t.Run("view fills the screen with both columns", func(t *testing.T) {
+		m, _ := press(t, splitTestModel(), keyV, keyTab)
+		lines := strings.Split(m.View(), "\n")
+		if len(lines) != m.height {
+			t.Errorf("view has %d lines, want %d", len(lines), m.height)
+		}
+		for _, l := range lines {
+			if w := lipgloss.Width(l); w > m.width {
+				t.Errorf("line wider than screen (%d): %q", w, l)
+			}
+		}
+		view := m.View()
+		for _, want := range []string{"STATUS", "│", "DETAILS", "Name:      build", "ctrl+w w: switch pane"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("view missing %q", want)
+			}
+		}
+	})
+}
+
+func TestJoinColumns(t *testing.T) {
+	got := joinColumns([]string{"abcdef", "x"}, []string{"123456"}, 4, 3, 3)
+	want := []string{"abcd│123", "x   │", "    │"}
+	for i := range want {
+		if stripped := strings.ReplaceAll(got[i], styleDim.Render("│"), "│"); stripped != want[i] {
+			t.Errorf("line %d = %q, want %q", i, stripped, want[i])
+		}
+	}
+}
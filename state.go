@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// localState is prtop's persisted per-PR state, stored as JSON under the XDG
+// state directory. It is separate from config.toml, which prtop only writes
+// from the settings pane.
+type localState struct {
+	// Acks maps "repo#number" to the names of acknowledged checks.
+	Acks map[string][]string `json:"acks,omitempty"`
+	// Ignored maps "owner/repo" to the names of checks hidden in all of
+	// its PRs.
+	Ignored map[string][]string `json:"ignored,omitempty"`
+}
+
+// stateStore loads and saves localState. A store with an empty path keeps
+// state in memory only.
+type stateStore struct {
+	path  string
+	state localState
+}
+
+func defaultStatePath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "prtop", "state.json")
+}
+
+// openStateStore reads the state file at path. A missing file is not an error.
+func openStateStore(path string) (*stateStore, error) {
+	s := &stateStore{path: path}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *stateStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func prKey(repo, prNumber string) string {
+	return repo + "#" + prNumber
+}
+
+// acks returns the set of acknowledged check names for a PR.
+func (s *stateStore) acks(repo, prNumber string) map[string]bool {
+	set := map[string]bool{}
+	for _, name := range s.state.Acks[prKey(repo, prNumber)] {
+		set[name] = true
+	}
+	return set
+}
+
+// toggleAck acknowledges check on the PR, or un-acknowledges it if it
+// already was, and persists the change. It returns the new acked state.
+func (s *stateStore) toggleAck(repo, prNumber, check string) (bool, error) {
+	set := s.acks(repo, prNumber)
+	acked := !set[check]
+	if acked {
+		set[check] = true
+	} else {
+		delete(set, check)
+	}
+
+	names := sortedNames(set)
+	if s.state.Acks == nil {
+		s.state.Acks = map[string][]string{}
+	}
+	key := prKey(repo, prNumber)
+	if len(names) == 0 {
+		delete(s.state.Acks, key)
+	} else {
+		s.state.Acks[key] = names
+	}
+	return acked, s.save()
+}
+
+// ignored returns the set of check names hidden in repo's PRs.
+func (s *stateStore) ignored(repo string) map[string]bool {
+	set := map[string]bool{}
+	for _, name := range s.state.Ignored[repo] {
+		set[name] = true
+	}
+	return set
+}
+
+// toggleIgnore adds check to repo's ignore list, or removes it if it was
+// already there, and persists the change. It returns the new ignored state.
+func (s *stateStore) toggleIgnore(repo, check string) (bool, error) {
+	set := s.ignored(repo)
+	ignored := !set[check]
+	if ignored {
+		set[check] = true
+	} else {
+		delete(set, check)
+	}
+	if s.state.Ignored == nil {
+		s.state.Ignored = map[string][]string{}
+	}
+	if len(set) == 0 {
+		delete(s.state.Ignored, repo)
+	} else {
+		s.state.Ignored[repo] = sortedNames(set)
+	}
+	return ignored, s.save()
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
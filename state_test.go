@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateStoreAcks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prtop", "state.json")
+	s, err := openStateStore(path)
+	if err != nil {
+		t.Fatalf("openStateStore: %v", err)
+	}
+
+	acked, err := s.toggleAck("o/r", "1", "lint")
+	if err != nil || !acked {
+		t.Fatalf("toggleAck = %v, %v; want true, nil", acked, err)
+	}
+	if !s.acks("o/r", "1")["lint"] {
+		t.Error("lint should be acked")
+	}
+	if s.acks("o/r", "2")["lint"] {
+		t.Error("acks should be per PR")
+	}
+
+	// Persisted across reopen
+	s2, err := openStateStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if !s2.acks("o/r", "1")["lint"] {
+		t.Error("ack should persist to disk")
+	}
+
+	acked, err = s2.toggleAck("o/r", "1", "lint")
+	if err != nil || acked {
+		t.Fatalf("second toggleAck = %v, %v; want false, nil", acked, err)
+	}
+	if _, ok := s2.state.Acks["o/r#1"]; ok {
+		t.Error("empty ack list should be removed")
+	}
+}
+
+func TestStateStoreIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prtop", "state.json")
+	s, _ := openStateStore(path)
+	if ignored, err := s.toggleIgnore("o/r", "codecov"); err != nil || !ignored {
+		t.Fatalf("toggleIgnore = %v, %v; want true, nil", ignored, err)
+	}
+	s2, err := openStateStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s2.ignored("o/r")["codecov"] || s2.ignored("o/other")["codecov"] {
+		t.Errorf("ignored = %v, want codecov in o/r only", s2.state.Ignored)
+	}
+	if ignored, err := s2.toggleIgnore("o/r", "codecov"); err != nil || ignored {
+		t.Fatalf("second toggleIgnore = %v, %v; want false, nil", ignored, err)
+	}
+	if _, ok := s2.state.Ignored["o/r"]; ok {
+		t.Error("empty ignore list should be removed")
+	}
+}
+
+func TestOpenStateStore(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		s, err := openStateStore(filepath.Join(t.TempDir(), "none.json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(s.state.Acks) != 0 {
+			t.Error("state should be empty")
+		}
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := openStateStore(path); err == nil {
+			t.Error("expected error for corrupt state")
+		}
+	})
+
+	t.Run("in-memory store", func(t *testing.T) {
+		s := &stateStore{}
+		if _, err := s.toggleAck("o/r", "1", "x"); err != nil {
+			t.Errorf("in-memory toggleAck: %v", err)
+		}
+	})
+}
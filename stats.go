@@ -0,0 +1,400 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statsMaxPRs caps how many PRs `prtop stats` reads, at statsPageSize per
+// GraphQL request.
+const (
+	statsMaxPRs   = 200
+	statsPageSize = 20
+	statsTop      = 10
+)
+
+// statsQuery pages through a search for the repo's PRs. checkType: ALL
+// returns every attempt of a rerun check, not just the latest. The page
+// size and per-PR limits keep a page under GitHub's node limit.
+const statsQuery = `query($q: String!, $first: Int!, $after: String) {
+  search(query: $q, type: ISSUE, first: $first, after: $after) {
+    pageInfo { hasNextPage endCursor }
+    nodes { ... on PullRequest {
+      number
+      commits(last: 5) { nodes { commit {
+        checkSuites(first: 20) { nodes {
+          workflowRun { workflow { name } }
+          checkRuns(first: 50, filterBy: {checkType: ALL}) { nodes { name conclusion startedAt completedAt } }
+        } }
+      } } }
+    } }
+  }
+}`
+
+// statsRun is one attempt of a check run on a commit.
+type statsRun struct {
+	name        string
+	conclusion  string
+	startedAt   time.Time
+	completedAt time.Time
+}
+
+// statsPR is a PR's latest commits, oldest first, each with its check runs.
+type statsPR struct {
+	number  int
+	commits [][]statsRun
+}
+
+// checkStats aggregates one check's runs. Durations are in seconds in JSON.
+type checkStats struct {
+	Name     string `json:"name"`
+	Runs     int    `json:"runs"`
+	Failures int    `json:"failures"`
+	// Retries counts attempts after the first on the same commit
+	Retries      int     `json:"retries"`
+	AvgSeconds   float64 `json:"avg_seconds"`
+	TotalSeconds float64 `json:"total_seconds"`
+
+	total time.Duration
+	timed int
+}
+
+// ciStats is what `prtop stats` reports.
+type ciStats struct {
+	Repo  string    `json:"repo"`
+	Since time.Time `json:"since"`
+	PRs   int       `json:"prs"`
+	Runs  int       `json:"runs"`
+	// RetryRate is the share of checks on a commit that ran more than once
+	RetryRate float64 `json:"retry_rate"`
+	// TimeToGreenSeconds averages, over the PRs with a green commit, the
+	// wall time from the first check starting on the latest green commit to
+	// the last one finishing, reruns included
+	TimeToGreenSeconds float64      `json:"avg_time_to_green_seconds"`
+	GreenPRs           int          `json:"green_prs"`
+	Checks             []checkStats `json:"checks"`
+}
+
+// parseSince parses --since: a number of days ("30d"), a duration ("12h")
+// or a date ("2024-05-01").
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (want e.g. 30d, 12h or 2024-05-01)", s)
+}
+
+func runStats(s *session, args []string) (int, error) {
+	format := strings.ToLower(s.opts.format)
+	if format != "table" && format != "json" {
+		return exitFailed, fmt.Errorf("unknown stats format %q (want table or json)", s.opts.format)
+	}
+	if len(args) != 1 {
+		return exitFailed, errors.New("expected a repository: prtop stats owner/repo")
+	}
+	repo, ok := parseRepo(args[0], s.hosts...)
+	if !ok {
+		return exitFailed, fmt.Errorf("invalid repository: %s", args[0])
+	}
+	since, err := parseSince(s.opts.since, time.Now())
+	if err != nil {
+		return exitFailed, err
+	}
+	prs, err := fetchStatsPRs(s.acct(repo), repo, since)
+	if err != nil {
+		return exitFailed, err
+	}
+	stats := computeStats(repo, since, prs)
+	if format == "json" {
+		enc := json.NewEncoder(s.stdout)
+		enc.SetIndent("", "  ")
+		return exitOK, enc.Encode(stats)
+	}
+	stats.print(s.stdout)
+	return exitOK, nil
+}
+
+// fetchStatsPRs reads the check runs of the PRs updated since since, up to
+// statsMaxPRs, most recently updated first.
+func fetchStatsPRs(acct *Account, repo string, since time.Time) ([]statsPR, error) {
+	_, ownerRepo := splitRepoHost(repo)
+	q := fmt.Sprintf("repo:%s is:pr updated:>=%s sort:updated-desc", ownerRepo, since.UTC().Format(time.DateOnly))
+	var prs []statsPR
+	after := ""
+	for len(prs) < statsMaxPRs {
+		args := []string{"-f", "query=" + statsQuery, "-f", "q=" + q, "-F", fmt.Sprintf("first=%d", statsPageSize)}
+		if after != "" {
+			args = append(args, "-f", "after="+after)
+		}
+		out, err := ghAPI(acct, repo, "graphql", args...)
+		if err != nil {
+			return nil, err
+		}
+		page, next, err := parseStatsPage(out)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, page...)
+		if next == "" {
+			break
+		}
+		after = next
+	}
+	return prs, nil
+}
+
+// parseStatsPage parses one page of statsQuery, returning the cursor of the
+// next page or "" after the last.
+func parseStatsPage(out []byte) ([]statsPR, string, error) {
+	var resp struct {
+		Data struct {
+			Search struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					Number  int `json:"number"`
+					Commits struct {
+						Nodes []struct {
+							Commit struct {
+								CheckSuites struct {
+									Nodes []struct {
+										WorkflowRun *struct {
+											Workflow struct {
+												Name string `json:"name"`
+											} `json:"workflow"`
+										} `json:"workflowRun"`
+										CheckRuns struct {
+											Nodes []struct {
+												Name        string    `json:"name"`
+												Conclusion  string    `json:"conclusion"`
+												StartedAt   time.Time `json:"startedAt"`
+												CompletedAt time.Time `json:"completedAt"`
+											} `json:"nodes"`
+										} `json:"checkRuns"`
+									} `json:"nodes"`
+								} `json:"checkSuites"`
+							} `json:"commit"`
+						} `json:"nodes"`
+					} `json:"commits"`
+				} `json:"nodes"`
+			} `json:"search"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, "", fmt.Errorf("failed to parse check runs: %w", err)
+	}
+	var prs []statsPR
+	for _, n := range resp.Data.Search.Nodes {
+		if n.Number == 0 {
+			continue // an issue
+		}
+		pr := statsPR{number: n.Number}
+		for _, c := range n.Commits.Nodes {
+			var runs []statsRun
+			for _, suite := range c.Commit.CheckSuites.Nodes {
+				for _, r := range suite.CheckRuns.Nodes {
+					// The same name parsePRView gives the check
+					name := r.Name
+					if suite.WorkflowRun != nil && suite.WorkflowRun.Workflow.Name != "" {
+						name = fmt.Sprintf("%s (%s)", name, suite.WorkflowRun.Workflow.Name)
+					}
+					runs = append(runs, statsRun{name: name, conclusion: r.Conclusion, startedAt: r.StartedAt, completedAt: r.CompletedAt})
+				}
+			}
+			pr.commits = append(pr.commits, runs)
+		}
+		prs = append(prs, pr)
+	}
+	next := ""
+	if resp.Data.Search.PageInfo.HasNextPage {
+		next = resp.Data.Search.PageInfo.EndCursor
+	}
+	return prs, next, nil
+}
+
+func failedConclusion(c string) bool {
+	return c == "FAILURE" || c == "TIMED_OUT" || c == "STARTUP_FAILURE"
+}
+
+func greenConclusion(c string) bool {
+	return c == "SUCCESS" || c == "NEUTRAL" || c == "SKIPPED"
+}
+
+// computeStats aggregates the PRs' check runs. Runs that are still going,
+// were skipped or went stale are left out of the per-check numbers.
+func computeStats(repo string, since time.Time, prs []statsPR) ciStats {
+	st := ciStats{Repo: repo, Since: since, PRs: len(prs)}
+	byName := map[string]*checkStats{}
+	pairs, retried := 0, 0
+	var toGreen time.Duration
+	for _, pr := range prs {
+		var green time.Duration
+		isGreen := false
+		for _, runs := range pr.commits {
+			attempts := map[string][]statsRun{}
+			for _, r := range runs {
+				if r.conclusion == "" || r.conclusion == "SKIPPED" || r.conclusion == "STALE" {
+					continue
+				}
+				attempts[r.name] = append(attempts[r.name], r)
+				cs := byName[r.name]
+				if cs == nil {
+					cs = &checkStats{Name: r.name}
+					byName[r.name] = cs
+				}
+				cs.Runs++
+				st.Runs++
+				if failedConclusion(r.conclusion) {
+					cs.Failures++
+				}
+				if !r.startedAt.IsZero() && !r.completedAt.Before(r.startedAt) {
+					cs.total += r.completedAt.Sub(r.startedAt)
+					cs.timed++
+				}
+			}
+			if span, ok := greenSpan(attempts); ok {
+				green, isGreen = span, true
+			}
+			for name, a := range attempts {
+				pairs++
+				if len(a) > 1 {
+					retried++
+					byName[name].Retries += len(a) - 1
+				}
+			}
+		}
+		if isGreen {
+			toGreen += green
+			st.GreenPRs++
+		}
+	}
+	if pairs > 0 {
+		st.RetryRate = float64(retried) / float64(pairs)
+	}
+	if st.GreenPRs > 0 {
+		st.TimeToGreenSeconds = (toGreen / time.Duration(st.GreenPRs)).Seconds()
+	}
+	for _, cs := range byName {
+		if cs.timed > 0 {
+			cs.AvgSeconds = (cs.total / time.Duration(cs.timed)).Seconds()
+		}
+		cs.TotalSeconds = cs.total.Seconds()
+		st.Checks = append(st.Checks, *cs)
+	}
+	// Where the CI time goes: most total time first
+	slices.SortFunc(st.Checks, func(a, b checkStats) int {
+		return cmp.Or(cmp.Compare(b.total, a.total), strings.Compare(a.Name, b.Name))
+	})
+	return st
+}
+
+// greenSpan reports whether a commit's checks all ended green, going by
+// each check's latest attempt, and if so the wall time from the first
+// attempt starting to the last one finishing.
+func greenSpan(attempts map[string][]statsRun) (time.Duration, bool) {
+	if len(attempts) == 0 {
+		return 0, false
+	}
+	var first, last time.Time
+	for _, a := range attempts {
+		latest := a[0]
+		for _, r := range a {
+			if r.startedAt.After(latest.startedAt) {
+				latest = r
+			}
+			if !r.startedAt.IsZero() && (first.IsZero() || r.startedAt.Before(first)) {
+				first = r.startedAt
+			}
+			if r.completedAt.After(last) {
+				last = r.completedAt
+			}
+		}
+		if !greenConclusion(latest.conclusion) {
+			return 0, false
+		}
+	}
+	if first.IsZero() || last.Before(first) {
+		return 0, false
+	}
+	return last.Sub(first), true
+}
+
+// topChecks returns up to statsTop checks ordered by key, largest first,
+// leaving out those where key is zero.
+func (st ciStats) topChecks(key func(checkStats) float64) []checkStats {
+	var top []checkStats
+	for _, c := range st.Checks {
+		if key(c) > 0 {
+			top = append(top, c)
+		}
+	}
+	slices.SortStableFunc(top, func(a, b checkStats) int { return cmp.Compare(key(b), key(a)) })
+	return top[:min(len(top), statsTop)]
+}
+
+func secondsText(s float64) string {
+	return formatDuration(int(s + 0.5))
+}
+
+// print writes the stats as three tables, slowest, most failing and most
+// retried checks, and the repo-wide averages.
+func (st ciStats) print(w io.Writer) {
+	fmt.Fprintf(w, "%s since %s: %d PRs, %d check runs\n", st.Repo, st.Since.Format(time.DateOnly), st.PRs, st.Runs)
+	if st.Runs == 0 {
+		return
+	}
+	nameW := len("MOST RETRIED CHECKS")
+	for _, c := range st.Checks {
+		nameW = max(nameW, len(c.Name))
+	}
+	nameW = min(nameW, 50)
+	table := func(title, col2, col3 string, key func(checkStats) float64, cells func(checkStats) (string, string)) {
+		top := st.topChecks(key)
+		if len(top) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%-*s  %5s  %8s  %8s\n", nameW, title, "RUNS", col2, col3)
+		for _, c := range top {
+			a, b := cells(c)
+			fmt.Fprintf(w, "%-*s  %5d  %8s  %8s\n", nameW, elide(c.Name, nameW), c.Runs, a, b)
+		}
+	}
+	table("SLOWEST CHECKS", "AVERAGE", "TOTAL",
+		func(c checkStats) float64 { return c.AvgSeconds },
+		func(c checkStats) (string, string) {
+			return secondsText(c.AvgSeconds), formatInterval(c.total.Round(time.Second))
+		})
+	table("MOST FAILING CHECKS", "FAILED", "RATE",
+		func(c checkStats) float64 { return float64(c.Failures) },
+		func(c checkStats) (string, string) {
+			return strconv.Itoa(c.Failures), fmt.Sprintf("%d%%", c.Failures*100/c.Runs)
+		})
+	table("MOST RETRIED CHECKS", "RETRIES", "RATE",
+		func(c checkStats) float64 { return float64(c.Retries) },
+		func(c checkStats) (string, string) {
+			return strconv.Itoa(c.Retries), fmt.Sprintf("%d%%", c.Retries*100/c.Runs)
+		})
+
+	fmt.Fprintln(w)
+	if st.GreenPRs > 0 {
+		fmt.Fprintf(w, "Time to green: %s on average over %d PRs\n", secondsText(st.TimeToGreenSeconds), st.GreenPRs)
+	}
+	fmt.Fprintf(w, "Reruns: %.0f%% of checks on a commit ran more than once\n", st.RetryRate*100)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// statsPage builds a statsQuery response. Each commit is a list of
+// name, conclusion, start and end ("10:00") tuples in the CI workflow.
+func statsPage(t *testing.T, next string, prs map[int][][][4]string) string {
+	t.Helper()
+	type run struct {
+		Name        string `json:"name"`
+		Conclusion  string `json:"conclusion"`
+		StartedAt   string `json:"startedAt"`
+		CompletedAt string `json:"completedAt,omitempty"`
+	}
+	at := func(clock string) string {
+		if clock == "" {
+			return ""
+		}
+		return "2024-05-01T" + clock + ":00Z"
+	}
+	var nodes []any
+	for number, commits := range prs {
+		var commitNodes []any
+		for _, runs := range commits {
+			var rs []run
+			for _, r := range runs {
+				rs = append(rs, run{r[0], r[1], at(r[2]), at(r[3])})
+			}
+			commitNodes = append(commitNodes, map[string]any{"commit": map[string]any{"checkSuites": map[string]any{"nodes": []any{
+				map[string]any{"workflowRun": map[string]any{"workflow": map[string]any{"name": "CI"}}, "checkRuns": map[string]any{"nodes": rs}},
+			}}}})
+		}
+		nodes = append(nodes, map[string]any{"number": number, "commits": map[string]any{"nodes": commitNodes}})
+	}
+	out, err := json.Marshal(map[string]any{"data": map[string]any{"search": map[string]any{
+		"pageInfo": map[string]any{"hasNextPage": next != "", "endCursor": next},
+		"nodes":    nodes,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestRunStats(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"q=repo:o/r is:pr": statsPage(t, "cursor-1", map[int][][][4]string{
+			// Failed, rerun and passed: 20m to green
+			1: {{
+				{"build", "FAILURE", "10:00", "10:10"},
+				{"build", "SUCCESS", "10:12", "10:20"},
+				{"lint", "SUCCESS", "10:00", "10:02"},
+			}},
+		}),
+		"after=cursor-1 graphql": statsPage(t, "", map[int][][][4]string{
+			// Red, then green on the next push in 8m
+			2: {
+				{{"build", "FAILURE", "11:00", "11:06"}, {"lint", "SUCCESS", "11:00", "11:04"}},
+				{{"build", "SUCCESS", "12:00", "12:08"}, {"lint", "SKIPPED", "12:00", "12:00"}, {"e2e", "", "12:00", ""}},
+			},
+		}),
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+	s, stdout, _ := testSession(t)
+	s.opts.format = "json"
+
+	if _, err := runStats(s, []string{"o/r"}); err != nil {
+		t.Fatal(err)
+	}
+	var st ciStats
+	if err := json.Unmarshal(stdout.Bytes(), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.PRs != 2 || st.Runs != 6 || st.GreenPRs != 2 || st.TimeToGreenSeconds != (14*time.Minute).Seconds() ||
+		math.Abs(st.RetryRate-0.2) > 1e-9 || len(st.Checks) != 2 {
+		t.Fatalf("stats = %+v", st)
+	}
+	build := st.Checks[0]
+	if build.Name != "build (CI)" || build.Runs != 4 || build.Failures != 2 || build.Retries != 1 ||
+		build.AvgSeconds != (8*time.Minute).Seconds() || build.TotalSeconds != (32*time.Minute).Seconds() {
+		t.Errorf("build = %+v", build)
+	}
+
+	stdout.Reset()
+	s.opts.format = "table"
+	if _, err := runStats(s, []string{"o/r"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		": 2 PRs, 6 check runs",
+		"SLOWEST CHECKS",
+		"build (CI)               4     8m00s       32m",
+		"lint (CI)                2     3m00s        6m",
+		"build (CI)               4         2       50%",
+		"Time to green: 14m00s on average over 2 PRs",
+		"Reruns: 20% of checks on a commit ran more than once",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, stdout.String())
+		}
+	}
+
+	for _, args := range [][]string{{}, {"nope"}} {
+		if _, err := runStats(s, args); err == nil {
+			t.Errorf("runStats(%q): no error", args)
+		}
+	}
+	s.opts.format = "csv"
+	if _, err := runStats(s, []string{"o/r"}); err == nil {
+		t.Error("csv: no error")
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"30d":        time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local),
+		"12h":        time.Date(2024, 5, 31, 0, 0, 0, 0, time.Local),
+		"2024-05-01": time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local),
+	}
+	for in, want := range tests {
+		if got, err := parseSince(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0d", "-3d", "soon"} {
+		if _, err := parseSince(in, now); err == nil {
+			t.Errorf("parseSince(%q): no error", in)
+		}
+	}
+}
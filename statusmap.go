@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// StatusOverride maps a state or conclusion GitHub reports, such as
+// ACTION_REQUIRED or a provider's own BLOCKED, to a status. With Check set,
+// it only applies to the checks whose name matches that glob.
+type StatusOverride struct {
+	Raw    string `toml:"raw"`
+	Check  string `toml:"check"`
+	Status string `toml:"status"`
+}
+
+type statusOverride struct {
+	raw    string // upper case, as normalizeStatus compares
+	check  string // lower-case glob; "" for every check
+	status CheckStatus
+}
+
+// statusOverrides are the [[status]] entries of the config, consulted
+// before the built-in table.
+var statusOverrides []statusOverride
+
+// statusNames are the statuses an override can map to.
+var statusNames = map[string]CheckStatus{
+	"pass": Pass, "fail": Fail, "running": Running,
+	"skipped": Skipped, "cancelled": Cancelled, "neutral": Neutral,
+}
+
+// parseStatusOverrides validates the config's [[status]] entries.
+func parseStatusOverrides(entries []StatusOverride) ([]statusOverride, error) {
+	var out []statusOverride
+	for i, e := range entries {
+		raw := strings.ToUpper(strings.TrimSpace(e.Raw))
+		if raw == "" {
+			return nil, fmt.Errorf("status[%d]: raw is required", i)
+		}
+		status, ok := statusNames[strings.ToLower(strings.TrimSpace(e.Status))]
+		if !ok {
+			return nil, fmt.Errorf("status[%d]: unknown status %q (want pass, fail, running, skipped, cancelled or neutral)", i, e.Status)
+		}
+		if _, err := path.Match(e.Check, ""); err != nil {
+			return nil, fmt.Errorf("status[%d]: bad check pattern %q", i, e.Check)
+		}
+		out = append(out, statusOverride{raw: raw, check: strings.ToLower(e.Check), status: status})
+	}
+	return out, nil
+}
+
+// overriddenStatus is the status the config gives raw: for a check named
+// one of names, or with no names for any check.
+func overriddenStatus(raw string, names ...string) (CheckStatus, bool) {
+	for _, o := range statusOverrides {
+		if o.raw != raw || (o.check == "") != (len(names) == 0) {
+			continue
+		}
+		if o.check == "" {
+			return o.status, true
+		}
+		for _, name := range names {
+			if ok, _ := path.Match(o.check, strings.ToLower(name)); ok {
+				return o.status, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// checkStatus normalizes a check's raw state, letting a [[status]] entry
+// for the check's name, with or without its workflow, win over the rest.
+func checkStatus(raw string, names ...string) CheckStatus {
+	if status, ok := overriddenStatus(strings.ToUpper(strings.TrimSpace(raw)), names...); ok {
+		return status
+	}
+	return normalizeStatus(raw)
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestStatusOverrides(t *testing.T) {
+	overrides, err := parseStatusOverrides([]StatusOverride{
+		{Raw: "blocked", Status: "FAIL"},
+		{Raw: "ACTION_REQUIRED", Check: "license/*", Status: "skipped"},
+		{Raw: "neutral", Check: "lint*", Status: "fail"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusOverrides = overrides
+	t.Cleanup(func() { statusOverrides = nil })
+
+	tests := []struct {
+		raw   string
+		names []string
+		want  CheckStatus
+	}{
+		{"BLOCKED", nil, Fail},
+		{"blocked", []string{"build"}, Fail},
+		{"ACTION_REQUIRED", []string{"license/cla"}, Skipped},
+		{"ACTION_REQUIRED", []string{"build"}, Fail},
+		{"ACTION_REQUIRED", nil, Fail},
+		{"NEUTRAL", []string{"lint (CI)", "lint"}, Fail},
+		{"NEUTRAL", []string{"test (CI)", "test"}, Neutral},
+	}
+	for _, tt := range tests {
+		if got := checkStatus(tt.raw, tt.names...); got != tt.want {
+			t.Errorf("checkStatus(%q, %q) = %v, want %v", tt.raw, tt.names, got, tt.want)
+		}
+	}
+
+	checks := parseCheckItems([]ghCheckItem{
+		{Typename: "StatusContext", Context: "license/cla", State: "ACTION_REQUIRED"},
+		{Typename: "StatusContext", Context: "deploy", State: "BLOCKED"},
+	})
+	for _, c := range checks {
+		want := map[string]CheckStatus{"license/cla": Skipped, "deploy": Fail}[c.Name]
+		if c.Status != want {
+			t.Errorf("%s = %v, want %v", c.Name, c.Status, want)
+		}
+	}
+}
+
+func TestParseStatusOverridesErrors(t *testing.T) {
+	for _, e := range []StatusOverride{
+		{Status: "fail"},
+		{Raw: "BLOCKED", Status: "broken"},
+		{Raw: "BLOCKED", Check: "[x", Status: "fail"},
+	} {
+		if _, err := parseStatusOverrides([]StatusOverride{e}); err == nil {
+			t.Errorf("parseStatusOverrides(%+v) should fail", e)
+		}
+	}
+}
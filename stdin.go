@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// stdinPR is a PR as gh prints it with --json: `gh search prs --json url`,
+// `gh pr list --json url,title` and the like.
+type stdinPR struct {
+	URL        string `json:"url"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+func (p stdinPR) summary(hosts []string) (PRSummary, error) {
+	if p.URL != "" {
+		repo, number, ok := parsePRRef(p.URL, hosts...)
+		if !ok {
+			return PRSummary{}, fmt.Errorf("not a PR URL: %s", p.URL)
+		}
+		n, _ := strconv.Atoi(number)
+		return PRSummary{Repo: repo, Number: n, Title: p.Title, URL: p.URL}, nil
+	}
+	if p.Repository.NameWithOwner != "" && p.Number > 0 {
+		return PRSummary{Repo: p.Repository.NameWithOwner, Number: p.Number, Title: p.Title}, nil
+	}
+	return PRSummary{}, errors.New("JSON PR needs a url, or repository and number")
+}
+
+// readPRList reads the PRs for `prtop --stdin`: a JSON array of PRs as gh
+// prints it, or one PR per line as a URL, owner/repo#123 or a JSON object.
+// Blank lines and lines starting with # are skipped, and duplicates dropped.
+func readPRList(r io.Reader, hosts []string) ([]PRSummary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+
+	var prs []PRSummary
+	add := func(pr PRSummary) {
+		for _, p := range prs {
+			if p.Repo == pr.Repo && p.Number == pr.Number {
+				return
+			}
+		}
+		prs = append(prs, pr)
+	}
+
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("[")) {
+		var raw []stdinPR
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse stdin: %w", err)
+		}
+		for i, p := range raw {
+			pr, err := p.summary(hosts)
+			if err != nil {
+				return nil, fmt.Errorf("stdin PR %d: %w", i+1, err)
+			}
+			add(pr)
+		}
+		return prs, nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(text, "{") {
+			var p stdinPR
+			if err := json.Unmarshal([]byte(text), &p); err != nil {
+				return nil, fmt.Errorf("stdin line %d: %w", line, err)
+			}
+			pr, err := p.summary(hosts)
+			if err != nil {
+				return nil, fmt.Errorf("stdin line %d: %w", line, err)
+			}
+			add(pr)
+			continue
+		}
+		pr, ok, err := parseWatchLine(text, hosts)
+		if err != nil {
+			return nil, fmt.Errorf("stdin line %d: %w", line, err)
+		}
+		if ok {
+			add(pr)
+		}
+	}
+	return prs, sc.Err()
+}
+
+// isTerminal reports whether stdin or stdout is a character device, i.e.
+// nothing was piped in or out.
+func isTerminal(r any) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadPRList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []PRSummary
+	}{
+		{"urls", "https://github.com/o/r/pull/1\n\n# blockers\no/r#2\nhttps://github.com/o/r/pull/1\n",
+			[]PRSummary{{Repo: "o/r", Number: 1}, {Repo: "o/r", Number: 2}}},
+		{"gh search --json", `[{"url":"https://github.com/o/r/pull/3","title":"Fix"},
+			{"number":4,"repository":{"nameWithOwner":"x/y"}}]`,
+			[]PRSummary{{Repo: "o/r", Number: 3, Title: "Fix", URL: "https://github.com/o/r/pull/3"}, {Repo: "x/y", Number: 4}}},
+		{"json lines", "{\"url\":\"https://github.com/o/r/pull/5\"}\no/r#6\n",
+			[]PRSummary{{Repo: "o/r", Number: 5, URL: "https://github.com/o/r/pull/5"}, {Repo: "o/r", Number: 6}}},
+		{"empty", "\n  \n", nil},
+		{"empty array", "[]", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readPRList(strings.NewReader(tt.input), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("readPRList = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("PR %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadPRListErrors(t *testing.T) {
+	tests := []struct{ input, wantErr string }{
+		{"o/r#1\nnope\n", "stdin line 2: not a PR reference: nope"},
+		{`[{"title":"no url"}]`, "stdin PR 1: JSON PR needs a url"},
+		{`[{"url":"https://github.com/o/r/issues/1"}]`, "not a PR URL"},
+		{"[{", "failed to parse stdin"},
+		{"{\"url\": 5}\n", "stdin line 1:"},
+	}
+	for _, tt := range tests {
+		if _, err := readPRList(strings.NewReader(tt.input), nil); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("readPRList(%q) err = %v, want %q", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStdinDashboard(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.stdinPRs = []PRSummary{{Repo: "o/r", Number: 1}, {Repo: "x/y", Number: 9}}
+	m.width = 100
+	m.height = 20
+	if out := m.View(); !strings.Contains(out, "Pull requests from stdin") {
+		t.Errorf("subtitle should say the PRs came from stdin, got %q", out)
+	}
+
+	msg, ok := m.fetchPRListCmd()().(prListMsg)
+	if !ok || msg.err != nil || len(msg.prs) != 2 || msg.prs[1].Repo != "x/y" {
+		t.Fatalf("fetchPRListCmd = %+v, want the stdin PRs", msg)
+	}
+	// The dashboard appends watched PRs to the list it is given
+	msg.prs[0].Title = "changed"
+	if m.stdinPRs[0].Title != "" {
+		t.Error("fetchPRListCmd should hand out a copy of the stdin PRs")
+	}
+}
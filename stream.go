@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamEvent is one line of `prtop stream` output.
+//
+//	start  the first poll: the PR's title, head commit and rollup status
+//	check  a check appeared (no from), changed status, or was rerun
+//	push   the head commit changed (from and to are SHAs); every check of
+//	       the new commit follows as a check event
+//	done   no check is running any more; status is the final rollup
+type streamEvent struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Repo       string    `json:"repo"`
+	Number     int       `json:"number"`
+	HeadSHA    string    `json:"headSha,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Check      string    `json:"check,omitempty"`
+	Workflow   string    `json:"workflow,omitempty"`
+	From       string    `json:"from,omitempty"`
+	To         string    `json:"to,omitempty"`
+	DetailsURL string    `json:"detailsUrl,omitempty"`
+	Rerun      bool      `json:"rerun,omitempty"`
+	Status     string    `json:"status,omitempty"`
+}
+
+// streamEvents returns the events between two polls of a PR. prev is nil
+// for the first poll.
+func streamEvents(prev, cur *PRData, status string, base streamEvent) []streamEvent {
+	var events []streamEvent
+	var old []Check
+	switch {
+	case prev == nil:
+		e := base
+		e.Type, e.Title, e.Status = "start", cur.Title, status
+		events = append(events, e)
+	case cur.HeadSHA != "" && prev.HeadSHA != "" && cur.HeadSHA != prev.HeadSHA:
+		e := base
+		e.Type, e.From, e.To = "push", prev.HeadSHA, cur.HeadSHA
+		events = append(events, e)
+	default:
+		old = prev.Checks
+	}
+
+	byName := make(map[string]Check, len(cur.Checks))
+	for _, c := range cur.Checks {
+		byName[c.Name] = c
+	}
+	for _, d := range diffChecks(old, cur.Checks, base.Time) {
+		c := byName[d.Check]
+		e := base
+		e.Type, e.Check, e.Workflow, e.DetailsURL, e.Rerun = "check", c.Name, c.Workflow, c.DetailsURL, d.Rerun
+		e.To = strings.ToLower(d.To.String())
+		if !d.New {
+			e.From = strings.ToLower(d.From.String())
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// runStream polls a PR like wait does, writing an NDJSON event for each
+// check transition instead of a summary. It exits like wait.
+func runStream(s *session, args []string) (int, error) {
+	var deadline time.Time
+	if s.opts.timeout > 0 {
+		deadline = time.Now().Add(s.opts.timeout)
+	}
+	enc := json.NewEncoder(s.stdout)
+	var prev *PRData
+	var settle settler
+	for {
+		repo, prNumber, data, status, err := s.fetchChecks(args)
+		if err != nil {
+			return exitFailed, err
+		}
+		number, _ := strconv.Atoi(prNumber)
+		base := streamEvent{Time: time.Now().UTC(), Repo: repo, Number: number, HeadSHA: data.HeadSHA}
+		for _, e := range streamEvents(prev, data, status, base) {
+			if err := enc.Encode(e); err != nil {
+				return exitFailed, err
+			}
+		}
+		prev = data
+
+		settled := settle.observe(data.Checks, time.Now(), s.settle)
+		if status != rollupPending && (settled || status != rollupSuccess) {
+			e := base
+			e.Type, e.Status = "done", status
+			if err := enc.Encode(e); err != nil {
+				return exitFailed, err
+			}
+			return rollupExitCode(status), nil
+		}
+		if !deadline.IsZero() && time.Now().Add(s.interval).After(deadline) {
+			return exitPending, fmt.Errorf("timed out after %s", s.opts.timeout)
+		}
+		time.Sleep(s.interval)
+	}
+}
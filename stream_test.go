@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ghSequence answers `gh pr view` with each response in turn, repeating the
+// last one.
+func ghSequence(t *testing.T, responses ...string) {
+	t.Helper()
+	calls := 0
+	ghOverride = ghFunc(func(args []string) ([]byte, error) {
+		if len(args) < 2 || args[0] != "pr" || args[1] != "view" {
+			return nil, fmt.Errorf("unexpected gh %v", args)
+		}
+		r := responses[min(calls, len(responses)-1)]
+		calls++
+		return []byte(r), nil
+	})
+	t.Cleanup(func() { ghOverride = nil })
+}
+
+func streamPR(sha string, checks ...string) string {
+	return fmt.Sprintf(`{"title":"Fix it","headRefOid":%q,"statusCheckRollup":[%s]}`, sha, strings.Join(checks, ","))
+}
+
+func streamCheck(name, status, conclusion string) string {
+	return fmt.Sprintf(`{"__typename":"CheckRun","name":%q,"workflowName":"CI","status":%q,"conclusion":%q,"detailsUrl":"https://example.com/%s"}`,
+		name, status, conclusion, name)
+}
+
+func readStream(t *testing.T, out string) []streamEvent {
+	t.Helper()
+	var events []streamEvent
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		var e streamEvent
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", sc.Text(), err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// eventSummary renders events compactly for comparison.
+func eventSummary(events []streamEvent) []string {
+	var got []string
+	for _, e := range events {
+		s := e.Type
+		switch e.Type {
+		case "check":
+			s += fmt.Sprintf(" %s %s>%s", e.Check, e.From, e.To)
+		case "push":
+			s += fmt.Sprintf(" %s>%s", e.From, e.To)
+		case "start", "done":
+			s += " " + e.Status
+		}
+		got = append(got, s)
+	}
+	return got
+}
+
+func TestRunStream(t *testing.T) {
+	ghSequence(t,
+		streamPR("aaa", streamCheck("build", "IN_PROGRESS", ""), streamCheck("test", "QUEUED", "")),
+		streamPR("aaa", streamCheck("build", "IN_PROGRESS", ""), streamCheck("test", "QUEUED", "")),
+		streamPR("aaa", streamCheck("build", "COMPLETED", "SUCCESS"), streamCheck("test", "IN_PROGRESS", ""),
+			streamCheck("lint", "IN_PROGRESS", "")),
+		streamPR("bbb", streamCheck("build", "IN_PROGRESS", "")),
+		streamPR("bbb", streamCheck("build", "COMPLETED", "FAILURE")),
+	)
+	s, stdout, _ := testSession(t)
+	code, err := runStream(s, []string{"o/r#7"})
+	if code != exitFailed || err != nil {
+		t.Fatalf("runStream = %d, %v; want 1", code, err)
+	}
+
+	events := readStream(t, stdout.String())
+	want := []string{
+		"start pending",
+		"check build (CI) >running",
+		"check test (CI) >running",
+		"check lint (CI) >running",
+		"check build (CI) running>pass",
+		"push aaa>bbb",
+		"check build (CI) >running",
+		"check build (CI) running>fail",
+		"done failure",
+	}
+	if got := eventSummary(events); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	first := events[1]
+	if first.Repo != "o/r" || first.Number != 7 || first.HeadSHA != "aaa" || first.Workflow != "CI" ||
+		first.DetailsURL != "https://example.com/build" || first.Time.IsZero() {
+		t.Errorf("check event = %+v", first)
+	}
+	if events[0].Title != "Fix it" {
+		t.Errorf("start event = %+v, want the title", events[0])
+	}
+}
+
+func TestRunStreamTimeout(t *testing.T) {
+	ghSequence(t, streamPR("aaa", streamCheck("build", "IN_PROGRESS", "")))
+	s, stdout, _ := testSession(t)
+	s.opts.timeout = 30 * time.Millisecond
+	code, err := runStream(s, []string{"o/r#7"})
+	if code != exitPending || err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("runStream = %d, %v; want 8 and a timeout", code, err)
+	}
+	// Polls that change nothing print nothing
+	if got := eventSummary(readStream(t, stdout.String())); len(got) != 2 {
+		t.Errorf("events = %v, want start and one check", got)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// testReport summarizes the tests a check ran.
+type testReport struct {
+	Source  string // "go test" or the JUnit artifact's name
+	Ran     int
+	Failed  int
+	Skipped int
+	Failing []string // failing test (or package) names, in log order
+}
+
+// parseGoTestLog finds `go test` results in a job log, either the plain
+// output (--- PASS/FAIL/SKIP lines, with -v or not) or `go test -json`
+// events. It returns nil when the log has none.
+func parseGoTestLog(lines []string) *testReport {
+	r := &testReport{Source: "go test"}
+	found := false
+	record := func(action, name string) {
+		found = true
+		switch action {
+		case "pass", "PASS":
+			r.Ran++
+		case "fail", "FAIL":
+			r.Ran++
+			r.Failed++
+			r.Failing = append(r.Failing, name)
+		case "skip", "SKIP":
+			r.Skipped++
+		}
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, `{"`) {
+			var ev struct{ Action, Package, Test string }
+			if json.Unmarshal([]byte(trimmed), &ev) == nil && ev.Test != "" {
+				record(ev.Action, ev.Test)
+			}
+			continue
+		}
+		if rest, ok := strings.CutPrefix(trimmed, "--- "); ok {
+			action, name, ok := strings.Cut(rest, ": ")
+			if !ok {
+				continue
+			}
+			name, _, _ = strings.Cut(name, " (")
+			record(action, name)
+			continue
+		}
+		// A package that failed to build has no test lines of its own
+		if pkg, ok := strings.CutPrefix(trimmed, "FAIL\t"); ok && strings.HasSuffix(pkg, "[build failed]") {
+			found = true
+			r.Failing = append(r.Failing, pkg)
+		}
+	}
+	if !found {
+		return nil
+	}
+	return r
+}
+
+// junitSuite is a <testsuite>, or a <testsuites> wrapping more of them.
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []struct {
+		Name      string    `xml:"name,attr"`
+		Classname string    `xml:"classname,attr"`
+		Failure   *struct{} `xml:"failure"`
+		Error     *struct{} `xml:"error"`
+		Skipped   *struct{} `xml:"skipped"`
+	} `xml:"testcase"`
+}
+
+func (s junitSuite) addTo(r *testReport) {
+	for _, c := range s.Cases {
+		name := c.Name
+		if c.Classname != "" {
+			name = c.Classname + "." + c.Name
+		}
+		switch {
+		case c.Skipped != nil:
+			r.Skipped++
+		case c.Failure != nil || c.Error != nil:
+			r.Ran++
+			r.Failed++
+			r.Failing = append(r.Failing, name)
+		default:
+			r.Ran++
+		}
+	}
+	for _, sub := range s.Suites {
+		sub.addTo(r)
+	}
+}
+
+// parseJUnit adds the test cases of one JUnit XML document to r.
+func parseJUnit(data []byte, r *testReport) error {
+	var s junitSuite
+	if err := xml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	s.addTo(r)
+	return nil
+}
+
+// parseJUnitZip reads every .xml file in an artifact archive. It returns nil
+// when none of them holds test cases.
+func parseJUnitZip(name string, data []byte) (*testReport, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("artifact %s: %w", name, err)
+	}
+	r := &testReport{Source: name}
+	for _, f := range zr.File {
+		if !strings.EqualFold(path.Ext(f.Name), ".xml") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("artifact %s: %w", name, err)
+		}
+		doc, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("artifact %s: %w", name, err)
+		}
+		if err := parseJUnit(doc, r); err != nil {
+			logger.Debug("skipping non-JUnit XML", "artifact", name, "file", f.Name, "err", err)
+		}
+	}
+	if r.Ran+r.Skipped == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// fetchJUnitReport looks through an Actions run's artifacts for JUnit XML
+// and summarizes the first one that has test results. It returns nil when
+// there is none.
+func fetchJUnitReport(acct *Account, repo, runID string) (*testReport, error) {
+	out, err := ghAPI(acct, repo, "repos/{repo}/actions/runs/"+runID+"/artifacts")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Artifacts []struct {
+			ID      int64  `json:"id"`
+			Name    string `json:"name"`
+			Expired bool   `json:"expired"`
+		} `json:"artifacts"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse artifacts: %w", err)
+	}
+	for _, a := range list.Artifacts {
+		if a.Expired || !looksLikeTestResults(a.Name) {
+			continue
+		}
+		data, err := ghAPI(acct, repo, fmt.Sprintf("repos/{repo}/actions/artifacts/%d/zip", a.ID))
+		if err != nil {
+			return nil, err
+		}
+		r, err := parseJUnitZip(a.Name, data)
+		if err != nil || r != nil {
+			return r, err
+		}
+	}
+	return nil, nil
+}
+
+// looksLikeTestResults guesses from an artifact's name whether it holds
+// JUnit XML, to avoid downloading build outputs.
+func looksLikeTestResults(name string) bool {
+	name = strings.ToLower(name)
+	for _, hint := range []string{"junit", "test", "xunit", "surefire"} {
+		if strings.Contains(name, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTestReport is the detail pane's mini-report: counts, then the
+// failing tests.
+func renderTestReport(r *testReport) []string {
+	summary := fmt.Sprintf("Tests:     %d ran, %d failed", r.Ran, r.Failed)
+	if r.Skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", r.Skipped)
+	}
+	summary += " (" + r.Source + ")"
+	style := stylePass
+	if r.Failed > 0 || len(r.Failing) > 0 {
+		style = styleFail
+	}
+	lines := []string{style.Render(summary)}
+	for _, name := range r.Failing {
+		lines = append(lines, "  "+styleFail.Render("FAIL")+" "+name)
+	}
+	return lines
+}
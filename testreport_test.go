@@ -0,0 +1,164 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// parseGoTestLog
+// ---------------------------------------------------------------------------
+
+func TestParseGoTestLog(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  *testReport
+	}{
+		{"no tests", []string{"Run make build", "done"}, nil},
+		{"verbose", []string{
+			"=== RUN   TestA",
+			"--- PASS: TestA (0.00s)",
+			"=== RUN   TestB",
+			"    --- FAIL: TestB/empty (0.01s)",
+			"--- FAIL: TestB (0.01s)",
+			"--- SKIP: TestC (0.00s)",
+			"FAIL\texample.com/pkg\t0.02s",
+		}, &testReport{Source: "go test", Ran: 3, Failed: 2, Skipped: 1, Failing: []string{"TestB/empty", "TestB"}}},
+		{"build failure", []string{
+			"ok  \texample.com/a\t0.01s",
+			"FAIL\texample.com/b [build failed]",
+		}, &testReport{Source: "go test", Failing: []string{"example.com/b [build failed]"}}},
+		{"json", []string{
+			`{"Action":"run","Package":"p","Test":"TestA"}`,
+			`{"Action":"pass","Package":"p","Test":"TestA"}`,
+			`{"Action":"fail","Package":"p","Test":"TestB"}`,
+			`{"Action":"fail","Package":"p"}`,
+		}, &testReport{Source: "go test", Ran: 2, Failed: 1, Failing: []string{"TestB"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseGoTestLog(tt.lines)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("parseGoTestLog() = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && (got.Ran != tt.want.Ran || got.Failed != tt.want.Failed ||
+				got.Skipped != tt.want.Skipped || !slices.Equal(got.Failing, tt.want.Failing)) {
+				t.Errorf("parseGoTestLog() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// JUnit
+// ---------------------------------------------------------------------------
+
+const junitXML = `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="api" tests="3">
+    <testcase classname="api.Users" name="create"/>
+    <testcase classname="api.Users" name="delete"><failure message="boom"/></testcase>
+    <testcase classname="api.Users" name="slow"><skipped/></testcase>
+  </testsuite>
+  <testsuite name="db"><testcase name="migrate"><error/></testcase></testsuite>
+</testsuites>`
+
+func zipOf(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseJUnitZip(t *testing.T) {
+	t.Run("counts cases across files", func(t *testing.T) {
+		data := zipOf(t, map[string]string{
+			"reports/TEST-api.xml": junitXML,
+			"reports/single.XML":   `<testsuite><testcase name="one"/></testsuite>`,
+			"reports/notes.txt":    "not xml",
+			"reports/pom.xml":      "<project><name>x</name></project>",
+		})
+		r, err := parseJUnitZip("test-results", data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r == nil || r.Source != "test-results" || r.Ran != 4 || r.Failed != 2 || r.Skipped != 1 {
+			t.Fatalf("report = %+v", r)
+		}
+		slices.Sort(r.Failing)
+		if want := []string{"api.Users.delete", "migrate"}; !slices.Equal(r.Failing, want) {
+			t.Errorf("Failing = %q, want %q", r.Failing, want)
+		}
+	})
+
+	t.Run("no test cases", func(t *testing.T) {
+		r, err := parseJUnitZip("test-logs", zipOf(t, map[string]string{"a.txt": "x"}))
+		if r != nil || err != nil {
+			t.Errorf("got %+v, %v; want nil, nil", r, err)
+		}
+	})
+
+	t.Run("not a zip", func(t *testing.T) {
+		if _, err := parseJUnitZip("junit", []byte("nope")); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestFetchJUnitReportSkipsOtherArtifacts(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"actions/runs/5/artifacts": `{"artifacts":[{"id":1,"name":"dist-linux"},{"id":2,"name":"junit","expired":true}]}`,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	r, err := fetchJUnitReport(nil, "o/r", "5")
+	if r != nil || err != nil {
+		t.Errorf("got %+v, %v; want nil, nil", r, err)
+	}
+}
+
+func TestLooksLikeTestResults(t *testing.T) {
+	for name, want := range map[string]bool{
+		"junit-report": true, "Test Results": true, "surefire-reports": true,
+		"dist": false, "coverage": false,
+	} {
+		if got := looksLikeTestResults(name); got != want {
+			t.Errorf("looksLikeTestResults(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Detail pane
+// ---------------------------------------------------------------------------
+
+func TestDetailsTestReport(t *testing.T) {
+	m, _ := press(t, splitTestModel(), keyV, keyTab)
+	if got := strings.Join(m.paneLines(), "\n"); !strings.Contains(got, "Looking for test results...") {
+		t.Errorf("details while loading = %q", got)
+	}
+	updated, _ := m.Update(logsMsg{key: m.logKey, lines: []string{"--- FAIL: TestX (0.1s)"},
+		report: parseGoTestLog([]string{"--- FAIL: TestX (0.1s)"})})
+	m = updated.(model)
+	got := strings.Join(m.paneLines(), "\n")
+	for _, want := range []string{"Tests:     1 ran, 1 failed (go test)", "FAIL", "TestX"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("details missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// timelineSlack is how long after a check ends the next step of the
+// critical path may start and still count as waiting on it. Runners take a
+// few seconds to pick up a job whose needs: just finished.
+const timelineSlack = 30 * time.Second
+
+// timelineBar is one check's place on the run's timeline.
+type timelineBar struct {
+	check      Check
+	start, end time.Time // end is now for running checks
+	critical   bool
+}
+
+func (b timelineBar) duration() time.Duration { return b.end.Sub(b.start) }
+
+// timelineBars places every check that has started on the timeline, in
+// start order, and marks the critical path. fromNeeds reports whether
+// graphs supplied any of the path's steps.
+func timelineBars(checks []Check, graphs []depGraph, now time.Time) (bars []timelineBar, fromNeeds bool) {
+	for _, c := range checks {
+		if c.StartedAt.IsZero() {
+			continue
+		}
+		end := c.CompletedAt
+		if end.IsZero() {
+			if c.Completed {
+				continue // a status context: finished, but we don't know when
+			}
+			end = now
+		}
+		if end.Before(c.StartedAt) {
+			end = c.StartedAt
+		}
+		bars = append(bars, timelineBar{check: c, start: c.StartedAt, end: end})
+	}
+	sort.SliceStable(bars, func(i, j int) bool {
+		if !bars[i].start.Equal(bars[j].start) {
+			return bars[i].start.Before(bars[j].start)
+		}
+		return bars[i].check.Name < bars[j].check.Name
+	})
+	path, fromNeeds := criticalPath(bars, graphs)
+	for _, i := range path {
+		bars[i].critical = true
+	}
+	return bars, fromNeeds
+}
+
+// criticalPath finds the chain of checks that set the run's wall-clock
+// time, walking back from the check that ended last. A GitHub Actions job
+// found in graphs steps back to whichever of its needs: finished last; any
+// other check steps back to the check that ended last before it started.
+// It returns indexes into bars, first step first.
+func criticalPath(bars []timelineBar, graphs []depGraph) (path []int, fromNeeds bool) {
+	if len(bars) == 0 {
+		return nil, false
+	}
+	cur := 0
+	for i, b := range bars {
+		if b.end.After(bars[cur].end) {
+			cur = i
+		}
+	}
+	// Each step ends before the one after it, so the walk terminates
+	path = []int{cur}
+	for {
+		g, job, ok := jobFor(graphs, bars[cur].check)
+		prev := -1
+		for i, b := range bars {
+			if i == cur || !b.end.Before(bars[cur].end) {
+				continue
+			}
+			if ok {
+				if !slices.ContainsFunc(job.Needs, func(id string) bool {
+					need, known := g.findJob(id)
+					return known && g.ran(need, b.check)
+				}) {
+					continue
+				}
+			} else if b.end.After(bars[cur].start.Add(timelineSlack)) {
+				continue
+			}
+			if prev < 0 || b.end.After(bars[prev].end) {
+				prev = i
+			}
+		}
+		fromNeeds = fromNeeds || ok
+		if prev < 0 {
+			return path, fromNeeds
+		}
+		path = append([]int{prev}, path...)
+		cur = prev
+	}
+}
+
+// maxParallel is the most checks that ran at the same time.
+func maxParallel(bars []timelineBar) int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	edges := make([]edge, 0, 2*len(bars))
+	for _, b := range bars {
+		edges = append(edges, edge{b.start, 1}, edge{b.end, -1})
+	}
+	// Ends sort before starts at the same instant, so back-to-back jobs
+	// don't count as parallel
+	sort.Slice(edges, func(i, j int) bool {
+		if !edges[i].at.Equal(edges[j].at) {
+			return edges[i].at.Before(edges[j].at)
+		}
+		return edges[i].delta < edges[j].delta
+	})
+	n, most := 0, 0
+	for _, e := range edges {
+		n += e.delta
+		most = max(most, n)
+	}
+	return most
+}
+
+// renderTimeline draws each check as a bar placed by its start and end
+// time, width columns wide. Above the bars are the wall-clock time,
+// parallelism, long pole, critical path and the check on it that holds up
+// the all-green time the most.
+func renderTimeline(checks []Check, graphs []depGraph, now time.Time, width int) []string {
+	bars, fromNeeds := timelineBars(checks, graphs, now)
+	if len(bars) == 0 {
+		return []string{"No checks have started yet."}
+	}
+	t0, t1 := bars[0].start, bars[0].end
+	longest, bottleneck := 0, -1
+	var path []string
+	var running, waiting time.Duration
+	var prevEnd time.Time
+	for i, b := range bars {
+		if b.end.After(t1) {
+			t1 = b.end
+		}
+		if b.duration() > bars[longest].duration() {
+			longest = i
+		}
+		if !b.critical {
+			continue
+		}
+		path = append(path, b.check.Name)
+		running += b.duration()
+		if !prevEnd.IsZero() && b.start.After(prevEnd) {
+			waiting += b.start.Sub(prevEnd)
+		}
+		prevEnd = b.end
+		if bottleneck < 0 || b.duration() > bars[bottleneck].duration() {
+			bottleneck = i
+		}
+	}
+	total := t1.Sub(t0)
+	secs := func(d time.Duration) string { return formatDuration(int(d.Seconds())) }
+
+	source := "by timing"
+	if fromNeeds {
+		source = "by needs:"
+	}
+	lines := []string{
+		fmt.Sprintf("Wall clock %s, up to %d checks in parallel", secs(total), maxParallel(bars)),
+		fmt.Sprintf("Long pole: %s (%s)", bars[longest].check.Name, secs(bars[longest].duration())),
+		truncate(fmt.Sprintf("Critical path (%s): %s", source, strings.Join(path, " → ")), width),
+		fmt.Sprintf("  %s running, %s waiting between steps", secs(running), secs(waiting)),
+		fmt.Sprintf("Bottleneck: %s, %s of the critical path", bars[bottleneck].check.Name, secs(bars[bottleneck].duration())),
+	}
+	if skipped := len(checks) - len(bars); skipped > 0 {
+		lines = append(lines, styleDim.Render(fmt.Sprintf("%d checks without start and end times are not shown", skipped)))
+	}
+	lines = append(lines, "")
+
+	nameW := 0
+	for _, b := range bars {
+		nameW = max(nameW, len([]rune(b.check.Name)))
+	}
+	nameW = min(nameW, width/3)
+	const durW = 9
+	barW := width - 2 - nameW - 1 - durW
+	if barW < 10 {
+		return append(lines, "Too narrow to draw the timeline.")
+	}
+	col := func(t time.Time) int {
+		if total <= 0 {
+			return 0
+		}
+		return int(int64(t.Sub(t0)) * int64(barW) / int64(total))
+	}
+
+	axis := secs(total)
+	lines = append(lines, styleDim.Render(fmt.Sprintf("  %-*s %-*s%s", nameW, "", barW-len(axis), "0s", axis)))
+	for _, b := range bars {
+		marker := "  "
+		if b.critical {
+			marker = "* "
+		}
+		name := b.check.Name
+		if r := []rune(name); len(r) > nameW {
+			name = string(r[:nameW])
+		}
+		from := min(col(b.start), barW-1)
+		to := max(col(b.end), from+1)
+		fill := "█"
+		if !b.check.Completed {
+			fill = "▒" // still running
+		}
+		bar := strings.Repeat(" ", from) + statusStyle(b.check.Status).Render(strings.Repeat(fill, to-from)) +
+			strings.Repeat(" ", barW-to)
+		lines = append(lines, fmt.Sprintf("%s%-*s %s %s", marker, nameW, name, bar, secs(b.duration())))
+	}
+	return lines
+}
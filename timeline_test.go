@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var timelineT0 = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// timelineCheck is a check that ran from start to end seconds after
+// timelineT0; end < 0 means it is still running.
+func timelineCheck(name string, status CheckStatus, start, end int) Check {
+	c := Check{Name: name, Status: status, StartedAt: timelineT0.Add(time.Duration(start) * time.Second)}
+	if end >= 0 {
+		c.Completed = true
+		c.CompletedAt = timelineT0.Add(time.Duration(end) * time.Second)
+	}
+	return c
+}
+
+// A lint job alongside build → test → deploy, where test is the long pole.
+var timelineChecks = []Check{
+	timelineCheck("deploy", Running, 400, -1),
+	timelineCheck("lint", Pass, 0, 60),
+	timelineCheck("test", Fail, 130, 390),
+	timelineCheck("build", Pass, 0, 120),
+	{Name: "ci/jenkins", Status: Pass, Completed: true, StartedAt: timelineT0},
+	{Name: "queued", Status: Running},
+}
+
+func TestTimelineBars(t *testing.T) {
+	now := timelineT0.Add(460 * time.Second)
+	bars, fromNeeds := timelineBars(timelineChecks, nil, now)
+	var names, critical []string
+	for _, b := range bars {
+		names = append(names, b.check.Name)
+		if b.critical {
+			critical = append(critical, b.check.Name)
+		}
+	}
+	if strings.Join(names, " ") != "build lint test deploy" {
+		t.Errorf("bars = %v, want start order without untimed checks", names)
+	}
+	if strings.Join(critical, " ") != "build test deploy" || fromNeeds {
+		t.Errorf("critical path = %v (from needs %v)", critical, fromNeeds)
+	}
+	if !bars[3].end.Equal(now) {
+		t.Errorf("running check ends at %v, want now", bars[3].end)
+	}
+	if n := maxParallel(bars); n != 2 {
+		t.Errorf("maxParallel = %d, want 2", n)
+	}
+	backToBack, _ := timelineBars([]Check{timelineCheck("a", Pass, 0, 10), timelineCheck("b", Pass, 10, 20)}, nil, now)
+	if n := maxParallel(backToBack); n != 1 {
+		t.Errorf("back-to-back checks: maxParallel = %d, want 1", n)
+	}
+}
+
+func TestCriticalPathNeeds(t *testing.T) {
+	// deploy only needs lint, so test finishing just before it started
+	// isn't on the path
+	graphs := []depGraph{{Workflow: "CI", Jobs: []depJob{
+		{ID: "build"}, {ID: "lint"},
+		{ID: "test", Needs: []string{"build"}},
+		{ID: "deploy", Needs: []string{"lint"}},
+	}}}
+	var checks []Check
+	for _, c := range timelineChecks[:4] {
+		c.JobName, c.Workflow = c.Name, "CI"
+		c.Name += " (CI)"
+		checks = append(checks, c)
+	}
+	bars, fromNeeds := timelineBars(checks, graphs, timelineT0.Add(460*time.Second))
+	var critical []string
+	for _, b := range bars {
+		if b.critical {
+			critical = append(critical, b.check.JobName)
+		}
+	}
+	if strings.Join(critical, " ") != "lint deploy" || !fromNeeds {
+		t.Errorf("critical path = %v (from needs %v), want lint deploy", critical, fromNeeds)
+	}
+}
+
+func TestRenderTimeline(t *testing.T) {
+	now := timelineT0.Add(460 * time.Second)
+	lines := renderTimeline(timelineChecks, nil, now, 80)
+	out := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"Wall clock 7m40s, up to 2 checks in parallel",
+		"Long pole: test (4m20s)",
+		"Critical path (by timing): build → test → deploy",
+		"  7m20s running, 20s waiting between steps",
+		"Bottleneck: test, 4m20s of the critical path",
+		"2 checks without start and end times are not shown",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("timeline missing %q:\n%s", want, out)
+		}
+	}
+	var build, lint, deploy string
+	for _, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "* build"):
+			build = l
+		case strings.HasPrefix(l, "  lint"):
+			lint = l
+		case strings.HasPrefix(l, "* deploy"):
+			deploy = l
+		}
+	}
+	// 80 columns leave 62 for the bars over 460s
+	if !strings.Contains(build, "* build  "+strings.Repeat("█", 16)+" ") || !strings.HasSuffix(build, " 2m00s") {
+		t.Errorf("build bar = %q", build)
+	}
+	if !strings.Contains(lint, "  lint   "+strings.Repeat("█", 8)+" ") {
+		t.Errorf("lint bar = %q", lint)
+	}
+	if !strings.Contains(deploy, "▒") || !strings.HasSuffix(deploy, " 1m00s") {
+		t.Errorf("running deploy bar = %q", deploy)
+	}
+
+	if got := renderTimeline(timelineChecks, nil, now, 20); got[len(got)-1] != "Too narrow to draw the timeline." {
+		t.Errorf("narrow: %q", got[len(got)-1])
+	}
+	if got := renderTimeline([]Check{{Name: "queued", Status: Running}}, nil, now, 80); got[0] != "No checks have started yet." {
+		t.Errorf("nothing started: %q", got)
+	}
+}
+
+func TestTimelineOverlay(t *testing.T) {
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 100, 30
+	updated, _ := m.Update(prDataMsg{data: &PRData{Checks: []Check{timelineCheck("build", Pass, 0, 120)}}})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(model)
+	if m.overlay != overlayTimeline {
+		t.Fatalf("overlay = %v, want overlayTimeline", m.overlay)
+	}
+	if out := m.View(); !strings.Contains(out, "TIMELINE") || !strings.Contains(out, "Long pole: build (2m00s)") {
+		t.Errorf("timeline overlay:\n%s", out)
+	}
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,10 +19,13 @@ var (
 	styleFail    = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
 	styleRunning = lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true)
 	styleSkipped = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-	styleBold    = lipgloss.NewStyle().Bold(true)
-	styleDim     = lipgloss.NewStyle().Faint(true)
-	styleUnder   = lipgloss.NewStyle().Underline(true)
-	styleReverse = lipgloss.NewStyle().Reverse(true)
+	// Cancelled is loud because a cancelled job usually means something
+	// went wrong; neutral is informational
+	styleCancelled = lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true)
+	styleNeutral   = lipgloss.NewStyle().Foreground(lipgloss.Color("73"))
+	styleBold      = lipgloss.NewStyle().Bold(true)
+	styleDim       = lipgloss.NewStyle().Faint(true)
+	styleUnder     = lipgloss.NewStyle().Underline(true)
 
 	styleHeader     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
 	styleRepo       = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
@@ -31,18 +36,39 @@ var (
 	styleSelectedBg = lipgloss.NewStyle().Background(lipgloss.Color("236"))
 )
 
+// statusStyle returns the color style for a check status.
+func statusStyle(s CheckStatus) lipgloss.Style {
+	switch s {
+	case Pass:
+		return stylePass
+	case Fail:
+		return styleFail
+	case Running:
+		return styleRunning
+	case Cancelled:
+		return styleCancelled
+	case Neutral:
+		return styleNeutral
+	}
+	return styleSkipped
+}
+
 // View modes
 type viewMode int
 
 const (
 	modeSelecting viewMode = iota
 	modeViewing
+	modeDashboard
 )
 
 // Messages
 type prDataMsg struct {
-	data *PRData
-	err  error
+	data    *PRData
+	err     error
+	latency time.Duration
+	peek    bool   // cached data from peekCmd, not a fetch
+	key     string // prKey of the PR fetched; stale results are dropped
 }
 
 type prListMsg struct {
@@ -50,65 +76,381 @@ type prListMsg struct {
 	err error
 }
 
+type prCIMsg struct {
+	states map[string]prCI
+	err    error
+}
+
+type reviewThreadsMsg struct {
+	threads []reviewThread
+	err     error
+}
+
 type tickMsg time.Time
 
+// timeNow is the clock the UI draws with: the header clock, running
+// durations, relative times and the refresh countdown. Snapshot tests pin
+// it so their golden files don't change from run to run.
+var timeNow = time.Now
+
 // Model
 type model struct {
-	mode     viewMode
-	repo     string
-	prNumber string
-	interval time.Duration
-	prData   *PRData
-	err      error
-	selected int
-	width    int
-	height   int
+	mode       viewMode
+	repo       string
+	prNumber   string
+	interval   time.Duration // changed with + and -, saved to configPath
+	paused     bool          // polling stopped by `prtop ctl pause`
+	configPath string
+	prData     *PRData
+	err        error
+	selected   int
+	width      int
+	height     int
 	// Selection mode fields
-	prs        []PRSummary
-	loading    bool
-	canGoBack  bool // true when started in selecting mode
+	prs                 []PRSummary
+	loading             bool
+	canGoBack           bool        // true when started in selecting mode
+	selectRepo          string      // limits the selector to one repo's open PRs
+	selectIssue         int         // with selectRepo: list the PRs that close this issue
+	selectQuery         string      // list the open PRs matching this GitHub search query
+	selectNotifications bool        // list the open PRs in the user's notification feed
+	workspaceRemote     string      // the remote of the clone in the working directory: list selectRepo's open PRs touching its changes
+	stdinPRs            []PRSummary // a fixed list read by --stdin, instead of fetching one
+	prSort              prSort
+	groupByRepo         bool
+	selectColumns       []string   // the picker rows' details, from [selector] columns
+	autoSelect          autoSelect // whether the picker's first list opens a PR by itself
+	density             density    // viewing mode's layout, cycled with z
+	glyphs              glyphSet
+	columns             []tableColumn // [table] config; nil for the built-in columns
+	wrap                bool          // [display] wrap: j/k wrap around at either end
+	showIgnored         bool          // list checks on the repo's ignore list, greyed out
+	// [filter] mine, and whether M narrowed the table to those checks and
+	// the required ones
+	mine     *regexp.Regexp
+	mineOnly bool
+	required *requiredChecks
+	count    int  // pending count typed before a movement key, as in 5j
+	mini     bool // --mini: viewing mode in three lines
+	inline   bool // --inline: no alt screen, so the last frame stays in scrollback
+	quitting bool // the last frame is being drawn
+	// Attention mode: a blinking banner for the session's first failure,
+	// cleared by any key
+	attention bool
+	alert     bool
+	alertSeen bool
+	blinkOn   bool
+	// [display] celebrate, and the frames left of a running celebration,
+	// which any key also ends
+	celebration celebration
+	celebrating int
 	// Filtering and scrolling
 	hideSkipped bool // default: true
 	scrollOff   int  // first visible row index (into filtered list)
+	// Accounts from config; account indexes the one used by the selector
+	accounts []Account
+	account  int
+	// Debug overlay
+	showDebug bool
+	lastFetch fetchStats
+	// Alternate panel replacing the check table
+	overlay    overlayKind
+	overlayOff int
+	jsonWhole  bool // the JSON overlay shows the whole PR, not the selected check
+
+	settingsSel int // the settings pane's cursor
+	depGraphs   []depGraph
+	depsErr     error
+	threads     []reviewThread // nil until first fetched
+	threadsErr  error
+	security    *securityReport // nil until first fetched
+	// attempts holds the jobs of every attempt of each Actions run, by run ID
+	attempts map[string][]runJob
+	// Base branch status, refetched at most every baseStatusTTL
+	base      *baseStatus
+	baseKey   string
+	baseAsked time.Time
+	// Base branch check durations, refetched at most every baseDurationsTTL
+	baseDur      *baseDurations
+	baseDurKey   string
+	baseDurAsked time.Time
+	// Whether the flash has said which PR fields the installed gh lacks
+	ghNoted bool
+	// When viewing mode's data tick fires next, for the footer countdown
+	nextRefresh time.Time
+	// Head commit message and author, fetched once per head SHA
+	headCommit  *headCommit
+	commitAsked string
+	// Check suites' apps, fetched once per head SHA when some check's
+	// provider can't be guessed, and the provider P narrowed the table to
+	checkApps *checkApps
+	appsAsked string
+	provider  string
+	// [filter] hide_providers, lowercased, and whether H showed them again
+	hiddenProviders map[string]bool
+	showProviders   bool
+	// Head commit's deployments, refetched while any may still change
+	deploys      *deployments
+	deploysAsked string
+	deploysBusy  bool
+	// Workflow runs awaiting a maintainer's approval, and whether W was
+	// pressed once to approve them
+	approvals      *pendingApprovals
+	approvalsAsked string
+	approvalsBusy  bool
+	approveArmed   bool
+	mergeArmed     bool            // M pressed once in `prtop bots`; M again merges
+	autoMergeArmed bool            // E pressed once on the dashboard; E again enables auto-merge
+	marked         map[string]bool // dashboard PRs marked for a bulk action, by prKey
+	bots           bool            // `prtop bots`: selectRepo's dependency update PRs
+	mergeMethod    string          // how `prtop bots` merges: merge, squash or rebase
+	// GitHub's own rollup of the head commit's checks, asked for again
+	// whenever a check changes
+	ghRollup      *githubRollup
+	ghRollupAsked string
+	queue         *mergeQueue
+	queueBusy     bool
+	// Split view: check table on the left, pane on the right
+	split     bool
+	pane      paneKind
+	paneFocus bool // true when the pane, not the table, has focus
+	paneOff   int
+	splitPct  int  // table width as a percentage of the screen
+	winPrefix bool // ctrl+w pressed, waiting for the window command
+	logKey    string
+	logLines  []string    // nil until the log for logKey is fetched
+	logReport *testReport // tests found in that log or the run's JUnit XML
+	logErr    error
+	// The details pane's check run output, fetched like the log
+	outputKey    string
+	output       *checkOutput // nil when the check run has none
+	outputErr    error
+	outputLoaded bool
+	outputRules  []outputRule // [output] renderers config
+	// Command palette and one-line status message
+	paletteOpen  bool
+	paletteQuery string
+	paletteSel   int
+	gitRemote    string // remote for m.repo in the working directory, if any
+	gitBranch    string // checked-out branch when gitRemote is set
+	flash        string
+	// Status transitions observed this session for the current head SHA
+	events []checkEvent
+	// Head SHA of a push seen while watching, marked above the check
+	// table until its checks are in (see newPushDivider)
+	newPush string
+	// --on-change command and the current PR's last rollup status
+	onChange string
+	rollup   string
+	// --settle and how settled the current PR's checks are
+	settleWindow time.Duration
+	settle       settler
+	// Checks muted (m) until the next push; not persisted
+	muted mutedChecks
+	// --read-only: keys and palette commands that change the PR are off
+	readOnly bool
+	// --kiosk: the PRs shown in turn, the one showing, and for how long
+	kiosk      bool
+	kioskPRs   []PRSummary
+	kioskAt    int
+	kioskCycle time.Duration
+	// Locally persisted state (acknowledged checks) and the watchlist file
+	store *stateStore
+	watch *watchlist
+	// Dashboard mode: live check counts for every PR in prs
+	dashboard bool // true when started with --dashboard; esc returns there
+	dashRows  map[string]dashRow
+	release   *releaseTrain // `prtop release`: the dashboard's PRs are an ordered train
+	sched     *pollScheduler
+	pool      *fetchPool
 }
 
 func newModel(repo, prNumber string, interval time.Duration) model {
 	return model{
-		mode:        modeViewing,
-		repo:        repo,
-		prNumber:    prNumber,
-		interval:    interval,
-		hideSkipped: true,
+		mode:          modeViewing,
+		repo:          repo,
+		prNumber:      prNumber,
+		interval:      interval,
+		nextRefresh:   timeNow().Add(interval), // Init starts the tick
+		hideSkipped:   true,
+		splitPct:      splitDefault,
+		store:         &stateStore{},
+		watch:         &watchlist{},
+		selectColumns: defaultSelectColumns,
 	}
 }
 
 func newSelectModel(interval time.Duration) model {
 	return model{
-		mode:        modeSelecting,
-		interval:    interval,
-		loading:     true,
-		hideSkipped: true,
-		canGoBack:   true,
+		mode:          modeSelecting,
+		interval:      interval,
+		loading:       true,
+		hideSkipped:   true,
+		canGoBack:     true,
+		splitPct:      splitDefault,
+		store:         &stateStore{},
+		watch:         &watchlist{},
+		selectColumns: defaultSelectColumns,
 	}
 }
 
-func fetchPRListCmd() tea.Cmd {
+func newRepoSelectModel(repo string, interval time.Duration) model {
+	m := newSelectModel(interval)
+	m.selectRepo = repo
+	return m
+}
+
+// fetchPRListCmd fetches the selector's PR list: the PRs given on stdin,
+// the PRs in the user's notifications, the PRs touching the working
+// directory's changes, the PRs matching selectQuery or
+// linked to selectIssue, the open PRs of selectRepo, or else the user's
+// recent PRs across all repos.
+func (m model) fetchPRListCmd() tea.Cmd {
+	if m.stdinPRs != nil {
+		prs := append([]PRSummary(nil), m.stdinPRs...)
+		return func() tea.Msg { return prListMsg{prs: prs} }
+	}
+	if m.selectNotifications {
+		acct := m.activeAccount()
+		return func() tea.Msg {
+			prs, err := fetchNotificationPRs(acct)
+			return prListMsg{prs: prs, err: err}
+		}
+	}
+	if remote := m.workspaceRemote; remote != "" {
+		repo := m.selectRepo
+		acct := m.repoAccount(repo)
+		return func() tea.Msg {
+			prs, err := fetchWorkspacePRs(acct, repo, remote)
+			return prListMsg{prs: prs, err: err}
+		}
+	}
+	if query := m.selectQuery; query != "" {
+		acct := m.activeAccount()
+		return func() tea.Msg {
+			prs, err := fetchQueryPRs(acct, query)
+			return prListMsg{prs: prs, err: err}
+		}
+	}
+	repo := m.selectRepo
+	if m.bots {
+		acct := m.repoAccount(repo)
+		return func() tea.Msg {
+			prs, err := fetchBotPRs(acct, repo)
+			return prListMsg{prs: prs, err: err}
+		}
+	}
+	if issue := m.selectIssue; issue != 0 {
+		acct := m.repoAccount(repo)
+		return func() tea.Msg {
+			prs, err := fetchIssuePRs(acct, repo, issue)
+			return prListMsg{prs: prs, err: err}
+		}
+	}
+	if repo != "" {
+		acct := m.repoAccount(repo)
+		return func() tea.Msg {
+			prs, err := fetchRepoPRs(acct, repo)
+			return prListMsg{prs: prs, err: err}
+		}
+	}
+	acct := m.activeAccount()
 	return func() tea.Msg {
-		prs, err := fetchRecentPRs()
+		prs, err := fetchRecentPRs(acct)
 		return prListMsg{prs: prs, err: err}
 	}
 }
 
+// fetchCICmd looks up the CI state of the selector's PRs.
+func (m model) fetchCICmd() tea.Cmd {
+	accounts, acct := m.accounts, m.activeAccount()
+	if m.selectRepo != "" {
+		acct = m.repoAccount(m.selectRepo)
+	}
+	prs := m.prs
+	return func() tea.Msg {
+		states, err := fetchPRCIStates(accounts, acct, prs)
+		return prCIMsg{states: states, err: err}
+	}
+}
+
+// applyCIStates fills in the selector PRs' CI summaries, keyed by
+// summaryKey. PRs missing from states keep what they had.
+func (m model) applyCIStates(states map[string]prCI) model {
+	if len(states) == 0 {
+		return m
+	}
+	for i, pr := range m.prs {
+		if ci, ok := states[summaryKey(pr)]; ok {
+			m.prs[i].CIState = ci.State
+			m.prs[i].SkipCI = ci.SkipCI
+			m.prs[i].CICounts = ci.Counts
+			m.prs[i].ReviewDecision = ci.ReviewDecision
+			m.prs[i].BaseRef = ci.BaseRef
+			if ci.Author != "" {
+				m.prs[i].Author = ci.Author
+			}
+		}
+	}
+	if m.prSort == sortCI {
+		m = m.resortPRs()
+	}
+	return m
+}
+
+// activeAccount returns the account selected in the switcher, or nil when no
+// accounts are configured (gh's active login is used).
+func (m model) activeAccount() *Account {
+	if m.account < 0 || m.account >= len(m.accounts) {
+		return nil
+	}
+	return &m.accounts[m.account]
+}
+
+// repoAccount returns the account whose credentials should be used for repo.
+func (m model) repoAccount(repo string) *Account {
+	return accountForRepo(m.accounts, repo, m.activeAccount())
+}
+
+// isAcked reports whether check is a failure the user has acknowledged.
+// Acknowledged failures are greyed out and excluded from failure counts.
+func (m model) isAcked(c Check) bool {
+	return c.Status == Fail && m.store.acks(m.repo, m.prNumber)[c.Name]
+}
+
+// isIgnored reports whether check is on the repo's ignore list (I).
+func (m model) isIgnored(c Check) bool {
+	return m.store.ignored(m.repo)[c.Name]
+}
+
+// failingChecks returns the failing checks that still need attention,
+// i.e. excluding acknowledged and muted ones.
+func (m model) failingChecks() []Check {
+	if m.prData == nil {
+		return nil
+	}
+	var result []Check
+	for _, c := range m.prData.Checks {
+		if c.Status == Fail && !m.isAcked(c) && !m.isIgnored(c) && !m.isMuted(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 func (m model) filteredChecks() []Check {
 	if m.prData == nil {
 		return nil
 	}
-	if !m.hideSkipped {
+	ignored := m.store.ignored(m.repo)
+	if !m.hideSkipped && !m.mineOnly && m.provider == "" && (m.showProviders || len(m.hiddenProviders) == 0) &&
+		(m.showIgnored || len(ignored) == 0) {
 		return m.prData.Checks
 	}
 	result := make([]Check, 0, len(m.prData.Checks))
 	for _, c := range m.prData.Checks {
-		if c.Status != Skipped {
+		if (c.Status != Skipped || !m.hideSkipped) && (!ignored[c.Name] || m.showIgnored) && (!m.mineOnly || m.isMine(c)) &&
+			(m.provider == "" || c.Provider == m.provider) && !m.providerHidden(c) {
 			result = append(result, c)
 		}
 	}
@@ -116,70 +458,192 @@ func (m model) filteredChecks() []Check {
 }
 
 func (m model) Init() tea.Cmd {
-	if m.mode == modeSelecting {
-		return fetchPRListCmd()
+	switch m.mode {
+	case modeSelecting:
+		return tea.Batch(m.fetchPRListCmd(), uiTickCmd())
+	case modeDashboard:
+		return tea.Batch(m.fetchPRListCmd(), dashTickCmd(), m.pool.next())
+	}
+	cmds := []tea.Cmd{m.peekCmd(), m.refreshCmd(), m.tickCmd(), uiTickCmd()}
+	if m.kiosk {
+		cmds = append(cmds, kioskTickCmd(m.kioskCycle))
 	}
-	return tea.Batch(m.fetchCmd(), m.tickCmd())
+	return tea.Batch(cmds...)
 }
 
 func (m model) fetchCmd() tea.Cmd {
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	prNumber := m.prNumber
+	return func() tea.Msg {
+		start := time.Now()
+		data, err := fetchPRData(acct, repo, prNumber)
+		return prDataMsg{data: data, err: err, latency: time.Since(start), key: prKey(repo, prNumber)}
+	}
+}
+
+// peekCmd delivers the cached PR data, if any, so something is on screen
+// before the first live fetch returns.
+func (m model) peekCmd() tea.Cmd {
 	repo := m.repo
 	prNumber := m.prNumber
 	return func() tea.Msg {
-		data, err := fetchPRData(repo, prNumber)
-		return prDataMsg{data: data, err: err}
+		if data := peekPRData(repo, prNumber); data != nil {
+			return prDataMsg{data: data, peek: true, key: prKey(repo, prNumber)}
+		}
+		return nil
+	}
+}
+
+func (m model) fetchThreadsCmd() tea.Cmd {
+	acct := m.repoAccount(m.repo)
+	repo := m.repo
+	prNumber := m.prNumber
+	return func() tea.Msg {
+		threads, err := fetchReviewThreads(acct, repo, prNumber)
+		return reviewThreadsMsg{threads: threads, err: err}
 	}
 }
 
+// refreshCmd fetches everything shown in viewing mode.
+func (m model) refreshCmd() tea.Cmd {
+	cmds := []tea.Cmd{m.fetchCmd(), m.fetchThreadsCmd()}
+	if m.overlay != overlayThreads {
+		cmds = append(cmds, m.overlayCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
 func (m model) tickCmd() tea.Cmd {
 	return tea.Tick(m.interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
+// viewPR switches to viewing repo#prNumber.
+func (m model) viewPR(repo, prNumber string) (model, tea.Cmd) {
+	from := "viewing"
+	switch m.mode {
+	case modeSelecting:
+		from = "selecting"
+	case modeDashboard:
+		from = "dashboard"
+	}
+	logger.Debug("state transition", "from", from, "to", "viewing", "repo", repo, "pr", prNumber)
+	m.repo = repo
+	m.prNumber = prNumber
+	m.mode = modeViewing
+	m.selected = 0
+	m.scrollOff = 0
+	m.prData = nil
+	m.threads = nil
+	m.security = nil
+	m.attempts = nil
+	m.events = nil
+	m.rollup = ""
+	m.settle = settler{}
+	m.err = nil
+	m.overlay = overlayNone
+	m.provider = ""
+	cmds := []tea.Cmd{m.peekCmd(), m.refreshCmd()}
+	if from != "viewing" {
+		// Viewing mode's ticks are already running otherwise
+		m.nextRefresh = timeNow().Add(m.interval)
+		cmds = append(cmds, m.tickCmd())
+	}
+	if from == "dashboard" {
+		// The picker's UI tick carries on into viewing mode
+		cmds = append(cmds, uiTickCmd())
+	}
+	return m, tea.Batch(cmds...)
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.paletteOpen {
+			return m.updatePaletteKey(msg)
+		}
+		m.flash = ""
+		m = m.clearNewPush()
+		armed, mergeArmed, autoMergeArmed := m.approveArmed, m.mergeArmed, m.autoMergeArmed
+		m.approveArmed, m.mergeArmed, m.autoMergeArmed = false, false, false
+		if m.alert || m.celebrating > 0 {
+			// Any key clears the attention banner or the celebration; only
+			// quitting goes through
+			m.alert, m.celebrating = false, 0
+			if msg.Type != tea.KeyCtrlC && msg.String() != "q" {
+				return m, nil
+			}
+		}
+		if m.overlay != overlayNone {
+			if mm, handled := m.updateOverlayKey(msg); handled {
+				return mm, nil
+			}
+		}
+		if m.split && m.mode == modeViewing {
+			if mm, cmd, handled := m.updateSplitKey(msg); handled {
+				return mm, cmd
+			}
+		}
+		if mm, ok := m.updateNavKey(msg); ok {
+			m = mm
+			break
+		}
 		switch msg.Type {
 		case tea.KeyCtrlC:
-			return m, tea.Quit
+			return m.quit()
+		case tea.KeyCtrlY:
+			if m.mode == modeViewing {
+				return m, m.copyReportCmd()
+			}
 		case tea.KeyEsc:
+			if m.mode == modeViewing && m.dashboard {
+				logger.Debug("state transition", "from", "viewing", "to", "dashboard")
+				from := prKey(m.repo, m.prNumber)
+				m.mode = modeDashboard
+				m.selected = 0
+				for i, pr := range m.prs {
+					if summaryKey(pr) == from {
+						m.selected = i
+					}
+				}
+				m.scrollOff = 0
+				m.prData = nil
+				m.threads = nil
+				m.security = nil
+				m.events = nil
+				m.rollup = ""
+				m.settle = settler{}
+				m.err = nil
+				m.overlay = overlayNone
+				return m, tea.Batch(m.pollDueCmd(time.Now()), dashTickCmd())
+			}
 			if m.mode == modeViewing && m.canGoBack {
+				logger.Debug("state transition", "from", "viewing", "to", "selecting")
 				m.mode = modeSelecting
 				m.selected = 0
 				m.scrollOff = 0
 				m.prData = nil
+				m.threads = nil
+				m.security = nil
+				m.events = nil
+				m.rollup = ""
+				m.settle = settler{}
 				m.err = nil
 				m.loading = true
-				return m, fetchPRListCmd()
-			}
-		case tea.KeyUp:
-			if m.selected > 0 {
-				m.selected--
+				return m, m.fetchPRListCmd()
 			}
-		case tea.KeyDown:
-			if m.mode == modeSelecting {
-				if len(m.prs) > 0 && m.selected < len(m.prs)-1 {
-					m.selected++
-				}
-			} else {
-				checks := m.filteredChecks()
-				if len(checks) > 0 && m.selected < len(checks)-1 {
-					m.selected++
-				}
+		case tea.KeySpace:
+			if m.mode == modeDashboard {
+				m = m.toggleMark()
 			}
 		case tea.KeyEnter:
-			if m.mode == modeSelecting {
+			if m.mode != modeViewing {
 				if len(m.prs) > 0 {
 					pr := m.prs[m.selected]
-					m.repo = pr.Repo
-					m.prNumber = fmt.Sprintf("%d", pr.Number)
-					m.mode = modeViewing
-					m.selected = 0
-					m.scrollOff = 0
-					m.prData = nil
-					m.err = nil
-					return m, tea.Batch(m.fetchCmd(), m.tickCmd())
+					return m.viewPR(pr.Repo, fmt.Sprintf("%d", pr.Number))
 				}
 			} else {
 				checks := m.filteredChecks()
@@ -191,30 +655,165 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		case tea.KeyRunes:
-			switch string(msg.Runes) {
+			switch key := string(msg.Runes); key {
 			case "q":
-				return m, tea.Quit
-			case "r":
+				return m.quit()
+			case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
 				if m.mode == modeSelecting {
+					return m.quickSelect(int(key[0] - '0'))
+				}
+			case "r":
+				switch m.mode {
+				case modeSelecting:
+					m.loading = true
+					return m, m.fetchPRListCmd()
+				case modeDashboard:
+					if len(m.markedPRs()) > 0 {
+						return m.refreshMarked()
+					}
+					m.sched.pokeAll(time.Now())
+					return m, m.fetchPRListCmd()
+				}
+				return m, m.refreshCmd()
+			case "a":
+				if m.mode != modeViewing && m.selectRepo == "" && len(m.accounts) > 1 {
+					m.account = (m.account + 1) % len(m.accounts)
+					m.selected = 0
+					m.prs = nil
+					m.err = nil
 					m.loading = true
-					return m, fetchPRListCmd()
+					return m, m.fetchPRListCmd()
 				}
-				return m, m.fetchCmd()
-			case "k":
-				if m.selected > 0 {
-					m.selected--
+			case "w":
+				m = m.toggleWatch()
+			case "o":
+				if m.mode == modeSelecting {
+					m.prSort = (m.prSort + 1) % prSort(len(prSortNames))
+					m = m.resortPRs()
 				}
-			case "j":
+			case "R":
 				if m.mode == modeSelecting {
-					if len(m.prs) > 0 && m.selected < len(m.prs)-1 {
-						m.selected++
+					m.groupByRepo = !m.groupByRepo
+					m = m.resortPRs()
+				}
+			case "A":
+				if c, ok := m.selectedCheck(); ok && m.mode == modeViewing {
+					if c.Status != Fail && !m.store.acks(m.repo, m.prNumber)[c.Name] {
+						m.flash = "Only failing checks can be acknowledged"
+						break
+					}
+					acked, err := m.store.toggleAck(m.repo, m.prNumber, c.Name)
+					switch {
+					case err != nil:
+						m.flash = fmt.Sprintf("Error: %s", err)
+					case acked:
+						m.flash = "Acknowledged " + c.Name + " (A again to undo)"
+					default:
+						m.flash = "Un-acknowledged " + c.Name
 					}
-				} else {
-					checks := m.filteredChecks()
-					if len(checks) > 0 && m.selected < len(checks)-1 {
-						m.selected++
+				}
+			case "I":
+				if c, ok := m.selectedCheck(); ok && m.mode == modeViewing {
+					m = m.toggleIgnore(c)
+				}
+			case "m":
+				if c, ok := m.selectedCheck(); ok && m.mode == modeViewing {
+					m = m.toggleMute(c)
+				}
+			case "M":
+				if m.mode == modeViewing {
+					return m.toggleMine()
+				}
+				if m.mode == modeDashboard && m.bots {
+					return m.mergeGreenBots(mergeArmed)
+				}
+			case "b":
+				if m.mode == modeDashboard && m.bots {
+					return m.rebaseSelectedBot()
+				}
+			case "F":
+				if m.mode == modeDashboard {
+					return m.rerunMarked()
+				}
+			case "E":
+				if m.mode == modeDashboard {
+					return m.autoMergeMarked(autoMergeArmed)
+				}
+			case "P":
+				if m.mode == modeViewing {
+					m = m.cycleProvider()
+				}
+			case "H":
+				if m.mode == modeViewing {
+					m = m.toggleHiddenProviders()
+				}
+			case "W":
+				if m.mode == modeViewing {
+					return m.approveRuns(armed)
+				}
+			case ":":
+				if m.mode == modeViewing {
+					return m.openPalette(), nil
+				}
+			case ",":
+				if m.mode == modeViewing {
+					return m.toggleOverlay(overlaySettings)
+				}
+			case "u":
+				if m.mode == modeViewing {
+					return m.toggleOverlay(overlayThreads)
+				}
+			case "S":
+				if m.mode == modeViewing {
+					return m.toggleOverlay(overlaySecurity)
+				}
+			case "e":
+				if m.mode == modeViewing {
+					return m.toggleOverlay(overlayEvents)
+				}
+			case "p":
+				if m.mode == modeViewing {
+					return m.toggleOverlay(overlayAttempts)
+				}
+			case "Q":
+				if m.mode == modeViewing {
+					return m.toggleOverlay(overlayQueue)
+				}
+			case "J":
+				if m.mode == modeViewing {
+					return m.openJSON(false)
+				}
+			case "t":
+				if m.mode == modeViewing {
+					m.depGraphs, m.depsErr = nil, nil
+					return m.toggleOverlay(overlayTimeline)
+				}
+			case "D":
+				m.showDebug = !m.showDebug
+			case "+", "=":
+				m = m.adjustInterval(true)
+			case "-":
+				m = m.adjustInterval(false)
+			case "z":
+				if m.mode == modeViewing {
+					m.density = (m.density + 1) % density(len(densityNames))
+					m.flash = "Density: " + m.density.String()
+				}
+			case "v":
+				if m.mode == modeViewing {
+					m.split = !m.split
+					m.paneFocus = false
+					m.winPrefix = false
+					if m.split {
+						m, cmd = m.ensureLogs()
+						return m.scrollPaneToEnd(), cmd
 					}
 				}
+			case "d":
+				if m.mode == modeViewing {
+					m.depGraphs, m.depsErr = nil, nil
+					return m.toggleOverlay(overlayDeps)
+				}
 			case "s":
 				if m.mode == modeViewing {
 					m.hideSkipped = !m.hideSkipped
@@ -227,22 +826,96 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case prListMsg:
 		m.loading = false
 		if msg.err != nil {
+			logger.Debug("PR list failed", "err", msg.err)
 			m.err = msg.err
 		} else {
+			logger.Debug("PR list loaded", "count", len(msg.prs))
 			m.prs = msg.prs
 			m.err = nil
 			m.selected = 0
+			if m.mode == modeSelecting {
+				sortPRs(m.prs, m.prSort, m.groupByRepo)
+				repo, prNumber, ok := m.autoSelectPR()
+				m.autoSelect = autoSelectOff
+				if ok {
+					return m.viewPR(repo, prNumber)
+				}
+			}
+			if m.mode == modeDashboard {
+				if m.release == nil && !m.bots {
+					m.prs = m.watch.withWatched(m.prs)
+				}
+				now := time.Now()
+				m.syncDashboard(now)
+				return m, m.pollDueCmd(now)
+			}
+			if len(m.prs) > 0 {
+				// Last known CI until the fetch answers
+				m = m.applyCIStates(peekPRCIStates(m.prs))
+				return m, m.fetchCICmd()
+			}
+		}
+
+	case prCIMsg:
+		if msg.err != nil {
+			logger.Debug("CI states failed", "err", msg.err)
+		}
+		m = m.applyCIStates(msg.states)
+
+	case dashTickMsg:
+		if m.mode == modeDashboard && m.paused {
+			return m, dashTickCmd()
+		}
+		if m.mode == modeDashboard {
+			return m, tea.Batch(m.pollDueCmd(time.Time(msg)), dashTickCmd())
 		}
 
+	case dashResultMsg:
+		return m, tea.Batch(m.applyDashResult(dashResult(msg)), m.pool.next())
+
 	case prDataMsg:
-		if m.mode != modeViewing {
+		if m.mode != modeViewing || msg.key != "" && msg.key != prKey(m.repo, m.prNumber) {
+			break
+		}
+		if msg.peek && m.prData != nil {
+			// The live fetch won the race; don't replace it with older data.
 			break
 		}
+		if !msg.peek {
+			m.lastFetch = fetchStats{at: time.Now(), latency: msg.latency, err: msg.err}
+		}
 		if msg.err != nil {
+			logger.Debug("fetch failed", "repo", m.repo, "pr", m.prNumber, "err", msg.err)
 			m.err = msg.err
 		} else {
+			m.lastFetch.bytes = msg.data.payloadBytes
+			logger.Debug("fetch ok", "repo", m.repo, "pr", m.prNumber,
+				"checks", len(msg.data.Checks), "latency", msg.latency)
+			m = m.recordSnapshot(msg.data, time.Now())
 			m.prData = msg.data
+			m = m.withCheckApps()
 			m.err = nil
+			if !msg.peek {
+				journaling.observe(m.repo, m.prNumber, msg.data, time.Now())
+				if note := ghDroppedNote(); note != "" && !m.ghNoted {
+					m.flash, m.ghNoted = note, true
+				}
+				var alertCmd, partyCmd, baseCmd, durCmd, commitCmd, appsCmd, deployCmd, approvalCmd, ghRollupCmd, queueCmd tea.Cmd
+				previous := m.rollup
+				m.rollup, cmd = m.rollupHook(m.repo, m.prNumber, msg.data, m.rollup, &m.settle)
+				m, alertCmd = m.checkAttention()
+				m, partyCmd = m.checkCelebration(previous)
+				m, baseCmd = m.baseStatusCmd(time.Now())
+				m, durCmd = m.baseDurationsCmd(time.Now())
+				m, commitCmd = m.headCommitCmd()
+				m, appsCmd = m.checkAppsCmd()
+				m, deployCmd = m.deploymentsCmd()
+				m, approvalCmd = m.approvalsCmd()
+				m, ghRollupCmd = m.githubRollupCmd()
+				m, queueCmd = m.mergeQueueCmd()
+				cmd = tea.Batch(cmd, alertCmd, partyCmd, baseCmd, durCmd, commitCmd, appsCmd, deployCmd, approvalCmd,
+					ghRollupCmd, queueCmd, m.attemptsCmd(), m.requiredCmd())
+			}
 			// Clamp selection against filtered list
 			checks := m.filteredChecks()
 			if len(checks) > 0 {
@@ -254,11 +927,198 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case actionMsg:
+		if msg.err != nil {
+			m.flash = errorFlash(msg.text, msg.err)
+			return m, nil
+		}
+		m.flash = msg.text
+		if m.mode == modeViewing {
+			return m, m.fetchCmd()
+		}
+		if m.mode == modeDashboard && m.bots {
+			m.sched.pokeAll(time.Now())
+			return m, m.fetchPRListCmd()
+		}
+		if m.mode == modeDashboard {
+			return m.refreshMarked()
+		}
+
+	case reportMsg:
+		if msg.err != nil {
+			m.flash = errorFlash(msg.text, msg.err)
+		} else {
+			m.flash = msg.text
+		}
+
+	case reviewThreadsMsg:
+		if m.mode != modeViewing {
+			break
+		}
+		if msg.err != nil {
+			logger.Debug("review threads failed", "err", msg.err)
+			m.threadsErr = msg.err
+		} else {
+			m.threads = msg.threads
+			m.threadsErr = nil
+		}
+
+	case baseStatusMsg:
+		if baseKey(msg.status.repo, msg.status.branch) == m.baseKey {
+			if msg.status.err != nil {
+				logger.Debug("base branch status failed", "branch", msg.status.branch, "err", msg.status.err)
+			}
+			m.base = msg.status
+		}
+
+	case attemptsMsg:
+		if msg.err != nil {
+			logger.Debug("workflow run attempts failed", "run", msg.runID, "err", msg.err)
+			break
+		}
+		attempts := make(map[string][]runJob, len(m.attempts)+1)
+		for id, jobs := range m.attempts {
+			attempts[id] = jobs
+		}
+		attempts[msg.runID] = msg.jobs
+		m.attempts = attempts
+
+	case baseDurationsMsg:
+		if baseKey(msg.durations.repo, msg.durations.branch) == m.baseDurKey {
+			if msg.durations.err != nil {
+				logger.Debug("base branch durations failed", "branch", msg.durations.branch, "err", msg.durations.err)
+			}
+			m.baseDur = msg.durations
+		}
+
+	case headCommitMsg:
+		if msg.commit.err != nil {
+			logger.Debug("head commit failed", "sha", msg.commit.sha, "err", msg.commit.err)
+		}
+		if m.prData != nil && msg.commit.repo == m.repo && msg.commit.sha == m.prData.HeadSHA {
+			m.headCommit = msg.commit
+		}
+
+	case checkAppsMsg:
+		if msg.apps.err != nil {
+			logger.Debug("check suite apps failed", "sha", msg.apps.sha, "err", msg.apps.err)
+		}
+		if m.prData != nil && msg.apps.repo == m.repo && msg.apps.sha == m.prData.HeadSHA {
+			m.checkApps = msg.apps
+			m = m.withCheckApps()
+		}
+
+	case deploymentsMsg:
+		if msg.deploys.err != nil {
+			logger.Debug("deployments failed", "sha", msg.deploys.sha, "err", msg.deploys.err)
+		}
+		m.deploysBusy = false
+		if m.prData != nil && msg.deploys.repo == m.repo && msg.deploys.sha == m.prData.HeadSHA {
+			m.deploys = msg.deploys
+		}
+
+	case approvalsMsg:
+		if msg.approvals.err != nil {
+			logger.Debug("pending approvals failed", "sha", msg.approvals.sha, "err", msg.approvals.err)
+		}
+		m.approvalsBusy = false
+		if m.prData != nil && msg.approvals.repo == m.repo && msg.approvals.sha == m.prData.HeadSHA {
+			m.approvals = msg.approvals
+		}
+
+	case githubRollupMsg:
+		if msg.rollup.err != nil {
+			logger.Debug("GitHub rollup failed", "repo", msg.rollup.repo, "err", msg.rollup.err)
+		}
+		if msg.rollup.repo == m.repo {
+			m.ghRollup = msg.rollup
+		}
+
+	case mergeQueueMsg:
+		m.queueBusy = false
+		if msg.queue.err != nil {
+			logger.Debug("merge queue unavailable", "repo", msg.queue.repo, "err", msg.queue.err)
+		}
+		m.queue = msg.queue
+
+	case requiredMsg:
+		if msg.key == prKey(m.repo, m.prNumber) {
+			if msg.checks.err != nil {
+				logger.Debug("required checks failed", "pr", msg.key, "err", msg.checks.err)
+				m.flash = fmt.Sprintf("Couldn't load required checks: %s", msg.checks.err)
+			}
+			m.required = msg.checks
+			m = m.clampSelection()
+		}
+
+	case securityMsg:
+		if m.prData != nil && msg.report.sha == m.prData.HeadSHA {
+			m.security = msg.report
+		}
+
+	case logsMsg:
+		if msg.key == m.logKey {
+			m.logLines, m.logReport, m.logErr = msg.lines, msg.report, msg.err
+			if m.pane == paneLogs {
+				m = m.scrollPaneToEnd()
+			}
+		}
+
+	case outputMsg:
+		if msg.key == m.outputKey {
+			m.output, m.outputErr, m.outputLoaded = msg.output, msg.err, true
+		}
+
+	case depGraphsMsg:
+		if msg.err != nil {
+			m.depsErr = msg.err
+		} else {
+			m.depGraphs = msg.graphs
+			if m.depGraphs == nil {
+				m.depGraphs = []depGraph{}
+			}
+			m.depsErr = nil
+		}
+
+	case attentionTickMsg:
+		if m.alert {
+			m.blinkOn = !m.blinkOn
+			return m, attentionTickCmd()
+		}
+
+	case kioskTickMsg:
+		if m.kiosk {
+			m, cmd = m.nextKioskPR()
+			return m, tea.Batch(cmd, kioskTickCmd(m.kioskCycle))
+		}
+
+	case celebrateTickMsg:
+		if m.celebrating > 0 {
+			if m.celebrating--; m.celebrating > 0 {
+				return m, celebrateTickCmd()
+			}
+		}
+
 	case tickMsg:
 		if m.mode == modeViewing {
-			return m, tea.Batch(m.fetchCmd(), m.tickCmd())
+			m.nextRefresh = time.Time(msg).Add(m.interval)
+			if m.paused {
+				return m, m.tickCmd()
+			}
+			return m, tea.Batch(m.refreshCmd(), m.tickCmd())
 		}
 
+	case uiTickMsg:
+		if m.mode == modeViewing || m.mode == modeSelecting {
+			return m, uiTickCmd()
+		}
+
+	case branchPRMsg:
+		return m.openBranchPR(msg)
+
+	case ctlMsg:
+		return m.handleCtl(msg)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -268,15 +1128,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.selected < m.scrollOff {
 		m.scrollOff = m.selected
 	}
-	maxRows := m.height - 8
-	if maxRows < 1 {
-		maxRows = 1
-	}
+	maxRows := m.tableRows()
 	if m.selected >= m.scrollOff+maxRows {
 		m.scrollOff = m.selected - maxRows + 1
 	}
 
-	return m, nil
+	// The logs pane follows the selected check
+	var logsCmd tea.Cmd
+	m, logsCmd = m.ensureLogs()
+	return m, tea.Batch(cmd, logsCmd)
 }
 
 func relativeTime(updatedAt string) string {
@@ -284,7 +1144,8 @@ func relativeTime(updatedAt string) string {
 	if err != nil {
 		return ""
 	}
-	d := time.Since(t)
+	d := timeNow().Sub(t)
+	days := int(d.Hours() / 24)
 	switch {
 	case d < time.Minute:
 		return "just now"
@@ -292,90 +1153,283 @@ func relativeTime(updatedAt string) string {
 		return fmt.Sprintf("%dm ago", int(d.Minutes()))
 	case d < 24*time.Hour:
 		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case days < 14:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 60:
+		return fmt.Sprintf("%dw ago", days/7)
+	case days < 365:
+		return fmt.Sprintf("%dmo ago", days/30)
 	default:
-		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+		return fmt.Sprintf("%dy ago", days/365)
 	}
 }
 
-func (m model) viewSelecting() string {
-	if m.width == 0 {
-		return "Loading..."
+// absoluteTime is an RFC 3339 timestamp as local time for the picker's
+// detail line, e.g. "Wed 2024-05-01 10:00 CEST", or "" when it doesn't
+// parse.
+func absoluteTime(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ""
 	}
+	return t.Local().Format("Mon 2006-01-02 15:04 MST")
+}
 
-	var b strings.Builder
+// selectorDetail is the line under the selected PR in the picker: the
+// files it shares with the working directory, and when it was updated and
+// opened, in full.
+func selectorDetail(pr PRSummary) string {
+	var parts []string
+	if pr.Overlap != "" {
+		parts = append(parts, "touches "+pr.Overlap)
+	}
+	if updated := absoluteTime(pr.UpdatedAt); updated != "" {
+		parts = append(parts, "updated "+updated)
+	}
+	if created := absoluteTime(pr.CreatedAt); created != "" {
+		parts = append(parts, "opened "+created)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// ciBadge renders a selector PR's CI summary: its check counts by outcome
+// ("✓3 ✗1 ●2"), or the rollup's glyph when the counts aren't known.
+func ciBadge(pr PRSummary, glyphs glyphSet) string {
+	var parts []string
+	switch c := pr.CICounts; {
+	case c.total() > 0:
+		for _, n := range []struct {
+			status CheckStatus
+			count  int
+		}{{Pass, c.Pass}, {Fail, c.Fail}, {Running, c.Running}} {
+			if n.count > 0 {
+				parts = append(parts, statusStyle(n.status).Render(fmt.Sprintf("%s%d", glyphs.glyph(n.status), n.count)))
+			}
+		}
+	case pr.CIState == "SUCCESS":
+		parts = append(parts, stylePass.Render(glyphs.glyph(Pass)))
+	case pr.CIState == "FAILURE" || pr.CIState == "ERROR":
+		parts = append(parts, styleFail.Render(glyphs.glyph(Fail)))
+	case pr.CIState == "PENDING" || pr.CIState == "EXPECTED":
+		parts = append(parts, styleRunning.Render(glyphs.glyph(Running)))
+	}
+	if pr.SkipCI {
+		parts = append(parts, styleSkipped.Render("CI skipped"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// listSubtitle describes where the selector's (or dashboard's) PRs come from.
+func (m model) listSubtitle() string {
+	subtitle := "  Your recent open pull requests"
+	switch {
+	case m.stdinPRs != nil:
+		subtitle = "  Pull requests from stdin"
+	case m.selectNotifications:
+		subtitle = "  Open pull requests in your notifications"
+	case m.workspaceRemote != "":
+		subtitle = "  Open pull requests in " + m.selectRepo + " touching the files you've changed"
+	case m.selectQuery != "":
+		subtitle = "  Open pull requests matching " + m.selectQuery
+	case m.bots:
+		subtitle = "  Open dependency updates in " + m.selectRepo
+	case m.selectIssue != 0:
+		subtitle = fmt.Sprintf("  Open pull requests linked to %s#%d", m.selectRepo, m.selectIssue)
+	case m.selectRepo != "":
+		subtitle = "  Open pull requests in " + m.selectRepo
+	}
+	if acct := m.activeAccount(); acct != nil {
+		subtitle += " (" + acct.Name + ")"
+	}
+	return subtitle
+}
+
+func (m model) viewSelecting() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
 	maxWidth := m.width
 
 	// Header
 	b.WriteString(styleHeader.Render("  prtop"))
 	b.WriteString("\n")
-	b.WriteString(styleDim.Render("  Your recent open pull requests"))
+	b.WriteString(styleDim.Render(m.listSubtitle()))
 	b.WriteString("\n\n")
 
 	if m.err != nil {
-		b.WriteString(styleFail.Render(truncate(fmt.Sprintf("Error: %s", m.err), maxWidth)))
-		b.WriteString("\n\n")
-		b.WriteString(styleDim.Render("r: retry | q: quit"))
+		b.WriteString(viewError(m.err, maxWidth))
 		return b.String()
 	}
 
 	if m.loading {
-		b.WriteString("Fetching your open PRs...")
+		if m.selectRepo != "" {
+			b.WriteString("Fetching open PRs...")
+		} else {
+			b.WriteString("Fetching your open PRs...")
+		}
 		return b.String()
 	}
 
 	if len(m.prs) == 0 {
-		b.WriteString("No open PRs found.")
+		if m.selectIssue != 0 {
+			b.WriteString("No open PRs are linked to this issue.")
+		} else if m.selectNotifications {
+			b.WriteString("No open PRs in your notifications.")
+		} else if m.workspaceRemote != "" {
+			b.WriteString("No open PRs touch the files you've changed.")
+		} else {
+			b.WriteString("No open PRs found.")
+		}
 		b.WriteString("\n\n")
 		b.WriteString(styleDim.Render("r: retry | q: quit"))
 		return b.String()
 	}
 
+	// Each PR takes 3 lines (line1 + line2 + blank), plus 1 for its repo's
+	// group header. The header uses 3 lines and the footer 1; when the
+	// PRs don't fit, the list scrolls from scrollOff and a scrollbar takes
+	// the last column.
+	avail := max(m.height-4, 1)
+	total := 3 * len(m.prs)
+	for idx, pr := range m.prs {
+		if m.groupByRepo && (idx == 0 || m.prs[idx-1].Repo != pr.Repo) {
+			total++
+		}
+	}
+	if total > avail {
+		maxWidth -= 2
+	}
+	blocks := make([][]string, len(m.prs))
 	for idx, pr := range m.prs {
+		var lines []string
+		if m.groupByRepo && (idx == 0 || m.prs[idx-1].Repo != pr.Repo) {
+			lines = append(lines, styleHeader.Render(truncate("── "+pr.Repo, maxWidth)))
+		}
 		isSelected := idx == m.selected
 		marker := "  "
 		if isSelected {
 			marker = styleSelected.Render("▸ ")
 		}
 
-		// Line 1: marker + repo + #number
-		repoStr := styleRepo.Render(pr.Repo)
-		numStr := stylePRNumber.Render(fmt.Sprintf("#%d", pr.Number))
-		line1 := marker + repoStr + " " + numStr
+		// Line 1: marker + repo + #number + CI + the configured details
+		num := fmt.Sprintf("#%d", pr.Number)
+		repo := truncate(pr.Repo, max(maxWidth-5-len(num), 1))
+		line1 := marker + quickSelectLabel(idx) + styleRepo.Render(repo) + " " + stylePRNumber.Render(num)
+		used := 5 + len(repo) + len(num)
+		if badge := ciBadge(pr, m.glyphs); badge != "" && used+2+lipgloss.Width(badge) <= maxWidth {
+			line1 += "  " + badge
+			used += 2 + lipgloss.Width(badge)
+		}
+		for _, detail := range m.selectRowDetails(pr) {
+			if used+2+lipgloss.Width(detail) <= maxWidth {
+				line1 += "  " + detail
+				used += 2 + lipgloss.Width(detail)
+			}
+		}
+		if pr.Reason != "" && used+2+len(pr.Reason) <= maxWidth {
+			line1 += "  " + styleDim.Render(pr.Reason)
+		}
 
 		// Line 2: title + updated timestamp
-		titleStr := styleTitle.Render(pr.Title)
-		updated := relativeTime(pr.UpdatedAt)
-		line2 := "  " + titleStr
-		if updated != "" {
+		title := truncate(pr.Title, max(maxWidth-2, 1))
+		line2 := "  " + styleTitle.Render(title)
+		if updated := relativeTime(pr.UpdatedAt); updated != "" && 2+len([]rune(title))+10+len(updated) <= maxWidth {
 			line2 += "  " + styleUpdatedAt.Render("updated "+updated)
 		}
 
+		line3 := ""
 		if isSelected {
-			b.WriteString(styleSelectedBg.Render(line1))
-			b.WriteString("\n")
-			b.WriteString(styleSelectedBg.Render(line2))
-		} else {
-			b.WriteString(line1)
-			b.WriteString("\n")
-			b.WriteString(line2)
+			line1, line2 = styleSelectedBg.Render(line1), styleSelectedBg.Render(line2)
+			if detail := selectorDetail(pr); detail != "" {
+				line3 = "  " + styleDim.Render(truncate(detail, max(maxWidth-2, 1)))
+			}
 		}
-		b.WriteString("\n\n")
+		blocks[idx] = append(lines, line1, line2, line3)
 	}
+	// scrollOff counts PRs, assuming 3 lines each; group headers can push
+	// the selection further down, so start moves on until it fits
+	start := min(max(m.scrollOff, 0), len(blocks)-1)
+	used, offset := 0, 0
+	for idx := start; idx <= min(m.selected, len(blocks)-1); idx++ {
+		used += len(blocks[idx])
+		for used > avail && start < idx {
+			used -= len(blocks[start])
+			start++
+		}
+	}
+	for _, block := range blocks[:start] {
+		offset += len(block)
+	}
+	var visible []string
+	for _, block := range blocks[start:] {
+		visible = append(visible, block...)
+	}
+	visible = visible[:min(len(visible), avail)]
+	bar := scrollbar(len(visible), total, offset)
+	for i, l := range visible {
+		b.WriteString(l)
+		if total > avail {
+			b.WriteString(strings.Repeat(" ", max(maxWidth-lipgloss.Width(l), 0)) + " " + bar[i])
+		}
+		b.WriteString("\n")
+	}
+	linesUsed := 3 + len(visible)
 
-	// Pad to bottom — each PR uses 3 lines (line1 + line2 + blank), header uses 3
-	linesUsed := 3 + len(m.prs)*3
+	// Pad to bottom
 	for i := linesUsed; i < m.height-1; i++ {
 		b.WriteString("\n")
 	}
 
-	b.WriteString(styleDim.Render(truncate("up/down: select | enter: view PR | q: quit", maxWidth)))
+	footer := fmt.Sprintf("up/down: select | enter or 1-9: view PR | 0: current branch | o: sort (%s) | R: group by repo | q: quit", m.prSort)
+	if len(m.accounts) > 1 && m.selectRepo == "" {
+		footer = fmt.Sprintf("up/down: select | enter or 1-9: view PR | 0: current branch | o: sort (%s) | R: group by repo | a: switch account | q: quit", m.prSort)
+	}
+	if m.flash != "" {
+		b.WriteString(styleRunning.Render(truncate(m.flash, m.width)))
+	} else {
+		b.WriteString(styleDim.Render(truncate(footer, m.width)))
+	}
 
 	return b.String()
 }
 
+// The smallest terminal prtop lays out in. Below it, lines would wrap and
+// scroll the header away, so View shows a placeholder instead.
+const (
+	minTermWidth  = 40
+	minTermHeight = 10
+)
+
+// tooSmall reports whether the terminal is below the minimum size. Mini
+// mode is exempt, being three lines by design; a size of 0 means it isn't
+// known yet.
+func (m model) tooSmall() bool {
+	return !m.mini && m.width > 0 && m.height > 0 && (m.width < minTermWidth || m.height < minTermHeight)
+}
+
+func (m model) viewTooSmall() string {
+	lines := []string{
+		styleBold.Render(truncate("Terminal too small", m.width)),
+		truncate(fmt.Sprintf("need ≥ %dx%d, have %dx%d", minTermWidth, minTermHeight, m.width, m.height), m.width),
+		styleDim.Render(truncate("q: quit", m.width)),
+	}
+	return strings.Join(lines[:min(len(lines), m.height)], "\n")
+}
+
 func (m model) View() string {
-	if m.mode == modeSelecting {
+	if m.tooSmall() {
+		return m.viewTooSmall()
+	}
+	switch m.mode {
+	case modeSelecting:
 		return m.viewSelecting()
+	case modeDashboard:
+		return m.viewDashboard()
+	}
+	if m.mini {
+		return m.viewMini()
 	}
 
 	if m.width == 0 {
@@ -386,20 +1440,15 @@ func (m model) View() string {
 	maxWidth := m.width
 
 	// Header
-	now := time.Now().Format("2006-01-02 15:04:05")
-	header := fmt.Sprintf("PR Checks - %s #%s", m.repo, m.prNumber)
-	pad := maxWidth - len(header) - len(now)
-	if pad < 1 {
-		pad = 1
+	if m.density == densityCompact {
+		b.WriteString(m.viewCompactHeader())
+	} else {
+		b.WriteString(m.viewHeader())
 	}
-	headerLine := header + strings.Repeat(" ", pad) + now
-	b.WriteString(styleBold.Render(truncate(headerLine, maxWidth)))
 	b.WriteString("\n")
 
-	if m.err != nil {
-		b.WriteString(styleFail.Render(truncate(fmt.Sprintf("Error: %s", m.err), maxWidth)))
-		b.WriteString("\n\n")
-		b.WriteString(styleDim.Render("r: retry | q: quit"))
+	if m.err != nil && !m.kiosk {
+		b.WriteString(viewError(m.err, maxWidth))
 		return b.String()
 	}
 
@@ -408,69 +1457,158 @@ func (m model) View() string {
 		return b.String()
 	}
 
-	// PR title
-	if m.prData.Title != "" {
-		b.WriteString(truncate(m.prData.Title, maxWidth))
+	if m.density != densityCompact {
+		b.WriteString(m.viewPRSummary(maxWidth))
+	}
+
+	// Calculate how many rows we can show
+	// Lines used: header(1) + title(1) + branch(1) + blank(1) + summary(1) + blank(1) + table header(1) + footer(1) = 8
+	// (compact: header(1) + table header(1) + footer(1) = 3)
+	maxRows := m.bodyRows()
+
+	if m.celebrating > 0 && !m.quitting {
+		lines := m.viewCelebration(maxWidth, maxRows+1)
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		for i := m.headerLines() + len(lines); i < m.height-1; i++ {
+			b.WriteString("\n")
+		}
+		b.WriteString(styleDim.Render(truncate("any key: back to the checks | q: quit", maxWidth)))
+		return b.String()
+	}
+	if m.paletteOpen {
+		b.WriteString(m.viewPalette(maxRows))
+		return b.String()
+	}
+	if m.overlay != overlayNone {
+		b.WriteString(m.viewOverlay(maxRows))
+		return b.String()
+	}
+
+	body := m.viewCheckTable(maxWidth, m.tableRows())
+	if m.split {
+		body = m.viewSplit(maxRows)
+	}
+	for _, line := range body {
+		b.WriteString(line)
 		b.WriteString("\n")
 	}
 
-	// Branch + URL
-	info := fmt.Sprintf("Branch: %s", m.prData.HeadRefName)
-	if m.prData.URL != "" {
-		info += fmt.Sprintf("    URL: %s", m.prData.URL)
+	if m.inline && m.quitting {
+		// Left in scrollback: no padding or key hints
+		return strings.TrimSuffix(b.String(), "\n")
 	}
-	b.WriteString(styleDim.Render(truncate(info, maxWidth)))
-	b.WriteString("\n")
 
-	// Blank line
-	b.WriteString("\n")
+	// Footer - pad to bottom of screen
+	linesUsed := m.headerLines() + len(body)
+	for i := linesUsed; i < m.height-1; i++ {
+		b.WriteString("\n")
+	}
 
-	// Summary (always count from unfiltered list for accurate totals)
-	allChecks := m.prData.Checks
-	total := len(allChecks)
-	counts := map[CheckStatus]int{}
-	for _, c := range allChecks {
-		counts[c.Status]++
+	filterHint := "s: show skipped"
+	if !m.hideSkipped {
+		filterHint = "s: hide skipped"
 	}
-	summary := fmt.Sprintf("Checks: %d total", total)
-	var parts []string
-	if n := counts[Pass]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d passed", n))
+	if m.mineOnly {
+		filterHint = "M: all checks | " + filterHint
 	}
-	if n := counts[Running]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d running", n))
+	if m.provider != "" {
+		filterHint = "P: " + m.provider + " | " + filterHint
 	}
-	if n := counts[Fail]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d failed", n))
+	if m.providerHiddenCount() > 0 {
+		filterHint = "H: show hidden providers | " + filterHint
 	}
-	if n := counts[Skipped]; n > 0 {
-		parts = append(parts, fmt.Sprintf("%d skipped", n))
+	backHint := ""
+	if m.canGoBack {
+		backHint = " | esc: back"
 	}
-	if len(parts) > 0 {
-		summary += " - " + strings.Join(parts, ", ")
+	refresh := m.refreshStatus(timeNow())
+	footer := fmt.Sprintf("Refresh: %s | %s | up/down: select | enter: open | v: split | z: %s | r: refresh%s | q: quit",
+		refresh, filterHint, m.density, backHint)
+	if m.readOnly {
+		footer = "READ-ONLY | " + footer
 	}
-	if m.hideSkipped && counts[Skipped] > 0 {
-		summary += fmt.Sprintf(" (%d hidden)", counts[Skipped])
+	if m.split {
+		footer = fmt.Sprintf("tab: %s | ctrl+w w: switch pane | ctrl+w </>: resize | up/down: select | v: close split%s | q: quit",
+			m.pane, backHint)
+	}
+	if m.density == densityCompact && m.flash != "" {
+		// Compact mode has no status line, so messages take the footer
+		b.WriteString(styleRunning.Render(truncate(m.flash, maxWidth)))
+		return b.String()
+	}
+	if !m.kiosk {
+		b.WriteString(styleDim.Render(truncate(footer, maxWidth)))
 	}
-	b.WriteString(styleBold.Render(truncate(summary, maxWidth)))
-	b.WriteString("\n\n")
 
-	// Table header
-	statusW := 12
-	durW := 12
-	tableHdr := fmt.Sprintf("  %-*s%-*sNAME", statusW-2, "STATUS", durW, "DURATION")
-	b.WriteString(styleUnder.Render(truncate(tableHdr, maxWidth)))
-	b.WriteString("\n")
+	return b.String()
+}
 
-	// Calculate how many rows we can show
-	// Lines used: header(1) + title(1) + branch(1) + blank(1) + summary(1) + blank(1) + table header(1) + footer(1) = 8
-	maxRows := m.height - 8
-	if maxRows < 1 {
-		maxRows = 1
+// quit ends the program. The flag lets View draw the last frame for
+// scrollback when running --inline.
+func (m model) quit() (model, tea.Cmd) {
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// viewCheckTable renders the check table's header and up to maxRows rows
+// (from scrollOff) for a column width wide.
+func (m model) viewCheckTable(width, maxRows int) []string {
+	statusHdr := "STATUS"
+	switch {
+	case m.density == densityCompact:
+		statusHdr = ""
+	case m.glyphs != glyphsNone:
+		statusHdr = "  STATUS"
+	}
+	durations := m.baseDurationsFor()
+	if m.density == densityCompact {
+		durations = nil
+	}
+	now := timeNow()
+	cols := m.tableColumns()
+	checks := m.filteredChecks()
+	// A list longer than the screen gets a scrollbar in the last column
+	// and its position in the header
+	overflow := len(checks) > maxRows
+	fullWidth := width
+	if overflow {
+		width -= 2
+	}
+	widths, nameW := m.columnWidths(cols, checks, width, statusHdr, durations, now)
+
+	var hdr strings.Builder
+	hdr.WriteString("  ")
+	for i, c := range cols {
+		text := c.header
+		switch c.name {
+		case columnStatus:
+			text = statusHdr
+		case columnDuration:
+			text = "DURATION"
+		case columnName:
+			text = "NAME"
+		}
+		if i < len(cols)-1 {
+			w := widths[i]
+			if c.name == columnName {
+				w = nameW
+			}
+			text = fmt.Sprintf("%-*s", w, elide(text, w-1))
+		}
+		hdr.WriteString(text)
+	}
+	lines := []string{styleUnder.Render(truncate(hdr.String(), width))}
+	if overflow {
+		last := min(m.scrollOff+maxRows, len(checks))
+		pos := fmt.Sprintf("%d–%d of %d", m.scrollOff+1, last, len(checks))
+		text := truncate(hdr.String(), max(fullWidth-len([]rune(pos))-1, 0))
+		lines[0] = styleUnder.Render(text) + strings.Repeat(" ", max(fullWidth-len([]rune(text))-len([]rune(pos)), 0)) + styleDim.Render(pos)
 	}
 
 	// Table rows (use filtered list with scroll offset)
-	checks := m.filteredChecks()
 	visible := checks
 	if m.scrollOff < len(checks) {
 		visible = checks[m.scrollOff:]
@@ -482,95 +1620,390 @@ func (m model) View() string {
 			break
 		}
 
-		// Compute live duration for running checks
-		dur := check.Duration
-		if !check.Completed && !check.StartedAt.IsZero() {
-			delta := int(time.Since(check.StartedAt).Seconds())
-			if delta < 0 {
-				delta = 0
-			}
-			dur = formatDuration(delta)
-		}
-
 		isSelected := (idx + m.scrollOff) == m.selected
 		marker := "  "
 		if isSelected {
 			marker = "> "
 		}
 
-		statusStr := fmt.Sprintf("%s%-*s", marker, statusW-2, check.Status.String())
-		durStr := fmt.Sprintf("%-*s", durW, dur)
-
-		// Name column gets remaining width
-		nameMaxW := maxWidth - statusW - durW
-		if nameMaxW < 0 {
-			nameMaxW = 0
+		// Apply status color; acknowledged failures are greyed out
+		style := statusStyle(check.Status)
+		restStyle := lipgloss.NewStyle()
+		if m.isAcked(check) || m.isIgnored(check) || m.isMuted(check) {
+			style = styleSkipped
+			restStyle = styleSkipped
 		}
-		nameRunes := []rune(check.Name)
-		nameStr := check.Name
-		if len(nameRunes) > nameMaxW {
-			nameStr = string(nameRunes[:nameMaxW])
+		if isSelected {
+			style = style.Reverse(true)
+			restStyle = restStyle.Reverse(true)
 		}
 
-		// Apply status color
-		var styledStatus string
-		switch check.Status {
-		case Pass:
-			if isSelected {
-				styledStatus = stylePass.Reverse(true).Render(statusStr)
-			} else {
-				styledStatus = stylePass.Render(statusStr)
+		var row strings.Builder
+		for i, c := range cols {
+			cellStyle := restStyle
+			if c.name == columnStatus {
+				cellStyle = style
 			}
-		case Fail:
-			if isSelected {
-				styledStatus = styleFail.Reverse(true).Render(statusStr)
-			} else {
-				styledStatus = styleFail.Render(statusStr)
+			prefix := ""
+			if i == 0 {
+				prefix = marker
 			}
-		case Running:
-			if isSelected {
-				styledStatus = styleRunning.Reverse(true).Render(statusStr)
-			} else {
-				styledStatus = styleRunning.Render(statusStr)
+			w := widths[i]
+			if c.name == columnName {
+				w = nameW
 			}
-		case Skipped:
-			if isSelected {
-				styledStatus = styleSkipped.Reverse(true).Render(statusStr)
-			} else {
-				styledStatus = styleSkipped.Render(statusStr)
+			// Cells leave a column free before the next one
+			pad := func(text string) string {
+				if i == len(cols)-1 {
+					return elide(text, w)
+				}
+				return fmt.Sprintf("%-*s", w, elide(text, w-1))
+			}
+			switch c.name {
+			case columnDuration:
+				dur := liveDuration(check, now)
+				pct, regression, ok := durations.durationDelta(check, now)
+				if !ok || len(dur)+1+len(formatDelta(pct)) > w-1 {
+					row.WriteString(cellStyle.Render(prefix + pad(dur)))
+					break
+				}
+				// Regressions stand out; other deltas are for reference
+				deltaStyle := styleDim
+				switch {
+				case m.isAcked(check):
+					deltaStyle = styleSkipped
+				case regression:
+					deltaStyle = styleFail
+				}
+				if isSelected {
+					deltaStyle = deltaStyle.Reverse(true)
+				}
+				delta := formatDelta(pct)
+				gap := strings.Repeat(" ", max(0, w-len(dur)-1-len(delta)))
+				row.WriteString(cellStyle.Render(prefix+dur+" ") + deltaStyle.Render(delta) + cellStyle.Render(gap))
+			default:
+				row.WriteString(cellStyle.Render(prefix + pad(m.cellText(c, check, now))))
 			}
 		}
+		lines = append(lines, row.String())
+		if m.density == densityComfy {
+			lines = append(lines, "")
+		}
+	}
+	if overflow {
+		perRow := 1
+		if m.density == densityComfy {
+			perRow = 2
+		}
+		body := lines[1:]
+		bar := scrollbar(len(body), len(checks)*perRow, m.scrollOff*perRow)
+		for i, l := range body {
+			body[i] = l + strings.Repeat(" ", max(width-lipgloss.Width(l), 0)) + " " + bar[i]
+		}
+	}
+	return lines
+}
 
-		if isSelected {
-			b.WriteString(styledStatus + styleReverse.Render(durStr+nameStr))
-		} else {
-			b.WriteString(styledStatus + durStr + nameStr)
+// cellText is a check's text in column c, before padding. The duration
+// column's delta is added by viewCheckTable.
+func (m model) cellText(c tableColumn, check Check, now time.Time) string {
+	switch c.name {
+	case columnStatus:
+		switch {
+		case m.density == densityCompact:
+			return m.glyphs.glyph(check.Status)
+		case m.glyphs != glyphsNone:
+			return m.glyphs.glyph(check.Status) + " " + check.Status.String()
 		}
-		b.WriteString("\n")
+		return check.Status.String()
+	case columnDuration:
+		return liveDuration(check, now)
+	case columnName:
+		name := check.Name
+		if note := m.attemptNote(check); note != "" {
+			name += "  (" + note + ")"
+		}
+		if note := m.deployNote(check); note != "" {
+			name += "  (" + note + ")"
+		}
+		if m.isIgnored(check) {
+			name += "  (ignored)"
+		}
+		if m.isMuted(check) {
+			name += "  (muted)"
+		}
+		return name
 	}
+	return c.render(check)
+}
 
-	// Footer - pad to bottom of screen
-	visibleRows := len(visible)
-	if visibleRows > maxRows {
-		visibleRows = maxRows
+// liveDuration is a check's duration, counting up while it runs.
+func liveDuration(check Check, now time.Time) string {
+	if !check.Completed && !check.StartedAt.IsZero() {
+		return formatDuration(max(int(now.Sub(check.StartedAt).Seconds()), 0))
 	}
-	linesUsed := 7 + visibleRows
-	for i := linesUsed; i < m.height-1; i++ {
+	return check.Duration
+}
+
+// columnWidths sizes each column to its widest cell across all the checks,
+// not just the visible ones, so scrolling doesn't shift the table. The name
+// column gets what's left of width; when that is too little, the widest
+// other columns give up space first and their cells are elided. Widths
+// include the gap before the next column.
+func (m model) columnWidths(cols []tableColumn, checks []Check, width int, statusHdr string,
+	durations *baseDurations, now time.Time) (widths []int, nameW int) {
+	const colMax = 40  // no single column wider than this before the name
+	const nameMin = 12 // keep this much of the name before squeezing
+	widths = make([]int, len(cols))
+	minW := make([]int, len(cols))
+	nameWant := len("NAME")
+	for i, c := range cols {
+		hdr := c.header
+		switch c.name {
+		case columnStatus:
+			hdr = statusHdr
+		case columnDuration:
+			hdr = "DURATION"
+		case columnName:
+			for _, check := range checks {
+				nameWant = max(nameWant, lipgloss.Width(m.cellText(c, check, now)))
+			}
+			continue
+		}
+		if c.tmpl != nil && c.width > 0 {
+			widths[i], minW[i] = c.width, c.width
+			continue
+		}
+		w := lipgloss.Width(hdr)
+		for _, check := range checks {
+			cw := lipgloss.Width(m.cellText(c, check, now))
+			if c.name == columnDuration {
+				if pct, _, ok := durations.durationDelta(check, now); ok {
+					cw += 1 + len(formatDelta(pct))
+				}
+			}
+			w = max(w, cw)
+		}
+		widths[i] = min(w, colMax) + 2
+		minW[i] = min(widths[i], max(lipgloss.Width(hdr), 4)+1)
+	}
+
+	avail := width - 2 // the selection marker
+	fixed := func() int {
+		total := 0
+		for _, w := range widths {
+			total += w
+		}
+		return total
+	}
+	want := min(nameWant, nameMin)
+	if !slices.ContainsFunc(cols, func(c tableColumn) bool { return c.name == columnName }) {
+		want = 0
+	}
+	for fixed()+want > avail {
+		// Squeeze the widest column that can still give
+		widest := -1
+		for i := range widths {
+			if widths[i] > minW[i] && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths, max(avail-fixed(), 0)
+}
+
+// tableColumns is the check table's columns from the config, or the
+// built-in status, duration and name.
+func (m model) tableColumns() []tableColumn {
+	if m.columns == nil {
+		cols, _ := parseTable(Table{})
+		return cols
+	}
+	return m.columns
+}
+
+// viewHeader is viewing mode's first line: the PR, conflicts and approval
+// badges, the grade and the clock.
+func (m model) viewHeader() string {
+	var b strings.Builder
+	now := timeNow().Format("2006-01-02 15:04:05")
+	header := fmt.Sprintf("PR Checks - %s #%s", m.repo, m.prNumber) + m.kioskPosition()
+	badge := ""
+	if m.prData != nil && m.prData.Mergeable == "CONFLICTING" {
+		badge = " CONFLICTS"
+	}
+	if len(m.awaitingApproval()) > 0 {
+		badge += " AWAITING APPROVAL"
+	}
+	badge += m.mergeQueueBadge()
+	grade := m.grade()
+	right := now
+	if grade != "" {
+		right = grade + "  " + now
+		if len(header)+1+len(right) > m.width {
+			// a narrow terminal keeps the grade over the clock
+			right, now = grade, ""
+		}
+	}
+	pad := m.width - len(header) - len(right)
+	if pad < 1 {
+		pad = 1
+	}
+	fits := len(header)+len(badge)+1+len(right) <= m.width
+	switch {
+	case fits && badge != "":
+		pad -= len(badge)
+		b.WriteString(styleBold.Render(header) + styleFail.Reverse(true).Render(badge) +
+			styleBold.Render(strings.Repeat(" ", pad)) + viewGrade(grade, now))
+	case fits && grade != "":
+		b.WriteString(styleBold.Render(header+strings.Repeat(" ", pad)) + viewGrade(grade, now))
+	default:
+		headerLine := header + badge + strings.Repeat(" ", pad) + right
+		b.WriteString(styleBold.Render(truncate(headerLine, m.width)))
+	}
+	return b.String()
+}
+
+// checkCounts counts the PR's checks by status, leaving acknowledged
+// failures out of the counts and returning their number separately.
+// Ignored checks aren't counted at all.
+func (m model) checkCounts() (map[CheckStatus]int, int) {
+	counts := map[CheckStatus]int{}
+	acked := 0
+	if m.prData == nil {
+		return counts, 0
+	}
+	for _, c := range m.prData.Checks {
+		if m.isIgnored(c) {
+			continue
+		}
+		if m.isAcked(c) {
+			acked++
+			continue
+		}
+		counts[c.Status]++
+	}
+	return counts, acked
+}
+
+// viewPRSummary renders the lines between the header and the check table:
+// title, branch and links, the status line and the check counts.
+func (m model) viewPRSummary(maxWidth int) string {
+	var b strings.Builder
+
+	// PR title
+	if m.prData.Title != "" {
+		b.WriteString(truncate(m.prData.Title, maxWidth))
 		b.WriteString("\n")
 	}
 
-	filterHint := "s: show skipped"
-	if !m.hideSkipped {
-		filterHint = "s: hide skipped"
+	// Branch + URL
+	info := fmt.Sprintf("Branch: %s", m.prData.HeadRefName)
+	if commit := m.commitInfo(); commit != "" {
+		info += "    " + commit
 	}
-	backHint := ""
-	if m.canGoBack {
-		backHint = " | esc: back"
+	if m.prData.TasksTotal > 0 {
+		info += fmt.Sprintf("    Tasks: %d/%d", m.prData.TasksDone, m.prData.TasksTotal)
 	}
-	footer := fmt.Sprintf("Refresh: %ds | %s | up/down: select | enter: open | r: refresh%s | q: quit",
-		int(m.interval.Seconds()), filterHint, backHint)
-	b.WriteString(styleDim.Render(truncate(footer, maxWidth)))
+	if review := reviewSummary(m.prData.ReviewDecision, m.threads); review != "" {
+		info += "    " + review
+	}
+	if m.prData.URL != "" {
+		info += fmt.Sprintf("    URL: %s", m.prData.URL)
+	}
+	b.WriteString(styleDim.Render(truncate(info, maxWidth)))
+	b.WriteString("\n")
 
+	// Blank line, or the status message / debug overlay when set
+	retry := retryBanner(timeNow())
+	switch {
+	case m.flash != "":
+		b.WriteString(styleRunning.Render(truncate(m.flash, maxWidth)))
+	case m.showDebug:
+		b.WriteString(styleDim.Render(truncate(m.lastFetch.String(), maxWidth)))
+	case retry != "":
+		b.WriteString(styleRunning.Render(truncate(retry, maxWidth)))
+	case m.approvalBanner() != "":
+		b.WriteString(styleRunning.Render(truncate(m.approvalBanner(), maxWidth)))
+	case m.mergeQueueLine() != "":
+		b.WriteString(styleRunning.Render(truncate(m.mergeQueueLine(), maxWidth)))
+	case m.rollupDiscrepancy() != "":
+		b.WriteString(styleFail.Render(truncate(m.rollupDiscrepancy(), maxWidth)))
+	case !m.prData.CachedAt.IsZero():
+		cached := "Showing cached data from " + relativeTime(m.prData.CachedAt.Format(time.RFC3339))
+		b.WriteString(styleDim.Render(truncate(cached, maxWidth)))
+	default:
+		b.WriteString(m.baseBanner())
+	}
+	b.WriteString("\n")
+
+	// Summary (always count from unfiltered list for accurate totals)
+	counts, acked := m.checkCounts()
+	ignored := m.ignoredCount()
+	summary := fmt.Sprintf("Checks: %d total", len(m.prData.Checks)-ignored)
+	var parts []string
+	if n := counts[Pass]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d passed", n))
+	}
+	if n := counts[Running]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d running", n))
+	}
+	if n := counts[Fail]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", n))
+	}
+	if n := counts[Cancelled]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d cancelled", n))
+	}
+	if n := counts[Neutral]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d neutral", n))
+	}
+	if n := counts[Skipped]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", n))
+	}
+	if acked > 0 {
+		parts = append(parts, fmt.Sprintf("%d acknowledged", acked))
+	}
+	if ignored > 0 {
+		parts = append(parts, fmt.Sprintf("%d ignored", ignored))
+	}
+	if n := m.mutedCount(); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d muted", n))
+	}
+	if n := m.durationRegressions(timeNow()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d slower than %s", n, m.prData.BaseRefName))
+	}
+	if len(parts) > 0 {
+		summary += " - " + strings.Join(parts, ", ")
+	}
+	hidden := m.providerHiddenCount()
+	if m.hideSkipped {
+		hidden += counts[Skipped]
+	}
+	if hidden > 0 {
+		summary += fmt.Sprintf(" (%d hidden)", hidden)
+	}
+	if m.kiosk {
+		b.WriteString(m.viewKioskSummary(maxWidth))
+		b.WriteString("\n\n")
+		return b.String()
+	}
+	line := m.attentionBanner() + styleBold.Render(truncate(summary, maxWidth))
+	if cov, ok := m.prCoverage(); ok {
+		if text := "    Coverage: " + cov.String(); lipgloss.Width(line)+lipgloss.Width(text) <= maxWidth {
+			line += text
+		}
+	}
+	if note := m.githubRollupNote(); note != "" && lipgloss.Width(line)+4+len(note) <= maxWidth {
+		line += "    " + styleDim.Render(note)
+	}
+	b.WriteString(line)
+	b.WriteString("\n")
+	b.WriteString(m.newPushDivider(maxWidth))
+	b.WriteString("\n")
 	return b.String()
 }
 
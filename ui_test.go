@@ -7,8 +7,39 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
+// ---------------------------------------------------------------------------
+// Key helpers
+// ---------------------------------------------------------------------------
+
+// press feeds keys to m in order, returning the model and the last command.
+func press(t *testing.T, m model, keys ...tea.KeyMsg) (model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var updated tea.Model
+		updated, cmd = m.Update(k)
+		m = updated.(model)
+	}
+	return m, cmd
+}
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+// typed is the keys typing s, one rune at a time.
+func typed(s string) []tea.KeyMsg {
+	var keys []tea.KeyMsg
+	for _, r := range s {
+		keys = append(keys, runeKey(r))
+	}
+	return keys
+}
+
 // ---------------------------------------------------------------------------
 // relativeTime
 // ---------------------------------------------------------------------------
@@ -39,6 +70,21 @@ func TestRelativeTime(t *testing.T) {
 			updatedAt: time.Now().UTC().Add(-3 * 24 * time.Hour).Format(time.RFC3339),
 			want:      "3d ago",
 		},
+		{
+			name:      "weeks ago",
+			updatedAt: time.Now().UTC().Add(-20 * 24 * time.Hour).Format(time.RFC3339),
+			want:      "2w ago",
+		},
+		{
+			name:      "months ago",
+			updatedAt: time.Now().UTC().Add(-100 * 24 * time.Hour).Format(time.RFC3339),
+			want:      "3mo ago",
+		},
+		{
+			name:      "years ago",
+			updatedAt: time.Now().UTC().Add(-800 * 24 * time.Hour).Format(time.RFC3339),
+			want:      "2y ago",
+		},
 		{
 			name:      "invalid timestamp",
 			updatedAt: "not-a-timestamp",
@@ -125,6 +171,19 @@ func TestNewSelectModel(t *testing.T) {
 	}
 }
 
+func TestNewRepoSelectModel(t *testing.T) {
+	m := newRepoSelectModel("owner/repo", 5*time.Second)
+	if m.mode != modeSelecting {
+		t.Errorf("mode = %v, want modeSelecting", m.mode)
+	}
+	if m.selectRepo != "owner/repo" {
+		t.Errorf("selectRepo = %q, want %q", m.selectRepo, "owner/repo")
+	}
+	if m.Init() == nil {
+		t.Error("Init should return a command to fetch the repo's PRs")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // model.Update
 // ---------------------------------------------------------------------------
@@ -309,6 +368,20 @@ func TestModelUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("prListMsg fetches CI states and prCIMsg applies them", func(t *testing.T) {
+		resetRespCache(t)
+		m := newSelectModel(5 * time.Second)
+		updated, cmd := m.Update(prListMsg{prs: []PRSummary{{Repo: "o/r", Number: 1}, {Repo: "o/r", Number: 2}}})
+		if cmd == nil {
+			t.Fatal("a non-empty PR list should fetch CI states")
+		}
+		updated, _ = updated.(model).Update(prCIMsg{states: map[string]prCI{"o/r#2": {State: "PENDING"}}})
+		um := updated.(model)
+		if um.prs[0].CIState != "" || um.prs[1].CIState != "PENDING" {
+			t.Errorf("CIState = %q, %q; want \"\", PENDING", um.prs[0].CIState, um.prs[1].CIState)
+		}
+	})
+
 	t.Run("prListMsg with error", func(t *testing.T) {
 		m := newSelectModel(5 * time.Second)
 		m.loading = true
@@ -454,6 +527,24 @@ func TestModelUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("hiding skipped keeps cancelled and neutral checks", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width, m.height = 120, 40
+		m.prData = &PRData{Checks: []Check{
+			{Name: "deploy", Status: Cancelled},
+			{Name: "build", Status: Pass},
+			{Name: "docs", Status: Neutral},
+			{Name: "skip1", Status: Skipped},
+		}}
+		m.hideSkipped = true
+		if checks := m.filteredChecks(); len(checks) != 3 || checks[0].Name != "deploy" {
+			t.Errorf("filtered checks = %+v", checks)
+		}
+		if view := m.View(); !strings.Contains(view, "1 passed, 1 cancelled, 1 neutral, 1 skipped") || !strings.Contains(view, "CANCELLED") {
+			t.Errorf("view missing the cancelled and neutral counts:\n%s", view)
+		}
+	})
+
 	t.Run("prDataMsg clamps to filtered length", func(t *testing.T) {
 		m := newModel("o/r", "1", 5*time.Second)
 		m.height = 40
@@ -528,6 +619,41 @@ func TestModelUpdate(t *testing.T) {
 			t.Errorf("mode = %v, want modeSelecting", um.mode)
 		}
 	})
+
+	t.Run("a cycles accounts in selecting mode", func(t *testing.T) {
+		m := newSelectModel(5 * time.Second)
+		m.accounts = []Account{{Name: "personal"}, {Name: "work"}}
+		m.loading = false
+		m.prs = []PRSummary{{Repo: "a"}}
+
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		um := updated.(model)
+		if um.account != 1 {
+			t.Errorf("account = %d, want 1", um.account)
+		}
+		if !um.loading {
+			t.Error("loading should be true after switching account")
+		}
+		if cmd == nil {
+			t.Error("expected cmd to refetch PR list")
+		}
+
+		updated, _ = um.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		if got := updated.(model).account; got != 0 {
+			t.Errorf("account = %d, want 0 (wrap around)", got)
+		}
+	})
+
+	t.Run("a does nothing with a single account", func(t *testing.T) {
+		m := newSelectModel(5 * time.Second)
+		m.accounts = []Account{{Name: "personal"}}
+		m.loading = false
+
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		if cmd != nil {
+			t.Error("expected nil cmd with a single account")
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -535,6 +661,24 @@ func TestModelUpdate(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestViewSelecting(t *testing.T) {
+	t.Run("CI glyphs, skipped CI and draft", func(t *testing.T) {
+		m := newSelectModel(5 * time.Second)
+		m.width = 80
+		m.height = 30
+		m.loading = false
+		m.prs = []PRSummary{
+			{Repo: "o/r", Number: 1, Title: "Green", CIState: "SUCCESS"},
+			{Repo: "o/r", Number: 2, Title: "Red", CIState: "FAILURE", Draft: true},
+			{Repo: "o/r", Number: 3, Title: "Docs", SkipCI: true},
+		}
+		out := m.viewSelecting()
+		for _, want := range []string{"✓", "✗", "draft", "CI skipped"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output should contain %q, got %q", want, out)
+			}
+		}
+	})
+
 	t.Run("width=0 shows Loading", func(t *testing.T) {
 		m := newSelectModel(5 * time.Second)
 		m.width = 0
@@ -601,6 +745,18 @@ func TestViewSelecting(t *testing.T) {
 		}
 	})
 
+	t.Run("repo-scoped selector names the repo", func(t *testing.T) {
+		m := newRepoSelectModel("owner/repo", 5*time.Second)
+		m.width = 80
+		m.height = 30
+		m.loading = false
+		m.prs = []PRSummary{{Repo: "owner/repo", Number: 7, Title: "Fix"}}
+		out := m.viewSelecting()
+		if !strings.Contains(out, "Open pull requests in owner/repo") {
+			t.Errorf("output should name the scoped repo, got %q", out)
+		}
+	})
+
 	t.Run("selected item has marker", func(t *testing.T) {
 		m := newSelectModel(5 * time.Second)
 		m.width = 80
@@ -615,6 +771,50 @@ func TestViewSelecting(t *testing.T) {
 			t.Error("output should contain selection marker '▸'")
 		}
 	})
+
+	t.Run("long list scrolls to the selection and fits the screen", func(t *testing.T) {
+		m := newSelectModel(5 * time.Second)
+		m.width = 40
+		m.height = 12
+		m.loading = false
+		for i := 1; i <= 10; i++ {
+			m.prs = append(m.prs, PRSummary{Repo: "owner/repo", Number: i, Title: strings.Repeat("long title ", 6)})
+		}
+		m.selected = 6
+		out := m.viewSelecting()
+		lines := strings.Split(out, "\n")
+		if len(lines) != m.height {
+			t.Errorf("got %d lines, want %d:\n%s", len(lines), m.height, out)
+		}
+		for _, l := range lines {
+			if w := lipgloss.Width(l); w > m.width {
+				t.Errorf("line wider than the terminal (%d): %q", w, l)
+			}
+		}
+		if !strings.Contains(out, "▸ 7 owner/repo #7") || strings.Contains(out, "#1 ") || !strings.Contains(lines[len(lines)-1], "up/down") {
+			t.Errorf("selected PR not in view:\n%s", out)
+		}
+	})
+}
+
+func TestViewTooSmall(t *testing.T) {
+	for _, m := range []model{newSelectModel(5 * time.Second), newModel("o/r", "1", 5*time.Second), newDashboardModel("", 5*time.Second)} {
+		m.width, m.height = 30, 8
+		out := m.View()
+		if !strings.Contains(out, "Terminal too small") || !strings.Contains(out, "need ≥ 40x10, have 30x8") {
+			t.Errorf("mode %v: View() = %q", m.mode, out)
+		}
+		m.width, m.height = 40, 10
+		if strings.Contains(m.View(), "too small") {
+			t.Errorf("mode %v: 40x10 is big enough", m.mode)
+		}
+	}
+	m := newModel("o/r", "1", 5*time.Second)
+	m.mini = true
+	m.width, m.height = 30, 3
+	if strings.Contains(m.View(), "too small") {
+		t.Error("mini mode is exempt")
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -772,6 +972,123 @@ func TestView(t *testing.T) {
 		}
 	})
 
+	t.Run("D toggles debug overlay", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width = 120
+		m.height = 30
+		m.prData = &PRData{Checks: []Check{{Name: "build", Status: Pass}}}
+		if strings.Contains(m.View(), "debug:") {
+			t.Error("debug overlay should be hidden by default")
+		}
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+		um := updated.(model)
+		updated, _ = um.Update(prDataMsg{data: &PRData{payloadBytes: 2048}, latency: 300 * time.Millisecond})
+		um = updated.(model)
+		out := um.View()
+		if !strings.Contains(out, "300ms") || !strings.Contains(out, "2.0 KB") {
+			t.Errorf("debug overlay should show latency and size, got %q", out)
+		}
+	})
+
+	t.Run("d opens dependency overlay and esc closes it", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width = 120
+		m.height = 30
+		m.prData = &PRData{Checks: []Check{{Name: "lint (CI)", JobName: "lint", Workflow: "CI", Status: Pass}}}
+
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		um := updated.(model)
+		if um.overlay != overlayDeps {
+			t.Fatalf("overlay = %v, want overlayDeps", um.overlay)
+		}
+		if cmd == nil {
+			t.Error("expected cmd to fetch workflow definitions")
+		}
+		if !strings.Contains(um.View(), "Loading workflow definitions") {
+			t.Error("overlay should show loading state")
+		}
+
+		updated, _ = um.Update(depGraphsMsg{graphs: []depGraph{{Workflow: "CI", Jobs: []depJob{{ID: "lint"}}}}})
+		um = updated.(model)
+		out := um.View()
+		if !strings.Contains(out, "JOB DEPENDENCIES") || !strings.Contains(out, "lint  PASS") {
+			t.Errorf("overlay should render the graph, got %q", out)
+		}
+
+		updated, _ = um.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if updated.(model).overlay != overlayNone {
+			t.Error("esc should close the overlay")
+		}
+	})
+
+	t.Run("A acknowledges a failing check", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width = 120
+		m.height = 30
+		m.prData = &PRData{Checks: []Check{
+			{Name: "flaky", Status: Fail},
+			{Name: "build", Status: Pass},
+		}}
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+		um := updated.(model)
+		if len(um.failingChecks()) != 0 {
+			t.Error("acknowledged check should not count as failing")
+		}
+		out := um.View()
+		if strings.Contains(out, "1 failed") {
+			t.Error("summary should not count the acknowledged failure")
+		}
+		if !strings.Contains(out, "1 acknowledged") {
+			t.Error("summary should show acknowledged count")
+		}
+
+		updated, _ = um.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+		um = updated.(model)
+		if len(um.failingChecks()) != 1 {
+			t.Error("A again should un-acknowledge")
+		}
+	})
+
+	t.Run("A ignores passing checks", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.prData = &PRData{Checks: []Check{{Name: "build", Status: Pass}}}
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+		um := updated.(model)
+		if um.store.acks("o/r", "1")["build"] {
+			t.Error("passing check should not be acknowledged")
+		}
+	})
+
+	t.Run("task progress shown when description has tasks", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width = 120
+		m.height = 30
+		m.prData = &PRData{HeadRefName: "feat", TasksDone: 3, TasksTotal: 7}
+		if !strings.Contains(m.View(), "Tasks: 3/7") {
+			t.Error("header should show task progress")
+		}
+		m.prData.TasksTotal = 0
+		if strings.Contains(m.View(), "Tasks:") {
+			t.Error("task progress should be hidden without tasks")
+		}
+	})
+
+	t.Run("conflicts badge in header", func(t *testing.T) {
+		m := newModel("o/r", "1", 5*time.Second)
+		m.width = 120
+		m.height = 30
+		m.prData = &PRData{Mergeable: "MERGEABLE"}
+		if strings.Contains(m.View(), "CONFLICTS") {
+			t.Error("no badge expected for mergeable PR")
+		}
+		m.prData.Mergeable = "CONFLICTING"
+		if !strings.Contains(m.View(), "CONFLICTS") {
+			t.Error("badge expected for conflicting PR")
+		}
+	})
+
 	t.Run("footer shows esc hint when canGoBack", func(t *testing.T) {
 		m := newSelectModel(5 * time.Second)
 		m.mode = modeViewing
@@ -794,6 +1111,24 @@ func TestView(t *testing.T) {
 			t.Error("footer should not contain 'esc: back' when canGoBack=false")
 		}
 	})
+
+	t.Run("inline quit leaves the checks without padding or footer", func(t *testing.T) {
+		m := newModel("o/r", "7", 5*time.Second)
+		m.inline = true
+		m.width, m.height = 120, 30
+		m.prData = &PRData{Title: "Test PR", Checks: []Check{{Name: "a", Status: Pass}}}
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+		if cmd == nil {
+			t.Fatal("q should quit")
+		}
+		out := updated.(model).View()
+		if strings.Contains(out, "q: quit") || strings.HasSuffix(out, "\n") {
+			t.Errorf("last frame has footer or padding:\n%q", out)
+		}
+		if !strings.Contains(out, "Test PR") {
+			t.Errorf("last frame lost the PR:\n%s", out)
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -863,6 +1198,35 @@ func TestFilteredChecks(t *testing.T) {
 // scroll offset
 // ---------------------------------------------------------------------------
 
+func TestCheckTableScrollbar(t *testing.T) {
+	m := navModel(12)
+	m.width = 60
+	m.scrollOff, m.selected = 3, 3
+	lines := m.viewCheckTable(m.width, 4)
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if hdr := ansi.Strip(lines[0]); !strings.HasSuffix(hdr, "4–7 of 12") || lipgloss.Width(hdr) != m.width {
+		t.Errorf("header = %q", hdr)
+	}
+	var bar string
+	for _, l := range lines[1:] {
+		l = ansi.Strip(l)
+		if lipgloss.Width(l) != m.width {
+			t.Errorf("row %q is %d wide, want %d", l, lipgloss.Width(l), m.width)
+		}
+		bar += string([]rune(l)[m.width-1:])
+	}
+	if bar != "│┃││" {
+		t.Errorf("scrollbar = %q", bar)
+	}
+
+	// A list that fits has neither
+	if lines := m.viewCheckTable(m.width, 12); strings.Contains(lines[0], "of 12") || strings.Contains(lines[1], "┃") {
+		t.Errorf("short list:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
 func TestScrollOffset(t *testing.T) {
 	t.Run("selected beyond viewport adjusts scrollOff", func(t *testing.T) {
 		m := newModel("o/r", "1", 5*time.Second)
@@ -985,3 +1349,19 @@ func TestPRDataMsgIgnoredInSelectingMode(t *testing.T) {
 		t.Error("prData should remain nil (prDataMsg should be ignored in selecting mode)")
 	}
 }
+
+func TestCIBadgeCounts(t *testing.T) {
+	tests := []struct {
+		pr   PRSummary
+		want string
+	}{
+		{PRSummary{CIState: "FAILURE", CICounts: ciCounts{Pass: 12, Fail: 1, Running: 2}}, "+12 x1 *2"},
+		{PRSummary{CIState: "SUCCESS", CICounts: ciCounts{Pass: 4}}, "+4"},
+		{PRSummary{CIState: "PENDING"}, "*"},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(ciBadge(tt.pr, glyphsASCII)); got != tt.want {
+			t.Errorf("ciBadge(%+v) = %q, want %q", tt.pr, got, tt.want)
+		}
+	}
+}
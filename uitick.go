@@ -0,0 +1,22 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// uiTickMsg repaints viewing mode and the PR picker once a second between
+// polls, so running checks' durations, the header clock, the refresh
+// countdown and the picker's "updated 5m ago" times move on without
+// fetching anything. Fetching stays on tickMsg. One tick runs across
+// switches between the picker and viewing mode.
+type uiTickMsg time.Time
+
+// uiTickCmd ticks on the wall clock's second boundary, in step with the
+// header clock.
+func uiTickCmd() tea.Cmd {
+	return tea.Every(time.Second, func(t time.Time) tea.Msg {
+		return uiTickMsg(t)
+	})
+}
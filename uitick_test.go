@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestUITick(t *testing.T) {
+	saved := timeNow
+	t.Cleanup(func() { timeNow = saved })
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return started.Add(90 * time.Second) }
+
+	m := newModel("o/r", "1", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.store = &stateStore{}
+	m.prData = &PRData{Checks: []Check{{Name: "build", Status: Running, StartedAt: started}}}
+	next := m.nextRefresh
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "1m30s") {
+		t.Fatalf("running check's duration before the tick:\n%s", view)
+	}
+
+	timeNow = func() time.Time { return started.Add(95 * time.Second) }
+	updated, cmd := m.Update(uiTickMsg(timeNow()))
+	m = updated.(model)
+	if cmd == nil {
+		t.Fatal("UI tick stopped while viewing")
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "1m35s") || strings.Contains(view, "1m30s") {
+		t.Errorf("running check's duration didn't count up:\n%s", view)
+	}
+	if !m.nextRefresh.Equal(next) || !m.lastFetch.at.IsZero() {
+		t.Errorf("UI tick touched polling: next %v, last fetch %v", m.nextRefresh, m.lastFetch.at)
+	}
+
+	m.mode = modeDashboard
+	if _, cmd := m.Update(uiTickMsg(timeNow())); cmd != nil {
+		t.Error("UI tick kept running on the dashboard")
+	}
+}
+
+func TestUITickPicker(t *testing.T) {
+	saved := timeNow
+	t.Cleanup(func() { timeNow = saved })
+	updated := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return updated.Add(2 * time.Minute) }
+
+	m := newRepoSelectModel("o/r", 5*time.Second)
+	m.width, m.height = 120, 20
+	m.loading = false
+	m.prs = []PRSummary{
+		{Repo: "o/r", Number: 1, Title: "First", UpdatedAt: updated.Format(time.RFC3339), CreatedAt: updated.Add(-time.Hour).Format(time.RFC3339)},
+		{Repo: "o/r", Number: 2, Title: "Second", UpdatedAt: updated.Format(time.RFC3339)},
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "updated 2m ago") {
+		t.Fatalf("view = %s", view)
+	}
+
+	timeNow = func() time.Time { return updated.Add(7 * time.Minute) }
+	next, cmd := m.Update(uiTickMsg(timeNow()))
+	m = next.(model)
+	if cmd == nil {
+		t.Fatal("UI tick stopped while selecting")
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "updated 7m ago") {
+		t.Errorf("relative time didn't move on: %s", view)
+	}
+	detail := "updated " + absoluteTime(m.prs[0].UpdatedAt) + " · opened " + absoluteTime(m.prs[0].CreatedAt)
+	if !strings.Contains(view, detail) {
+		t.Errorf("selected PR has no %q line: %s", detail, view)
+	}
+	if strings.Count(view, "opened ") != 1 {
+		t.Errorf("detail line shown for unselected PRs too: %s", view)
+	}
+
+	m.mode = modeDashboard
+	if _, cmd := m.Update(uiTickMsg(timeNow())); cmd != nil {
+		t.Error("UI tick kept running on the dashboard")
+	}
+}
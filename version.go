@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"strings"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=abc1234 -X main.date=2024-05-01"
+//
+// The Makefile does this from git. Builds without ldflags fall back to what
+// the Go toolchain recorded (the module version for `go install`, the VCS
+// revision for a build in a checkout).
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+// pseudoVersion matches the timestamp-commit part of a Go pseudo-version
+// such as v0.0.0-20240501120000-abcdef123456.
+var pseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}`)
+
+// buildInfo returns the version, commit and build date, filling in whatever
+// ldflags didn't set from the binary's embedded build info.
+func buildInfo() (v, c, d string) {
+	v, c, d = version, commit, date
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		info = &debug.BuildInfo{}
+	}
+	// A build in a checkout gets a pseudo-version, which says less than the
+	// commit shown next to it
+	if m := info.Main.Version; v == "" && m != "" && m != "(devel)" && !pseudoVersion.MatchString(m) {
+		v = m
+	}
+	if v == "" {
+		v = "dev"
+	}
+	if c != "" {
+		return v, c, d
+	}
+	dirty := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			c = s.Value
+		case "vcs.time":
+			if d == "" {
+				d = s.Value
+			}
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if len(c) > 12 {
+		c = c[:12]
+	}
+	if c != "" && dirty {
+		c += "-dirty"
+	}
+	return v, c, d
+}
+
+// versionString is the --version output: "prtop v1.2.0 (abc1234, 2024-05-01)".
+func versionString() string {
+	v, c, d := buildInfo()
+	var extra []string
+	if c != "" {
+		extra = append(extra, c)
+	}
+	if d != "" {
+		extra = append(extra, d)
+	}
+	if len(extra) == 0 {
+		return "prtop " + v
+	}
+	return fmt.Sprintf("prtop %s (%s)", v, strings.Join(extra, ", "))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// versionString
+// ---------------------------------------------------------------------------
+
+func TestVersionString(t *testing.T) {
+	t.Cleanup(func() { version, commit, date = "", "", "" })
+
+	version, commit, date = "v1.2.0", "abc1234", "2024-05-01"
+	if got := versionString(); got != "prtop v1.2.0 (abc1234, 2024-05-01)" {
+		t.Errorf("versionString() = %q", got)
+	}
+
+	// Without ldflags the test binary has no module version or VCS info
+	version, commit, date = "", "", ""
+	if got := versionString(); !strings.HasPrefix(got, "prtop dev") {
+		t.Errorf("versionString() = %q, want prtop dev", got)
+	}
+}
+
+func TestPseudoVersion(t *testing.T) {
+	for v, want := range map[string]bool{
+		"v0.0.0-20240501120000-abcdef123456":         true,
+		"v1.2.1-0.20240501120000-abcdef123456+dirty": true,
+		"v1.2.0": false,
+	} {
+		if got := pseudoVersion.MatchString(v); got != want {
+			t.Errorf("pseudoVersion.MatchString(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// watchlist is a file of PR references (URLs or owner/repo#123, one per
+// line, # starts a comment line) that the dashboard always includes. An
+// empty path keeps the list in memory only.
+type watchlist struct {
+	path  string
+	hosts []string
+	prs   []PRSummary // in file order; Title is filled in by the dashboard
+}
+
+func defaultWatchlistPath() string {
+	dir := configDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "watchlist")
+}
+
+// parseWatchLine returns the PR on a watchlist line. Blank and comment
+// lines return ok=false and no error.
+func parseWatchLine(line string, hosts []string) (PRSummary, bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return PRSummary{}, false, nil
+	}
+	repo, number, ok := parsePRRef(line, hosts...)
+	if !ok {
+		return PRSummary{}, false, fmt.Errorf("not a PR reference: %s", line)
+	}
+	n, _ := strconv.Atoi(number)
+	return PRSummary{Repo: repo, Number: n}, true, nil
+}
+
+// openWatchlist reads the watchlist at path. A missing file is not an error.
+func openWatchlist(path string, hosts []string) (*watchlist, error) {
+	w := &watchlist{path: path, hosts: hosts}
+	if path == "" {
+		return w, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		pr, ok, err := parseWatchLine(line, hosts)
+		if err != nil {
+			return nil, fmt.Errorf("watchlist %s line %d: %w", path, i+1, err)
+		}
+		if ok && !w.has(pr.Repo, pr.Number) {
+			w.prs = append(w.prs, pr)
+		}
+	}
+	return w, nil
+}
+
+func (w *watchlist) has(repo string, number int) bool {
+	for _, pr := range w.prs {
+		if pr.Repo == repo && pr.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+// toggle adds the PR to the watchlist, or removes it if already there, and
+// rewrites the file. Comments and other lines are kept as they are.
+func (w *watchlist) toggle(repo string, number int) (added bool, err error) {
+	var lines []string
+	if w.path != "" {
+		data, err := os.ReadFile(w.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, fmt.Errorf("failed to read watchlist: %w", err)
+		}
+		if len(data) > 0 {
+			lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		}
+	}
+
+	var prs []PRSummary
+	if w.has(repo, number) {
+		kept := lines[:0]
+		for _, line := range lines {
+			if pr, ok, _ := parseWatchLine(line, w.hosts); ok && pr.Repo == repo && pr.Number == number {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		lines = kept
+		for _, pr := range w.prs {
+			if pr.Repo != repo || pr.Number != number {
+				prs = append(prs, pr)
+			}
+		}
+	} else {
+		lines = append(lines, prKey(repo, strconv.Itoa(number)))
+		prs = append(append(prs, w.prs...), PRSummary{Repo: repo, Number: number})
+		added = true
+	}
+
+	if w.path != "" {
+		if err := w.write(lines); err != nil {
+			return false, err
+		}
+	}
+	w.prs = prs
+	return added, nil
+}
+
+func (w *watchlist) write(lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	return nil
+}
+
+// withWatched appends the watchlist PRs missing from prs.
+func (w *watchlist) withWatched(prs []PRSummary) []PRSummary {
+	for _, pr := range w.prs {
+		found := false
+		for _, p := range prs {
+			if p.Repo == pr.Repo && p.Number == pr.Number {
+				found = true
+				break
+			}
+		}
+		if !found {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// toggleWatch adds or removes the viewed PR (or the dashboard row under the
+// cursor) from the watchlist.
+func (m model) toggleWatch() model {
+	var repo string
+	var number int
+	switch m.mode {
+	case modeViewing:
+		repo = m.repo
+		number, _ = strconv.Atoi(m.prNumber)
+	case modeDashboard:
+		if m.selected >= len(m.prs) {
+			return m
+		}
+		repo, number = m.prs[m.selected].Repo, m.prs[m.selected].Number
+	default:
+		return m
+	}
+	added, err := m.watch.toggle(repo, number)
+	switch {
+	case err != nil:
+		m.flash = fmt.Sprintf("Error: %s", err)
+	case added:
+		m.flash = fmt.Sprintf("Added %s#%d to the watchlist", repo, number)
+	default:
+		m.flash = fmt.Sprintf("Removed %s#%d from the watchlist", repo, number)
+	}
+	return m
+}
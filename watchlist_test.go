@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOpenWatchlist(t *testing.T) {
+	t.Run("missing file is empty", func(t *testing.T) {
+		w, err := openWatchlist(filepath.Join(t.TempDir(), "watchlist"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(w.prs) != 0 {
+			t.Errorf("got %d PRs, want 0", len(w.prs))
+		}
+	})
+
+	t.Run("URLs, shorthand, comments and duplicates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "watchlist")
+		data := "# release 2.0 blockers\nhttps://github.com/o/r/pull/1\n\no/r#1\nghe.corp.com/team/svc#7\n"
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		w, err := openWatchlist(path, []string{"ghe.corp.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(w.prs) != 2 || !w.has("o/r", 1) || !w.has("ghe.corp.com/team/svc", 7) {
+			t.Errorf("prs = %+v", w.prs)
+		}
+	})
+
+	t.Run("bad line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "watchlist")
+		if err := os.WriteFile(path, []byte("o/r#1\nnot a pr\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := openWatchlist(path, nil)
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("err = %v, want line 2 error", err)
+		}
+	})
+}
+
+func TestWatchlistToggle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prtop", "watchlist")
+	w, err := openWatchlist(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added, err := w.toggle("o/r", 5); err != nil || !added {
+		t.Fatalf("toggle add = %v, %v", added, err)
+	}
+	if err := os.WriteFile(path, []byte("# keep me\nhttps://github.com/o/r/pull/5\nx/y#2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, _ = openWatchlist(path, nil)
+	if added, err := w.toggle("o/r", 5); err != nil || added {
+		t.Fatalf("toggle remove = %v, %v", added, err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "# keep me\nx/y#2\n" {
+		t.Errorf("file after remove = %q", data)
+	}
+	if w.has("o/r", 5) {
+		t.Error("removed PR should no longer be watched")
+	}
+}
+
+func TestDashboardIncludesWatchlist(t *testing.T) {
+	m := newDashboardModel("", 5*time.Second)
+	m.watch = &watchlist{prs: []PRSummary{{Repo: "o/r", Number: 1}, {Repo: "x/y", Number: 9}}}
+	m.width = 100
+	m.height = 20
+
+	updated, _ := m.Update(prListMsg{prs: []PRSummary{{Repo: "o/r", Number: 1, Title: "Mine"}}})
+	m = updated.(model)
+	if len(m.prs) != 2 || m.prs[1].Repo != "x/y" {
+		t.Fatalf("prs = %+v, want watched x/y#9 appended once", m.prs)
+	}
+	if out := m.View(); !strings.Contains(out, "★ Mine") {
+		t.Errorf("watched PRs should be starred, got %q", out)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	m = updated.(model)
+	if m.watch.has("o/r", 1) || !strings.Contains(m.flash, "Removed o/r#1") {
+		t.Errorf("w should unwatch the selected row, flash = %q", m.flash)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const workspacePRsQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: OPEN, first: 50, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes {
+        number title url updatedAt createdAt isDraft headRefName
+        headRepositoryOwner { login }
+        files(first: 100) { nodes { path } }
+      }
+    }
+  }
+}`
+
+// workspaceArgs resolves --workspace's repo: the one given, or else the
+// one gh picks for the working directory. The working directory must be a
+// clone of it; remote is the git remote that points at it.
+func (s *session) workspaceArgs(name string, args []string) (repo, remote string, err error) {
+	repo, err = s.repoArg(name, args)
+	if err != nil {
+		return "", "", err
+	}
+	if repo == "" {
+		out, err := runGh(nil, "repo", "view", "--json", "url")
+		if err != nil {
+			return "", "", fmt.Errorf("--workspace: no repo given and none found for the working directory: %w", err)
+		}
+		var r struct {
+			URL string `json:"url"`
+		}
+		var ok bool
+		if json.Unmarshal(out, &r) == nil {
+			repo, ok = parseRepo(r.URL, s.hosts...)
+		}
+		if !ok {
+			return "", "", fmt.Errorf("--workspace: gh returned no repo for the working directory")
+		}
+	}
+	remote = localRemote(repo, s.hosts)
+	if remote == "" {
+		return "", "", fmt.Errorf("--workspace looks at the working directory, which isn't a clone of %s", repo)
+	}
+	return repo, remote, nil
+}
+
+// workspaceFiles lists the files, relative to the repo's root, that the
+// working directory changes: uncommitted and untracked files, and those
+// the branch's commits changed since it left remote's default branch.
+func workspaceFiles(remote string) ([]string, error) {
+	runs := [][]string{
+		{"diff", "--name-only", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard", "--full-name"},
+	}
+	if base := defaultBranch(remote); base != "" {
+		runs = append(runs, []string{"diff", "--name-only", base + "...HEAD"})
+	}
+	seen := map[string]bool{}
+	var files []string
+	for _, args := range runs {
+		out, err := execCommand("git", args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+		}
+		for _, f := range strings.Split(string(out), "\n") {
+			if f = strings.TrimSpace(f); f != "" && !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// defaultBranch is remote's default branch as git knows it, e.g.
+// origin/main, or "" when the clone never recorded it.
+func defaultBranch(remote string) string {
+	out, err := execCommand("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD").Output()
+	if err != nil {
+		logger.Debug("no default branch for remote", "remote", remote, "err", err)
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// fetchWorkspacePRs lists repo's open PRs that change a file the working
+// directory (a clone with remote) changes too, with the shared files in
+// Overlap. The PR of the checked-out branch, as pushed to its upstream, is
+// left out, but not other PRs from branches of the same name, such as a
+// fork's main. Only the 50 most recently updated PRs, and their first 100
+// files, are compared.
+func fetchWorkspacePRs(acct *Account, repo, remote string) ([]PRSummary, error) {
+	files, err := workspaceFiles(remote)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	mine := map[string]bool{}
+	for _, f := range files {
+		mine[f] = true
+	}
+
+	_, ownerRepo := splitRepoHost(repo)
+	owner, name, _ := strings.Cut(ownerRepo, "/")
+	out, err := ghAPI(acct, repo, "graphql",
+		"-f", "query="+workspacePRsQuery,
+		"-F", "owner="+owner,
+		"-F", "name="+name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequests struct {
+					Nodes []struct {
+						Number      int    `json:"number"`
+						Title       string `json:"title"`
+						URL         string `json:"url"`
+						UpdatedAt   string `json:"updatedAt"`
+						CreatedAt   string `json:"createdAt"`
+						IsDraft     bool   `json:"isDraft"`
+						HeadRefName string `json:"headRefName"`
+						HeadOwner   *struct {
+							Login string `json:"login"`
+						} `json:"headRepositoryOwner"`
+						Files struct {
+							Nodes []struct {
+								Path string `json:"path"`
+							} `json:"nodes"`
+						} `json:"files"`
+					} `json:"nodes"`
+				} `json:"pullRequests"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse open PRs: %w", err)
+	}
+
+	headOwner, headRef, pushed := branchHead()
+	var prs []PRSummary
+	for _, n := range resp.Data.Repository.PullRequests.Nodes {
+		if pushed && n.HeadRefName == headRef && n.HeadOwner != nil && strings.EqualFold(n.HeadOwner.Login, headOwner) {
+			continue
+		}
+		var overlap []string
+		for _, f := range n.Files.Nodes {
+			if mine[f.Path] {
+				overlap = append(overlap, f.Path)
+			}
+		}
+		if len(overlap) == 0 {
+			continue
+		}
+		prs = append(prs, PRSummary{
+			Repo:      repo,
+			Number:    n.Number,
+			Title:     n.Title,
+			URL:       n.URL,
+			UpdatedAt: n.UpdatedAt,
+			CreatedAt: n.CreatedAt,
+			Draft:     n.IsDraft,
+			Reason:    overlapReason(overlap),
+			Overlap:   strings.Join(overlap, ", "),
+		})
+	}
+	sortPRs(prs, sortUpdated, false)
+	return prs, nil
+}
+
+// overlapReason is the picker's note on a PR touching the working
+// directory's files.
+func overlapReason(overlap []string) string {
+	if len(overlap) == 1 {
+		return "touches " + overlap[0]
+	}
+	return fmt.Sprintf("touches %d of your files", len(overlap))
+}
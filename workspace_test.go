@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const workspaceOpenPRs = `{"data":{"repository":{"pullRequests":{"nodes":[
+	{"number":3,"title":"Rework the uploader","headRefName":"uploader","updatedAt":"2024-05-01T10:00:00Z","files":{"nodes":[{"path":"upload/retry.go"},{"path":"upload/upload.go"},{"path":"README.md"}]}},
+	{"number":5,"title":"Bump deps","headRefName":"deps","updatedAt":"2024-05-02T10:00:00Z","files":{"nodes":[{"path":"go.mod"}]}},
+	{"number":8,"title":"My own PR","headRefName":"retry","headRepositoryOwner":{"login":"Me"},"updatedAt":"2024-05-03T10:00:00Z","files":{"nodes":[{"path":"upload/retry.go"}]}},
+	{"number":10,"title":"Retry from a fork","headRefName":"retry","headRepositoryOwner":{"login":"someone"},"updatedAt":"2024-04-30T10:00:00Z","files":{"nodes":[{"path":"upload/retry.go"}]}},
+	{"number":9,"title":"Docs","headRefName":"docs","updatedAt":"2024-05-04T10:00:00Z","files":{"nodes":[{"path":"docs/new.md"}]}}]}}}}`
+
+func TestFetchWorkspacePRs(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"diff --name-only HEAD":           "upload/upload.go\n",
+		"ls-files --others":               "docs/new.md\n",
+		"symbolic-ref":                    "origin/main\n",
+		"diff --name-only origin/main...": "upload/retry.go\nupload/upload.go\n",
+		"@{upstream}":                     "origin/retry\n",
+		"remote get-url origin":           "git@github.com:me/r.git\n",
+		"graphql":                         workspaceOpenPRs,
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+
+	files, err := workspaceFiles("origin")
+	if err != nil || strings.Join(files, " ") != "docs/new.md upload/retry.go upload/upload.go" {
+		t.Errorf("workspaceFiles = %q, %v", files, err)
+	}
+	prs, err := fetchWorkspacePRs(nil, "o/r", "origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, pr := range prs {
+		got = append(got, pr.Title+": "+pr.Reason)
+	}
+	want := []string{"Docs: touches docs/new.md", "Rework the uploader: touches 2 of your files", "Retry from a fork: touches upload/retry.go"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("PRs =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(prs) == 3 && prs[1].Overlap != "upload/retry.go, upload/upload.go" {
+		t.Errorf("overlap = %q", prs[1].Overlap)
+	}
+
+	// Without a recorded default branch only the uncommitted files count
+	execCommand = fakeExecByArgs(map[string]string{
+		"diff --name-only HEAD": "go.mod\n",
+		"ls-files --others":     "",
+		"graphql":               workspaceOpenPRs,
+	})
+	if prs, err := fetchWorkspacePRs(nil, "o/r", "origin"); err != nil || len(prs) != 1 || prs[0].Number != 5 {
+		t.Errorf("fetchWorkspacePRs = %+v, %v; want #5", prs, err)
+	}
+}
+
+func TestWorkspacePicker(t *testing.T) {
+	m := newRepoSelectModel("o/r", 5*time.Second)
+	m.workspaceRemote = "origin"
+	m.width, m.height = 100, 30
+	m.loading = false
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "touching the files you've changed") || !strings.Contains(view, "No open PRs touch the files you've changed.") {
+		t.Errorf("empty picker:\n%s", view)
+	}
+	m.prs = []PRSummary{{Repo: "o/r", Number: 3, Title: "Rework the uploader", Reason: "touches 2 of your files", Overlap: "a.go, b.go"}}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "touches 2 of your files") || !strings.Contains(view, "touches a.go, b.go") {
+		t.Errorf("picker:\n%s", view)
+	}
+}
+
+func TestWorkspaceArgs(t *testing.T) {
+	execCommand = fakeExecByArgs(map[string]string{
+		"repo view": `{"url":"https://github.com/o/r"}`,
+		"remote -v": "origin\tgit@github.com:me/r.git (fetch)\nupstream\thttps://github.com/o/r.git (fetch)\n",
+	})
+	t.Cleanup(func() { execCommand = exec.Command })
+	s, _, _ := testSession(t)
+	if repo, remote, err := s.workspaceArgs("select", nil); repo != "o/r" || remote != "upstream" || err != nil {
+		t.Errorf("workspaceArgs = %q, %q, %v; want o/r, upstream", repo, remote, err)
+	}
+	if _, _, err := s.workspaceArgs("select", []string{"other/repo"}); err == nil || !strings.Contains(err.Error(), "isn't a clone of other/repo") {
+		t.Errorf("another repo: err = %v", err)
+	}
+}